@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"starter-boilerplate/internal"
+	"starter-boilerplate/internal/shared/config"
+	"starter-boilerplate/internal/shared/logger"
+	pkgamqp "starter-boilerplate/pkg/amqp"
+
+	"gopkg.in/yaml.v3"
+)
+
+// errDrift is returned by diff when the live broker differs from the desired topology.
+var errDrift = errors.New("topology drift detected")
+
+func main() {
+	cfg := config.SetupConfig()
+	logger.SetupLogger(cfg.Logger)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if len(os.Args) < 2 {
+		printUsage()
+		os.Exit(1)
+	}
+
+	if err := run(ctx, cfg, os.Args[1]); err != nil {
+		if errors.Is(err, errDrift) {
+			os.Exit(2)
+		}
+		slog.Error("topology command failed", slog.Any("error", err))
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, cfg *config.Config, cmd string) error {
+	desired := internal.Topology()
+	if err := desired.Validate(); err != nil {
+		return fmt.Errorf("invalid topology: %w", err)
+	}
+
+	switch cmd {
+	case "print":
+		return yaml.NewEncoder(os.Stdout).Encode(desired)
+	case "diff":
+		return diff(ctx, cfg.AMQP, desired)
+	case "apply":
+		return apply(cfg.AMQP, desired)
+	default:
+		printUsage()
+		return fmt.Errorf("unknown command: %s", cmd)
+	}
+}
+
+func diff(ctx context.Context, cfg pkgamqp.AMQPConfig, desired pkgamqp.Topology) error {
+	mgmtURL, vhost, err := cfg.ManagementEndpoint()
+	if err != nil {
+		return err
+	}
+
+	live, err := pkgamqp.FetchTopology(ctx, http.DefaultClient, mgmtURL, vhost)
+	if err != nil {
+		return err
+	}
+
+	changes := pkgamqp.DiffTopology(desired, live)
+	if len(changes) == 0 {
+		fmt.Println("no changes")
+		return nil
+	}
+	for _, c := range changes {
+		fmt.Println(c)
+	}
+	return errDrift
+}
+
+func apply(cfg pkgamqp.AMQPConfig, desired pkgamqp.Topology) error {
+	conn := pkgamqp.Setup(cfg, slog.Default())
+	if conn == nil {
+		return errors.New("amqp connection is required (check amqp config, standalone must be false)")
+	}
+	defer func() { _ = conn.Close() }()
+
+	broker := pkgamqp.NewBroker(conn, cfg.Pool)
+	defer broker.Shutdown()
+
+	if err := broker.Declare(desired); err != nil {
+		return err
+	}
+	fmt.Println("topology applied")
+	return nil
+}
+
+func printUsage() {
+	fmt.Println("Usage: topology <command>")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  print   Print the desired topology as YAML")
+	fmt.Println("  diff    Compare the desired topology with the live broker (management API); exits 2 on drift")
+	fmt.Println("  apply   Declare the desired topology on the broker")
+}
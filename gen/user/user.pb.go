@@ -9,7 +9,9 @@ package user
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
+	_ "starter-boilerplate/gen/auth"
 	sync "sync"
 	unsafe "unsafe"
 )
@@ -21,28 +23,832 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type User struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	Role          string                 `protobuf:"bytes,3,opt,name=role,proto3" json:"role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *User) Reset() {
+	*x = User{}
+	mi := &file_user_user_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *User) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*User) ProtoMessage() {}
+
+func (x *User) ProtoReflect() protoreflect.Message {
+	mi := &file_user_user_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use User.ProtoReflect.Descriptor instead.
+func (*User) Descriptor() ([]byte, []int) {
+	return file_user_user_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *User) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *User) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *User) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+type TokenPair struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccessToken   string                 `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	RefreshToken  string                 `protobuf:"bytes,2,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TokenPair) Reset() {
+	*x = TokenPair{}
+	mi := &file_user_user_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TokenPair) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TokenPair) ProtoMessage() {}
+
+func (x *TokenPair) ProtoReflect() protoreflect.Message {
+	mi := &file_user_user_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TokenPair.ProtoReflect.Descriptor instead.
+func (*TokenPair) Descriptor() ([]byte, []int) {
+	return file_user_user_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TokenPair) GetAccessToken() string {
+	if x != nil {
+		return x.AccessToken
+	}
+	return ""
+}
+
+func (x *TokenPair) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+type LoginRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoginRequest) Reset() {
+	*x = LoginRequest{}
+	mi := &file_user_user_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoginRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoginRequest) ProtoMessage() {}
+
+func (x *LoginRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_user_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoginRequest.ProtoReflect.Descriptor instead.
+func (*LoginRequest) Descriptor() ([]byte, []int) {
+	return file_user_user_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *LoginRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *LoginRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type RegisterRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterRequest) Reset() {
+	*x = RegisterRequest{}
+	mi := &file_user_user_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterRequest) ProtoMessage() {}
+
+func (x *RegisterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_user_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterRequest.ProtoReflect.Descriptor instead.
+func (*RegisterRequest) Descriptor() ([]byte, []int) {
+	return file_user_user_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *RegisterRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *RegisterRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type RefreshRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RefreshToken  string                 `protobuf:"bytes,1,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefreshRequest) Reset() {
+	*x = RefreshRequest{}
+	mi := &file_user_user_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefreshRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefreshRequest) ProtoMessage() {}
+
+func (x *RefreshRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_user_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefreshRequest.ProtoReflect.Descriptor instead.
+func (*RefreshRequest) Descriptor() ([]byte, []int) {
+	return file_user_user_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RefreshRequest) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+type ChangePasswordRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OldPassword   string                 `protobuf:"bytes,1,opt,name=old_password,json=oldPassword,proto3" json:"old_password,omitempty"`
+	NewPassword   string                 `protobuf:"bytes,2,opt,name=new_password,json=newPassword,proto3" json:"new_password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangePasswordRequest) Reset() {
+	*x = ChangePasswordRequest{}
+	mi := &file_user_user_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangePasswordRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangePasswordRequest) ProtoMessage() {}
+
+func (x *ChangePasswordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_user_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangePasswordRequest.ProtoReflect.Descriptor instead.
+func (*ChangePasswordRequest) Descriptor() ([]byte, []int) {
+	return file_user_user_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ChangePasswordRequest) GetOldPassword() string {
+	if x != nil {
+		return x.OldPassword
+	}
+	return ""
+}
+
+func (x *ChangePasswordRequest) GetNewPassword() string {
+	if x != nil {
+		return x.NewPassword
+	}
+	return ""
+}
+
+type ChangePasswordResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangePasswordResponse) Reset() {
+	*x = ChangePasswordResponse{}
+	mi := &file_user_user_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangePasswordResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangePasswordResponse) ProtoMessage() {}
+
+func (x *ChangePasswordResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_user_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangePasswordResponse.ProtoReflect.Descriptor instead.
+func (*ChangePasswordResponse) Descriptor() ([]byte, []int) {
+	return file_user_user_proto_rawDescGZIP(), []int{6}
+}
+
 type GetUserRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserRequest) Reset() {
+	*x = GetUserRequest{}
+	mi := &file_user_user_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserRequest) ProtoMessage() {}
+
+func (x *GetUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_user_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserRequest.ProtoReflect.Descriptor instead.
+func (*GetUserRequest) Descriptor() ([]byte, []int) {
+	return file_user_user_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetUserRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	Role          string                 `protobuf:"bytes,3,opt,name=role,proto3" json:"role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserResponse) Reset() {
+	*x = GetUserResponse{}
+	mi := &file_user_user_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserResponse) ProtoMessage() {}
+
+func (x *GetUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_user_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserResponse.ProtoReflect.Descriptor instead.
+func (*GetUserResponse) Descriptor() ([]byte, []int) {
+	return file_user_user_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetUserResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetUserResponse) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *GetUserResponse) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+type ListUsersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersRequest) Reset() {
+	*x = ListUsersRequest{}
+	mi := &file_user_user_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersRequest) ProtoMessage() {}
+
+func (x *ListUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_user_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersRequest.ProtoReflect.Descriptor instead.
+func (*ListUsersRequest) Descriptor() ([]byte, []int) {
+	return file_user_user_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListUsersRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListUsersRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type ListUsersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*User                `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	Total         int64                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersResponse) Reset() {
+	*x = ListUsersResponse{}
+	mi := &file_user_user_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersResponse) ProtoMessage() {}
+
+func (x *ListUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_user_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersResponse.ProtoReflect.Descriptor instead.
+func (*ListUsersResponse) Descriptor() ([]byte, []int) {
+	return file_user_user_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListUsersResponse) GetUsers() []*User {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *ListUsersResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+// Profile values by kind; keys of nested values are dotted paths.
+type Profile struct {
+	state         protoimpl.MessageState            `protogen:"open.v1"`
+	UserId        string                            `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Numbers       map[string]float64                `protobuf:"bytes,2,rep,name=numbers,proto3" json:"numbers,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
+	Strings       map[string]string                 `protobuf:"bytes,3,rep,name=strings,proto3" json:"strings,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Bools         map[string]bool                   `protobuf:"bytes,4,rep,name=bools,proto3" json:"bools,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	Times         map[string]*timestamppb.Timestamp `protobuf:"bytes,5,rep,name=times,proto3" json:"times,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Tags          map[string]*StringList            `protobuf:"bytes,6,rep,name=tags,proto3" json:"tags,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Profile) Reset() {
+	*x = Profile{}
+	mi := &file_user_user_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Profile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Profile) ProtoMessage() {}
+
+func (x *Profile) ProtoReflect() protoreflect.Message {
+	mi := &file_user_user_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Profile.ProtoReflect.Descriptor instead.
+func (*Profile) Descriptor() ([]byte, []int) {
+	return file_user_user_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Profile) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Profile) GetNumbers() map[string]float64 {
+	if x != nil {
+		return x.Numbers
+	}
+	return nil
+}
+
+func (x *Profile) GetStrings() map[string]string {
+	if x != nil {
+		return x.Strings
+	}
+	return nil
+}
+
+func (x *Profile) GetBools() map[string]bool {
+	if x != nil {
+		return x.Bools
+	}
+	return nil
+}
+
+func (x *Profile) GetTimes() map[string]*timestamppb.Timestamp {
+	if x != nil {
+		return x.Times
+	}
+	return nil
+}
+
+func (x *Profile) GetTags() map[string]*StringList {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type StringList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Values        []string               `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StringList) Reset() {
+	*x = StringList{}
+	mi := &file_user_user_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StringList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StringList) ProtoMessage() {}
+
+func (x *StringList) ProtoReflect() protoreflect.Message {
+	mi := &file_user_user_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StringList.ProtoReflect.Descriptor instead.
+func (*StringList) Descriptor() ([]byte, []int) {
+	return file_user_user_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *StringList) GetValues() []string {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type ProfileValue struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Kind:
+	//
+	//	*ProfileValue_Number
+	//	*ProfileValue_String_
+	//	*ProfileValue_Bool
+	//	*ProfileValue_Time
+	Kind          isProfileValue_Kind `protobuf_oneof:"kind"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProfileValue) Reset() {
+	*x = ProfileValue{}
+	mi := &file_user_user_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProfileValue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProfileValue) ProtoMessage() {}
+
+func (x *ProfileValue) ProtoReflect() protoreflect.Message {
+	mi := &file_user_user_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProfileValue.ProtoReflect.Descriptor instead.
+func (*ProfileValue) Descriptor() ([]byte, []int) {
+	return file_user_user_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ProfileValue) GetKind() isProfileValue_Kind {
+	if x != nil {
+		return x.Kind
+	}
+	return nil
+}
+
+func (x *ProfileValue) GetNumber() float64 {
+	if x != nil {
+		if x, ok := x.Kind.(*ProfileValue_Number); ok {
+			return x.Number
+		}
+	}
+	return 0
+}
+
+func (x *ProfileValue) GetString_() string {
+	if x != nil {
+		if x, ok := x.Kind.(*ProfileValue_String_); ok {
+			return x.String_
+		}
+	}
+	return ""
+}
+
+func (x *ProfileValue) GetBool() bool {
+	if x != nil {
+		if x, ok := x.Kind.(*ProfileValue_Bool); ok {
+			return x.Bool
+		}
+	}
+	return false
+}
+
+func (x *ProfileValue) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		if x, ok := x.Kind.(*ProfileValue_Time); ok {
+			return x.Time
+		}
+	}
+	return nil
+}
+
+type isProfileValue_Kind interface {
+	isProfileValue_Kind()
+}
+
+type ProfileValue_Number struct {
+	Number float64 `protobuf:"fixed64,1,opt,name=number,proto3,oneof"`
+}
+
+type ProfileValue_String_ struct {
+	String_ string `protobuf:"bytes,2,opt,name=string,proto3,oneof"`
+}
+
+type ProfileValue_Bool struct {
+	Bool bool `protobuf:"varint,3,opt,name=bool,proto3,oneof"`
+}
+
+type ProfileValue_Time struct {
+	Time *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=time,proto3,oneof"`
+}
+
+func (*ProfileValue_Number) isProfileValue_Kind() {}
+
+func (*ProfileValue_String_) isProfileValue_Kind() {}
+
+func (*ProfileValue_Bool) isProfileValue_Kind() {}
+
+func (*ProfileValue_Time) isProfileValue_Kind() {}
+
+// CompareAndSet sets key to value if it holds expected; otherwise nothing in
+// the request is applied and the RPC fails like HTTP 409, with the
+// profile_condition_failed reason.
+type CompareAndSet struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Expected      *ProfileValue          `protobuf:"bytes,2,opt,name=expected,proto3" json:"expected,omitempty"`
+	Value         *ProfileValue          `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetUserRequest) Reset() {
-	*x = GetUserRequest{}
-	mi := &file_user_user_proto_msgTypes[0]
+func (x *CompareAndSet) Reset() {
+	*x = CompareAndSet{}
+	mi := &file_user_user_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetUserRequest) String() string {
+func (x *CompareAndSet) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetUserRequest) ProtoMessage() {}
+func (*CompareAndSet) ProtoMessage() {}
 
-func (x *GetUserRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_user_proto_msgTypes[0]
+func (x *CompareAndSet) ProtoReflect() protoreflect.Message {
+	mi := &file_user_user_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -53,42 +859,54 @@ func (x *GetUserRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetUserRequest.ProtoReflect.Descriptor instead.
-func (*GetUserRequest) Descriptor() ([]byte, []int) {
-	return file_user_user_proto_rawDescGZIP(), []int{0}
+// Deprecated: Use CompareAndSet.ProtoReflect.Descriptor instead.
+func (*CompareAndSet) Descriptor() ([]byte, []int) {
+	return file_user_user_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *GetUserRequest) GetId() string {
+func (x *CompareAndSet) GetKey() string {
 	if x != nil {
-		return x.Id
+		return x.Key
 	}
 	return ""
 }
 
-type GetUserResponse struct {
+func (x *CompareAndSet) GetExpected() *ProfileValue {
+	if x != nil {
+		return x.Expected
+	}
+	return nil
+}
+
+func (x *CompareAndSet) GetValue() *ProfileValue {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+type GetProfileRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
-	Role          string                 `protobuf:"bytes,3,opt,name=role,proto3" json:"role,omitempty"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetUserResponse) Reset() {
-	*x = GetUserResponse{}
-	mi := &file_user_user_proto_msgTypes[1]
+func (x *GetProfileRequest) Reset() {
+	*x = GetProfileRequest{}
+	mi := &file_user_user_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetUserResponse) String() string {
+func (x *GetProfileRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetUserResponse) ProtoMessage() {}
+func (*GetProfileRequest) ProtoMessage() {}
 
-func (x *GetUserResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_user_proto_msgTypes[1]
+func (x *GetProfileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_user_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -99,45 +917,434 @@ func (x *GetUserResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetUserResponse.ProtoReflect.Descriptor instead.
-func (*GetUserResponse) Descriptor() ([]byte, []int) {
-	return file_user_user_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use GetProfileRequest.ProtoReflect.Descriptor instead.
+func (*GetProfileRequest) Descriptor() ([]byte, []int) {
+	return file_user_user_proto_rawDescGZIP(), []int{15}
 }
 
-func (x *GetUserResponse) GetId() string {
+func (x *GetProfileRequest) GetUserId() string {
 	if x != nil {
-		return x.Id
+		return x.UserId
 	}
 	return ""
 }
 
-func (x *GetUserResponse) GetEmail() string {
+// UpdateProfileRequest applies all operations atomically, one per key.
+// max_numbers and min_numbers only move a number up or down; set_if_absent
+// leaves keys with a value alone; delete_keys removes keys of any kind.
+type UpdateProfileRequest struct {
+	state         protoimpl.MessageState            `protogen:"open.v1"`
+	UserId        string                            `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	SetNumbers    map[string]float64                `protobuf:"bytes,2,rep,name=set_numbers,json=setNumbers,proto3" json:"set_numbers,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
+	IncrNumbers   map[string]float64                `protobuf:"bytes,3,rep,name=incr_numbers,json=incrNumbers,proto3" json:"incr_numbers,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
+	SetStrings    map[string]string                 `protobuf:"bytes,4,rep,name=set_strings,json=setStrings,proto3" json:"set_strings,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	MaxNumbers    map[string]float64                `protobuf:"bytes,5,rep,name=max_numbers,json=maxNumbers,proto3" json:"max_numbers,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
+	MinNumbers    map[string]float64                `protobuf:"bytes,6,rep,name=min_numbers,json=minNumbers,proto3" json:"min_numbers,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
+	SetBools      map[string]bool                   `protobuf:"bytes,7,rep,name=set_bools,json=setBools,proto3" json:"set_bools,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	SetTimes      map[string]*timestamppb.Timestamp `protobuf:"bytes,8,rep,name=set_times,json=setTimes,proto3" json:"set_times,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	AddTags       map[string]*StringList            `protobuf:"bytes,9,rep,name=add_tags,json=addTags,proto3" json:"add_tags,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	RemoveTags    map[string]*StringList            `protobuf:"bytes,10,rep,name=remove_tags,json=removeTags,proto3" json:"remove_tags,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	SetIfAbsent   map[string]*ProfileValue          `protobuf:"bytes,11,rep,name=set_if_absent,json=setIfAbsent,proto3" json:"set_if_absent,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	DeleteKeys    []string                          `protobuf:"bytes,12,rep,name=delete_keys,json=deleteKeys,proto3" json:"delete_keys,omitempty"`
+	CompareAndSet []*CompareAndSet                  `protobuf:"bytes,13,rep,name=compare_and_set,json=compareAndSet,proto3" json:"compare_and_set,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateProfileRequest) Reset() {
+	*x = UpdateProfileRequest{}
+	mi := &file_user_user_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateProfileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateProfileRequest) ProtoMessage() {}
+
+func (x *UpdateProfileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_user_proto_msgTypes[16]
 	if x != nil {
-		return x.Email
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateProfileRequest.ProtoReflect.Descriptor instead.
+func (*UpdateProfileRequest) Descriptor() ([]byte, []int) {
+	return file_user_user_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *UpdateProfileRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
 	}
 	return ""
 }
 
-func (x *GetUserResponse) GetRole() string {
+func (x *UpdateProfileRequest) GetSetNumbers() map[string]float64 {
 	if x != nil {
-		return x.Role
+		return x.SetNumbers
+	}
+	return nil
+}
+
+func (x *UpdateProfileRequest) GetIncrNumbers() map[string]float64 {
+	if x != nil {
+		return x.IncrNumbers
+	}
+	return nil
+}
+
+func (x *UpdateProfileRequest) GetSetStrings() map[string]string {
+	if x != nil {
+		return x.SetStrings
+	}
+	return nil
+}
+
+func (x *UpdateProfileRequest) GetMaxNumbers() map[string]float64 {
+	if x != nil {
+		return x.MaxNumbers
+	}
+	return nil
+}
+
+func (x *UpdateProfileRequest) GetMinNumbers() map[string]float64 {
+	if x != nil {
+		return x.MinNumbers
+	}
+	return nil
+}
+
+func (x *UpdateProfileRequest) GetSetBools() map[string]bool {
+	if x != nil {
+		return x.SetBools
+	}
+	return nil
+}
+
+func (x *UpdateProfileRequest) GetSetTimes() map[string]*timestamppb.Timestamp {
+	if x != nil {
+		return x.SetTimes
+	}
+	return nil
+}
+
+func (x *UpdateProfileRequest) GetAddTags() map[string]*StringList {
+	if x != nil {
+		return x.AddTags
+	}
+	return nil
+}
+
+func (x *UpdateProfileRequest) GetRemoveTags() map[string]*StringList {
+	if x != nil {
+		return x.RemoveTags
+	}
+	return nil
+}
+
+func (x *UpdateProfileRequest) GetSetIfAbsent() map[string]*ProfileValue {
+	if x != nil {
+		return x.SetIfAbsent
+	}
+	return nil
+}
+
+func (x *UpdateProfileRequest) GetDeleteKeys() []string {
+	if x != nil {
+		return x.DeleteKeys
+	}
+	return nil
+}
+
+func (x *UpdateProfileRequest) GetCompareAndSet() []*CompareAndSet {
+	if x != nil {
+		return x.CompareAndSet
+	}
+	return nil
+}
+
+// WatchUserEventsRequest selects the events of user_id, or of every user when
+// empty (admin only). When after_position is set, published events after it
+// are replayed before live ones, so a client resumes without gaps; 0 replays
+// everything still in the outbox. Without it, only live events are streamed.
+type WatchUserEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	AfterPosition *int64                 `protobuf:"varint,2,opt,name=after_position,json=afterPosition,proto3,oneof" json:"after_position,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchUserEventsRequest) Reset() {
+	*x = WatchUserEventsRequest{}
+	mi := &file_user_user_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchUserEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchUserEventsRequest) ProtoMessage() {}
+
+func (x *WatchUserEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_user_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchUserEventsRequest.ProtoReflect.Descriptor instead.
+func (*WatchUserEventsRequest) Descriptor() ([]byte, []int) {
+	return file_user_user_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *WatchUserEventsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *WatchUserEventsRequest) GetAfterPosition() int64 {
+	if x != nil && x.AfterPosition != nil {
+		return *x.AfterPosition
+	}
+	return 0
+}
+
+// UserEvent.position is the outbox entry ID: pass the last one received as
+// after_position to resume. payload is the JSON-encoded event.
+type UserEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Position      int64                  `protobuf:"varint,1,opt,name=position,proto3" json:"position,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Payload       []byte                 `protobuf:"bytes,4,opt,name=payload,proto3" json:"payload,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserEvent) Reset() {
+	*x = UserEvent{}
+	mi := &file_user_user_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserEvent) ProtoMessage() {}
+
+func (x *UserEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_user_user_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserEvent.ProtoReflect.Descriptor instead.
+func (*UserEvent) Descriptor() ([]byte, []int) {
+	return file_user_user_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *UserEvent) GetPosition() int64 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *UserEvent) GetName() string {
+	if x != nil {
+		return x.Name
 	}
 	return ""
 }
 
+func (x *UserEvent) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UserEvent) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
 var File_user_user_proto protoreflect.FileDescriptor
 
 const file_user_user_proto_rawDesc = "" +
 	"\n" +
-	"\x0fuser/user.proto\x12\x04user\" \n" +
+	"\x0fuser/user.proto\x12\x04user\x1a\x0fauth/auth.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"@\n" +
+	"\x04User\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x12\n" +
+	"\x04role\x18\x03 \x01(\tR\x04role\"S\n" +
+	"\tTokenPair\x12!\n" +
+	"\faccess_token\x18\x01 \x01(\tR\vaccessToken\x12#\n" +
+	"\rrefresh_token\x18\x02 \x01(\tR\frefreshToken\"@\n" +
+	"\fLoginRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\"C\n" +
+	"\x0fRegisterRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\"5\n" +
+	"\x0eRefreshRequest\x12#\n" +
+	"\rrefresh_token\x18\x01 \x01(\tR\frefreshToken\"]\n" +
+	"\x15ChangePasswordRequest\x12!\n" +
+	"\fold_password\x18\x01 \x01(\tR\voldPassword\x12!\n" +
+	"\fnew_password\x18\x02 \x01(\tR\vnewPassword\"\x18\n" +
+	"\x16ChangePasswordResponse\" \n" +
 	"\x0eGetUserRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\"K\n" +
 	"\x0fGetUserResponse\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
 	"\x05email\x18\x02 \x01(\tR\x05email\x12\x12\n" +
-	"\x04role\x18\x03 \x01(\tR\x04role2F\n" +
-	"\fUserContract\x126\n" +
-	"\aGetUser\x12\x14.user.GetUserRequest\x1a\x15.user.GetUserResponseB#Z!starter-boilerplate/gen/user;userb\x06proto3"
+	"\x04role\x18\x03 \x01(\tR\x04role\"@\n" +
+	"\x10ListUsersRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x05R\x06offset\"K\n" +
+	"\x11ListUsersResponse\x12 \n" +
+	"\x05users\x18\x01 \x03(\v2\n" +
+	".user.UserR\x05users\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x03R\x05total\"\xee\x04\n" +
+	"\aProfile\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x124\n" +
+	"\anumbers\x18\x02 \x03(\v2\x1a.user.Profile.NumbersEntryR\anumbers\x124\n" +
+	"\astrings\x18\x03 \x03(\v2\x1a.user.Profile.StringsEntryR\astrings\x12.\n" +
+	"\x05bools\x18\x04 \x03(\v2\x18.user.Profile.BoolsEntryR\x05bools\x12.\n" +
+	"\x05times\x18\x05 \x03(\v2\x18.user.Profile.TimesEntryR\x05times\x12+\n" +
+	"\x04tags\x18\x06 \x03(\v2\x17.user.Profile.TagsEntryR\x04tags\x1a:\n" +
+	"\fNumbersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\x1a:\n" +
+	"\fStringsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a8\n" +
+	"\n" +
+	"BoolsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\bR\x05value:\x028\x01\x1aT\n" +
+	"\n" +
+	"TimesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x120\n" +
+	"\x05value\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x05value:\x028\x01\x1aI\n" +
+	"\tTagsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12&\n" +
+	"\x05value\x18\x02 \x01(\v2\x10.user.StringListR\x05value:\x028\x01\"$\n" +
+	"\n" +
+	"StringList\x12\x16\n" +
+	"\x06values\x18\x01 \x03(\tR\x06values\"\x92\x01\n" +
+	"\fProfileValue\x12\x18\n" +
+	"\x06number\x18\x01 \x01(\x01H\x00R\x06number\x12\x18\n" +
+	"\x06string\x18\x02 \x01(\tH\x00R\x06string\x12\x14\n" +
+	"\x04bool\x18\x03 \x01(\bH\x00R\x04bool\x120\n" +
+	"\x04time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampH\x00R\x04timeB\x06\n" +
+	"\x04kind\"{\n" +
+	"\rCompareAndSet\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12.\n" +
+	"\bexpected\x18\x02 \x01(\v2\x12.user.ProfileValueR\bexpected\x12(\n" +
+	"\x05value\x18\x03 \x01(\v2\x12.user.ProfileValueR\x05value\",\n" +
+	"\x11GetProfileRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"\xc6\f\n" +
+	"\x14UpdateProfileRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12K\n" +
+	"\vset_numbers\x18\x02 \x03(\v2*.user.UpdateProfileRequest.SetNumbersEntryR\n" +
+	"setNumbers\x12N\n" +
+	"\fincr_numbers\x18\x03 \x03(\v2+.user.UpdateProfileRequest.IncrNumbersEntryR\vincrNumbers\x12K\n" +
+	"\vset_strings\x18\x04 \x03(\v2*.user.UpdateProfileRequest.SetStringsEntryR\n" +
+	"setStrings\x12K\n" +
+	"\vmax_numbers\x18\x05 \x03(\v2*.user.UpdateProfileRequest.MaxNumbersEntryR\n" +
+	"maxNumbers\x12K\n" +
+	"\vmin_numbers\x18\x06 \x03(\v2*.user.UpdateProfileRequest.MinNumbersEntryR\n" +
+	"minNumbers\x12E\n" +
+	"\tset_bools\x18\a \x03(\v2(.user.UpdateProfileRequest.SetBoolsEntryR\bsetBools\x12E\n" +
+	"\tset_times\x18\b \x03(\v2(.user.UpdateProfileRequest.SetTimesEntryR\bsetTimes\x12B\n" +
+	"\badd_tags\x18\t \x03(\v2'.user.UpdateProfileRequest.AddTagsEntryR\aaddTags\x12K\n" +
+	"\vremove_tags\x18\n" +
+	" \x03(\v2*.user.UpdateProfileRequest.RemoveTagsEntryR\n" +
+	"removeTags\x12O\n" +
+	"\rset_if_absent\x18\v \x03(\v2+.user.UpdateProfileRequest.SetIfAbsentEntryR\vsetIfAbsent\x12\x1f\n" +
+	"\vdelete_keys\x18\f \x03(\tR\n" +
+	"deleteKeys\x12;\n" +
+	"\x0fcompare_and_set\x18\r \x03(\v2\x13.user.CompareAndSetR\rcompareAndSet\x1a=\n" +
+	"\x0fSetNumbersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\x1a>\n" +
+	"\x10IncrNumbersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\x1a=\n" +
+	"\x0fSetStringsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a=\n" +
+	"\x0fMaxNumbersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\x1a=\n" +
+	"\x0fMinNumbersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\x1a;\n" +
+	"\rSetBoolsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\bR\x05value:\x028\x01\x1aW\n" +
+	"\rSetTimesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x120\n" +
+	"\x05value\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x05value:\x028\x01\x1aL\n" +
+	"\fAddTagsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12&\n" +
+	"\x05value\x18\x02 \x01(\v2\x10.user.StringListR\x05value:\x028\x01\x1aO\n" +
+	"\x0fRemoveTagsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12&\n" +
+	"\x05value\x18\x02 \x01(\v2\x10.user.StringListR\x05value:\x028\x01\x1aR\n" +
+	"\x10SetIfAbsentEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12(\n" +
+	"\x05value\x18\x02 \x01(\v2\x12.user.ProfileValueR\x05value:\x028\x01\"p\n" +
+	"\x16WatchUserEventsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12*\n" +
+	"\x0eafter_position\x18\x02 \x01(\x03H\x00R\rafterPosition\x88\x01\x01B\x11\n" +
+	"\x0f_after_position\"n\n" +
+	"\tUserEvent\x12\x1a\n" +
+	"\bposition\x18\x01 \x01(\x03R\bposition\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x18\n" +
+	"\apayload\x18\x04 \x01(\fR\apayload2\xc0\x04\n" +
+	"\fUserContract\x124\n" +
+	"\x05Login\x12\x12.user.LoginRequest\x1a\x0f.user.TokenPair\"\x06\x8a\xb5\x18\x02\b\x01\x12:\n" +
+	"\bRegister\x12\x15.user.RegisterRequest\x1a\x0f.user.TokenPair\"\x06\x8a\xb5\x18\x02\b\x01\x128\n" +
+	"\aRefresh\x12\x14.user.RefreshRequest\x1a\x0f.user.TokenPair\"\x06\x8a\xb5\x18\x02\b\x01\x12K\n" +
+	"\x0eChangePassword\x12\x1b.user.ChangePasswordRequest\x1a\x1c.user.ChangePasswordResponse\x126\n" +
+	"\aGetUser\x12\x14.user.GetUserRequest\x1a\x15.user.GetUserResponse\x12I\n" +
+	"\tListUsers\x12\x16.user.ListUsersRequest\x1a\x17.user.ListUsersResponse\"\v\x8a\xb5\x18\a\x12\x05admin\x124\n" +
+	"\n" +
+	"GetProfile\x12\x17.user.GetProfileRequest\x1a\r.user.Profile\x12:\n" +
+	"\rUpdateProfile\x12\x1a.user.UpdateProfileRequest\x1a\r.user.Profile\x12B\n" +
+	"\x0fWatchUserEvents\x12\x1c.user.WatchUserEventsRequest\x1a\x0f.user.UserEvent0\x01B#Z!starter-boilerplate/gen/user;userb\x06proto3"
 
 var (
 	file_user_user_proto_rawDescOnce sync.Once
@@ -151,19 +1358,94 @@ func file_user_user_proto_rawDescGZIP() []byte {
 	return file_user_user_proto_rawDescData
 }
 
-var file_user_user_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_user_user_proto_msgTypes = make([]protoimpl.MessageInfo, 34)
 var file_user_user_proto_goTypes = []any{
-	(*GetUserRequest)(nil),  // 0: user.GetUserRequest
-	(*GetUserResponse)(nil), // 1: user.GetUserResponse
+	(*User)(nil),                   // 0: user.User
+	(*TokenPair)(nil),              // 1: user.TokenPair
+	(*LoginRequest)(nil),           // 2: user.LoginRequest
+	(*RegisterRequest)(nil),        // 3: user.RegisterRequest
+	(*RefreshRequest)(nil),         // 4: user.RefreshRequest
+	(*ChangePasswordRequest)(nil),  // 5: user.ChangePasswordRequest
+	(*ChangePasswordResponse)(nil), // 6: user.ChangePasswordResponse
+	(*GetUserRequest)(nil),         // 7: user.GetUserRequest
+	(*GetUserResponse)(nil),        // 8: user.GetUserResponse
+	(*ListUsersRequest)(nil),       // 9: user.ListUsersRequest
+	(*ListUsersResponse)(nil),      // 10: user.ListUsersResponse
+	(*Profile)(nil),                // 11: user.Profile
+	(*StringList)(nil),             // 12: user.StringList
+	(*ProfileValue)(nil),           // 13: user.ProfileValue
+	(*CompareAndSet)(nil),          // 14: user.CompareAndSet
+	(*GetProfileRequest)(nil),      // 15: user.GetProfileRequest
+	(*UpdateProfileRequest)(nil),   // 16: user.UpdateProfileRequest
+	(*WatchUserEventsRequest)(nil), // 17: user.WatchUserEventsRequest
+	(*UserEvent)(nil),              // 18: user.UserEvent
+	nil,                            // 19: user.Profile.NumbersEntry
+	nil,                            // 20: user.Profile.StringsEntry
+	nil,                            // 21: user.Profile.BoolsEntry
+	nil,                            // 22: user.Profile.TimesEntry
+	nil,                            // 23: user.Profile.TagsEntry
+	nil,                            // 24: user.UpdateProfileRequest.SetNumbersEntry
+	nil,                            // 25: user.UpdateProfileRequest.IncrNumbersEntry
+	nil,                            // 26: user.UpdateProfileRequest.SetStringsEntry
+	nil,                            // 27: user.UpdateProfileRequest.MaxNumbersEntry
+	nil,                            // 28: user.UpdateProfileRequest.MinNumbersEntry
+	nil,                            // 29: user.UpdateProfileRequest.SetBoolsEntry
+	nil,                            // 30: user.UpdateProfileRequest.SetTimesEntry
+	nil,                            // 31: user.UpdateProfileRequest.AddTagsEntry
+	nil,                            // 32: user.UpdateProfileRequest.RemoveTagsEntry
+	nil,                            // 33: user.UpdateProfileRequest.SetIfAbsentEntry
+	(*timestamppb.Timestamp)(nil),  // 34: google.protobuf.Timestamp
 }
 var file_user_user_proto_depIdxs = []int32{
-	0, // 0: user.UserContract.GetUser:input_type -> user.GetUserRequest
-	1, // 1: user.UserContract.GetUser:output_type -> user.GetUserResponse
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0,  // 0: user.ListUsersResponse.users:type_name -> user.User
+	19, // 1: user.Profile.numbers:type_name -> user.Profile.NumbersEntry
+	20, // 2: user.Profile.strings:type_name -> user.Profile.StringsEntry
+	21, // 3: user.Profile.bools:type_name -> user.Profile.BoolsEntry
+	22, // 4: user.Profile.times:type_name -> user.Profile.TimesEntry
+	23, // 5: user.Profile.tags:type_name -> user.Profile.TagsEntry
+	34, // 6: user.ProfileValue.time:type_name -> google.protobuf.Timestamp
+	13, // 7: user.CompareAndSet.expected:type_name -> user.ProfileValue
+	13, // 8: user.CompareAndSet.value:type_name -> user.ProfileValue
+	24, // 9: user.UpdateProfileRequest.set_numbers:type_name -> user.UpdateProfileRequest.SetNumbersEntry
+	25, // 10: user.UpdateProfileRequest.incr_numbers:type_name -> user.UpdateProfileRequest.IncrNumbersEntry
+	26, // 11: user.UpdateProfileRequest.set_strings:type_name -> user.UpdateProfileRequest.SetStringsEntry
+	27, // 12: user.UpdateProfileRequest.max_numbers:type_name -> user.UpdateProfileRequest.MaxNumbersEntry
+	28, // 13: user.UpdateProfileRequest.min_numbers:type_name -> user.UpdateProfileRequest.MinNumbersEntry
+	29, // 14: user.UpdateProfileRequest.set_bools:type_name -> user.UpdateProfileRequest.SetBoolsEntry
+	30, // 15: user.UpdateProfileRequest.set_times:type_name -> user.UpdateProfileRequest.SetTimesEntry
+	31, // 16: user.UpdateProfileRequest.add_tags:type_name -> user.UpdateProfileRequest.AddTagsEntry
+	32, // 17: user.UpdateProfileRequest.remove_tags:type_name -> user.UpdateProfileRequest.RemoveTagsEntry
+	33, // 18: user.UpdateProfileRequest.set_if_absent:type_name -> user.UpdateProfileRequest.SetIfAbsentEntry
+	14, // 19: user.UpdateProfileRequest.compare_and_set:type_name -> user.CompareAndSet
+	34, // 20: user.Profile.TimesEntry.value:type_name -> google.protobuf.Timestamp
+	12, // 21: user.Profile.TagsEntry.value:type_name -> user.StringList
+	34, // 22: user.UpdateProfileRequest.SetTimesEntry.value:type_name -> google.protobuf.Timestamp
+	12, // 23: user.UpdateProfileRequest.AddTagsEntry.value:type_name -> user.StringList
+	12, // 24: user.UpdateProfileRequest.RemoveTagsEntry.value:type_name -> user.StringList
+	13, // 25: user.UpdateProfileRequest.SetIfAbsentEntry.value:type_name -> user.ProfileValue
+	2,  // 26: user.UserContract.Login:input_type -> user.LoginRequest
+	3,  // 27: user.UserContract.Register:input_type -> user.RegisterRequest
+	4,  // 28: user.UserContract.Refresh:input_type -> user.RefreshRequest
+	5,  // 29: user.UserContract.ChangePassword:input_type -> user.ChangePasswordRequest
+	7,  // 30: user.UserContract.GetUser:input_type -> user.GetUserRequest
+	9,  // 31: user.UserContract.ListUsers:input_type -> user.ListUsersRequest
+	15, // 32: user.UserContract.GetProfile:input_type -> user.GetProfileRequest
+	16, // 33: user.UserContract.UpdateProfile:input_type -> user.UpdateProfileRequest
+	17, // 34: user.UserContract.WatchUserEvents:input_type -> user.WatchUserEventsRequest
+	1,  // 35: user.UserContract.Login:output_type -> user.TokenPair
+	1,  // 36: user.UserContract.Register:output_type -> user.TokenPair
+	1,  // 37: user.UserContract.Refresh:output_type -> user.TokenPair
+	6,  // 38: user.UserContract.ChangePassword:output_type -> user.ChangePasswordResponse
+	8,  // 39: user.UserContract.GetUser:output_type -> user.GetUserResponse
+	10, // 40: user.UserContract.ListUsers:output_type -> user.ListUsersResponse
+	11, // 41: user.UserContract.GetProfile:output_type -> user.Profile
+	11, // 42: user.UserContract.UpdateProfile:output_type -> user.Profile
+	18, // 43: user.UserContract.WatchUserEvents:output_type -> user.UserEvent
+	35, // [35:44] is the sub-list for method output_type
+	26, // [26:35] is the sub-list for method input_type
+	26, // [26:26] is the sub-list for extension type_name
+	26, // [26:26] is the sub-list for extension extendee
+	0,  // [0:26] is the sub-list for field type_name
 }
 
 func init() { file_user_user_proto_init() }
@@ -171,13 +1453,20 @@ func file_user_user_proto_init() {
 	if File_user_user_proto != nil {
 		return
 	}
+	file_user_user_proto_msgTypes[13].OneofWrappers = []any{
+		(*ProfileValue_Number)(nil),
+		(*ProfileValue_String_)(nil),
+		(*ProfileValue_Bool)(nil),
+		(*ProfileValue_Time)(nil),
+	}
+	file_user_user_proto_msgTypes[17].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_user_user_proto_rawDesc), len(file_user_user_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   34,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -0,0 +1,339 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: user/user.proto
+
+package userconnect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	http "net/http"
+	user "starter-boilerplate/gen/user"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// UserContractName is the fully-qualified name of the UserContract service.
+	UserContractName = "user.UserContract"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// UserContractLoginProcedure is the fully-qualified name of the UserContract's Login RPC.
+	UserContractLoginProcedure = "/user.UserContract/Login"
+	// UserContractRegisterProcedure is the fully-qualified name of the UserContract's Register RPC.
+	UserContractRegisterProcedure = "/user.UserContract/Register"
+	// UserContractRefreshProcedure is the fully-qualified name of the UserContract's Refresh RPC.
+	UserContractRefreshProcedure = "/user.UserContract/Refresh"
+	// UserContractChangePasswordProcedure is the fully-qualified name of the UserContract's
+	// ChangePassword RPC.
+	UserContractChangePasswordProcedure = "/user.UserContract/ChangePassword"
+	// UserContractGetUserProcedure is the fully-qualified name of the UserContract's GetUser RPC.
+	UserContractGetUserProcedure = "/user.UserContract/GetUser"
+	// UserContractListUsersProcedure is the fully-qualified name of the UserContract's ListUsers RPC.
+	UserContractListUsersProcedure = "/user.UserContract/ListUsers"
+	// UserContractGetProfileProcedure is the fully-qualified name of the UserContract's GetProfile RPC.
+	UserContractGetProfileProcedure = "/user.UserContract/GetProfile"
+	// UserContractUpdateProfileProcedure is the fully-qualified name of the UserContract's
+	// UpdateProfile RPC.
+	UserContractUpdateProfileProcedure = "/user.UserContract/UpdateProfile"
+	// UserContractWatchUserEventsProcedure is the fully-qualified name of the UserContract's
+	// WatchUserEvents RPC.
+	UserContractWatchUserEventsProcedure = "/user.UserContract/WatchUserEvents"
+)
+
+// UserContractClient is a client for the user.UserContract service.
+type UserContractClient interface {
+	Login(context.Context, *connect.Request[user.LoginRequest]) (*connect.Response[user.TokenPair], error)
+	Register(context.Context, *connect.Request[user.RegisterRequest]) (*connect.Response[user.TokenPair], error)
+	Refresh(context.Context, *connect.Request[user.RefreshRequest]) (*connect.Response[user.TokenPair], error)
+	ChangePassword(context.Context, *connect.Request[user.ChangePasswordRequest]) (*connect.Response[user.ChangePasswordResponse], error)
+	GetUser(context.Context, *connect.Request[user.GetUserRequest]) (*connect.Response[user.GetUserResponse], error)
+	ListUsers(context.Context, *connect.Request[user.ListUsersRequest]) (*connect.Response[user.ListUsersResponse], error)
+	GetProfile(context.Context, *connect.Request[user.GetProfileRequest]) (*connect.Response[user.Profile], error)
+	UpdateProfile(context.Context, *connect.Request[user.UpdateProfileRequest]) (*connect.Response[user.Profile], error)
+	// WatchUserEvents streams the domain events of a user, or of every user
+	// (admin only), as they are published.
+	WatchUserEvents(context.Context, *connect.Request[user.WatchUserEventsRequest]) (*connect.ServerStreamForClient[user.UserEvent], error)
+}
+
+// NewUserContractClient constructs a client for the user.UserContract service. By default, it uses
+// the Connect protocol with the binary Protobuf Codec, asks for gzipped responses, and sends
+// uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC() or
+// connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewUserContractClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) UserContractClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	userContractMethods := user.File_user_user_proto.Services().ByName("UserContract").Methods()
+	return &userContractClient{
+		login: connect.NewClient[user.LoginRequest, user.TokenPair](
+			httpClient,
+			baseURL+UserContractLoginProcedure,
+			connect.WithSchema(userContractMethods.ByName("Login")),
+			connect.WithClientOptions(opts...),
+		),
+		register: connect.NewClient[user.RegisterRequest, user.TokenPair](
+			httpClient,
+			baseURL+UserContractRegisterProcedure,
+			connect.WithSchema(userContractMethods.ByName("Register")),
+			connect.WithClientOptions(opts...),
+		),
+		refresh: connect.NewClient[user.RefreshRequest, user.TokenPair](
+			httpClient,
+			baseURL+UserContractRefreshProcedure,
+			connect.WithSchema(userContractMethods.ByName("Refresh")),
+			connect.WithClientOptions(opts...),
+		),
+		changePassword: connect.NewClient[user.ChangePasswordRequest, user.ChangePasswordResponse](
+			httpClient,
+			baseURL+UserContractChangePasswordProcedure,
+			connect.WithSchema(userContractMethods.ByName("ChangePassword")),
+			connect.WithClientOptions(opts...),
+		),
+		getUser: connect.NewClient[user.GetUserRequest, user.GetUserResponse](
+			httpClient,
+			baseURL+UserContractGetUserProcedure,
+			connect.WithSchema(userContractMethods.ByName("GetUser")),
+			connect.WithClientOptions(opts...),
+		),
+		listUsers: connect.NewClient[user.ListUsersRequest, user.ListUsersResponse](
+			httpClient,
+			baseURL+UserContractListUsersProcedure,
+			connect.WithSchema(userContractMethods.ByName("ListUsers")),
+			connect.WithClientOptions(opts...),
+		),
+		getProfile: connect.NewClient[user.GetProfileRequest, user.Profile](
+			httpClient,
+			baseURL+UserContractGetProfileProcedure,
+			connect.WithSchema(userContractMethods.ByName("GetProfile")),
+			connect.WithClientOptions(opts...),
+		),
+		updateProfile: connect.NewClient[user.UpdateProfileRequest, user.Profile](
+			httpClient,
+			baseURL+UserContractUpdateProfileProcedure,
+			connect.WithSchema(userContractMethods.ByName("UpdateProfile")),
+			connect.WithClientOptions(opts...),
+		),
+		watchUserEvents: connect.NewClient[user.WatchUserEventsRequest, user.UserEvent](
+			httpClient,
+			baseURL+UserContractWatchUserEventsProcedure,
+			connect.WithSchema(userContractMethods.ByName("WatchUserEvents")),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// userContractClient implements UserContractClient.
+type userContractClient struct {
+	login           *connect.Client[user.LoginRequest, user.TokenPair]
+	register        *connect.Client[user.RegisterRequest, user.TokenPair]
+	refresh         *connect.Client[user.RefreshRequest, user.TokenPair]
+	changePassword  *connect.Client[user.ChangePasswordRequest, user.ChangePasswordResponse]
+	getUser         *connect.Client[user.GetUserRequest, user.GetUserResponse]
+	listUsers       *connect.Client[user.ListUsersRequest, user.ListUsersResponse]
+	getProfile      *connect.Client[user.GetProfileRequest, user.Profile]
+	updateProfile   *connect.Client[user.UpdateProfileRequest, user.Profile]
+	watchUserEvents *connect.Client[user.WatchUserEventsRequest, user.UserEvent]
+}
+
+// Login calls user.UserContract.Login.
+func (c *userContractClient) Login(ctx context.Context, req *connect.Request[user.LoginRequest]) (*connect.Response[user.TokenPair], error) {
+	return c.login.CallUnary(ctx, req)
+}
+
+// Register calls user.UserContract.Register.
+func (c *userContractClient) Register(ctx context.Context, req *connect.Request[user.RegisterRequest]) (*connect.Response[user.TokenPair], error) {
+	return c.register.CallUnary(ctx, req)
+}
+
+// Refresh calls user.UserContract.Refresh.
+func (c *userContractClient) Refresh(ctx context.Context, req *connect.Request[user.RefreshRequest]) (*connect.Response[user.TokenPair], error) {
+	return c.refresh.CallUnary(ctx, req)
+}
+
+// ChangePassword calls user.UserContract.ChangePassword.
+func (c *userContractClient) ChangePassword(ctx context.Context, req *connect.Request[user.ChangePasswordRequest]) (*connect.Response[user.ChangePasswordResponse], error) {
+	return c.changePassword.CallUnary(ctx, req)
+}
+
+// GetUser calls user.UserContract.GetUser.
+func (c *userContractClient) GetUser(ctx context.Context, req *connect.Request[user.GetUserRequest]) (*connect.Response[user.GetUserResponse], error) {
+	return c.getUser.CallUnary(ctx, req)
+}
+
+// ListUsers calls user.UserContract.ListUsers.
+func (c *userContractClient) ListUsers(ctx context.Context, req *connect.Request[user.ListUsersRequest]) (*connect.Response[user.ListUsersResponse], error) {
+	return c.listUsers.CallUnary(ctx, req)
+}
+
+// GetProfile calls user.UserContract.GetProfile.
+func (c *userContractClient) GetProfile(ctx context.Context, req *connect.Request[user.GetProfileRequest]) (*connect.Response[user.Profile], error) {
+	return c.getProfile.CallUnary(ctx, req)
+}
+
+// UpdateProfile calls user.UserContract.UpdateProfile.
+func (c *userContractClient) UpdateProfile(ctx context.Context, req *connect.Request[user.UpdateProfileRequest]) (*connect.Response[user.Profile], error) {
+	return c.updateProfile.CallUnary(ctx, req)
+}
+
+// WatchUserEvents calls user.UserContract.WatchUserEvents.
+func (c *userContractClient) WatchUserEvents(ctx context.Context, req *connect.Request[user.WatchUserEventsRequest]) (*connect.ServerStreamForClient[user.UserEvent], error) {
+	return c.watchUserEvents.CallServerStream(ctx, req)
+}
+
+// UserContractHandler is an implementation of the user.UserContract service.
+type UserContractHandler interface {
+	Login(context.Context, *connect.Request[user.LoginRequest]) (*connect.Response[user.TokenPair], error)
+	Register(context.Context, *connect.Request[user.RegisterRequest]) (*connect.Response[user.TokenPair], error)
+	Refresh(context.Context, *connect.Request[user.RefreshRequest]) (*connect.Response[user.TokenPair], error)
+	ChangePassword(context.Context, *connect.Request[user.ChangePasswordRequest]) (*connect.Response[user.ChangePasswordResponse], error)
+	GetUser(context.Context, *connect.Request[user.GetUserRequest]) (*connect.Response[user.GetUserResponse], error)
+	ListUsers(context.Context, *connect.Request[user.ListUsersRequest]) (*connect.Response[user.ListUsersResponse], error)
+	GetProfile(context.Context, *connect.Request[user.GetProfileRequest]) (*connect.Response[user.Profile], error)
+	UpdateProfile(context.Context, *connect.Request[user.UpdateProfileRequest]) (*connect.Response[user.Profile], error)
+	// WatchUserEvents streams the domain events of a user, or of every user
+	// (admin only), as they are published.
+	WatchUserEvents(context.Context, *connect.Request[user.WatchUserEventsRequest], *connect.ServerStream[user.UserEvent]) error
+}
+
+// NewUserContractHandler builds an HTTP handler from the service implementation. It returns the
+// path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewUserContractHandler(svc UserContractHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	userContractMethods := user.File_user_user_proto.Services().ByName("UserContract").Methods()
+	userContractLoginHandler := connect.NewUnaryHandler(
+		UserContractLoginProcedure,
+		svc.Login,
+		connect.WithSchema(userContractMethods.ByName("Login")),
+		connect.WithHandlerOptions(opts...),
+	)
+	userContractRegisterHandler := connect.NewUnaryHandler(
+		UserContractRegisterProcedure,
+		svc.Register,
+		connect.WithSchema(userContractMethods.ByName("Register")),
+		connect.WithHandlerOptions(opts...),
+	)
+	userContractRefreshHandler := connect.NewUnaryHandler(
+		UserContractRefreshProcedure,
+		svc.Refresh,
+		connect.WithSchema(userContractMethods.ByName("Refresh")),
+		connect.WithHandlerOptions(opts...),
+	)
+	userContractChangePasswordHandler := connect.NewUnaryHandler(
+		UserContractChangePasswordProcedure,
+		svc.ChangePassword,
+		connect.WithSchema(userContractMethods.ByName("ChangePassword")),
+		connect.WithHandlerOptions(opts...),
+	)
+	userContractGetUserHandler := connect.NewUnaryHandler(
+		UserContractGetUserProcedure,
+		svc.GetUser,
+		connect.WithSchema(userContractMethods.ByName("GetUser")),
+		connect.WithHandlerOptions(opts...),
+	)
+	userContractListUsersHandler := connect.NewUnaryHandler(
+		UserContractListUsersProcedure,
+		svc.ListUsers,
+		connect.WithSchema(userContractMethods.ByName("ListUsers")),
+		connect.WithHandlerOptions(opts...),
+	)
+	userContractGetProfileHandler := connect.NewUnaryHandler(
+		UserContractGetProfileProcedure,
+		svc.GetProfile,
+		connect.WithSchema(userContractMethods.ByName("GetProfile")),
+		connect.WithHandlerOptions(opts...),
+	)
+	userContractUpdateProfileHandler := connect.NewUnaryHandler(
+		UserContractUpdateProfileProcedure,
+		svc.UpdateProfile,
+		connect.WithSchema(userContractMethods.ByName("UpdateProfile")),
+		connect.WithHandlerOptions(opts...),
+	)
+	userContractWatchUserEventsHandler := connect.NewServerStreamHandler(
+		UserContractWatchUserEventsProcedure,
+		svc.WatchUserEvents,
+		connect.WithSchema(userContractMethods.ByName("WatchUserEvents")),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/user.UserContract/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case UserContractLoginProcedure:
+			userContractLoginHandler.ServeHTTP(w, r)
+		case UserContractRegisterProcedure:
+			userContractRegisterHandler.ServeHTTP(w, r)
+		case UserContractRefreshProcedure:
+			userContractRefreshHandler.ServeHTTP(w, r)
+		case UserContractChangePasswordProcedure:
+			userContractChangePasswordHandler.ServeHTTP(w, r)
+		case UserContractGetUserProcedure:
+			userContractGetUserHandler.ServeHTTP(w, r)
+		case UserContractListUsersProcedure:
+			userContractListUsersHandler.ServeHTTP(w, r)
+		case UserContractGetProfileProcedure:
+			userContractGetProfileHandler.ServeHTTP(w, r)
+		case UserContractUpdateProfileProcedure:
+			userContractUpdateProfileHandler.ServeHTTP(w, r)
+		case UserContractWatchUserEventsProcedure:
+			userContractWatchUserEventsHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedUserContractHandler returns CodeUnimplemented from all methods.
+type UnimplementedUserContractHandler struct{}
+
+func (UnimplementedUserContractHandler) Login(context.Context, *connect.Request[user.LoginRequest]) (*connect.Response[user.TokenPair], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("user.UserContract.Login is not implemented"))
+}
+
+func (UnimplementedUserContractHandler) Register(context.Context, *connect.Request[user.RegisterRequest]) (*connect.Response[user.TokenPair], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("user.UserContract.Register is not implemented"))
+}
+
+func (UnimplementedUserContractHandler) Refresh(context.Context, *connect.Request[user.RefreshRequest]) (*connect.Response[user.TokenPair], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("user.UserContract.Refresh is not implemented"))
+}
+
+func (UnimplementedUserContractHandler) ChangePassword(context.Context, *connect.Request[user.ChangePasswordRequest]) (*connect.Response[user.ChangePasswordResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("user.UserContract.ChangePassword is not implemented"))
+}
+
+func (UnimplementedUserContractHandler) GetUser(context.Context, *connect.Request[user.GetUserRequest]) (*connect.Response[user.GetUserResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("user.UserContract.GetUser is not implemented"))
+}
+
+func (UnimplementedUserContractHandler) ListUsers(context.Context, *connect.Request[user.ListUsersRequest]) (*connect.Response[user.ListUsersResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("user.UserContract.ListUsers is not implemented"))
+}
+
+func (UnimplementedUserContractHandler) GetProfile(context.Context, *connect.Request[user.GetProfileRequest]) (*connect.Response[user.Profile], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("user.UserContract.GetProfile is not implemented"))
+}
+
+func (UnimplementedUserContractHandler) UpdateProfile(context.Context, *connect.Request[user.UpdateProfileRequest]) (*connect.Response[user.Profile], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("user.UserContract.UpdateProfile is not implemented"))
+}
+
+func (UnimplementedUserContractHandler) WatchUserEvents(context.Context, *connect.Request[user.WatchUserEventsRequest], *connect.ServerStream[user.UserEvent]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("user.UserContract.WatchUserEvents is not implemented"))
+}
@@ -7,11 +7,14 @@ import (
 	"log/slog"
 	"net/http"
 
+	"starter-boilerplate/internal/shared/admin"
 	"starter-boilerplate/internal/shared/app"
 	"starter-boilerplate/internal/shared/centrifugenode"
 	"starter-boilerplate/internal/shared/config"
 	sharedconsumer "starter-boilerplate/internal/shared/consumer"
+	"starter-boilerplate/internal/shared/cron"
 	"starter-boilerplate/internal/shared/huma"
+	sharedi18n "starter-boilerplate/internal/shared/i18n"
 	sharedjwt "starter-boilerplate/internal/shared/jwt"
 	"starter-boilerplate/internal/shared/logger"
 	"starter-boilerplate/internal/shared/middleware"
@@ -22,42 +25,63 @@ import (
 	pkgdb "starter-boilerplate/pkg/db"
 	"starter-boilerplate/pkg/event"
 	pkggrpc "starter-boilerplate/pkg/grpc"
+	"starter-boilerplate/pkg/jobs"
+	pkgkafka "starter-boilerplate/pkg/kafka"
 	"starter-boilerplate/pkg/outbox"
 	"starter-boilerplate/pkg/redis"
+	"starter-boilerplate/pkg/saga"
+	"starter-boilerplate/pkg/scheduler"
 
 	gocentrifuge "github.com/centrifugal/centrifuge"
 	gohuma "github.com/danielgtaylor/huma/v2"
 	"github.com/google/wire"
 	goredis "github.com/redis/go-redis/v9"
+	kafkago "github.com/segmentio/kafka-go"
 	gogrpc "google.golang.org/grpc"
 )
 
-func newApp(httpSrv *http.Server, cfg *config.Config, _ user.Module, _ middleware.Init, _ *slog.Logger, _ *goredis.Client, grpcSrv *gogrpc.Server, api gohuma.API, broker *pkgamqp.Broker, relay *outbox.Relay, centrifugeNode *gocentrifuge.Node, _ centrifugenode.Init) *app.App {
-	return app.New(httpSrv, cfg, grpcSrv, api, broker, relay, centrifugeNode)
+func newApp(httpSrv *http.Server, cfg *config.Config, _ user.Module, _ middleware.Init, _ *slog.Logger, _ *goredis.Client, grpcSrv *gogrpc.Server, loopback *pkggrpc.Loopback, api gohuma.API, broker *pkgamqp.Broker, relay *outbox.Relay, sagas *saga.Manager, sched *scheduler.Scheduler, jobWorker *jobs.Worker, grpcHealth *pkggrpc.Health, eventHub *event.Hub, centrifugeNode *gocentrifuge.Node, _ centrifugenode.Init, _ admin.Init, _ cron.Init) *app.App {
+	return app.New(httpSrv, cfg, grpcSrv, loopback, api, broker, relay, sagas, sched, jobWorker, grpcHealth, eventHub, centrifugeNode)
+}
+
+// newOutboxPublisher relays outbox entries to AMQP, and also to Kafka when it
+// is enabled. The consumers of the app read AMQP, so Kafka only adds a copy
+// of the event stream for other systems.
+func newOutboxPublisher(cfg pkgkafka.Config, amqpPub *event.OutboxPublisher, writer *kafkago.Writer) outbox.Publisher {
+	if writer != nil {
+		return outbox.NewFanoutPublisher(amqpPub, pkgkafka.NewOutboxPublisher(writer, cfg.EventsTopic()))
+	}
+	return amqpPub
 }
 
 func InitializeApp(ctx context.Context) *app.App {
 	wire.Build(
 		config.SetupConfig,
 		logger.SetupLogger,
-		wire.FieldsOf(new(*config.Config), "App", "Logger", "DB", "JWT", "Redis", "GRPC", "AMQP", "Outbox", "Centrifuge"),
+		wire.FieldsOf(new(*config.Config), "App", "Errors", "Logger", "DB", "JWT", "Redis", "GRPC", "AMQP", "Outbox", "Centrifuge", "Kafka", "Saga", "Scheduler", "Cron", "Jobs"),
 
 		wire.NewSet(pkgdb.Setup, pkgdb.NewUnitOfWork, wire.Bind(new(pkgdb.UoW), new(*pkgdb.UnitOfWork))),
 		redis.Setup,
 		pkgamqp.Setup,
+		pkgkafka.Setup,
 		wire.NewSet(server.SetupMux, server.SetupHTTPServer),
 		huma.Setup,
-		pkggrpc.Setup,
+		sharedi18n.NewCatalog,
+		wire.NewSet(middleware.NewGRPCAuth, middleware.NewGRPCInterceptors, server.NewHealthChecks, pkggrpc.NewHealth, pkggrpc.Setup, pkggrpc.SetupLoopback),
 		sharedjwt.NewJWTManager,
 
-		wire.NewSet(event.NewEventBus, event.NewDefaultOutboxPublisher, wire.Bind(new(outbox.Publisher), new(*event.OutboxPublisher))),
+		wire.NewSet(event.NewEventBus, event.NewDefaultOutboxPublisher, newOutboxPublisher, event.NewHub),
 		wire.NewSet(outbox.NewRepository, outbox.NewOutboxBus, wire.Bind(new(outbox.Bus), new(*outbox.OutboxBus)), outbox.NewRelay),
+		wire.NewSet(saga.NewRepository, saga.NewManager),
+		wire.NewSet(jobs.NewRepository, jobs.NewClient, wire.Bind(new(jobs.Enqueuer), new(*jobs.Client)), jobs.NewWorker),
+		wire.NewSet(scheduler.NewRepository, scheduler.NewScheduler, cron.Setup),
 
 		pkgcentrifuge.Setup,
 		wire.NewSet(centrifugenode.NewPublisher, centrifugenode.Setup),
 
 		middleware.Setup,
 		sharedconsumer.Setup,
+		admin.Setup,
 		user.InitializeUserModule,
 
 		newApp,
@@ -0,0 +1,144 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"starter-boilerplate/internal/shared/errs"
+	pkgamqp "starter-boilerplate/pkg/amqp"
+	"starter-boilerplate/pkg/apperror"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+type ConsumerDTO struct {
+	Queue         string     `json:"queue"`
+	Concurrency   int        `json:"concurrency"`
+	Prefetch      int        `json:"prefetch"`
+	Paused        bool       `json:"paused"`
+	InFlight      int64      `json:"in_flight"`
+	Processed     uint64     `json:"processed"`
+	Failed        uint64     `json:"failed"`
+	LastMessageAt *time.Time `json:"last_message_at,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+	LastErrorAt   *time.Time `json:"last_error_at,omitempty"`
+}
+
+func newConsumerDTO(info pkgamqp.ConsumerInfo) ConsumerDTO {
+	return ConsumerDTO{
+		Queue:         info.Queue,
+		Concurrency:   info.Concurrency,
+		Prefetch:      info.Prefetch,
+		Paused:        info.Paused,
+		InFlight:      info.InFlight,
+		Processed:     info.Processed,
+		Failed:        info.Failed,
+		LastMessageAt: timePtr(info.LastMessageAt),
+		LastError:     info.LastError,
+		LastErrorAt:   timePtr(info.LastErrorAt),
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+type listConsumersOutput struct {
+	Body struct {
+		Consumers []ConsumerDTO `json:"consumers"`
+	}
+}
+
+type queueInput struct {
+	Queue string `path:"queue"`
+}
+
+type setPrefetchInput struct {
+	Queue string `path:"queue"`
+	Body  struct {
+		Prefetch int `json:"prefetch" minimum:"1" maximum:"65535"`
+	}
+}
+
+type ConsumersHandler struct {
+	broker *pkgamqp.Broker
+}
+
+func NewConsumersHandler(broker *pkgamqp.Broker) *ConsumersHandler {
+	return &ConsumersHandler{broker: broker}
+}
+
+func (h *ConsumersHandler) Register(api huma.API) {
+	huma.Register(api, adminOperation(huma.Operation{
+		OperationID: "list-consumers",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/admin/consumers",
+		Summary:     "List AMQP consumers with runtime stats",
+	}), h.list)
+
+	huma.Register(api, adminOperation(huma.Operation{
+		OperationID:   "pause-consumer",
+		Method:        http.MethodPost,
+		Path:          "/api/v1/admin/consumers/{queue}/pause",
+		Summary:       "Pause an AMQP consumer",
+		DefaultStatus: http.StatusNoContent,
+	}), h.pause)
+
+	huma.Register(api, adminOperation(huma.Operation{
+		OperationID:   "resume-consumer",
+		Method:        http.MethodPost,
+		Path:          "/api/v1/admin/consumers/{queue}/resume",
+		Summary:       "Resume a paused AMQP consumer",
+		DefaultStatus: http.StatusNoContent,
+	}), h.resume)
+
+	huma.Register(api, adminOperation(huma.Operation{
+		OperationID:   "set-consumer-prefetch",
+		Method:        http.MethodPut,
+		Path:          "/api/v1/admin/consumers/{queue}/prefetch",
+		Summary:       "Change the prefetch count of an AMQP consumer",
+		DefaultStatus: http.StatusNoContent,
+	}), h.setPrefetch)
+}
+
+func (h *ConsumersHandler) list(_ context.Context, _ *struct{}) (*listConsumersOutput, error) {
+	out := &listConsumersOutput{}
+	out.Body.Consumers = []ConsumerDTO{}
+	for _, info := range h.broker.Consumers() {
+		out.Body.Consumers = append(out.Body.Consumers, newConsumerDTO(info))
+	}
+	return out, nil
+}
+
+func (h *ConsumersHandler) pause(_ context.Context, input *queueInput) (*struct{}, error) {
+	if err := h.broker.PauseConsumer(input.Queue); err != nil {
+		return nil, mapConsumerError(err)
+	}
+	return nil, nil
+}
+
+func (h *ConsumersHandler) resume(_ context.Context, input *queueInput) (*struct{}, error) {
+	if err := h.broker.ResumeConsumer(input.Queue); err != nil {
+		return nil, mapConsumerError(err)
+	}
+	return nil, nil
+}
+
+func (h *ConsumersHandler) setPrefetch(_ context.Context, input *setPrefetchInput) (*struct{}, error) {
+	if err := h.broker.SetConsumerPrefetch(input.Queue, input.Body.Prefetch); err != nil {
+		return nil, mapConsumerError(err)
+	}
+	return nil, nil
+}
+
+func mapConsumerError(err error) error {
+	if errors.Is(err, pkgamqp.ErrConsumerNotFound) {
+		return errs.ErrNotFound
+	}
+	return apperror.Wrap(err, http.StatusInternalServerError, "consumer admin")
+}
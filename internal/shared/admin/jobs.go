@@ -0,0 +1,117 @@
+package admin
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"starter-boilerplate/internal/shared/errs"
+	"starter-boilerplate/pkg/apperror"
+	"starter-boilerplate/pkg/jobs"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+type JobDTO struct {
+	ID          int64     `json:"id"`
+	Queue       string    `json:"queue"`
+	Kind        string    `json:"kind"`
+	Payload     any       `json:"payload"`
+	Priority    int       `json:"priority"`
+	UniqueKey   string    `json:"unique_key,omitempty"`
+	State       string    `json:"state"`
+	Attempts    int       `json:"attempts"`
+	MaxAttempts int       `json:"max_attempts"`
+	RunAt       time.Time `json:"run_at"`
+	LastError   string    `json:"last_error,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func newJobDTO(job jobs.Job) JobDTO {
+	return JobDTO{
+		ID:          job.ID,
+		Queue:       job.Queue,
+		Kind:        job.Kind,
+		Payload:     job.Payload,
+		Priority:    job.Priority,
+		UniqueKey:   job.UniqueKey,
+		State:       string(job.State),
+		Attempts:    job.Attempts,
+		MaxAttempts: job.MaxAttempts,
+		RunAt:       time.Unix(job.RunAt, 0).UTC(),
+		LastError:   job.LastError,
+		CreatedAt:   time.Unix(job.CreatedAt, 0).UTC(),
+		UpdatedAt:   time.Unix(job.UpdatedAt, 0).UTC(),
+	}
+}
+
+type listFailedJobsInput struct {
+	Queue string `query:"queue" doc:"Only jobs of this queue"`
+	Limit int    `query:"limit" default:"100" minimum:"1" maximum:"1000"`
+}
+
+type listJobsOutput struct {
+	Body struct {
+		Jobs []JobDTO `json:"jobs"`
+	}
+}
+
+type jobIDInput struct {
+	ID int64 `path:"id"`
+}
+
+type jobOutput struct {
+	Body JobDTO
+}
+
+type JobsHandler struct {
+	repo *jobs.Repository
+}
+
+func NewJobsHandler(repo *jobs.Repository) *JobsHandler {
+	return &JobsHandler{repo: repo}
+}
+
+func (h *JobsHandler) Register(api huma.API) {
+	huma.Register(api, adminOperation(huma.Operation{
+		OperationID: "list-failed-jobs",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/admin/jobs/failed",
+		Summary:     "List background jobs that will not be retried, most recent first",
+	}), h.listFailed)
+
+	huma.Register(api, adminOperation(huma.Operation{
+		OperationID: "get-job",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/admin/jobs/{id}",
+		Summary:     "Get a background job",
+	}), h.get)
+}
+
+func (h *JobsHandler) listFailed(ctx context.Context, input *listFailedJobsInput) (*listJobsOutput, error) {
+	list, err := h.repo.ListFailed(ctx, input.Queue, input.Limit)
+	if err != nil {
+		return nil, apperror.Wrap(err, http.StatusInternalServerError, "jobs admin")
+	}
+
+	out := &listJobsOutput{}
+	out.Body.Jobs = make([]JobDTO, 0, len(list))
+	for _, job := range list {
+		out.Body.Jobs = append(out.Body.Jobs, newJobDTO(job))
+	}
+	return out, nil
+}
+
+func (h *JobsHandler) get(ctx context.Context, input *jobIDInput) (*jobOutput, error) {
+	job, err := h.repo.Get(ctx, input.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errs.ErrNotFound
+	}
+	if err != nil {
+		return nil, apperror.Wrap(err, http.StatusInternalServerError, "jobs admin")
+	}
+	return &jobOutput{Body: newJobDTO(*job)}, nil
+}
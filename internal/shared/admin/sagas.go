@@ -0,0 +1,122 @@
+package admin
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"starter-boilerplate/internal/shared/errs"
+	"starter-boilerplate/pkg/apperror"
+	"starter-boilerplate/pkg/saga"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+type SagaDTO struct {
+	ID            int64      `json:"id"`
+	Saga          string     `json:"saga"`
+	CorrelationID string     `json:"correlation_id"`
+	Status        string     `json:"status"`
+	Step          string     `json:"step,omitempty"`
+	Completed     []string   `json:"completed"`
+	Data          any        `json:"data"`
+	Error         string     `json:"error,omitempty"`
+	Deadline      *time.Time `json:"deadline,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+func newSagaDTO(inst saga.Instance) SagaDTO {
+	dto := SagaDTO{
+		ID:            inst.ID,
+		Saga:          inst.Saga,
+		CorrelationID: inst.CorrelationID,
+		Status:        string(inst.Status),
+		Step:          inst.Step,
+		Completed:     inst.Completed,
+		Data:          inst.Data,
+		Error:         inst.Error,
+		CreatedAt:     time.Unix(inst.CreatedAt, 0).UTC(),
+		UpdatedAt:     time.Unix(inst.UpdatedAt, 0).UTC(),
+	}
+	if inst.Deadline > 0 {
+		deadline := time.Unix(inst.Deadline, 0).UTC()
+		dto.Deadline = &deadline
+	}
+	return dto
+}
+
+type listStuckSagasInput struct {
+	OlderThan string `query:"older_than" default:"1h" doc:"Running instances not updated for this long are stuck (Go duration)"`
+	Limit     int    `query:"limit" default:"100" minimum:"1" maximum:"1000"`
+}
+
+type listSagasOutput struct {
+	Body struct {
+		Sagas []SagaDTO `json:"sagas"`
+	}
+}
+
+type sagaIDInput struct {
+	ID int64 `path:"id"`
+}
+
+type sagaOutput struct {
+	Body SagaDTO
+}
+
+type SagasHandler struct {
+	repo *saga.Repository
+}
+
+func NewSagasHandler(repo *saga.Repository) *SagasHandler {
+	return &SagasHandler{repo: repo}
+}
+
+func (h *SagasHandler) Register(api huma.API) {
+	huma.Register(api, adminOperation(huma.Operation{
+		OperationID: "list-stuck-sagas",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/admin/sagas/stuck",
+		Summary:     "List failed saga instances and running ones without progress",
+	}), h.listStuck)
+
+	huma.Register(api, adminOperation(huma.Operation{
+		OperationID: "get-saga",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/admin/sagas/{id}",
+		Summary:     "Get a saga instance",
+	}), h.get)
+}
+
+func (h *SagasHandler) listStuck(ctx context.Context, input *listStuckSagasInput) (*listSagasOutput, error) {
+	olderThan, err := time.ParseDuration(input.OlderThan)
+	if err != nil || olderThan < 0 {
+		return nil, apperror.New(http.StatusUnprocessableEntity, "older_than must be a non-negative duration")
+	}
+
+	insts, err := h.repo.ListStuck(ctx, time.Now().Add(-olderThan).Unix(), input.Limit)
+	if err != nil {
+		return nil, apperror.Wrap(err, http.StatusInternalServerError, "saga admin")
+	}
+
+	out := &listSagasOutput{}
+	out.Body.Sagas = make([]SagaDTO, 0, len(insts))
+	for _, inst := range insts {
+		out.Body.Sagas = append(out.Body.Sagas, newSagaDTO(inst))
+	}
+	return out, nil
+}
+
+func (h *SagasHandler) get(ctx context.Context, input *sagaIDInput) (*sagaOutput, error) {
+	inst, err := h.repo.Get(ctx, input.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errs.ErrNotFound
+	}
+	if err != nil {
+		return nil, apperror.Wrap(err, http.StatusInternalServerError, "saga admin")
+	}
+	return &sagaOutput{Body: newSagaDTO(*inst)}, nil
+}
@@ -0,0 +1,76 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"starter-boilerplate/pkg/apperror"
+	"starter-boilerplate/pkg/scheduler"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+type JobRunDTO struct {
+	ID         int64     `json:"id"`
+	Job        string    `json:"job"`
+	Node       string    `json:"node"`
+	Status     string    `json:"status"`
+	Error      string    `json:"error,omitempty"`
+	StartedAt  time.Time `json:"started_at"`
+	DurationMs int64     `json:"duration_ms"`
+}
+
+func newJobRunDTO(run scheduler.Run) JobRunDTO {
+	return JobRunDTO{
+		ID:         run.ID,
+		Job:        run.Job,
+		Node:       run.Node,
+		Status:     string(run.Status),
+		Error:      run.Error,
+		StartedAt:  time.Unix(run.StartedAt, 0).UTC(),
+		DurationMs: run.DurationMs,
+	}
+}
+
+type listJobRunsInput struct {
+	Job   string `query:"job" doc:"Only runs of this job"`
+	Limit int    `query:"limit" default:"100" minimum:"1" maximum:"1000"`
+}
+
+type listJobRunsOutput struct {
+	Body struct {
+		Runs []JobRunDTO `json:"runs"`
+	}
+}
+
+type SchedulerHandler struct {
+	repo *scheduler.Repository
+}
+
+func NewSchedulerHandler(repo *scheduler.Repository) *SchedulerHandler {
+	return &SchedulerHandler{repo: repo}
+}
+
+func (h *SchedulerHandler) Register(api huma.API) {
+	huma.Register(api, adminOperation(huma.Operation{
+		OperationID: "list-job-runs",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/admin/scheduler/runs",
+		Summary:     "List recent scheduled job runs, newest first",
+	}), h.listRuns)
+}
+
+func (h *SchedulerHandler) listRuns(ctx context.Context, input *listJobRunsInput) (*listJobRunsOutput, error) {
+	runs, err := h.repo.List(ctx, input.Job, input.Limit)
+	if err != nil {
+		return nil, apperror.Wrap(err, http.StatusInternalServerError, "scheduler admin")
+	}
+
+	out := &listJobRunsOutput{}
+	out.Body.Runs = make([]JobRunDTO, 0, len(runs))
+	for _, run := range runs {
+		out.Body.Runs = append(out.Body.Runs, newJobRunDTO(run))
+	}
+	return out, nil
+}
@@ -0,0 +1,34 @@
+package admin
+
+import (
+	pkgamqp "starter-boilerplate/pkg/amqp"
+	"starter-boilerplate/pkg/jobs"
+	"starter-boilerplate/pkg/saga"
+	"starter-boilerplate/pkg/scheduler"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+type Init struct{}
+
+// Setup registers the admin HTTP API. Every operation requires a bearer token with the admin role.
+func Setup(api huma.API, broker *pkgamqp.Broker, sagas *saga.Repository, schedulerRuns *scheduler.Repository, backgroundJobs *jobs.Repository) Init {
+	NewConsumersHandler(broker).Register(api)
+	NewSagasHandler(sagas).Register(api)
+	NewSchedulerHandler(schedulerRuns).Register(api)
+	NewJobsHandler(backgroundJobs).Register(api)
+	return Init{}
+}
+
+// adminOperation tags op and restricts it to authenticated admins.
+func adminOperation(op huma.Operation) huma.Operation {
+	op.Tags = append(op.Tags, "admin")
+	op.Security = []map[string][]string{
+		{"bearerAuth": {}},
+	}
+	if op.Metadata == nil {
+		op.Metadata = map[string]any{}
+	}
+	op.Metadata["requiredRoles"] = []string{"admin"}
+	return op
+}
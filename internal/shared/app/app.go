@@ -7,11 +7,17 @@ import (
 	"log/slog"
 	"net"
 	"net/http"
+	"sync"
 	"time"
 
 	"starter-boilerplate/internal/shared/config"
 	pkgamqp "starter-boilerplate/pkg/amqp"
+	"starter-boilerplate/pkg/event"
+	pkggrpc "starter-boilerplate/pkg/grpc"
+	"starter-boilerplate/pkg/jobs"
 	"starter-boilerplate/pkg/outbox"
+	"starter-boilerplate/pkg/saga"
+	"starter-boilerplate/pkg/scheduler"
 
 	"github.com/centrifugal/centrifuge"
 	gohuma "github.com/danielgtaylor/huma/v2"
@@ -22,23 +28,35 @@ import (
 type App struct {
 	HTTPServer     *http.Server
 	GRPCServer     *gogrpc.Server
+	loopback       *pkggrpc.Loopback
 	Config         *config.Config
 	Api            gohuma.API
 	broker         *pkgamqp.Broker
 	relay          *outbox.Relay
+	sagas          *saga.Manager
+	scheduler      *scheduler.Scheduler
+	jobs           *jobs.Worker
+	grpcHealth     *pkggrpc.Health
+	eventHub       *event.Hub
 	centrifugeNode *centrifuge.Node
 	ready          chan struct{}
 	startErr       chan error
 }
 
-func New(httpSrv *http.Server, cfg *config.Config, grpcSrv *gogrpc.Server, api gohuma.API, broker *pkgamqp.Broker, relay *outbox.Relay, centrifugeNode *centrifuge.Node) *App {
+func New(httpSrv *http.Server, cfg *config.Config, grpcSrv *gogrpc.Server, loopback *pkggrpc.Loopback, api gohuma.API, broker *pkgamqp.Broker, relay *outbox.Relay, sagas *saga.Manager, sched *scheduler.Scheduler, jobWorker *jobs.Worker, grpcHealth *pkggrpc.Health, eventHub *event.Hub, centrifugeNode *centrifuge.Node) *App {
 	return &App{
 		HTTPServer:     httpSrv,
 		GRPCServer:     grpcSrv,
+		loopback:       loopback,
 		Config:         cfg,
 		Api:            api,
 		broker:         broker,
 		relay:          relay,
+		sagas:          sagas,
+		scheduler:      sched,
+		jobs:           jobWorker,
+		grpcHealth:     grpcHealth,
+		eventHub:       eventHub,
 		centrifugeNode: centrifugeNode,
 		ready:          make(chan struct{}),
 		startErr:       make(chan error, 1),
@@ -96,6 +114,17 @@ func (a *App) Run(ctx context.Context) error {
 		return nil
 	})
 
+	g.Go(func() error {
+		if err := a.loopback.Serve(a.GRPCServer); err != nil {
+			return fmt.Errorf("grpc loopback: %w", err)
+		}
+		return nil
+	})
+
+	g.Go(func() error {
+		return a.grpcHealth.Run(gCtx)
+	})
+
 	g.Go(func() error {
 		return a.broker.Run(gCtx)
 	})
@@ -104,6 +133,18 @@ func (a *App) Run(ctx context.Context) error {
 		return a.relay.Run(gCtx)
 	})
 
+	g.Go(func() error {
+		return a.sagas.Run(gCtx)
+	})
+
+	g.Go(func() error {
+		return a.scheduler.Run(gCtx)
+	})
+
+	g.Go(func() error {
+		return a.jobs.Run(gCtx)
+	})
+
 	if a.centrifugeNode != nil {
 		g.Go(func() error {
 			if err := a.centrifugeNode.Run(); err != nil {
@@ -122,15 +163,51 @@ func (a *App) Run(ctx context.Context) error {
 	return g.Wait()
 }
 
+// shutdown stops the app in phases. Each phase gets its own ShutdownTimeout
+// budget, so a slow phase does not leave the next one without time; the whole
+// shutdown takes at most three times ShutdownTimeout:
+//  1. gRPC health turns NOT_SERVING, event streams end, and inbound servers
+//     (HTTP, gRPC, Centrifuge) stop accepting and finish their requests;
+//  2. AMQP consumers drain: fetching already stopped with the run context, in-flight
+//     handlers finish or are requeued at the deadline;
+//  3. scheduled and background jobs finish; at the deadline they are cancelled, and
+//     background jobs go back to their queue;
+//  4. publishers, the RPC client and the gateways' loopback connection are
+//     closed, once nothing can publish or call anymore.
 func (a *App) shutdown() error {
 	slog.Info("shutting down servers...")
-
+	a.grpcHealth.Shutdown()
+	a.eventHub.Close()
+
+	var err error
+	shutdownPhases(a.Config.App.ShutdownTimeout,
+		func(ctx context.Context) { err = a.stopServers(ctx) },
+		func(ctx context.Context) { a.broker.Drain(ctx) },
+		func(ctx context.Context) {
+			var workers sync.WaitGroup
+			workers.Go(func() { a.scheduler.Shutdown(ctx) })
+			workers.Go(func() { a.jobs.Shutdown(ctx) })
+			workers.Wait()
+		},
+	)
 	a.broker.Shutdown()
+	_ = a.loopback.Close()
 
-	timeout := a.Config.App.ShutdownTimeout
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
-	defer cancel()
+	return err
+}
 
+// shutdownPhases runs phases in order, each with a context that is done
+// timeout after the phase starts.
+func shutdownPhases(timeout time.Duration, phases ...func(ctx context.Context)) {
+	for _, phase := range phases {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		phase(ctx)
+		cancel()
+	}
+}
+
+// stopServers gracefully stops the inbound servers in parallel.
+func (a *App) stopServers(ctx context.Context) error {
 	var g errgroup.Group
 
 	if a.centrifugeNode != nil {
@@ -158,13 +235,10 @@ func (a *App) shutdown() error {
 			close(stopped)
 		}()
 
-		t := time.NewTimer(timeout)
-		defer t.Stop()
-
 		select {
 		case <-stopped:
 			slog.Info("grpc server stopped gracefully")
-		case <-t.C:
+		case <-ctx.Done():
 			slog.Warn("grpc server shutdown timed out, forcing stop")
 			a.GRPCServer.Stop()
 		}
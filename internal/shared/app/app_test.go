@@ -0,0 +1,28 @@
+//go:build unit
+
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShutdownPhases_EachPhaseGetsItsOwnBudget(t *testing.T) {
+	const timeout = 50 * time.Millisecond
+
+	var drainBudget time.Duration
+	shutdownPhases(timeout,
+		func(ctx context.Context) { <-ctx.Done() }, // servers use up their whole budget
+		func(ctx context.Context) {
+			deadline, ok := ctx.Deadline()
+			assert.True(t, ok)
+			drainBudget = time.Until(deadline)
+			assert.NoError(t, ctx.Err())
+		},
+	)
+
+	assert.Greater(t, drainBudget, timeout/2, "drain must not inherit the servers' spent deadline")
+}
@@ -6,12 +6,18 @@ import (
 	"time"
 
 	pkgamqp "starter-boilerplate/pkg/amqp"
+	"starter-boilerplate/pkg/apperror"
 	pkgcentrifuge "starter-boilerplate/pkg/centrifuge"
 	pkgdb "starter-boilerplate/pkg/db"
 	pkggrpc "starter-boilerplate/pkg/grpc"
+	"starter-boilerplate/pkg/jobs"
+	pkgkafka "starter-boilerplate/pkg/kafka"
 	"starter-boilerplate/pkg/outbox"
 	pkgredis "starter-boilerplate/pkg/redis"
+	"starter-boilerplate/pkg/saga"
+	"starter-boilerplate/pkg/scheduler"
 
+	"starter-boilerplate/internal/shared/cron"
 	sharedjwt "starter-boilerplate/internal/shared/jwt"
 	sharedlogger "starter-boilerplate/internal/shared/logger"
 
@@ -31,6 +37,7 @@ type AppConfig struct {
 
 type Config struct {
 	App        AppConfig                 `yaml:"app"`
+	Errors     apperror.Config           `yaml:"errors"`
 	Logger     sharedlogger.LoggerConfig `yaml:"logger"`
 	DB         pkgdb.DBConfig            `yaml:"db"`
 	Redis      pkgredis.RedisConfig      `yaml:"redis"`
@@ -39,6 +46,11 @@ type Config struct {
 	AMQP       pkgamqp.AMQPConfig        `yaml:"amqp"`
 	Outbox     outbox.RelayConfig        `yaml:"outbox"`
 	Centrifuge pkgcentrifuge.Config      `yaml:"centrifuge"`
+	Kafka      pkgkafka.Config           `yaml:"kafka"`
+	Saga       saga.Config               `yaml:"saga"`
+	Scheduler  scheduler.Config          `yaml:"scheduler"`
+	Cron       cron.Config               `yaml:"cron"`
+	Jobs       jobs.Config               `yaml:"jobs"`
 }
 
 func SetupConfig() *Config {
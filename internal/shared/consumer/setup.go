@@ -9,5 +9,6 @@ import (
 func Setup(conn *amqp091.Connection, cfg pkgamqp.AMQPConfig) *pkgamqp.Broker {
 	b := pkgamqp.NewBroker(conn, cfg.Pool)
 	b.Use(pkgamqp.WithRecover(), pkgamqp.WithLogging())
+	b.Use(cfg.Middleware.Middlewares()...)
 	return b
 }
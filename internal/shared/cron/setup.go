@@ -0,0 +1,78 @@
+package cron
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"starter-boilerplate/pkg/jobs"
+	"starter-boilerplate/pkg/outbox"
+	"starter-boilerplate/pkg/scheduler"
+)
+
+// Config schedules the application's periodic jobs.
+type Config struct {
+	OutboxCleanup   string        `yaml:"outbox_cleanup"`   // schedule, default: @hourly
+	OutboxRetention time.Duration `yaml:"outbox_retention"` // entries published longer ago than this are deleted, default: 168h
+	JobsCleanup     string        `yaml:"jobs_cleanup"`     // schedule, default: @hourly
+	JobsRetention   time.Duration `yaml:"jobs_retention"`   // succeeded jobs older than this are deleted, default: 168h
+}
+
+type Init struct{}
+
+// Setup registers the application's periodic jobs on the scheduler.
+func Setup(s *scheduler.Scheduler, outboxRepo *outbox.Repository, jobsRepo *jobs.Repository, cfg Config) Init {
+	s.Register(outboxCleanupJob(outboxRepo, cfg))
+	s.Register(jobsCleanupJob(jobsRepo, cfg))
+	return Init{}
+}
+
+func outboxCleanupJob(repo *outbox.Repository, cfg Config) scheduler.Job {
+	schedule := cfg.OutboxCleanup
+	if schedule == "" {
+		schedule = "@hourly"
+	}
+	retention := cfg.OutboxRetention
+	if retention == 0 {
+		retention = 7 * 24 * time.Hour
+	}
+
+	return scheduler.Job{
+		Name:     "outbox_cleanup",
+		Schedule: schedule,
+		Timeout:  5 * time.Minute,
+		Run: func(ctx context.Context) error {
+			n, err := repo.DeletePublishedBefore(ctx, time.Now().Add(-retention).Unix())
+			if err != nil {
+				return err
+			}
+			slog.Info("outbox cleanup", slog.Int64("deleted", n))
+			return nil
+		},
+	}
+}
+
+func jobsCleanupJob(repo *jobs.Repository, cfg Config) scheduler.Job {
+	schedule := cfg.JobsCleanup
+	if schedule == "" {
+		schedule = "@hourly"
+	}
+	retention := cfg.JobsRetention
+	if retention == 0 {
+		retention = 7 * 24 * time.Hour
+	}
+
+	return scheduler.Job{
+		Name:     "jobs_cleanup",
+		Schedule: schedule,
+		Timeout:  5 * time.Minute,
+		Run: func(ctx context.Context) error {
+			n, err := repo.DeleteSucceededBefore(ctx, time.Now().Add(-retention).Unix())
+			if err != nil {
+				return err
+			}
+			slog.Info("jobs cleanup", slog.Int64("deleted", n))
+			return nil
+		},
+	}
+}
@@ -6,10 +6,14 @@ import (
 	"starter-boilerplate/pkg/apperror"
 )
 
+// Sentinels carry explicit codes: errors.Is matches any AppError with the same
+// code, including copies refined with With*.
 var (
-	ErrAccessDenied       = apperror.New(http.StatusForbidden, "access denied")
-	ErrNotFound           = apperror.New(http.StatusNotFound, "not found")
-	ErrInvalidCredentials = apperror.New(http.StatusUnauthorized, "invalid credentials")
-	ErrInvalidToken       = apperror.New(http.StatusUnauthorized, "invalid token")
-	ErrEmailAlreadyExists = apperror.New(http.StatusConflict, "email already exists")
+	ErrAccessDenied           = apperror.New(http.StatusForbidden, "access denied").WithCode("access_denied")
+	ErrNotFound               = apperror.New(http.StatusNotFound, "not found").WithCode("not_found")
+	ErrInvalidCredentials     = apperror.New(http.StatusUnauthorized, "invalid credentials").WithCode("invalid_credentials")
+	ErrInvalidToken           = apperror.New(http.StatusUnauthorized, "invalid token").WithCode("invalid_token")
+	ErrEmailAlreadyExists     = apperror.New(http.StatusConflict, "email already exists").WithCode("email_already_exists")
+	ErrInvalidProfileUpdate   = apperror.New(http.StatusBadRequest, "invalid profile update").WithCode("invalid_profile_update")
+	ErrProfileConditionFailed = apperror.New(http.StatusConflict, "profile condition failed").WithCode("profile_condition_failed")
 )
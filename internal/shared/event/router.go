@@ -2,7 +2,6 @@ package event
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log/slog"
 
@@ -24,12 +23,13 @@ func NewRouter() *Router {
 }
 
 // Route registers a typed handler keyed by T's EventName().
-// Unmarshal and validation happen automatically before fn is called.
+// Unmarshal (with the codec matching meta.ContentType) and validation
+// happen automatically before fn is called.
 func Route[T pkgevent.Event](r *Router, fn func(ctx context.Context, payload T, meta pkgamqp.DeliveryMeta) error) {
 	var zero T
 	r.routes[zero.EventName()] = func(ctx context.Context, body []byte, meta pkgamqp.DeliveryMeta) error {
 		var payload T
-		if err := json.Unmarshal(body, &payload); err != nil {
+		if err := pkgamqp.Decode(meta.ContentType, body, &payload); err != nil {
 			return fmt.Errorf("unmarshal: %w", err)
 		}
 		if err := pkgamqp.Validate(ctx, payload); err != nil {
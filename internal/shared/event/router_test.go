@@ -115,3 +115,25 @@ func TestRouter_FallbackError(t *testing.T) {
 
 	assert.EqualError(t, err, "fallback error")
 }
+
+func TestRouter_DecodesByContentType(t *testing.T) {
+	r := NewRouter()
+
+	var received testEvent
+	Route(r, func(_ context.Context, payload testEvent, _ pkgamqp.DeliveryMeta) error {
+		received = payload
+		return nil
+	})
+
+	body, err := pkgamqp.MsgpackCodec{}.Marshal(testEvent{Value: "packed"})
+	require.NoError(t, err)
+
+	handler := r.Handler()
+	err = handler(context.Background(), body, pkgamqp.DeliveryMeta{
+		RoutingKey:  "test.event",
+		ContentType: pkgamqp.ContentTypeMsgpack,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "packed", received.Value)
+}
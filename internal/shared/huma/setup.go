@@ -1,25 +1,31 @@
 package huma
 
 import (
+	"errors"
 	"log/slog"
 	"net/http"
 
 	"starter-boilerplate/internal/shared/config"
+	"starter-boilerplate/internal/shared/middleware"
+	"starter-boilerplate/pkg/apperror"
+	"starter-boilerplate/pkg/i18n"
 
 	gohuma "github.com/danielgtaylor/huma/v2"
 	"github.com/danielgtaylor/huma/v2/adapters/humago"
 )
 
-func Setup(mux *http.ServeMux, cfg config.AppConfig) gohuma.API {
+func Setup(mux *http.ServeMux, cfg config.AppConfig, errCfg apperror.Config) gohuma.API {
+	// Every error is an AppError, rendered as problem details by
+	// problemTransformer. NewError only shapes the documented error schema.
+	gohuma.NewError = func(status int, msg string, errs ...error) gohuma.StatusError {
+		return newAppError(status, msg, errs).Problem(errCfg, "")
+	}
 	gohuma.NewErrorWithContext = func(_ gohuma.Context, status int, msg string, errs ...error) gohuma.StatusError {
-		if status >= 500 {
-			slog.Error("internal error", slog.Int("status", status), slog.Any("errors", errs))
-			return gohuma.NewError(status, "internal server error")
-		}
-		return gohuma.NewError(status, msg, errs...)
+		return newAppError(status, msg, errs)
 	}
 
 	humaConfig := gohuma.DefaultConfig("Starter API", "1.0.0")
+	humaConfig.Transformers = append(humaConfig.Transformers, problemTransformer(errCfg))
 
 	if !cfg.SwaggerDocs {
 		humaConfig.DocsPath = ""
@@ -40,3 +46,46 @@ func Setup(mux *http.ServeMux, cfg config.AppConfig) gohuma.API {
 
 	return api
 }
+
+// newAppError turns huma's errors into an AppError: validation details become
+// field violations, with the reason of their message (see violationReason),
+// and anything else passed to a server error is its cause.
+func newAppError(status int, msg string, errs []error) *apperror.AppError {
+	appErr := apperror.New(status, msg)
+	if status >= http.StatusInternalServerError {
+		return appErr.WithCause(errors.Join(errs...))
+	}
+	for _, err := range errs {
+		var detailer gohuma.ErrorDetailer
+		if errors.As(err, &detailer) {
+			d := detailer.ErrorDetail()
+			reason, args := violationReason(d.Message)
+			appErr = appErr.WithField(d.Location, reason, d.Message, args...)
+			continue
+		}
+		appErr = appErr.WithField("", "", err.Error())
+	}
+	return appErr
+}
+
+// problemTransformer renders AppErrors as problem details with the request ID,
+// in the language of the request (see middleware.NewLanguageMiddleware), and
+// logs server errors, whose cause the problem leaves out.
+func problemTransformer(errCfg apperror.Config) gohuma.Transformer {
+	return func(ctx gohuma.Context, _ string, v any) (any, error) {
+		appErr, ok := v.(*apperror.AppError)
+		if !ok {
+			return v, nil
+		}
+		requestID := middleware.RequestIDFromContext(ctx.Context())
+		if appErr.Status >= http.StatusInternalServerError {
+			slog.Error("internal error",
+				slog.Int("status", appErr.Status),
+				slog.String("request_id", requestID),
+				slog.String("message", appErr.Message),
+				slog.Any("error", errors.Unwrap(appErr)),
+			)
+		}
+		return i18n.FromContext(ctx.Context()).Problem(appErr.Problem(errCfg, requestID)), nil
+	}
+}
@@ -0,0 +1,158 @@
+//go:build unit
+
+package huma
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"starter-boilerplate/internal/shared/config"
+	"starter-boilerplate/internal/shared/i18n"
+	"starter-boilerplate/internal/shared/middleware"
+	"starter-boilerplate/pkg/apperror"
+
+	gohuma "github.com/danielgtaylor/huma/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type createInput struct {
+	Body struct {
+		Email string `json:"email" format:"email"`
+	}
+}
+
+func setupAPI(t *testing.T, handler func(context.Context, *createInput) (*struct{}, error)) *http.ServeMux {
+	t.Helper()
+	mux := http.NewServeMux()
+	api := Setup(mux, config.AppConfig{}, apperror.Config{TypeBaseURI: "https://errors.example.com/"})
+	api.UseMiddleware(middleware.NewRequestIDMiddleware())
+	api.UseMiddleware(middleware.NewLanguageMiddleware(i18n.NewCatalog()))
+	gohuma.Post(api, "/items", handler)
+	return mux
+}
+
+func post(t *testing.T, mux *http.ServeMux, body string) (*httptest.ResponseRecorder, apperror.Problem) {
+	t.Helper()
+	return postIn(t, mux, "", body)
+}
+
+func postIn(t *testing.T, mux *http.ServeMux, acceptLanguage, body string) (*httptest.ResponseRecorder, apperror.Problem) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Request-Id", "req-1")
+	req.Header.Set("Accept-Language", acceptLanguage)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	var p apperror.Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+	return rec, p
+}
+
+func TestSetup_AppErrorIsProblem(t *testing.T) {
+	mux := setupAPI(t, func(context.Context, *createInput) (*struct{}, error) {
+		return nil, apperror.New(http.StatusConflict, "email already exists").WithCode("email_already_exists")
+	})
+
+	rec, p := post(t, mux, `{"email":"a@b.c"}`)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "https://errors.example.com/email_already_exists", p.Type)
+	assert.Equal(t, "email_already_exists", p.Code)
+	assert.Equal(t, "email already exists", p.Detail)
+	assert.Equal(t, "req-1", p.RequestID)
+}
+
+func TestSetup_ValidationErrorsAreFieldViolations(t *testing.T) {
+	mux := setupAPI(t, func(context.Context, *createInput) (*struct{}, error) {
+		return &struct{}{}, nil
+	})
+
+	rec, p := post(t, mux, `{"email":"not-an-email"}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, "unprocessable_entity", p.Code)
+	require.Len(t, p.Errors, 1)
+	assert.Equal(t, "body.email", p.Errors[0].Field)
+	assert.Equal(t, "format", p.Errors[0].Reason)
+}
+
+func TestSetup_ErrorsAreLocalized(t *testing.T) {
+	mux := setupAPI(t, func(context.Context, *createInput) (*struct{}, error) {
+		return nil, apperror.New(http.StatusConflict, "email already exists").WithCode("email_already_exists")
+	})
+
+	tests := []struct {
+		acceptLanguage, language, detail string
+	}{
+		{"ru-RU,ru;q=0.9", "ru", "пользователь с таким email уже существует"},
+		{"es", "es", "ya existe un usuario con este email"},
+		{"de", "en", "email already exists"},
+		{"", "en", "email already exists"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.acceptLanguage, func(t *testing.T) {
+			rec, p := postIn(t, mux, tt.acceptLanguage, `{"email":"a@b.c"}`)
+
+			assert.Equal(t, tt.language, rec.Header().Get("Content-Language"))
+			assert.Equal(t, tt.detail, p.Detail)
+			assert.Equal(t, "email_already_exists", p.Code, "codes are not translated")
+		})
+	}
+}
+
+func TestSetup_ValidationErrorsAreLocalized(t *testing.T) {
+	mux := setupAPI(t, func(context.Context, *createInput) (*struct{}, error) {
+		return &struct{}{}, nil
+	})
+
+	_, p := postIn(t, mux, "ru", `{"email":"not-an-email"}`)
+
+	assert.Equal(t, "ошибка валидации", p.Detail)
+	require.Len(t, p.Errors, 1)
+	assert.Equal(t, "значение должно быть в формате email", p.Errors[0].Message)
+}
+
+func TestViolationReason(t *testing.T) {
+	tests := []struct {
+		msg    string
+		reason string
+		args   []any
+	}{
+		{"expected length >= 6", "min_length", []any{"6"}},
+		{"expected number >= 0", "minimum", []any{"0"}},
+		{"expected required property email to be present", "required", []any{"email"}},
+		{"expected string to be RFC 5322 email: mail: missing @", "format", []any{"email"}},
+		{"expected integer", "type", []any{"integer"}},
+		{"expected string", "type", []any{"string"}},
+		{"something else", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.msg, func(t *testing.T) {
+			reason, args := violationReason(tt.msg)
+			assert.Equal(t, tt.reason, reason)
+			assert.Equal(t, tt.args, args)
+		})
+	}
+}
+
+func TestSetup_ServerErrorsAreSanitized(t *testing.T) {
+	mux := setupAPI(t, func(context.Context, *createInput) (*struct{}, error) {
+		return nil, errors.New("connection refused")
+	})
+
+	rec, p := post(t, mux, `{"email":"a@b.c"}`)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, "internal server error", p.Detail)
+	assert.Equal(t, "internal_server_error", p.Code)
+	assert.NotContains(t, rec.Body.String(), "connection refused")
+}
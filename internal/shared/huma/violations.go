@@ -0,0 +1,76 @@
+package huma
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2/validation"
+)
+
+// violationRule recognizes one of huma's validation messages: its reason and
+// arguments let the message be translated (see i18n.Messages.Violations).
+// Messages of a fixed format or type name it as their argument.
+type violationRule struct {
+	reason string
+	re     *regexp.Regexp
+	arg    string
+}
+
+var violationRules = []violationRule{
+	newViolationRule("required", validation.MsgExpectedRequiredProperty, ""),
+	newViolationRule("unexpected_property", validation.MsgUnexpectedProperty, ""),
+	newViolationRule("min_length", validation.MsgExpectedMinLength, ""),
+	newViolationRule("max_length", validation.MsgExpectedMaxLength, ""),
+	newViolationRule("minimum", validation.MsgExpectedMinimumNumber, ""),
+	newViolationRule("exclusive_minimum", validation.MsgExpectedExclusiveMinimumNumber, ""),
+	newViolationRule("maximum", validation.MsgExpectedMaximumNumber, ""),
+	newViolationRule("exclusive_maximum", validation.MsgExpectedExclusiveMaximumNumber, ""),
+	newViolationRule("multiple_of", validation.MsgExpectedNumberBeMultipleOf, ""),
+	newViolationRule("min_items", validation.MsgExpectedMinItems, ""),
+	newViolationRule("max_items", validation.MsgExpectedMaxItems, ""),
+	newViolationRule("pattern", validation.MsgExpectedMatchPattern, ""),
+	newViolationRule("enum", validation.MsgExpectedOneOf, ""),
+	newViolationRule("format", validation.MsgExpectedRFC5322Email, "email"),
+	newViolationRule("format", validation.MsgExpectedRFC3339DateTime, "date-time"),
+	newViolationRule("format", validation.MsgExpectedRFC3339Date, "date"),
+	newViolationRule("format", validation.MsgExpectedRFC3339Time, "time"),
+	newViolationRule("format", validation.MsgExpectedRFC4122UUID, "uuid"),
+	newViolationRule("format", validation.MsgExpectedRFC3986URI, "uri"),
+	newViolationRule("format", validation.MsgExpectedRFC5890Hostname, "hostname"),
+	newViolationRule("format", validation.MsgExpectedRFC2673IPv4, "ipv4"),
+	newViolationRule("format", validation.MsgExpectedRFC2373IPv6, "ipv6"),
+	newViolationRule("format", validation.MsgExpectedDuration, "duration"),
+	newViolationRule("type", validation.MsgExpectedBoolean, "boolean"),
+	newViolationRule("type", validation.MsgExpectedNumber, "number"),
+	newViolationRule("type", validation.MsgExpectedInteger, "integer"),
+	newViolationRule("type", validation.MsgExpectedString, "string"),
+	newViolationRule("type", validation.MsgExpectedArray, "array"),
+	newViolationRule("type", validation.MsgExpectedObject, "object"),
+}
+
+// newViolationRule matches format, with its verbs as the arguments.
+func newViolationRule(reason, format, arg string) violationRule {
+	pattern := regexp.QuoteMeta(format)
+	pattern = strings.NewReplacer("%v", "(.*)", "%d", "(.*)", "%s", "(.*)").Replace(pattern)
+	return violationRule{reason: reason, re: regexp.MustCompile("^" + pattern + "$"), arg: arg}
+}
+
+// violationReason returns the reason and arguments of a validation message,
+// or "" for messages no rule recognizes.
+func violationReason(msg string) (string, []any) {
+	for _, r := range violationRules {
+		m := r.re.FindStringSubmatch(msg)
+		if m == nil {
+			continue
+		}
+		if r.arg != "" {
+			return r.reason, []any{r.arg}
+		}
+		args := make([]any, 0, len(m)-1)
+		for _, v := range m[1:] {
+			args = append(args, v)
+		}
+		return r.reason, args
+	}
+	return "", nil
+}
@@ -0,0 +1,95 @@
+package i18n
+
+import (
+	pkgi18n "starter-boilerplate/pkg/i18n"
+
+	"golang.org/x/text/language"
+)
+
+// NewCatalog returns the translations of the error codes (see errs and
+// apperror.CodeForStatus) and of the field violation reasons used by the
+// HTTP and gRPC APIs. English is the language of the original messages.
+func NewCatalog() *pkgi18n.Catalog {
+	return pkgi18n.NewCatalog(language.English, map[language.Tag]pkgi18n.Messages{
+		language.Russian: russian,
+		language.Spanish: spanish,
+	})
+}
+
+var russian = pkgi18n.Messages{
+	Errors: map[string]string{
+		"access_denied":            "доступ запрещён",
+		"not_found":                "не найдено",
+		"invalid_credentials":      "неверный email или пароль",
+		"invalid_token":            "недействительный токен",
+		"email_already_exists":     "пользователь с таким email уже существует",
+		"invalid_profile_update":   "некорректное изменение профиля",
+		"profile_condition_failed": "профиль изменился, условие не выполнено",
+		"bad_request":              "некорректный запрос",
+		"unauthorized":             "требуется аутентификация",
+		"forbidden":                "недостаточно прав",
+		"conflict":                 "конфликт с текущим состоянием ресурса",
+		"unprocessable_entity":     "ошибка валидации",
+		"too_many_requests":        "слишком много запросов, попробуйте позже",
+		"internal_server_error":    "внутренняя ошибка сервера",
+	},
+	Violations: map[string]string{
+		"required":            "отсутствует обязательное поле %v",
+		"unexpected_property": "неизвестное поле",
+		"type":                "ожидается значение типа %v",
+		"format":              "значение должно быть в формате %v",
+		"enum":                "значение должно быть одним из: %v",
+		"pattern":             "значение должно соответствовать шаблону %v",
+		"min_length":          "длина должна быть не меньше %v",
+		"max_length":          "длина должна быть не больше %v",
+		"minimum":             "значение должно быть не меньше %v",
+		"exclusive_minimum":   "значение должно быть больше %v",
+		"maximum":             "значение должно быть не больше %v",
+		"exclusive_maximum":   "значение должно быть меньше %v",
+		"multiple_of":         "значение должно быть кратно %v",
+		"min_items":           "элементов должно быть не меньше %v",
+		"max_items":           "элементов должно быть не больше %v",
+		"unknown_key":         "неизвестный ключ профиля",
+		"read_only":           "ключ недоступен для изменения",
+		"changed_twice":       "ключ изменяется несколькими операциями",
+	},
+}
+
+var spanish = pkgi18n.Messages{
+	Errors: map[string]string{
+		"access_denied":            "acceso denegado",
+		"not_found":                "no encontrado",
+		"invalid_credentials":      "email o contraseña incorrectos",
+		"invalid_token":            "token no válido",
+		"email_already_exists":     "ya existe un usuario con este email",
+		"invalid_profile_update":   "cambio de perfil no válido",
+		"profile_condition_failed": "el perfil ha cambiado, la condición no se cumple",
+		"bad_request":              "solicitud incorrecta",
+		"unauthorized":             "se requiere autenticación",
+		"forbidden":                "permisos insuficientes",
+		"conflict":                 "conflicto con el estado actual del recurso",
+		"unprocessable_entity":     "error de validación",
+		"too_many_requests":        "demasiadas solicitudes, inténtelo más tarde",
+		"internal_server_error":    "error interno del servidor",
+	},
+	Violations: map[string]string{
+		"required":            "falta el campo obligatorio %v",
+		"unexpected_property": "campo desconocido",
+		"type":                "se esperaba un valor de tipo %v",
+		"format":              "el valor debe tener el formato %v",
+		"enum":                "el valor debe ser uno de: %v",
+		"pattern":             "el valor debe coincidir con el patrón %v",
+		"min_length":          "la longitud debe ser al menos %v",
+		"max_length":          "la longitud debe ser como máximo %v",
+		"minimum":             "el valor debe ser al menos %v",
+		"exclusive_minimum":   "el valor debe ser mayor que %v",
+		"maximum":             "el valor debe ser como máximo %v",
+		"exclusive_maximum":   "el valor debe ser menor que %v",
+		"multiple_of":         "el valor debe ser múltiplo de %v",
+		"min_items":           "debe tener al menos %v elementos",
+		"max_items":           "debe tener como máximo %v elementos",
+		"unknown_key":         "clave de perfil desconocida",
+		"read_only":           "la clave no se puede modificar",
+		"changed_twice":       "la clave se modifica en más de una operación",
+	},
+}
@@ -68,6 +68,12 @@ func NewAuthCtx(ctx context.Context) AuthCtx {
 	return &authCtx{Context: ctx}
 }
 
+// WithClaims returns a copy of ctx carrying claims, for transports other than
+// huma (gRPC) that authenticate requests themselves.
+func WithClaims(ctx context.Context, claims *jwt.Claims) context.Context {
+	return context.WithValue(ctx, claimsContextKey{}, claims)
+}
+
 func AuthFromCtx(ctx context.Context) (*jwt.Claims, bool) {
 	claims, ok := ctx.Value(claimsContextKey{}).(*jwt.Claims)
 	return claims, ok && claims != nil
@@ -0,0 +1,152 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+
+	authpb "starter-boilerplate/gen/auth"
+	"starter-boilerplate/pkg/apperror"
+	pkggrpc "starter-boilerplate/pkg/grpc"
+	"starter-boilerplate/pkg/jwt"
+
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// publicServices are infrastructure services callable without credentials.
+var publicServices = map[string]bool{
+	healthpb.Health_ServiceDesc.ServiceName:    true,
+	"grpc.reflection.v1.ServerReflection":      true,
+	"grpc.reflection.v1alpha.ServerReflection": true,
+}
+
+// GRPCAuth is the gRPC counterpart of the auth and role middlewares. It reads
+// the auth.rule option of the called method, authenticates the caller and
+// stores the claims where AuthFromCtx finds them.
+type GRPCAuth struct {
+	jwtManager   *jwt.Manager
+	serviceRoles map[string]string
+	rules        sync.Map // full method → *authpb.Rule
+}
+
+func NewGRPCAuth(jwtManager *jwt.Manager, cfg pkggrpc.GRPCConfig) *GRPCAuth {
+	return &GRPCAuth{jwtManager: jwtManager, serviceRoles: cfg.TLS.ServiceRoles}
+}
+
+func (a *GRPCAuth) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := a.authorize(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func (a *GRPCAuth) Stream() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.authorize(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func (a *GRPCAuth) authorize(ctx context.Context, fullMethod string) (context.Context, error) {
+	rule := a.rule(fullMethod)
+	if rule.GetPublic() {
+		return ctx, nil
+	}
+
+	claims, err := a.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if roles := rule.GetRoles(); len(roles) > 0 && !containsRole(roles, claims.Role) {
+		return nil, apperror.New(http.StatusForbidden, "insufficient permissions")
+	}
+	return WithClaims(ctx, claims), nil
+}
+
+// authenticate prefers a bearer access token. Without one, a verified mTLS
+// client certificate listed in the service roles authenticates as a service:
+// its identity becomes the claims' UserID.
+func (a *GRPCAuth) authenticate(ctx context.Context) (*jwt.Claims, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		if identity, ok := pkggrpc.PeerIdentity(ctx); ok {
+			if role, ok := a.serviceRoles[identity]; ok {
+				return &jwt.Claims{UserID: identity, Role: role}, nil
+			}
+		}
+		return nil, apperror.New(http.StatusUnauthorized, "missing authorization header")
+	}
+
+	token, found := strings.CutPrefix(values[0], "Bearer ")
+	if !found {
+		return nil, apperror.New(http.StatusUnauthorized, "invalid authorization header format")
+	}
+
+	claims, err := a.jwtManager.ValidateAccessToken(token)
+	if err != nil {
+		return nil, apperror.New(http.StatusUnauthorized, "invalid or expired token")
+	}
+	return claims, nil
+}
+
+// rule resolves the auth.rule option of fullMethod ("/pkg.Service/Method")
+// from the global proto registry. Health and reflection are public; other
+// methods without a rule, or unknown to the registry, get an empty rule:
+// authenticated, any role.
+func (a *GRPCAuth) rule(fullMethod string) *authpb.Rule {
+	if r, ok := a.rules.Load(fullMethod); ok {
+		return r.(*authpb.Rule)
+	}
+
+	rule := &authpb.Rule{}
+	service, _, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if publicServices[service] {
+		rule.Public = true
+		a.rules.Store(fullMethod, rule)
+		return rule
+	}
+
+	name := protoreflect.FullName(strings.ReplaceAll(strings.TrimPrefix(fullMethod, "/"), "/", "."))
+	if desc, err := protoregistry.GlobalFiles.FindDescriptorByName(name); err == nil {
+		if method, ok := desc.(protoreflect.MethodDescriptor); ok {
+			if r, ok := proto.GetExtension(method.Options(), authpb.E_Rule).(*authpb.Rule); ok && r != nil {
+				rule = r
+			}
+		}
+	}
+
+	a.rules.Store(fullMethod, rule)
+	return rule
+}
+
+func containsRole(roles []string, role string) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// serverStream overrides the context of a grpc.ServerStream.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,181 @@
+//go:build unit
+
+package middleware
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"testing"
+	"time"
+
+	_ "starter-boilerplate/gen/user"
+	"starter-boilerplate/pkg/apperror"
+	pkggrpc "starter-boilerplate/pkg/grpc"
+	"starter-boilerplate/pkg/jwt"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+const (
+	methodLogin     = "/user.UserContract/Login"
+	methodGetUser   = "/user.UserContract/GetUser"
+	methodListUsers = "/user.UserContract/ListUsers"
+)
+
+func newTestGRPCAuth() (*GRPCAuth, *jwt.Manager) {
+	m := jwt.NewManager(jwt.Config{
+		AccessSecret:  "test-access-secret",
+		RefreshSecret: "test-refresh-secret",
+		AccessTTL:     15 * time.Minute,
+		RefreshTTL:    24 * time.Hour,
+	})
+	cfg := pkggrpc.GRPCConfig{TLS: pkggrpc.TLSConfig{ServiceRoles: map[string]string{"billing": "admin"}}}
+	return NewGRPCAuth(m, cfg), m
+}
+
+func bearerCtx(t *testing.T, m *jwt.Manager, userID, role string) context.Context {
+	token, err := m.GenerateAccessToken(userID, role)
+	require.NoError(t, err)
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+}
+
+func callUnary(a *GRPCAuth, ctx context.Context, method string) (*jwt.Claims, error) {
+	var claims *jwt.Claims
+	handler := func(ctx context.Context, _ any) (any, error) {
+		claims, _ = AuthFromCtx(ctx)
+		return nil, nil
+	}
+	_, err := a.Unary()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+	return claims, err
+}
+
+func requireStatus(t *testing.T, err error, status int, msg string) {
+	t.Helper()
+	var appErr *apperror.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, status, appErr.Status)
+	assert.Equal(t, msg, appErr.Message)
+}
+
+func TestGRPCAuth_PublicMethod(t *testing.T) {
+	a, _ := newTestGRPCAuth()
+
+	claims, err := callUnary(a, context.Background(), methodLogin)
+
+	require.NoError(t, err)
+	assert.Nil(t, claims)
+}
+
+func TestGRPCAuth_MissingToken(t *testing.T) {
+	a, _ := newTestGRPCAuth()
+
+	_, err := callUnary(a, context.Background(), methodGetUser)
+
+	requireStatus(t, err, http.StatusUnauthorized, "missing authorization header")
+}
+
+func TestGRPCAuth_InvalidHeaderFormat(t *testing.T) {
+	a, _ := newTestGRPCAuth()
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Token abc"))
+
+	_, err := callUnary(a, ctx, methodGetUser)
+
+	requireStatus(t, err, http.StatusUnauthorized, "invalid authorization header format")
+}
+
+func TestGRPCAuth_InvalidToken(t *testing.T) {
+	a, _ := newTestGRPCAuth()
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer garbage"))
+
+	_, err := callUnary(a, ctx, methodGetUser)
+
+	requireStatus(t, err, http.StatusUnauthorized, "invalid or expired token")
+}
+
+func TestGRPCAuth_InjectsClaims(t *testing.T) {
+	a, m := newTestGRPCAuth()
+
+	claims, err := callUnary(a, bearerCtx(t, m, "user-1", "user"), methodGetUser)
+
+	require.NoError(t, err)
+	require.NotNil(t, claims)
+	assert.Equal(t, "user-1", claims.UserID)
+	assert.Equal(t, "user", claims.Role)
+}
+
+func TestGRPCAuth_RoleRequired(t *testing.T) {
+	a, m := newTestGRPCAuth()
+
+	_, err := callUnary(a, bearerCtx(t, m, "user-1", "user"), methodListUsers)
+	requireStatus(t, err, http.StatusForbidden, "insufficient permissions")
+
+	claims, err := callUnary(a, bearerCtx(t, m, "admin-1", "admin"), methodListUsers)
+	require.NoError(t, err)
+	assert.Equal(t, "admin-1", claims.UserID)
+}
+
+func TestGRPCAuth_UnknownMethodRequiresAuth(t *testing.T) {
+	a, _ := newTestGRPCAuth()
+
+	_, err := callUnary(a, context.Background(), "/unknown.Service/Method")
+
+	requireStatus(t, err, http.StatusUnauthorized, "missing authorization header")
+}
+
+func mtlsCtx(commonName string) context.Context {
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: commonName}}
+	info := credentials.TLSInfo{State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}}
+	return peer.NewContext(context.Background(), &peer.Peer{AuthInfo: info})
+}
+
+func TestGRPCAuth_ServiceIdentity(t *testing.T) {
+	a, _ := newTestGRPCAuth()
+
+	claims, err := callUnary(a, mtlsCtx("billing"), methodListUsers)
+
+	require.NoError(t, err)
+	assert.Equal(t, "billing", claims.UserID)
+	assert.Equal(t, "admin", claims.Role)
+}
+
+func TestGRPCAuth_UnknownServiceIdentity(t *testing.T) {
+	a, _ := newTestGRPCAuth()
+
+	_, err := callUnary(a, mtlsCtx("stranger"), methodListUsers)
+
+	requireStatus(t, err, http.StatusUnauthorized, "missing authorization header")
+}
+
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testServerStream) Context() context.Context { return s.ctx }
+
+func TestGRPCAuth_Stream(t *testing.T) {
+	a, m := newTestGRPCAuth()
+	info := &grpc.StreamServerInfo{FullMethod: methodGetUser}
+
+	var claims *jwt.Claims
+	handler := func(_ any, ss grpc.ServerStream) error {
+		claims, _ = AuthFromCtx(ss.Context())
+		return nil
+	}
+
+	err := a.Stream()(nil, &testServerStream{ctx: context.Background()}, info, handler)
+	requireStatus(t, err, http.StatusUnauthorized, "missing authorization header")
+
+	err = a.Stream()(nil, &testServerStream{ctx: bearerCtx(t, m, "user-1", "user")}, info, handler)
+	require.NoError(t, err)
+	require.NotNil(t, claims)
+	assert.Equal(t, "user-1", claims.UserID)
+}
@@ -0,0 +1,144 @@
+//go:build unit
+
+package middleware
+
+import (
+	"context"
+	"testing"
+
+	"starter-boilerplate/pkg/apperror"
+	"starter-boilerplate/pkg/i18n"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPCRequestID_FromMetadata(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "req-1"))
+
+	var got string
+	_, err := NewGRPCRequestIDInterceptor()(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, _ any) (any, error) {
+		got = RequestIDFromContext(ctx)
+		return nil, nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "req-1", got)
+}
+
+func TestGRPCRequestID_Generated(t *testing.T) {
+	var got string
+	_, err := NewGRPCRequestIDInterceptor()(context.Background(), nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, _ any) (any, error) {
+		got = RequestIDFromContext(ctx)
+		return nil, nil
+	})
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, got)
+}
+
+type headerStream struct {
+	testServerStream
+	header metadata.MD
+}
+
+func (s *headerStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func TestGRPCStreamRequestID(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "req-2"))
+	ss := &headerStream{testServerStream: testServerStream{ctx: ctx}}
+
+	var got string
+	err := NewGRPCStreamRequestIDInterceptor()(nil, ss, &grpc.StreamServerInfo{}, func(_ any, ss grpc.ServerStream) error {
+		got = RequestIDFromContext(ss.Context())
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "req-2", got)
+	assert.Equal(t, []string{"req-2"}, ss.header.Get("x-request-id"))
+}
+
+func TestGRPCLanguage(t *testing.T) {
+	catalog := i18n.NewCatalog(language.English, map[language.Tag]i18n.Messages{language.Russian: {}})
+
+	tests := []struct {
+		md   metadata.MD
+		want language.Tag
+	}{
+		{metadata.Pairs("grpc-accept-language", "ru-RU, en;q=0.5"), language.Russian},
+		{metadata.Pairs("grpc-accept-language", "fr"), language.English},
+		{metadata.MD{}, language.English},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want.String(), func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+
+			var got language.Tag
+			_, err := NewGRPCLanguageInterceptor(catalog)(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, _ any) (any, error) {
+				got = i18n.FromContext(ctx).Tag()
+				return nil, nil
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGRPCRecover(t *testing.T) {
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "req-1")
+	_, err := newGRPCRecoverInterceptor(apperror.Config{Domain: "test"})(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/test/Method"}, func(context.Context, any) (any, error) {
+		panic("boom")
+	})
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, codes.Internal, st.Code())
+	assert.Equal(t, "internal server error", st.Message())
+
+	var info *errdetails.ErrorInfo
+	var req *errdetails.RequestInfo
+	for _, d := range st.Details() {
+		switch d := d.(type) {
+		case *errdetails.ErrorInfo:
+			info = d
+		case *errdetails.RequestInfo:
+			req = d
+		}
+	}
+	require.NotNil(t, info, "the problem response needs the code")
+	assert.Equal(t, "internal_server_error", info.Reason)
+	assert.Equal(t, "test", info.Domain)
+	require.NotNil(t, req, "the problem response needs the request ID")
+	assert.Equal(t, "req-1", req.RequestId)
+}
+
+func TestGRPCStreamRecover(t *testing.T) {
+	ss := &testServerStream{ctx: context.WithValue(context.Background(), requestIDKey{}, "req-1")}
+
+	err := newGRPCStreamRecoverInterceptor(apperror.Config{})(nil, ss, &grpc.StreamServerInfo{FullMethod: "/test/Stream"}, func(any, grpc.ServerStream) error {
+		panic("boom")
+	})
+
+	st := status.Convert(err)
+	assert.Equal(t, codes.Internal, st.Code())
+	assert.NotEmpty(t, st.Details())
+}
+
+func TestGRPCAuth_HealthIsPublic(t *testing.T) {
+	a, _ := newTestGRPCAuth()
+
+	_, err := callUnary(a, context.Background(), "/grpc.health.v1.Health/Check")
+
+	require.NoError(t, err)
+}
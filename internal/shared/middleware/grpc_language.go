@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+
+	pkggrpc "starter-boilerplate/pkg/grpc"
+	"starter-boilerplate/pkg/i18n"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// NewGRPCLanguageInterceptor is the gRPC counterpart of NewLanguageMiddleware:
+// the language comes from the "grpc-accept-language" metadata, which the
+// gateways fill from Accept-Language.
+func NewGRPCLanguageInterceptor(catalog *i18n.Catalog) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(grpcLocalized(ctx, catalog), req)
+	}
+}
+
+func NewGRPCStreamLanguageInterceptor(catalog *i18n.Catalog) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &serverStream{ServerStream: ss, ctx: grpcLocalized(ss.Context(), catalog)})
+	}
+}
+
+func grpcLocalized(ctx context.Context, catalog *i18n.Catalog) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	tag := catalog.Match(strings.Join(md.Get(pkggrpc.LanguageMetadataKey), ","))
+	return i18n.WithLocalizer(ctx, catalog.Localizer(tag))
+}
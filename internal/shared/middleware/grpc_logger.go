@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func newGRPCLoggerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logGRPCCall(ctx, "grpc request", info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+func newGRPCStreamLoggerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		logGRPCCall(ss.Context(), "grpc stream", info.FullMethod, start, err)
+		return err
+	}
+}
+
+// logGRPCCall logs like the HTTP logger middleware: server-side failures as
+// errors, client-side ones as warnings.
+func logGRPCCall(ctx context.Context, msg, method string, start time.Time, err error) {
+	code := status.Code(err)
+
+	var ip string
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		host, _, splitErr := net.SplitHostPort(p.Addr.String())
+		if splitErr != nil {
+			host = p.Addr.String()
+		}
+		ip = host
+	}
+
+	attrs := []any{
+		slog.String("method", method),
+		slog.String("code", code.String()),
+		slog.Duration("latency", time.Since(start)),
+		slog.String("ip", ip),
+		slog.String("request_id", RequestIDFromContext(ctx)),
+	}
+
+	switch code {
+	case codes.OK:
+		slog.Info(msg, attrs...)
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable, codes.Unimplemented:
+		slog.Error(msg, attrs...)
+	default:
+		slog.Warn(msg, attrs...)
+	}
+}
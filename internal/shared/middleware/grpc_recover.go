@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"starter-boilerplate/pkg/apperror"
+	pkggrpc "starter-boilerplate/pkg/grpc"
+
+	"google.golang.org/grpc"
+)
+
+// newGRPCRecoverInterceptor is the gRPC counterpart of WithRecover. It sits
+// outside the error interceptor, so it builds the same rich status itself.
+func newGRPCRecoverInterceptor(errCfg apperror.Config) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recoveredGRPCPanic(ctx, errCfg, info.FullMethod, r)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+func newGRPCStreamRecoverInterceptor(errCfg apperror.Config) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recoveredGRPCPanic(ss.Context(), errCfg, info.FullMethod, r)
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
+func recoveredGRPCPanic(ctx context.Context, errCfg apperror.Config, method string, r any) error {
+	id := RequestIDFromContext(ctx)
+	slog.Error("panic recovered",
+		slog.Any("error", r),
+		slog.String("method", method),
+		slog.String("request_id", id),
+	)
+	return pkggrpc.AppErrorStatus(ctx, errCfg, id, apperror.New(http.StatusInternalServerError, "")).Err()
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const requestIDMetadataKey = "x-request-id"
+
+// NewGRPCRequestIDInterceptor is the gRPC counterpart of NewRequestIDMiddleware:
+// it takes the request ID from the "x-request-id" metadata or generates one,
+// returns it in the response header and stores it for RequestIDFromContext.
+func NewGRPCRequestIDInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		id := grpcRequestID(ctx)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDMetadataKey, id))
+		return handler(context.WithValue(ctx, requestIDKey{}, id), req)
+	}
+}
+
+func NewGRPCStreamRequestIDInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		id := grpcRequestID(ss.Context())
+		_ = ss.SetHeader(metadata.Pairs(requestIDMetadataKey, id))
+		return handler(srv, &serverStream{ServerStream: ss, ctx: context.WithValue(ss.Context(), requestIDKey{}, id)})
+	}
+}
+
+func grpcRequestID(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if v := md.Get(requestIDMetadataKey); len(v) > 0 && v[0] != "" {
+		return v[0]
+	}
+	return uuid.New().String()
+}
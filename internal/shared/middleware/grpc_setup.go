@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"starter-boilerplate/pkg/apperror"
+	pkggrpc "starter-boilerplate/pkg/grpc"
+	"starter-boilerplate/pkg/i18n"
+
+	"google.golang.org/grpc"
+)
+
+// NewGRPCInterceptors returns the gRPC server's interceptor chain, mirroring
+// the HTTP middleware order (outermost first): request ID, language, access
+// log, panic recovery, AppError → status mapping, then authentication and
+// roles.
+func NewGRPCInterceptors(auth *GRPCAuth, errCfg apperror.Config, catalog *i18n.Catalog) pkggrpc.Interceptors {
+	return pkggrpc.Interceptors{
+		Unary: []grpc.UnaryServerInterceptor{
+			NewGRPCRequestIDInterceptor(),
+			NewGRPCLanguageInterceptor(catalog),
+			newGRPCLoggerInterceptor(),
+			newGRPCRecoverInterceptor(errCfg),
+			pkggrpc.ErrorInterceptor(errCfg, RequestIDFromContext),
+			auth.Unary(),
+		},
+		Stream: []grpc.StreamServerInterceptor{
+			NewGRPCStreamRequestIDInterceptor(),
+			NewGRPCStreamLanguageInterceptor(catalog),
+			newGRPCStreamLoggerInterceptor(),
+			newGRPCStreamRecoverInterceptor(errCfg),
+			pkggrpc.StreamErrorInterceptor(errCfg, RequestIDFromContext),
+			auth.Stream(),
+		},
+	}
+}
@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"starter-boilerplate/pkg/i18n"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// NewLanguageMiddleware negotiates the language of the request from
+// Accept-Language among the catalog's, announces it as Content-Language and
+// stores its localizer for i18n.FromContext.
+func NewLanguageMiddleware(catalog *i18n.Catalog) func(huma.Context, func(huma.Context)) {
+	return func(ctx huma.Context, next func(huma.Context)) {
+		tag := catalog.Match(ctx.Header("Accept-Language"))
+		ctx.SetHeader("Content-Language", tag.String())
+		next(huma.WithContext(ctx, i18n.WithLocalizer(ctx.Context(), catalog.Localizer(tag))))
+	}
+}
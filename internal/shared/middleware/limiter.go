@@ -22,7 +22,7 @@ type rateLimiter struct {
 	window   time.Duration
 }
 
-func NewLimiterMiddleware(max int, window time.Duration) func(huma.Context, func(huma.Context)) {
+func NewLimiterMiddleware(api huma.API, max int, window time.Duration) func(huma.Context, func(huma.Context)) {
 	rl := &rateLimiter{
 		visitors: make(map[string]*visitor),
 		max:      max,
@@ -60,9 +60,7 @@ func NewLimiterMiddleware(max int, window time.Duration) func(huma.Context, func
 		if count > rl.max {
 			retryAfter := int(time.Until(resetAt).Seconds()) + 1
 			ctx.SetHeader("Retry-After", strconv.Itoa(retryAfter))
-			ctx.SetHeader("Content-Type", "application/json")
-			ctx.SetStatus(http.StatusTooManyRequests)
-			_, _ = ctx.BodyWriter().Write([]byte(`{"title":"Too Many Requests","status":429}`))
+			_ = huma.WriteErr(api, ctx, http.StatusTooManyRequests, "rate limit exceeded")
 			return
 		}
 
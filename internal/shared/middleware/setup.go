@@ -1,10 +1,13 @@
 package middleware
 
 import (
+	"encoding/json"
 	"log/slog"
 	"net/http"
 	"time"
 
+	"starter-boilerplate/pkg/apperror"
+	"starter-boilerplate/pkg/i18n"
 	"starter-boilerplate/pkg/jwt"
 
 	"github.com/danielgtaylor/huma/v2"
@@ -12,16 +15,17 @@ import (
 
 type Init struct{}
 
-func Setup(srv *http.Server, api huma.API, jwtManager *jwt.Manager) Init {
+func Setup(srv *http.Server, api huma.API, jwtManager *jwt.Manager, errCfg apperror.Config, catalog *i18n.Catalog) Init {
 	// Huma-level middleware (order: outermost first)
 	api.UseMiddleware(NewRequestIDMiddleware())
+	api.UseMiddleware(NewLanguageMiddleware(catalog))
 	api.UseMiddleware(newLoggerMiddleware())
-	api.UseMiddleware(NewLimiterMiddleware(100, time.Minute))
+	api.UseMiddleware(NewLimiterMiddleware(api, 100, time.Minute))
 	api.UseMiddleware(NewAuthMiddleware(api, jwtManager))
 	api.UseMiddleware(NewRoleMiddleware(api))
 
 	// HTTP-level middleware
-	srv.Handler = WithCORS(WithRecover(srv.Handler))
+	srv.Handler = WithCORS(WithRecover(srv.Handler, errCfg))
 
 	slog.Info("middlewares installed")
 
@@ -32,7 +36,8 @@ func WithCORS(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, PATCH, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Request-Id, Connect-Protocol-Version, Connect-Timeout-Ms, Grpc-Timeout, X-Grpc-Web, X-User-Agent")
+		w.Header().Set("Access-Control-Expose-Headers", "X-Request-Id, Grpc-Status, Grpc-Message, Grpc-Status-Details-Bin")
 		if r.Method == http.MethodOptions {
 			w.WriteHeader(http.StatusNoContent)
 			return
@@ -41,7 +46,9 @@ func WithCORS(next http.Handler) http.Handler {
 	})
 }
 
-func WithRecover(next http.Handler) http.Handler {
+// WithRecover answers panics with a 500 problem; the request ID is the one
+// the request ID middleware already set on the response, if any.
+func WithRecover(next http.Handler, errCfg apperror.Config) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
 			if err := recover(); err != nil {
@@ -50,9 +57,10 @@ func WithRecover(next http.Handler) http.Handler {
 					slog.String("method", r.Method),
 					slog.String("path", r.URL.Path),
 				)
-				w.Header().Set("Content-Type", "application/json")
+				p := apperror.New(http.StatusInternalServerError, "").Problem(errCfg, w.Header().Get("X-Request-Id"))
+				w.Header().Set("Content-Type", "application/problem+json")
 				w.WriteHeader(http.StatusInternalServerError)
-				_, _ = w.Write([]byte(`{"title":"Internal Server Error","status":500}`))
+				_ = json.NewEncoder(w).Encode(p)
 			}
 		}()
 		next.ServeHTTP(w, r)
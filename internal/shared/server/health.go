@@ -0,0 +1,26 @@
+package server
+
+import (
+	"context"
+
+	pkgamqp "starter-boilerplate/pkg/amqp"
+	pkggrpc "starter-boilerplate/pkg/grpc"
+
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/uptrace/bun"
+)
+
+// NewHealthChecks returns the readiness checks of the gRPC health service.
+// Connections skipped in standalone mode are not checked.
+func NewHealthChecks(db *bun.DB, redis *goredis.Client, broker *pkgamqp.Broker) pkggrpc.HealthChecks {
+	checks := pkggrpc.HealthChecks{
+		"amqp": func(context.Context) error { return broker.Ping() },
+	}
+	if db != nil {
+		checks["postgres"] = db.PingContext
+	}
+	if redis != nil {
+		checks["redis"] = func(ctx context.Context) error { return redis.Ping(ctx).Err() }
+	}
+	return checks
+}
@@ -0,0 +1,17 @@
+package internal
+
+import (
+	userconsumer "starter-boilerplate/internal/user/transport/consumer"
+	pkgamqp "starter-boilerplate/pkg/amqp"
+	"starter-boilerplate/pkg/event"
+)
+
+// Topology returns the complete AMQP topology of the application: shared event
+// exchanges plus the queues and bindings of every subdomain's consumers.
+// A new subdomain with consumers adds its Topology here.
+func Topology() pkgamqp.Topology {
+	return pkgamqp.MergeTopologies(
+		event.Topology(),
+		userconsumer.Topology(),
+	)
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	pkgevent "starter-boilerplate/pkg/event"
+	"starter-boilerplate/pkg/outbox"
+)
+
+// LiveOnly is the position to pass to Watch to skip the replay.
+const LiveOnly = -1
+
+const (
+	// userEventPrefix selects the user domain events (user.created, ...).
+	userEventPrefix = "user."
+	replayPageSize  = 100
+)
+
+// ErrPositionExpired is returned by Watch for a position older than the
+// outbox retention: events after it may have been deleted, so the caller
+// must resync its state and watch from the current position.
+var ErrPositionExpired = errors.New("event stream: position no longer in the outbox")
+
+// eventLog is the part of the outbox repository used to replay events.
+type eventLog interface {
+	ListPublishedAfter(ctx context.Context, after int64, namePrefix, partitionKey string, limit int) ([]outbox.Entry, error)
+	OldestPosition(ctx context.Context) (int64, error)
+}
+
+// EventStream streams user domain events: published ones replayed from the
+// outbox, then live ones from the hub fed by the watch consumer.
+type EventStream struct {
+	hub *pkgevent.Hub
+	log eventLog
+}
+
+func NewEventStream(hub *pkgevent.Hub, outboxRepo *outbox.Repository) *EventStream {
+	return &EventStream{hub: hub, log: outboxRepo}
+}
+
+// Watch calls send with the events of userID (every user if empty) until ctx
+// is done or send fails. Unless after is LiveOnly, published events after that
+// position are replayed first; 0 replays the whole outbox, and a position
+// the outbox no longer reaches back to fails with ErrPositionExpired. It
+// returns pkgevent.ErrSlowSubscriber when the caller consumes slower than
+// events arrive, and pkgevent.ErrHubClosed on shutdown; either way the caller
+// may resume from the last position it received.
+func (s *EventStream) Watch(ctx context.Context, userID string, after int64, send func(pkgevent.Envelope) error) error {
+	// Subscribe before replaying, so that events published meanwhile are
+	// buffered rather than lost.
+	sub := s.hub.Subscribe(func(e pkgevent.Envelope) bool {
+		return userID == "" || e.Key == userID
+	})
+	defer sub.Close()
+
+	replayed := after
+	if after != LiveOnly {
+		var err error
+		if replayed, err = s.replay(ctx, userID, after, send); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-sub.Done():
+			return sub.Err()
+		case e := <-sub.Events():
+			if e.Position != 0 && e.Position <= replayed {
+				continue // already sent by the replay
+			}
+			if err := send(e); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// replay sends the published events after position and returns the last
+// position sent. Positions grow in the order entries are published, so
+// live events up to it were replayed.
+func (s *EventStream) replay(ctx context.Context, userID string, after int64, send func(pkgevent.Envelope) error) (int64, error) {
+	if after > 0 {
+		// Cleanup deletes the lowest positions first, so only positions
+		// below the oldest one left can be missing.
+		oldest, err := s.log.OldestPosition(ctx)
+		if err != nil {
+			return after, err
+		}
+		if after < oldest-1 {
+			return after, ErrPositionExpired
+		}
+	}
+	for {
+		entries, err := s.log.ListPublishedAfter(ctx, after, userEventPrefix, userID, replayPageSize)
+		if err != nil {
+			return after, err
+		}
+		for _, entry := range entries {
+			key, _ := entry.Headers[outbox.HeaderPartitionKey].(string)
+			if err := send(pkgevent.Envelope{Position: entry.Position, Name: entry.EventName, Key: key, Payload: entry.Payload}); err != nil {
+				return after, err
+			}
+			after = entry.Position
+		}
+		if len(entries) < replayPageSize {
+			return after, nil
+		}
+	}
+}
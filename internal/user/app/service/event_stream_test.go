@@ -0,0 +1,224 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	pkgevent "starter-boilerplate/pkg/event"
+	"starter-boilerplate/pkg/outbox"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEventLog serves ListPublishedAfter from entries ordered by position.
+type fakeEventLog struct {
+	entries []outbox.Entry
+	oldest  int64
+	calls   int
+}
+
+func (f *fakeEventLog) ListPublishedAfter(_ context.Context, after int64, namePrefix, partitionKey string, limit int) ([]outbox.Entry, error) {
+	f.calls++
+	var out []outbox.Entry
+	for _, e := range f.entries {
+		if e.Position <= after || len(e.EventName) < len(namePrefix) || e.EventName[:len(namePrefix)] != namePrefix {
+			continue
+		}
+		if partitionKey != "" && e.Headers[outbox.HeaderPartitionKey] != partitionKey {
+			continue
+		}
+		if out = append(out, e); len(out) == limit {
+			break
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeEventLog) OldestPosition(context.Context) (int64, error) {
+	return f.oldest, nil
+}
+
+func logEntry(position int64, userID string) outbox.Entry {
+	return outbox.Entry{
+		ID:        position,
+		Position:  position,
+		EventName: "user.logged_in",
+		Payload:   []byte(fmt.Sprintf(`{"user_id":%q}`, userID)),
+		Headers:   map[string]any{outbox.HeaderPartitionKey: userID},
+	}
+}
+
+// collect runs Watch in the background and returns the received positions.
+func collect(t *testing.T, es *EventStream, userID string, after int64) (positions chan int64, done chan error, cancel context.CancelFunc) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	positions = make(chan int64, 1000)
+	done = make(chan error, 1)
+	go func() {
+		done <- es.Watch(ctx, userID, after, func(e pkgevent.Envelope) error {
+			positions <- e.Position
+			return nil
+		})
+	}()
+	return positions, done, cancel
+}
+
+func next(t *testing.T, positions chan int64) int64 {
+	t.Helper()
+	select {
+	case p := <-positions:
+		return p
+	case <-time.After(time.Second):
+		t.Fatal("no event received")
+		return 0
+	}
+}
+
+// publishUntilReceived publishes e until the watcher, which may not have
+// subscribed yet, receives it.
+func publishUntilReceived(t *testing.T, hub *pkgevent.Hub, positions chan int64, e pkgevent.Envelope) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		hub.Publish(e)
+		select {
+		case p := <-positions:
+			return p == e.Position
+		default:
+			return false
+		}
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestEventStream_ReplaysThenStreamsLive(t *testing.T) {
+	hub := pkgevent.NewHub()
+	log := &fakeEventLog{entries: []outbox.Entry{logEntry(1, "u1"), logEntry(2, "u2"), logEntry(3, "u1"), logEntry(4, "u1")}}
+	es := &EventStream{hub: hub, log: log}
+
+	positions, done, cancel := collect(t, es, "u1", 1)
+
+	assert.Equal(t, int64(3), next(t, positions))
+	assert.Equal(t, int64(4), next(t, positions))
+
+	// Live duplicates of replayed events are skipped.
+	hub.Publish(pkgevent.Envelope{Position: 4, Key: "u1"})
+	hub.Publish(pkgevent.Envelope{Position: 5, Key: "u2"})
+	hub.Publish(pkgevent.Envelope{Position: 6, Key: "u1"})
+	assert.Equal(t, int64(6), next(t, positions))
+
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestEventStream_ReplaysInPositionOrder(t *testing.T) {
+	// Entries are published, and get their positions, out of ID order.
+	late, early := logEntry(1, "u1"), logEntry(2, "u1")
+	late.ID, early.ID = 8, 5
+	es := &EventStream{hub: pkgevent.NewHub(), log: &fakeEventLog{entries: []outbox.Entry{late, early}}}
+
+	positions, done, cancel := collect(t, es, "u1", 0)
+
+	assert.Equal(t, int64(1), next(t, positions))
+	assert.Equal(t, int64(2), next(t, positions))
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestEventStream_PositionExpired(t *testing.T) {
+	log := &fakeEventLog{entries: []outbox.Entry{logEntry(10, "u1"), logEntry(11, "u1")}, oldest: 10}
+	es := &EventStream{hub: pkgevent.NewHub(), log: log}
+
+	err := es.Watch(context.Background(), "u1", 8, func(pkgevent.Envelope) error { return nil })
+	require.ErrorIs(t, err, ErrPositionExpired)
+	assert.Zero(t, log.calls)
+
+	// Nothing can be missing right before the oldest position, and 0
+	// replays whatever is left.
+	for _, after := range []int64{9, 0} {
+		positions, done, cancel := collect(t, es, "u1", after)
+		assert.Equal(t, int64(10), next(t, positions))
+		cancel()
+		require.NoError(t, <-done)
+	}
+}
+
+func TestEventStream_ReplayPages(t *testing.T) {
+	log := &fakeEventLog{}
+	for i := range replayPageSize + 5 {
+		log.entries = append(log.entries, logEntry(int64(i+1), "u1"))
+	}
+	es := &EventStream{hub: pkgevent.NewHub(), log: log}
+
+	positions, done, cancel := collect(t, es, "", 0)
+
+	for want := int64(1); want <= replayPageSize+5; want++ {
+		require.Equal(t, want, next(t, positions))
+	}
+	cancel()
+	require.NoError(t, <-done)
+	assert.Equal(t, 2, log.calls)
+}
+
+func TestEventStream_NoReplayWithoutPosition(t *testing.T) {
+	hub := pkgevent.NewHub()
+	log := &fakeEventLog{entries: []outbox.Entry{logEntry(1, "u1")}}
+	es := &EventStream{hub: hub, log: log}
+
+	positions, done, cancel := collect(t, es, "", LiveOnly)
+	publishUntilReceived(t, hub, positions, pkgevent.Envelope{Position: 9, Key: "u1"})
+
+	cancel()
+	require.NoError(t, <-done)
+	assert.Zero(t, log.calls)
+}
+
+func TestEventStream_SendErrorStops(t *testing.T) {
+	es := &EventStream{hub: pkgevent.NewHub(), log: &fakeEventLog{entries: []outbox.Entry{logEntry(2, "u1"), logEntry(3, "u1")}}}
+	sendErr := errors.New("stream closed")
+
+	calls := 0
+	err := es.Watch(context.Background(), "u1", 1, func(pkgevent.Envelope) error {
+		calls++
+		return sendErr
+	})
+
+	require.ErrorIs(t, err, sendErr)
+	assert.Equal(t, 1, calls)
+}
+
+func TestEventStream_SlowWatcher(t *testing.T) {
+	hub := pkgevent.NewHub()
+	es := &EventStream{hub: hub, log: &fakeEventLog{}}
+
+	unblock := make(chan struct{})
+	positions := make(chan int64, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- es.Watch(context.Background(), "", LiveOnly, func(e pkgevent.Envelope) error {
+			select {
+			case positions <- e.Position:
+			default:
+			}
+			<-unblock
+			return nil
+		})
+	}()
+
+	publishUntilReceived(t, hub, positions, pkgevent.Envelope{Position: 1})
+	for i := range 1000 {
+		hub.Publish(pkgevent.Envelope{Position: int64(i + 2)})
+	}
+	close(unblock)
+
+	select {
+	case err := <-done:
+		require.ErrorIs(t, err, pkgevent.ErrSlowSubscriber)
+	case <-time.After(time.Second):
+		t.Fatal("watch did not end")
+	}
+}
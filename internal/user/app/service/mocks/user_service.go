@@ -44,3 +44,11 @@ func (m *UserService) HashPassword(password string) (string, error) {
 func (m *UserService) UpdatePassword(ctx context.Context, id, hash string) error {
 	return m.Called(ctx, id, hash).Error(0)
 }
+
+func (m *UserService) List(ctx context.Context, limit, offset int) ([]*model.User, int, error) {
+	args := m.Called(ctx, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*model.User), args.Int(1), args.Error(2)
+}
@@ -2,32 +2,134 @@ package service
 
 import (
 	"context"
+	"errors"
+	"time"
 
+	"starter-boilerplate/internal/shared/errs"
 	domainevent "starter-boilerplate/internal/user/domain/event"
 	"starter-boilerplate/internal/user/domain/model"
 	"starter-boilerplate/internal/user/domain/repository"
 	pkgamqp "starter-boilerplate/pkg/amqp"
+	pkgdb "starter-boilerplate/pkg/db"
+	"starter-boilerplate/pkg/outbox"
 )
 
 type ProfileService struct {
 	profileRepo repository.ProfileRepository
+	bus         outbox.Bus
+	uow         pkgdb.UoW
 }
 
-func NewProfileService(pr repository.ProfileRepository) *ProfileService {
-	return &ProfileService{profileRepo: pr}
+func NewProfileService(pr repository.ProfileRepository, bus outbox.Bus, uow pkgdb.UoW) *ProfileService {
+	return &ProfileService{profileRepo: pr, bus: bus, uow: uow}
 }
 
-func (s *ProfileService) OnUserCreated(ctx context.Context, evt domainevent.UserCreatedEvent, _ pkgamqp.DeliveryMeta) error {
-	return s.profileRepo.Upsert(ctx, &model.Profile{
-		UserID:  evt.UserID,
-		Numbers: map[string]float64{},
-		Strings: map[string]string{},
+func (s *ProfileService) FindByUserID(ctx context.Context, userID string) (*model.Profile, error) {
+	return s.profileRepo.FindByUserID(ctx, userID)
+}
+
+// Update checks upd against model.ProfileSchema for writer, applies it and
+// publishes ProfileUpdatedEvent in one transaction, and returns the updated
+// profile. A failed condition of upd (see ProfileUpdate.CompareAndSet) gives
+// ErrProfileConditionFailed and changes nothing. The event goes to the user,
+// so it carries the owner's view of the profile, and is not published when
+// only internal keys changed.
+func (s *ProfileService) Update(ctx context.Context, userID string, upd *model.ProfileUpdate, writer model.ProfileWriter) (*model.Profile, error) {
+	if err := checkProfileUpdate(upd, writer); err != nil {
+		return nil, err
+	}
+
+	var p *model.Profile
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		applied, err := s.profileRepo.Update(ctx, userID, upd)
+		if err != nil {
+			return err
+		}
+		p, err = s.profileRepo.FindByUserID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if p == nil {
+			return errs.ErrNotFound
+		}
+		if !applied {
+			return errs.ErrProfileConditionFailed
+		}
+		keys := model.VisibleKeys(upd.Keys(), model.ProfileVisibilityOwner)
+		if len(keys) == 0 {
+			return nil
+		}
+		return s.bus.Publish(ctx, domainevent.NewProfileUpdatedEvent(p.View(model.ProfileVisibilityOwner), keys))
 	})
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// checkProfileUpdate rejects empty updates and violations of the schema:
+// unknown keys, kind mismatches and broken bounds with
+// ErrInvalidProfileUpdate, keys only other writers may change with
+// ErrAccessDenied.
+func checkProfileUpdate(upd *model.ProfileUpdate, writer model.ProfileWriter) error {
+	if upd.IsEmpty() {
+		return errs.ErrInvalidProfileUpdate.WithMessage("at least one profile operation is required")
+	}
+	vs := upd.Violations(writer)
+	if len(vs) == 0 {
+		return nil
+	}
+
+	err := errs.ErrAccessDenied
+	for _, v := range vs {
+		if v.Reason != model.ProfileReadOnly {
+			err = errs.ErrInvalidProfileUpdate
+			break
+		}
+	}
+	for _, v := range vs {
+		var args []any
+		switch {
+		case v.Reason == model.ProfileWrongKind:
+			args = []any{string(v.Kind)}
+		case v.Limit != nil:
+			args = []any{v.Limit}
+		}
+		err = err.WithField(v.Key, v.Reason, v.Message(), args...)
+	}
+	return err
+}
+
+func (s *ProfileService) OnUserCreated(ctx context.Context, evt domainevent.UserCreatedEvent, _ pkgamqp.DeliveryMeta) error {
+	return s.profileRepo.Upsert(ctx, model.NewProfile(evt.UserID))
+}
+
+func (s *ProfileService) OnUserLoggedIn(ctx context.Context, evt domainevent.UserLoggedInEvent, _ pkgamqp.DeliveryMeta) error {
+	upd := model.NewProfileUpdate().
+		IncrNumber("logins", 1)
+
+	return s.serverUpdate(ctx, evt.UserID, upd)
 }
 
 func (s *ProfileService) OnPasswordChanged(ctx context.Context, evt domainevent.PasswordChangedEvent, _ pkgamqp.DeliveryMeta) error {
+	changedAt := evt.ChangedAt
+	if changedAt.IsZero() {
+		// Published before the event carried the time.
+		changedAt = time.Now()
+	}
 	upd := model.NewProfileUpdate().
-		IncrNumber("password_changes", 1)
+		IncrNumber("password_changes", 1).
+		SetTime("password_changed_at", changedAt)
+
+	return s.serverUpdate(ctx, evt.UserID, upd)
+}
 
-	return s.profileRepo.Update(ctx, evt.UserID, upd)
+// serverUpdate applies upd for an event handler.
+func (s *ProfileService) serverUpdate(ctx context.Context, userID string, upd *model.ProfileUpdate) error {
+	_, err := s.Update(ctx, userID, upd, model.ProfileWriterServer)
+	if errors.Is(err, errs.ErrNotFound) {
+		// The profile is created by OnUserCreated; nothing to count yet.
+		return nil
+	}
+	return err
 }
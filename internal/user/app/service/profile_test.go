@@ -4,28 +4,46 @@ package service
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"net/http"
 	"testing"
+	"time"
 
-	pkgamqp "starter-boilerplate/pkg/amqp"
-
+	"starter-boilerplate/internal/shared/errs"
 	domainevent "starter-boilerplate/internal/user/domain/event"
 	"starter-boilerplate/internal/user/domain/model"
 	repomocks "starter-boilerplate/internal/user/domain/repository/mocks"
+	pkgamqp "starter-boilerplate/pkg/amqp"
+	"starter-boilerplate/pkg/apperror"
+	"starter-boilerplate/pkg/outbox"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
+type mockBus struct {
+	mock.Mock
+}
+
+func (m *mockBus) Publish(ctx context.Context, event outbox.Event) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+// passUoW runs fn without a transaction.
+type passUoW struct{}
+
+func (passUoW) Do(ctx context.Context, fn func(ctx context.Context) error, _ ...*sql.TxOptions) error {
+	return fn(ctx)
+}
+
 func TestProfileService_OnUserCreated(t *testing.T) {
 	repo := new(repomocks.ProfileRepository)
-	svc := NewProfileService(repo)
+	svc := NewProfileService(repo, new(mockBus), passUoW{})
 
-	repo.On("Upsert", mock.Anything, &model.Profile{
-		UserID:  "user-1",
-		Numbers: map[string]float64{},
-		Strings: map[string]string{},
-	}).Return(nil)
+	repo.On("Upsert", mock.Anything, model.NewProfile("user-1")).Return(nil)
 
 	err := svc.OnUserCreated(context.Background(), domainevent.UserCreatedEvent{
 		UserID: "user-1",
@@ -38,7 +56,7 @@ func TestProfileService_OnUserCreated(t *testing.T) {
 
 func TestProfileService_OnUserCreated_RepoError(t *testing.T) {
 	repo := new(repomocks.ProfileRepository)
-	svc := NewProfileService(repo)
+	svc := NewProfileService(repo, new(mockBus), passUoW{})
 
 	repo.On("Upsert", mock.Anything, mock.Anything).Return(errors.New("db error"))
 
@@ -53,25 +71,92 @@ func TestProfileService_OnUserCreated_RepoError(t *testing.T) {
 
 func TestProfileService_OnPasswordChanged(t *testing.T) {
 	repo := new(repomocks.ProfileRepository)
-	svc := NewProfileService(repo)
+	bus := new(mockBus)
+	svc := NewProfileService(repo, bus, passUoW{})
 
-	expectedUpd := model.NewProfileUpdate().IncrNumber("password_changes", 1)
+	changedAt := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	expectedUpd := model.NewProfileUpdate().
+		IncrNumber("password_changes", 1).
+		SetTime("password_changed_at", changedAt)
+	updated := &model.Profile{
+		UserID:  "user-1",
+		Numbers: map[string]float64{"password_changes": 1},
+		Times:   map[string]time.Time{"password_changed_at": changedAt},
+	}
 
-	repo.On("Update", mock.Anything, "user-1", expectedUpd).Return(nil)
+	repo.On("Update", mock.Anything, "user-1", expectedUpd).Return(true, nil)
+	repo.On("FindByUserID", mock.Anything, "user-1").Return(updated, nil)
 
 	err := svc.OnPasswordChanged(context.Background(), domainevent.PasswordChangedEvent{
+		UserID:    "user-1",
+		ChangedAt: changedAt,
+	}, pkgamqp.DeliveryMeta{})
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+	// password_changes and password_changed_at are internal: nothing to
+	// tell the user.
+	bus.AssertNotCalled(t, "Publish")
+}
+
+func TestProfileService_OnUserLoggedIn(t *testing.T) {
+	repo := new(repomocks.ProfileRepository)
+	bus := new(mockBus)
+	svc := NewProfileService(repo, bus, passUoW{})
+
+	expectedUpd := model.NewProfileUpdate().IncrNumber("logins", 1)
+	updated := &model.Profile{UserID: "user-1", Numbers: map[string]float64{"logins": 4}}
+
+	repo.On("Update", mock.Anything, "user-1", expectedUpd).Return(true, nil)
+	repo.On("FindByUserID", mock.Anything, "user-1").Return(updated, nil)
+	bus.On("Publish", mock.Anything, mock.MatchedBy(func(e domainevent.ProfileUpdatedEvent) bool {
+		return e.Keys[0] == "logins" && e.Numbers["logins"] == 4
+	})).Return(nil)
+
+	err := svc.OnUserLoggedIn(context.Background(), domainevent.UserLoggedInEvent{
 		UserID: "user-1",
 	}, pkgamqp.DeliveryMeta{})
 
 	assert.NoError(t, err)
 	repo.AssertExpectations(t)
+	bus.AssertExpectations(t)
+}
+
+func TestProfileService_OnUserLoggedIn_NoProfile(t *testing.T) {
+	repo := new(repomocks.ProfileRepository)
+	svc := NewProfileService(repo, new(mockBus), passUoW{})
+
+	repo.On("Update", mock.Anything, "user-1", mock.Anything).Return(false, nil)
+	repo.On("FindByUserID", mock.Anything, "user-1").Return(nil, nil)
+
+	err := svc.OnUserLoggedIn(context.Background(), domainevent.UserLoggedInEvent{
+		UserID: "user-1",
+	}, pkgamqp.DeliveryMeta{})
+
+	assert.NoError(t, err)
+}
+
+func TestProfileService_OnPasswordChanged_NoProfile(t *testing.T) {
+	repo := new(repomocks.ProfileRepository)
+	bus := new(mockBus)
+	svc := NewProfileService(repo, bus, passUoW{})
+
+	repo.On("Update", mock.Anything, "user-1", mock.Anything).Return(false, nil)
+	repo.On("FindByUserID", mock.Anything, "user-1").Return(nil, nil)
+
+	err := svc.OnPasswordChanged(context.Background(), domainevent.PasswordChangedEvent{
+		UserID: "user-1",
+	}, pkgamqp.DeliveryMeta{})
+
+	assert.NoError(t, err)
+	bus.AssertNotCalled(t, "Publish")
 }
 
 func TestProfileService_OnPasswordChanged_RepoError(t *testing.T) {
 	repo := new(repomocks.ProfileRepository)
-	svc := NewProfileService(repo)
+	svc := NewProfileService(repo, new(mockBus), passUoW{})
 
-	repo.On("Update", mock.Anything, "user-1", mock.Anything).Return(errors.New("update failed"))
+	repo.On("Update", mock.Anything, "user-1", mock.Anything).Return(false, errors.New("update failed"))
 
 	err := svc.OnPasswordChanged(context.Background(), domainevent.PasswordChangedEvent{
 		UserID: "user-1",
@@ -80,3 +165,119 @@ func TestProfileService_OnPasswordChanged_RepoError(t *testing.T) {
 	assert.EqualError(t, err, "update failed")
 	repo.AssertExpectations(t)
 }
+
+func TestProfileService_Update_Empty(t *testing.T) {
+	repo := new(repomocks.ProfileRepository)
+	svc := NewProfileService(repo, new(mockBus), passUoW{})
+
+	_, err := svc.Update(context.Background(), "user-1", model.NewProfileUpdate(), model.ProfileWriterOwner)
+
+	assert.ErrorIs(t, err, errs.ErrInvalidProfileUpdate)
+	repo.AssertNotCalled(t, "Update")
+}
+
+func TestProfileService_Update_InvalidKeys(t *testing.T) {
+	repo := new(repomocks.ProfileRepository)
+	svc := NewProfileService(repo, new(mockBus), passUoW{})
+
+	upd := model.NewProfileUpdate().SetNumber("karma", 1).SetNumber("nickname", 1).IncrNumber("logins", 1)
+	_, err := svc.Update(context.Background(), "user-1", upd, model.ProfileWriterOwner)
+
+	var appErr *apperror.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusBadRequest, appErr.Status)
+	assert.Equal(t, "invalid_profile_update", appErr.Code)
+	var reasons []string
+	for _, v := range appErr.Details {
+		reasons = append(reasons, v.Field+":"+v.Reason)
+	}
+	assert.Equal(t, []string{"karma:unknown_key", "logins:read_only", "nickname:type"}, reasons)
+	assert.Equal(t, []any{"string"}, appErr.Details[2].Args())
+	repo.AssertNotCalled(t, "Update")
+}
+
+func TestProfileService_Update_Bounds(t *testing.T) {
+	repo := new(repomocks.ProfileRepository)
+	svc := NewProfileService(repo, new(mockBus), passUoW{})
+
+	upd := model.NewProfileUpdate().SetNumber("reputation", -1).SetString("settings.theme", "pink")
+	_, err := svc.Update(context.Background(), "user-1", upd, model.ProfileWriterAdmin)
+
+	var appErr *apperror.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, "invalid_profile_update", appErr.Code)
+	require.Len(t, appErr.Details, 2)
+	assert.Equal(t, "minimum", appErr.Details[0].Reason)
+	assert.Equal(t, []any{float64(0)}, appErr.Details[0].Args())
+	assert.Equal(t, "enum", appErr.Details[1].Reason)
+	assert.Equal(t, []any{"system, light, dark"}, appErr.Details[1].Args())
+	repo.AssertNotCalled(t, "Update")
+}
+
+func TestProfileService_Update_PublishesOwnerView(t *testing.T) {
+	repo := new(repomocks.ProfileRepository)
+	bus := new(mockBus)
+	svc := NewProfileService(repo, bus, passUoW{})
+
+	upd := model.NewProfileUpdate().IncrNumber("reputation", 5).IncrNumber("password_changes", 1)
+	updated := &model.Profile{UserID: "user-1", Numbers: map[string]float64{"reputation": 5, "password_changes": 1}}
+	repo.On("Update", mock.Anything, "user-1", upd).Return(true, nil)
+	repo.On("FindByUserID", mock.Anything, "user-1").Return(updated, nil)
+	bus.On("Publish", mock.Anything, domainevent.ProfileUpdatedEvent{
+		UserID:  "user-1",
+		Keys:    []string{"reputation"},
+		Numbers: map[string]float64{"reputation": 5},
+		Strings: map[string]string{},
+		Bools:   map[string]bool{},
+		Times:   map[string]time.Time{},
+		Tags:    map[string][]string{},
+	}).Return(nil)
+
+	p, err := svc.Update(context.Background(), "user-1", upd, model.ProfileWriterServer)
+
+	require.NoError(t, err)
+	assert.Equal(t, updated, p)
+	bus.AssertExpectations(t)
+}
+
+func TestProfileService_Update_ReadOnly(t *testing.T) {
+	repo := new(repomocks.ProfileRepository)
+	svc := NewProfileService(repo, new(mockBus), passUoW{})
+
+	_, err := svc.Update(context.Background(), "user-1", model.NewProfileUpdate().SetString("tier", "pro"), model.ProfileWriterOwner)
+
+	assert.ErrorIs(t, err, errs.ErrAccessDenied)
+	repo.AssertNotCalled(t, "Update")
+}
+
+func TestProfileService_Update_PublishError(t *testing.T) {
+	repo := new(repomocks.ProfileRepository)
+	bus := new(mockBus)
+	svc := NewProfileService(repo, bus, passUoW{})
+
+	upd := model.NewProfileUpdate().SetString("tier", "pro")
+	repo.On("Update", mock.Anything, "user-1", upd).Return(true, nil)
+	repo.On("FindByUserID", mock.Anything, "user-1").Return(&model.Profile{UserID: "user-1"}, nil)
+	bus.On("Publish", mock.Anything, mock.Anything).Return(errors.New("outbox error"))
+
+	p, err := svc.Update(context.Background(), "user-1", upd, model.ProfileWriterAdmin)
+
+	assert.Nil(t, p)
+	assert.EqualError(t, err, "outbox error")
+}
+
+func TestProfileService_Update_ConditionFailed(t *testing.T) {
+	repo := new(repomocks.ProfileRepository)
+	bus := new(mockBus)
+	svc := NewProfileService(repo, bus, passUoW{})
+
+	upd := model.NewProfileUpdate().CompareAndSet("tier", "free", "pro")
+	repo.On("Update", mock.Anything, "user-1", upd).Return(false, nil)
+	repo.On("FindByUserID", mock.Anything, "user-1").Return(&model.Profile{UserID: "user-1"}, nil)
+
+	p, err := svc.Update(context.Background(), "user-1", upd, model.ProfileWriterAdmin)
+
+	assert.Nil(t, p)
+	assert.ErrorIs(t, err, errs.ErrProfileConditionFailed)
+	bus.AssertNotCalled(t, "Publish")
+}
@@ -17,6 +17,7 @@ type UserService interface {
 	Create(ctx context.Context, user *model.User) error
 	HashPassword(password string) (string, error)
 	UpdatePassword(ctx context.Context, id, hash string) error
+	List(ctx context.Context, limit, offset int) ([]*model.User, int, error)
 }
 
 type userService struct {
@@ -57,3 +58,7 @@ func (s *userService) Create(ctx context.Context, user *model.User) error {
 func (s *userService) UpdatePassword(ctx context.Context, id, hash string) error {
 	return s.userRepo.UpdatePassword(ctx, id, hash)
 }
+
+func (s *userService) List(ctx context.Context, limit, offset int) ([]*model.User, int, error) {
+	return s.userRepo.List(ctx, limit, offset)
+}
@@ -0,0 +1,24 @@
+package usecase
+
+import (
+	"starter-boilerplate/internal/shared/errs"
+	"starter-boilerplate/internal/shared/middleware"
+	"starter-boilerplate/internal/user/domain/model"
+)
+
+// requireSelfOrAdmin allows admins and the user targetID itself.
+func requireSelfOrAdmin(ctx middleware.AuthCtx, targetID string) error {
+	claims := ctx.Claims()
+	if claims.Role != string(model.RoleAdmin) && claims.UserID != targetID {
+		return errs.ErrAccessDenied
+	}
+	return nil
+}
+
+// requireAdmin allows admins only.
+func requireAdmin(ctx middleware.AuthCtx) error {
+	if ctx.Claims().Role != string(model.RoleAdmin) {
+		return errs.ErrAccessDenied
+	}
+	return nil
+}
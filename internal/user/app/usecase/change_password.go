@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"starter-boilerplate/internal/shared/errs"
 	"starter-boilerplate/internal/shared/middleware"
@@ -46,7 +47,8 @@ func (uc *ChangePasswordUseCase) Execute(ctx middleware.AuthCtx, oldPassword, ne
 			return err
 		}
 		return uc.bus.Publish(ctx, domainevent.PasswordChangedEvent{
-			UserID: userID,
+			UserID:    userID,
+			ChangedAt: time.Now().UTC(),
 		})
 	})
 }
@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"starter-boilerplate/internal/shared/errs"
+	"starter-boilerplate/internal/shared/middleware"
+	"starter-boilerplate/internal/user/app/service"
+	"starter-boilerplate/internal/user/domain/model"
+)
+
+type GetProfileUseCase struct {
+	profileService *service.ProfileService
+}
+
+func NewGetProfileUseCase(ps *service.ProfileService) *GetProfileUseCase {
+	return &GetProfileUseCase{profileService: ps}
+}
+
+// Execute returns the profile of userID as the caller may see it: the user
+// and admins see public and owner keys, other users public keys only.
+// Internal keys are never returned.
+func (uc *GetProfileUseCase) Execute(ctx middleware.AuthCtx, userID string) (*model.Profile, error) {
+	visibility := model.ProfileVisibilityOwner
+	if requireSelfOrAdmin(ctx, userID) != nil {
+		visibility = model.ProfileVisibilityPublic
+	}
+	p, err := uc.profileService.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, errs.ErrNotFound
+	}
+	return p.View(visibility), nil
+}
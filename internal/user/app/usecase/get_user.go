@@ -16,9 +16,8 @@ func NewGetUserUseCase(us service.UserService) *GetUserUseCase {
 }
 
 func (uc *GetUserUseCase) Execute(ctx middleware.AuthCtx, targetID string) (*model.User, error) {
-	claims := ctx.Claims()
-	if claims.Role != "admin" && claims.UserID != targetID {
-		return nil, errs.ErrAccessDenied
+	if err := requireSelfOrAdmin(ctx, targetID); err != nil {
+		return nil, err
 	}
 
 	u, err := uc.userService.FindByID(ctx, targetID)
@@ -0,0 +1,23 @@
+package usecase
+
+import (
+	"starter-boilerplate/internal/shared/middleware"
+	"starter-boilerplate/internal/user/app/service"
+	"starter-boilerplate/internal/user/domain/model"
+)
+
+type ListUsersUseCase struct {
+	userService service.UserService
+}
+
+func NewListUsersUseCase(us service.UserService) *ListUsersUseCase {
+	return &ListUsersUseCase{userService: us}
+}
+
+// Execute returns a page of users ordered by email and the total count. Admin only.
+func (uc *ListUsersUseCase) Execute(ctx middleware.AuthCtx, limit, offset int) ([]*model.User, int, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, 0, err
+	}
+	return uc.userService.List(ctx, limit, offset)
+}
@@ -0,0 +1,40 @@
+//go:build unit
+
+package usecase
+
+import (
+	"testing"
+
+	"starter-boilerplate/internal/shared/errs"
+	servicemocks "starter-boilerplate/internal/user/app/service/mocks"
+	"starter-boilerplate/internal/user/domain/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestListUsers_Admin(t *testing.T) {
+	userSvc := new(servicemocks.UserService)
+	uc := NewListUsersUseCase(userSvc)
+
+	users := []*model.User{{ID: "1", Email: "a@example.com"}, {ID: "2", Email: "b@example.com"}}
+	userSvc.On("List", mock.Anything, 2, 4).Return(users, 7, nil)
+
+	result, total, err := uc.Execute(newAuthCtx("admin-1", "admin"), 2, 4)
+
+	assert.NoError(t, err)
+	assert.Equal(t, users, result)
+	assert.Equal(t, 7, total)
+	userSvc.AssertExpectations(t)
+}
+
+func TestListUsers_UserDenied(t *testing.T) {
+	userSvc := new(servicemocks.UserService)
+	uc := NewListUsersUseCase(userSvc)
+
+	result, _, err := uc.Execute(newAuthCtx("user-1", "user"), 20, 0)
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, errs.ErrAccessDenied)
+	userSvc.AssertNotCalled(t, "List")
+}
@@ -0,0 +1,140 @@
+//go:build unit
+
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"starter-boilerplate/internal/shared/errs"
+	"starter-boilerplate/internal/user/app/service"
+	"starter-boilerplate/internal/user/domain/model"
+	repomocks "starter-boilerplate/internal/user/domain/repository/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// passUoW runs fn without a transaction.
+type passUoW struct{}
+
+func (passUoW) Do(ctx context.Context, fn func(ctx context.Context) error, _ ...*sql.TxOptions) error {
+	return fn(ctx)
+}
+
+func TestGetProfile_Self(t *testing.T) {
+	repo := new(repomocks.ProfileRepository)
+	uc := NewGetProfileUseCase(service.NewProfileService(repo, new(mockBus), passUoW{}))
+
+	profile := model.NewProfile("user-1")
+	profile.Numbers["logins"] = 3
+	repo.On("FindByUserID", mock.Anything, "user-1").Return(profile, nil)
+
+	result, err := uc.Execute(newAuthCtx("user-1", "user"), "user-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]float64{"reputation": 0, "logins": 3}, result.Numbers)
+	assert.Equal(t, "system", result.Strings["settings.theme"])
+}
+
+func TestGetProfile_OtherUserSeesPublicKeys(t *testing.T) {
+	repo := new(repomocks.ProfileRepository)
+	uc := NewGetProfileUseCase(service.NewProfileService(repo, new(mockBus), passUoW{}))
+
+	profile := model.NewProfile("other-1")
+	profile.Strings["nickname"] = "neo"
+	repo.On("FindByUserID", mock.Anything, "other-1").Return(profile, nil)
+
+	result, err := uc.Execute(newAuthCtx("user-1", "user"), "other-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]float64{"reputation": 0}, result.Numbers)
+	assert.Equal(t, map[string]string{"nickname": "neo", "tier": "free"}, result.Strings)
+	assert.Equal(t, map[string]bool{"verified": false}, result.Bools)
+	assert.Equal(t, map[string][]string{"badges": {}}, result.Tags)
+}
+
+func TestGetProfile_NotFound(t *testing.T) {
+	repo := new(repomocks.ProfileRepository)
+	uc := NewGetProfileUseCase(service.NewProfileService(repo, new(mockBus), passUoW{}))
+
+	repo.On("FindByUserID", mock.Anything, "missing-1").Return(nil, nil)
+
+	result, err := uc.Execute(newAuthCtx("admin-1", "admin"), "missing-1")
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, errs.ErrNotFound)
+}
+
+func TestUpdateProfile_AdminUpdatesOther(t *testing.T) {
+	repo := new(repomocks.ProfileRepository)
+	bus := new(mockBus)
+	uc := NewUpdateProfileUseCase(service.NewProfileService(repo, bus, passUoW{}))
+
+	upd := model.NewProfileUpdate().IncrNumber("reputation", 1)
+	updated := &model.Profile{UserID: "user-1", Numbers: map[string]float64{"reputation": 4, "password_changes": 2}}
+	repo.On("Update", mock.Anything, "user-1", upd).Return(true, nil)
+	repo.On("FindByUserID", mock.Anything, "user-1").Return(updated, nil)
+	bus.On("Publish", mock.Anything, mock.Anything).Return(nil)
+
+	result, err := uc.Execute(newAuthCtx("admin-1", "admin"), "user-1", upd)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]float64{"reputation": 4}, result.Numbers)
+	repo.AssertExpectations(t)
+	bus.AssertExpectations(t)
+}
+
+func TestUpdateProfile_UserCannotSetAdminKey(t *testing.T) {
+	repo := new(repomocks.ProfileRepository)
+	uc := NewUpdateProfileUseCase(service.NewProfileService(repo, new(mockBus), passUoW{}))
+
+	result, err := uc.Execute(newAuthCtx("user-1", "user"), "user-1", model.NewProfileUpdate().IncrNumber("reputation", 1))
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, errs.ErrAccessDenied)
+	repo.AssertNotCalled(t, "Update")
+}
+
+func TestUpdateProfile_UserCannotUpdateOther(t *testing.T) {
+	repo := new(repomocks.ProfileRepository)
+	uc := NewUpdateProfileUseCase(service.NewProfileService(repo, new(mockBus), passUoW{}))
+
+	result, err := uc.Execute(newAuthCtx("user-1", "user"), "other-1", model.NewProfileUpdate().SetString("nickname", "x"))
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, errs.ErrAccessDenied)
+	repo.AssertNotCalled(t, "Update")
+}
+
+func TestUpdateProfile_NotFound(t *testing.T) {
+	repo := new(repomocks.ProfileRepository)
+	bus := new(mockBus)
+	uc := NewUpdateProfileUseCase(service.NewProfileService(repo, bus, passUoW{}))
+
+	upd := model.NewProfileUpdate().SetString("nickname", "x")
+	repo.On("Update", mock.Anything, "user-1", upd).Return(false, nil)
+	repo.On("FindByUserID", mock.Anything, "user-1").Return(nil, nil)
+
+	result, err := uc.Execute(newAuthCtx("user-1", "user"), "user-1", upd)
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, errs.ErrNotFound)
+	bus.AssertNotCalled(t, "Publish")
+}
+
+func TestUpdateProfile_RepoError(t *testing.T) {
+	repo := new(repomocks.ProfileRepository)
+	uc := NewUpdateProfileUseCase(service.NewProfileService(repo, new(mockBus), passUoW{}))
+
+	upd := model.NewProfileUpdate().SetString("nickname", "x")
+	repo.On("Update", mock.Anything, "user-1", upd).Return(false, errors.New("db error"))
+
+	result, err := uc.Execute(newAuthCtx("user-1", "user"), "user-1", upd)
+
+	assert.Nil(t, result)
+	assert.EqualError(t, err, "db error")
+	repo.AssertNotCalled(t, "FindByUserID")
+}
@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"starter-boilerplate/internal/shared/middleware"
+	"starter-boilerplate/internal/user/app/service"
+	"starter-boilerplate/internal/user/domain/model"
+)
+
+type UpdateProfileUseCase struct {
+	profileService *service.ProfileService
+}
+
+func NewUpdateProfileUseCase(ps *service.ProfileService) *UpdateProfileUseCase {
+	return &UpdateProfileUseCase{profileService: ps}
+}
+
+// Execute applies upd to the profile of userID and returns the updated
+// profile without its internal keys. Users change their own owner keys;
+// admins also change admin keys, of any profile.
+func (uc *UpdateProfileUseCase) Execute(ctx middleware.AuthCtx, userID string, upd *model.ProfileUpdate) (*model.Profile, error) {
+	if err := requireSelfOrAdmin(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	writer := model.ProfileWriterOwner
+	if ctx.Claims().Role == string(model.RoleAdmin) {
+		writer = model.ProfileWriterAdmin
+	}
+	p, err := uc.profileService.Update(ctx, userID, upd, writer)
+	if err != nil {
+		return nil, err
+	}
+	return p.View(model.ProfileVisibilityOwner), nil
+}
@@ -0,0 +1,28 @@
+package usecase
+
+import (
+	"starter-boilerplate/internal/shared/middleware"
+	"starter-boilerplate/internal/user/app/service"
+	pkgevent "starter-boilerplate/pkg/event"
+)
+
+type WatchUserEventsUseCase struct {
+	events *service.EventStream
+}
+
+func NewWatchUserEventsUseCase(es *service.EventStream) *WatchUserEventsUseCase {
+	return &WatchUserEventsUseCase{events: es}
+}
+
+// Execute streams the events of userID to send, see service.EventStream.Watch.
+// An empty userID watches every user and is admin only.
+func (uc *WatchUserEventsUseCase) Execute(ctx middleware.AuthCtx, userID string, after int64, send func(pkgevent.Envelope) error) error {
+	if userID == "" {
+		if err := requireAdmin(ctx); err != nil {
+			return err
+		}
+	} else if err := requireSelfOrAdmin(ctx, userID); err != nil {
+		return err
+	}
+	return uc.events.Watch(ctx, userID, after, send)
+}
@@ -0,0 +1,48 @@
+//go:build unit
+
+package usecase
+
+import (
+	"testing"
+
+	"starter-boilerplate/internal/shared/errs"
+	"starter-boilerplate/internal/shared/middleware"
+	"starter-boilerplate/internal/user/app/service"
+	pkgevent "starter-boilerplate/pkg/event"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newWatchUseCase() (*WatchUserEventsUseCase, *pkgevent.Hub) {
+	hub := pkgevent.NewHub()
+	return NewWatchUserEventsUseCase(service.NewEventStream(hub, nil)), hub
+}
+
+func noSend(pkgevent.Envelope) error { return nil }
+
+func TestWatchUserEvents_AllUsersRequiresAdmin(t *testing.T) {
+	uc, _ := newWatchUseCase()
+
+	err := uc.Execute(newAuthCtx("user-1", "user"), "", service.LiveOnly, noSend)
+
+	assert.ErrorIs(t, err, errs.ErrAccessDenied)
+}
+
+func TestWatchUserEvents_OtherUserDenied(t *testing.T) {
+	uc, _ := newWatchUseCase()
+
+	err := uc.Execute(newAuthCtx("user-1", "user"), "user-2", service.LiveOnly, noSend)
+
+	assert.ErrorIs(t, err, errs.ErrAccessDenied)
+}
+
+func TestWatchUserEvents_SelfAndAdminAllowed(t *testing.T) {
+	for _, ctx := range []middleware.AuthCtx{newAuthCtx("user-1", "user"), newAuthCtx("admin-1", "admin")} {
+		uc, hub := newWatchUseCase()
+		hub.Close()
+
+		err := uc.Execute(ctx, "user-1", service.LiveOnly, noSend)
+
+		assert.ErrorIs(t, err, pkgevent.ErrHubClosed)
+	}
+}
@@ -1,10 +1,14 @@
 package event
 
+import "time"
+
 const PasswordChanged = "user.password_changed"
 
 type PasswordChangedEvent struct {
-	UserID string `json:"user_id" validate:"required,uuid"`
+	UserID    string    `json:"user_id"    validate:"required,uuid"`
+	ChangedAt time.Time `json:"changed_at"`
 }
 
-func (PasswordChangedEvent) EventName() string { return PasswordChanged }
-func (PasswordChangedEvent) Tags() []string    { return []string{"profile"} }
+func (PasswordChangedEvent) EventName() string      { return PasswordChanged }
+func (PasswordChangedEvent) Tags() []string         { return []string{"profile"} }
+func (e PasswordChangedEvent) PartitionKey() string { return e.UserID }
@@ -0,0 +1,36 @@
+package event
+
+import (
+	"time"
+
+	"starter-boilerplate/internal/user/domain/model"
+)
+
+const ProfileUpdated = "user.profile_updated"
+
+// ProfileUpdatedEvent carries the changed keys and the profile after the
+// change.
+type ProfileUpdatedEvent struct {
+	UserID  string               `json:"user_id" validate:"required,uuid"`
+	Keys    []string             `json:"keys"    validate:"required,min=1"`
+	Numbers map[string]float64   `json:"numbers"`
+	Strings map[string]string    `json:"strings"`
+	Bools   map[string]bool      `json:"bools"`
+	Times   map[string]time.Time `json:"times"`
+	Tags    map[string][]string  `json:"tags"`
+}
+
+func NewProfileUpdatedEvent(p *model.Profile, keys []string) ProfileUpdatedEvent {
+	return ProfileUpdatedEvent{
+		UserID:  p.UserID,
+		Keys:    keys,
+		Numbers: p.Numbers,
+		Strings: p.Strings,
+		Bools:   p.Bools,
+		Times:   p.Times,
+		Tags:    p.Tags,
+	}
+}
+
+func (ProfileUpdatedEvent) EventName() string      { return ProfileUpdated }
+func (e ProfileUpdatedEvent) PartitionKey() string { return e.UserID }
@@ -7,5 +7,6 @@ type UserCreatedEvent struct {
 	Email  string `json:"email"   validate:"required,email"`
 }
 
-func (UserCreatedEvent) EventName() string { return UserCreated }
-func (UserCreatedEvent) Tags() []string    { return []string{"profile"} }
+func (UserCreatedEvent) EventName() string      { return UserCreated }
+func (UserCreatedEvent) Tags() []string         { return []string{"profile"} }
+func (e UserCreatedEvent) PartitionKey() string { return e.UserID }
@@ -8,4 +8,6 @@ type UserLoggedInEvent struct {
 	UserAgent string `json:"user_agent" validate:"required"`
 }
 
-func (UserLoggedInEvent) EventName() string { return UserLoggedIn }
+func (UserLoggedInEvent) EventName() string      { return UserLoggedIn }
+func (UserLoggedInEvent) Tags() []string         { return []string{"profile"} }
+func (e UserLoggedInEvent) PartitionKey() string { return e.UserID }
@@ -1,7 +1,84 @@
 package model
 
+import (
+	"slices"
+	"time"
+)
+
+// Profile holds the JSONB columns of a user profile, one map per kind. Keys
+// of nested values are dotted paths, e.g. "settings.theme".
 type Profile struct {
 	UserID  string
 	Numbers map[string]float64
 	Strings map[string]string
+	Bools   map[string]bool
+	Times   map[string]time.Time
+	// Tags are string sets, sorted.
+	Tags map[string][]string
+}
+
+// NewProfile returns the profile of a new user: the defaults of ProfileSchema.
+func NewProfile(userID string) *Profile {
+	p := &Profile{
+		UserID:  userID,
+		Numbers: map[string]float64{},
+		Strings: map[string]string{},
+		Bools:   map[string]bool{},
+		Times:   map[string]time.Time{},
+		Tags:    map[string][]string{},
+	}
+	for key, k := range ProfileSchema {
+		switch v := k.Default.(type) {
+		case float64:
+			p.Numbers[key] = v
+		case string:
+			p.Strings[key] = v
+		case bool:
+			p.Bools[key] = v
+		case time.Time:
+			p.Times[key] = v.UTC()
+		case []string:
+			tags := slices.Clone(v)
+			slices.Sort(tags)
+			p.Tags[key] = slices.Compact(tags)
+		}
+	}
+	return p
+}
+
+// View returns the keys of p readable at visibility v (see
+// ProfileVisibility). Keys missing from ProfileSchema are internal.
+func (p *Profile) View(v ProfileVisibility) *Profile {
+	return &Profile{
+		UserID:  p.UserID,
+		Numbers: visible(p.Numbers, v),
+		Strings: visible(p.Strings, v),
+		Bools:   visible(p.Bools, v),
+		Times:   visible(p.Times, v),
+		Tags:    visible(p.Tags, v),
+	}
+}
+
+func visible[V any](m map[string]V, v ProfileVisibility) map[string]V {
+	out := make(map[string]V, len(m))
+	for key, val := range m {
+		if profileVisibility(key) <= v {
+			out[key] = val
+		}
+	}
+	return out
+}
+
+func profileVisibility(key string) ProfileVisibility {
+	if k, ok := ProfileSchema[key]; ok {
+		return k.Visibility
+	}
+	return ProfileVisibilityInternal
+}
+
+// VisibleKeys returns the keys readable at visibility v, in order.
+func VisibleKeys(keys []string, v ProfileVisibility) []string {
+	return slices.DeleteFunc(slices.Clone(keys), func(key string) bool {
+		return profileVisibility(key) > v
+	})
 }
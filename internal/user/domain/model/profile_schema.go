@@ -0,0 +1,219 @@
+package model
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// ProfileKind is the JSONB column a profile key is stored in.
+type ProfileKind string
+
+const (
+	ProfileNumber ProfileKind = "number"
+	ProfileString ProfileKind = "string"
+	ProfileBool   ProfileKind = "bool"
+	ProfileTime   ProfileKind = "time"
+	ProfileTags   ProfileKind = "tags"
+)
+
+// ProfileWriter is who may change a profile key. Each writer may also change
+// the keys of the writers before it: admins change owner keys, and the server
+// (event handlers) changes every key.
+type ProfileWriter int
+
+const (
+	ProfileWriterOwner ProfileWriter = iota
+	ProfileWriterAdmin
+	ProfileWriterServer
+)
+
+func (w ProfileWriter) String() string {
+	switch w {
+	case ProfileWriterOwner:
+		return "owner"
+	case ProfileWriterAdmin:
+		return "admin"
+	default:
+		return "server"
+	}
+}
+
+// ProfileVisibility is who may read a profile key. Each level also sees the
+// keys of the levels before it.
+type ProfileVisibility int
+
+const (
+	// ProfileVisibilityPublic keys are readable by every signed-in user.
+	ProfileVisibilityPublic ProfileVisibility = iota
+	// ProfileVisibilityOwner keys are readable by the user and admins.
+	ProfileVisibilityOwner
+	// ProfileVisibilityInternal keys are only read by the server and never
+	// leave it.
+	ProfileVisibilityInternal
+)
+
+// ProfileKey is the schema of a profile key.
+type ProfileKey struct {
+	Kind       ProfileKind
+	Writer     ProfileWriter
+	Visibility ProfileVisibility
+	// Default is the value of the key in new profiles (see NewProfile), of
+	// the Go type of Kind; nil leaves the key unset.
+	Default any
+	// Min and Max bound numbers; nil is unbounded. Increments are clamped to
+	// the bounds instead of rejected.
+	Min, Max *float64
+	// MaxLen bounds the length of strings and of each tag; 0 is unbounded.
+	MaxLen int
+	// Enum lists the allowed strings; empty allows any.
+	Enum []string
+}
+
+func bound(v float64) *float64 { return &v }
+
+// ProfileSchema declares every profile key; updates of other keys are
+// rejected. Dotted keys are nested paths.
+var ProfileSchema = map[string]ProfileKey{
+	"nickname":                     {Kind: ProfileString, Writer: ProfileWriterOwner, Visibility: ProfileVisibilityPublic, MaxLen: 32},
+	"bio":                          {Kind: ProfileString, Writer: ProfileWriterOwner, Visibility: ProfileVisibilityPublic, MaxLen: 280},
+	"timezone":                     {Kind: ProfileString, Writer: ProfileWriterOwner, Visibility: ProfileVisibilityOwner, MaxLen: 64},
+	"avatar_url":                   {Kind: ProfileString, Writer: ProfileWriterOwner, Visibility: ProfileVisibilityPublic, MaxLen: 2048},
+	"interests":                    {Kind: ProfileTags, Writer: ProfileWriterOwner, Visibility: ProfileVisibilityOwner, Default: []string{}, MaxLen: 32},
+	"settings.theme":               {Kind: ProfileString, Writer: ProfileWriterOwner, Visibility: ProfileVisibilityOwner, Default: "system", Enum: []string{"system", "light", "dark"}},
+	"settings.notifications.email": {Kind: ProfileBool, Writer: ProfileWriterOwner, Visibility: ProfileVisibilityOwner, Default: true},
+	"settings.notifications.push":  {Kind: ProfileBool, Writer: ProfileWriterOwner, Visibility: ProfileVisibilityOwner, Default: false},
+	"tier":                         {Kind: ProfileString, Writer: ProfileWriterAdmin, Visibility: ProfileVisibilityPublic, Default: "free", Enum: []string{"free", "pro", "enterprise"}},
+	"reputation":                   {Kind: ProfileNumber, Writer: ProfileWriterAdmin, Visibility: ProfileVisibilityPublic, Default: 0.0, Min: bound(0), Max: bound(1_000_000)},
+	"verified":                     {Kind: ProfileBool, Writer: ProfileWriterAdmin, Visibility: ProfileVisibilityPublic, Default: false},
+	"badges":                       {Kind: ProfileTags, Writer: ProfileWriterAdmin, Visibility: ProfileVisibilityPublic, Default: []string{}, MaxLen: 32},
+	"logins":                       {Kind: ProfileNumber, Writer: ProfileWriterServer, Visibility: ProfileVisibilityOwner, Default: 0.0, Min: bound(0)},
+	"password_changes":             {Kind: ProfileNumber, Writer: ProfileWriterServer, Visibility: ProfileVisibilityInternal, Default: 0.0, Min: bound(0)},
+	"password_changed_at":          {Kind: ProfileTime, Writer: ProfileWriterServer, Visibility: ProfileVisibilityInternal},
+}
+
+// check returns the reason v, a value of the key's kind, breaks a bound of k
+// for, and the bound.
+func (k ProfileKey) check(v any) (string, any) {
+	switch v := v.(type) {
+	case float64:
+		if k.Min != nil && v < *k.Min {
+			return ProfileMinimum, *k.Min
+		}
+		if k.Max != nil && v > *k.Max {
+			return ProfileMaximum, *k.Max
+		}
+	case string:
+		if k.MaxLen > 0 && utf8.RuneCountInString(v) > k.MaxLen {
+			return ProfileMaxLength, k.MaxLen
+		}
+		if len(k.Enum) > 0 && !slices.Contains(k.Enum, v) {
+			return ProfileEnum, strings.Join(k.Enum, ", ")
+		}
+	case []string:
+		for _, tag := range v {
+			if reason, limit := k.check(tag); reason != "" {
+				return reason, limit
+			}
+		}
+	}
+	return "", nil
+}
+
+// Reasons of profile violations.
+const (
+	ProfileUnknownKey = "unknown_key"
+	ProfileWrongKind  = "type"
+	ProfileReadOnly   = "read_only"
+	ProfileMinimum    = "minimum"
+	ProfileMaximum    = "maximum"
+	ProfileMaxLength  = "max_length"
+	ProfileEnum       = "enum"
+	// ProfileChangedTwice is a key named by more than one change.
+	ProfileChangedTwice = "changed_twice"
+)
+
+// ProfileViolation is a key of a ProfileUpdate that is not in ProfileSchema,
+// is of another kind than the operation or condition, may not be changed by
+// the writer or is changed twice, or a value that breaks a bound of the key.
+type ProfileViolation struct {
+	Key    string
+	Reason string
+	// Kind is the kind of the key for ProfileWrongKind.
+	Kind ProfileKind
+	// Writer is the writer the key needs for ProfileReadOnly.
+	Writer ProfileWriter
+	// Limit is the broken bound for ProfileMinimum, ProfileMaximum,
+	// ProfileMaxLength and ProfileEnum.
+	Limit any
+}
+
+func (v ProfileViolation) Message() string {
+	switch v.Reason {
+	case ProfileUnknownKey:
+		return fmt.Sprintf("%s is not a profile key", v.Key)
+	case ProfileWrongKind:
+		return fmt.Sprintf("%s is a %s key", v.Key, v.Kind)
+	case ProfileMinimum:
+		return fmt.Sprintf("%s must be at least %v", v.Key, v.Limit)
+	case ProfileMaximum:
+		return fmt.Sprintf("%s must be at most %v", v.Key, v.Limit)
+	case ProfileMaxLength:
+		return fmt.Sprintf("%s must be at most %v characters long", v.Key, v.Limit)
+	case ProfileEnum:
+		return fmt.Sprintf("%s must be one of: %v", v.Key, v.Limit)
+	case ProfileChangedTwice:
+		return fmt.Sprintf("%s is changed by more than one operation", v.Key)
+	default:
+		return fmt.Sprintf("%s may only be changed by the %s", v.Key, v.Writer)
+	}
+}
+
+// Violations checks every change and condition of u against ProfileSchema
+// for writer, in key order. Deletions match any kind; conditions only read,
+// so any writer may use them, and are not bounded. Increments are not
+// bounded either: the repository clamps them. Keys changed twice are
+// reported because the change that wins may not be the one meant, e.g. in a
+// request body, whose operations have no order.
+func (u *ProfileUpdate) Violations(writer ProfileWriter) []ProfileViolation {
+	var vs []ProfileViolation
+	check := func(key string, kind ProfileKind, anyKind, write bool, value any) {
+		k, ok := ProfileSchema[key]
+		switch {
+		case !ok:
+			vs = append(vs, ProfileViolation{Key: key, Reason: ProfileUnknownKey})
+			return
+		case !anyKind && k.Kind != kind:
+			vs = append(vs, ProfileViolation{Key: key, Reason: ProfileWrongKind, Kind: k.Kind})
+			return
+		case write && k.Writer > writer:
+			vs = append(vs, ProfileViolation{Key: key, Reason: ProfileReadOnly, Writer: k.Writer})
+		}
+		if value != nil {
+			if reason, limit := k.check(value); reason != "" {
+				vs = append(vs, ProfileViolation{Key: key, Reason: reason, Limit: limit})
+			}
+		}
+	}
+	for _, c := range u.Changes {
+		var value any
+		switch c.Op {
+		case ProfileSet, ProfileMax, ProfileMin, ProfileSetIfAbsent, ProfileAddTags:
+			value = c.Value
+		}
+		check(c.Key, c.Kind, c.Op == ProfileDelete, true, value)
+	}
+	for _, c := range u.Conditions {
+		check(c.Key, c.Kind, false, false, nil)
+	}
+	for _, key := range u.replaced {
+		vs = append(vs, ProfileViolation{Key: key, Reason: ProfileChangedTwice})
+	}
+	slices.SortStableFunc(vs, func(a, b ProfileViolation) int {
+		return cmp.Compare(a.Key, b.Key)
+	})
+	// A compare-and-set checks its key twice.
+	return slices.Compact(vs)
+}
@@ -0,0 +1,152 @@
+//go:build unit
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfileUpdate_Violations(t *testing.T) {
+	upd := NewProfileUpdate().
+		SetString("nickname", "bob").
+		SetString("reputation", "high").
+		IncrNumber("logins", 1).
+		SetNumber("karma", 1)
+
+	vs := upd.Violations(ProfileWriterOwner)
+
+	assert.Equal(t, []ProfileViolation{
+		{Key: "karma", Reason: ProfileUnknownKey},
+		{Key: "logins", Reason: ProfileReadOnly, Writer: ProfileWriterServer},
+		{Key: "reputation", Reason: ProfileWrongKind, Kind: ProfileNumber},
+	}, vs)
+}
+
+func TestProfileUpdate_Violations_Writers(t *testing.T) {
+	tests := []struct {
+		key    string
+		writer ProfileWriter
+		ok     bool
+	}{
+		{"reputation", ProfileWriterOwner, false},
+		{"reputation", ProfileWriterAdmin, true},
+		{"logins", ProfileWriterAdmin, false},
+		{"logins", ProfileWriterServer, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key+"/"+tt.writer.String(), func(t *testing.T) {
+			vs := NewProfileUpdate().IncrNumber(tt.key, 1).Violations(tt.writer)
+			assert.Equal(t, tt.ok, len(vs) == 0)
+		})
+	}
+}
+
+func TestProfileUpdate_Violations_ChangedTwice(t *testing.T) {
+	upd := NewProfileUpdate().
+		SetString("bio", "hi").
+		DeleteKey("bio").
+		AddTags("interests", "go").
+		RemoveTags("interests", "rust")
+
+	assert.Equal(t, []ProfileViolation{{Key: "bio", Reason: ProfileChangedTwice}}, upd.Violations(ProfileWriterOwner))
+}
+
+func TestProfileViolation_Message(t *testing.T) {
+	assert.Equal(t, "karma is not a profile key", ProfileViolation{Key: "karma", Reason: ProfileUnknownKey}.Message())
+	assert.Equal(t, "tier is a string key", ProfileViolation{Key: "tier", Reason: ProfileWrongKind, Kind: ProfileString}.Message())
+	assert.Equal(t, "tier may only be changed by the admin", ProfileViolation{Key: "tier", Reason: ProfileReadOnly, Writer: ProfileWriterAdmin}.Message())
+	assert.Equal(t, "bio is changed by more than one operation", ProfileViolation{Key: "bio", Reason: ProfileChangedTwice}.Message())
+}
+
+func TestProfileUpdate_Violations_DeleteAndConditions(t *testing.T) {
+	upd := NewProfileUpdate().
+		DeleteKey("bio").
+		DeleteKey("logins").
+		CompareAndSet("tier", 3, "pro").
+		SetIfAbsent("settings.theme", true)
+
+	vs := upd.Violations(ProfileWriterOwner)
+
+	assert.Equal(t, []ProfileViolation{
+		{Key: "logins", Reason: ProfileReadOnly, Writer: ProfileWriterServer},
+		{Key: "settings.theme", Reason: ProfileWrongKind, Kind: ProfileString},
+		{Key: "tier", Reason: ProfileReadOnly, Writer: ProfileWriterAdmin},
+		{Key: "tier", Reason: ProfileWrongKind, Kind: ProfileString},
+	}, vs)
+}
+
+func TestProfileUpdate_Violations_Bounds(t *testing.T) {
+	upd := NewProfileUpdate().
+		SetNumber("reputation", 2_000_000).
+		SetMax("logins", -1).
+		IncrNumber("password_changes", -5).
+		SetString("nickname", strings.Repeat("ж", 33)).
+		SetIfAbsent("settings.theme", "pink").
+		AddTags("interests", "go", strings.Repeat("x", 33))
+
+	vs := upd.Violations(ProfileWriterServer)
+
+	assert.Equal(t, []ProfileViolation{
+		{Key: "interests", Reason: ProfileMaxLength, Limit: 32},
+		{Key: "logins", Reason: ProfileMinimum, Limit: float64(0)},
+		{Key: "nickname", Reason: ProfileMaxLength, Limit: 32},
+		{Key: "reputation", Reason: ProfileMaximum, Limit: float64(1_000_000)},
+		{Key: "settings.theme", Reason: ProfileEnum, Limit: "system, light, dark"},
+	}, vs)
+	assert.Equal(t, "settings.theme must be one of: system, light, dark", vs[4].Message())
+}
+
+func TestNewProfile_Defaults(t *testing.T) {
+	p := NewProfile("user-1")
+
+	for key, k := range ProfileSchema {
+		t.Run(key, func(t *testing.T) {
+			var v any
+			var ok bool
+			switch k.Kind {
+			case ProfileNumber:
+				v, ok = p.Numbers[key]
+			case ProfileString:
+				v, ok = p.Strings[key]
+			case ProfileBool:
+				v, ok = p.Bools[key]
+			case ProfileTime:
+				v, ok = p.Times[key]
+			case ProfileTags:
+				v, ok = p.Tags[key]
+			}
+			// A default of another type than the kind would be dropped.
+			assert.Equal(t, k.Default != nil, ok)
+			if ok {
+				reason, _ := k.check(v)
+				assert.Empty(t, reason)
+			}
+		})
+	}
+}
+
+func TestProfile_View(t *testing.T) {
+	p := NewProfile("user-1")
+	p.Numbers["password_changes"] = 2
+	p.Strings["legacy"] = "x"
+
+	public := p.View(ProfileVisibilityPublic)
+	owner := p.View(ProfileVisibilityOwner)
+	internal := p.View(ProfileVisibilityInternal)
+
+	assert.Equal(t, map[string]float64{"reputation": 0}, public.Numbers)
+	assert.Equal(t, map[string]float64{"reputation": 0, "logins": 0}, owner.Numbers)
+	assert.NotContains(t, owner.Strings, "legacy")
+	assert.Equal(t, p, internal)
+}
+
+func TestVisibleKeys(t *testing.T) {
+	keys := []string{"logins", "nickname", "password_changes", "unknown"}
+
+	assert.Equal(t, []string{"nickname"}, VisibleKeys(keys, ProfileVisibilityPublic))
+	assert.Equal(t, []string{"logins", "nickname"}, VisibleKeys(keys, ProfileVisibilityOwner))
+}
@@ -1,11 +1,52 @@
 package model
 
-// ProfileUpdate describes a partial update to a profile's JSONB columns.
-// It supports both set (overwrite) and increment operations on numeric fields.
+import (
+	"slices"
+	"time"
+)
+
+// ProfileOp is what a ProfileChange does to its key.
+type ProfileOp string
+
+const (
+	ProfileSet         ProfileOp = "set"
+	ProfileIncr        ProfileOp = "incr"
+	ProfileMax         ProfileOp = "max"
+	ProfileMin         ProfileOp = "min"
+	ProfileSetIfAbsent ProfileOp = "set_if_absent"
+	ProfileDelete      ProfileOp = "delete"
+	ProfileAddTags     ProfileOp = "add_tags"
+	ProfileRemoveTags  ProfileOp = "remove_tags"
+)
+
+// ProfileChange is one operation of a ProfileUpdate. Key is a dotted path
+// into the column of Kind. Value is a float64, string, bool, time.Time or
+// []string by Kind, and nil for ProfileDelete, whose Kind is empty: it
+// removes the key from every column.
+type ProfileChange struct {
+	Kind  ProfileKind
+	Key   string
+	Op    ProfileOp
+	Value any
+}
+
+// ProfileCondition makes a ProfileUpdate apply only while Key holds Value.
+type ProfileCondition struct {
+	Kind  ProfileKind
+	Key   string
+	Value any
+}
+
+// ProfileUpdate describes a partial update to a profile's JSONB columns,
+// applied atomically. There is one change per key: a later change replaces
+// an earlier one, except tag additions and removals, which combine, and
+// Violations reports the key (ProfileChangedTwice). Every change reads the
+// value the key had before the update.
 type ProfileUpdate struct {
-	NumberSets map[string]float64
-	NumberIncr map[string]float64
-	StringSets map[string]string
+	Changes    []ProfileChange
+	Conditions []ProfileCondition
+	// replaced are the keys whose change a later one replaced.
+	replaced []string
 }
 
 func NewProfileUpdate() *ProfileUpdate {
@@ -13,25 +54,129 @@ func NewProfileUpdate() *ProfileUpdate {
 }
 
 func (u *ProfileUpdate) SetNumber(key string, val float64) *ProfileUpdate {
-	if u.NumberSets == nil {
-		u.NumberSets = make(map[string]float64)
-	}
-	u.NumberSets[key] = val
-	return u
+	return u.change(ProfileChange{Kind: ProfileNumber, Key: key, Op: ProfileSet, Value: val})
 }
 
 func (u *ProfileUpdate) IncrNumber(key string, delta float64) *ProfileUpdate {
-	if u.NumberIncr == nil {
-		u.NumberIncr = make(map[string]float64)
-	}
-	u.NumberIncr[key] = delta
-	return u
+	return u.change(ProfileChange{Kind: ProfileNumber, Key: key, Op: ProfileIncr, Value: delta})
+}
+
+// SetMax sets key to val if key is missing or below it, e.g. for high scores.
+func (u *ProfileUpdate) SetMax(key string, val float64) *ProfileUpdate {
+	return u.change(ProfileChange{Kind: ProfileNumber, Key: key, Op: ProfileMax, Value: val})
+}
+
+// SetMin sets key to val if key is missing or above it.
+func (u *ProfileUpdate) SetMin(key string, val float64) *ProfileUpdate {
+	return u.change(ProfileChange{Kind: ProfileNumber, Key: key, Op: ProfileMin, Value: val})
 }
 
 func (u *ProfileUpdate) SetString(key string, val string) *ProfileUpdate {
-	if u.StringSets == nil {
-		u.StringSets = make(map[string]string)
+	return u.change(ProfileChange{Kind: ProfileString, Key: key, Op: ProfileSet, Value: val})
+}
+
+func (u *ProfileUpdate) SetBool(key string, val bool) *ProfileUpdate {
+	return u.change(ProfileChange{Kind: ProfileBool, Key: key, Op: ProfileSet, Value: val})
+}
+
+func (u *ProfileUpdate) SetTime(key string, val time.Time) *ProfileUpdate {
+	return u.change(ProfileChange{Kind: ProfileTime, Key: key, Op: ProfileSet, Value: val.UTC()})
+}
+
+// SetIfAbsent sets key to val unless it has a value. The kind follows val
+// (see ProfileKindOf).
+func (u *ProfileUpdate) SetIfAbsent(key string, val any) *ProfileUpdate {
+	kind, val := ProfileKindOf(val)
+	return u.change(ProfileChange{Kind: kind, Key: key, Op: ProfileSetIfAbsent, Value: val})
+}
+
+// DeleteKey removes key, whatever its kind.
+func (u *ProfileUpdate) DeleteKey(key string) *ProfileUpdate {
+	return u.change(ProfileChange{Key: key, Op: ProfileDelete})
+}
+
+// AddTags adds vals to the string set of key.
+func (u *ProfileUpdate) AddTags(key string, vals ...string) *ProfileUpdate {
+	return u.tags(key, ProfileAddTags, vals)
+}
+
+// RemoveTags removes vals from the string set of key; removals win over
+// additions of the same value.
+func (u *ProfileUpdate) RemoveTags(key string, vals ...string) *ProfileUpdate {
+	return u.tags(key, ProfileRemoveTags, vals)
+}
+
+// CompareAndSet sets key to val if it holds expected; otherwise the whole
+// update is not applied, which ProfileRepository.Update reports. The kinds
+// follow the values (see ProfileKindOf).
+func (u *ProfileUpdate) CompareAndSet(key string, expected, val any) *ProfileUpdate {
+	kind, expected := ProfileKindOf(expected)
+	u.Conditions = append(u.Conditions, ProfileCondition{Kind: kind, Key: key, Value: expected})
+	kind, val = ProfileKindOf(val)
+	return u.change(ProfileChange{Kind: kind, Key: key, Op: ProfileSet, Value: val})
+}
+
+func (u *ProfileUpdate) change(c ProfileChange) *ProfileUpdate {
+	u.replace(c.Key, func(ProfileChange) bool { return true })
+	u.Changes = append(u.Changes, c)
+	return u
+}
+
+func (u *ProfileUpdate) tags(key string, op ProfileOp, vals []string) *ProfileUpdate {
+	u.replace(key, func(o ProfileChange) bool { return o.Op != ProfileAddTags && o.Op != ProfileRemoveTags })
+	for i, c := range u.Changes {
+		if c.Key == key && c.Op == op {
+			u.Changes[i].Value = append(slices.Clone(c.Value.([]string)), vals...)
+			return u
+		}
 	}
-	u.StringSets[key] = val
+	u.Changes = append(u.Changes, ProfileChange{Kind: ProfileTags, Key: key, Op: op, Value: slices.Clone(vals)})
 	return u
 }
+
+// replace removes the changes of key that match and remembers the key if
+// there were any.
+func (u *ProfileUpdate) replace(key string, match func(ProfileChange) bool) {
+	n := len(u.Changes)
+	u.Changes = slices.DeleteFunc(u.Changes, func(o ProfileChange) bool { return o.Key == key && match(o) })
+	if len(u.Changes) < n && !slices.Contains(u.replaced, key) {
+		u.replaced = append(u.replaced, key)
+	}
+}
+
+// ProfileKindOf returns the kind of a scalar profile value, with ints as
+// float64 and times in UTC. Other values have no kind, which
+// ProfileUpdate.Violations reports.
+func ProfileKindOf(v any) (ProfileKind, any) {
+	switch v := v.(type) {
+	case float64:
+		return ProfileNumber, v
+	case int:
+		return ProfileNumber, float64(v)
+	case int64:
+		return ProfileNumber, float64(v)
+	case string:
+		return ProfileString, v
+	case bool:
+		return ProfileBool, v
+	case time.Time:
+		return ProfileTime, v.UTC()
+	default:
+		return "", v
+	}
+}
+
+// IsEmpty reports whether u has no changes.
+func (u *ProfileUpdate) IsEmpty() bool {
+	return len(u.Changes) == 0
+}
+
+// Keys returns the keys u changes, sorted.
+func (u *ProfileUpdate) Keys() []string {
+	keys := make([]string, 0, len(u.Changes))
+	for _, c := range u.Changes {
+		keys = append(keys, c.Key)
+	}
+	slices.Sort(keys)
+	return slices.Compact(keys)
+}
@@ -4,6 +4,7 @@ package model
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -11,25 +12,20 @@ import (
 func TestProfileUpdate_SetNumber(t *testing.T) {
 	upd := NewProfileUpdate().SetNumber("score", 42.5)
 
-	assert.Equal(t, map[string]float64{"score": 42.5}, upd.NumberSets)
-	assert.Nil(t, upd.NumberIncr)
-	assert.Nil(t, upd.StringSets)
+	assert.Equal(t, []ProfileChange{{Kind: ProfileNumber, Key: "score", Op: ProfileSet, Value: 42.5}}, upd.Changes)
+	assert.Nil(t, upd.Conditions)
 }
 
 func TestProfileUpdate_IncrNumber(t *testing.T) {
 	upd := NewProfileUpdate().IncrNumber("login_count", 1)
 
-	assert.Equal(t, map[string]float64{"login_count": 1}, upd.NumberIncr)
-	assert.Nil(t, upd.NumberSets)
-	assert.Nil(t, upd.StringSets)
+	assert.Equal(t, []ProfileChange{{Kind: ProfileNumber, Key: "login_count", Op: ProfileIncr, Value: 1.0}}, upd.Changes)
 }
 
 func TestProfileUpdate_SetString(t *testing.T) {
 	upd := NewProfileUpdate().SetString("nickname", "alice")
 
-	assert.Equal(t, map[string]string{"nickname": "alice"}, upd.StringSets)
-	assert.Nil(t, upd.NumberSets)
-	assert.Nil(t, upd.NumberIncr)
+	assert.Equal(t, []ProfileChange{{Kind: ProfileString, Key: "nickname", Op: ProfileSet, Value: "alice"}}, upd.Changes)
 }
 
 func TestProfileUpdate_Chaining(t *testing.T) {
@@ -37,26 +33,85 @@ func TestProfileUpdate_Chaining(t *testing.T) {
 		SetNumber("score", 100).
 		IncrNumber("login_count", 1).
 		SetString("nickname", "bob").
-		SetNumber("level", 5).
-		IncrNumber("xp", 250)
+		SetMax("best", 5).
+		SetMin("fastest", 3).
+		SetBool("settings.public", true).
+		SetTime("seen_at", time.Date(2026, 1, 2, 3, 4, 5, 0, time.FixedZone("X", 3600))).
+		DeleteKey("bio")
 
-	assert.Equal(t, map[string]float64{"score": 100, "level": 5}, upd.NumberSets)
-	assert.Equal(t, map[string]float64{"login_count": 1, "xp": 250}, upd.NumberIncr)
-	assert.Equal(t, map[string]string{"nickname": "bob"}, upd.StringSets)
+	assert.Equal(t, []ProfileChange{
+		{Kind: ProfileNumber, Key: "score", Op: ProfileSet, Value: 100.0},
+		{Kind: ProfileNumber, Key: "login_count", Op: ProfileIncr, Value: 1.0},
+		{Kind: ProfileString, Key: "nickname", Op: ProfileSet, Value: "bob"},
+		{Kind: ProfileNumber, Key: "best", Op: ProfileMax, Value: 5.0},
+		{Kind: ProfileNumber, Key: "fastest", Op: ProfileMin, Value: 3.0},
+		{Kind: ProfileBool, Key: "settings.public", Op: ProfileSet, Value: true},
+		{Kind: ProfileTime, Key: "seen_at", Op: ProfileSet, Value: time.Date(2026, 1, 2, 2, 4, 5, 0, time.UTC)},
+		{Key: "bio", Op: ProfileDelete},
+	}, upd.Changes)
 }
 
 func TestProfileUpdate_OverwriteSameKey(t *testing.T) {
 	upd := NewProfileUpdate().
 		SetNumber("score", 10).
-		SetNumber("score", 20)
+		IncrNumber("score", 20)
 
-	assert.Equal(t, 20.0, upd.NumberSets["score"])
+	assert.Equal(t, []ProfileChange{{Kind: ProfileNumber, Key: "score", Op: ProfileIncr, Value: 20.0}}, upd.Changes)
+}
+
+func TestProfileUpdate_Tags(t *testing.T) {
+	upd := NewProfileUpdate().
+		AddTags("badges", "gold").
+		RemoveTags("badges", "silver").
+		AddTags("badges", "bronze")
+
+	assert.Equal(t, []ProfileChange{
+		{Kind: ProfileTags, Key: "badges", Op: ProfileAddTags, Value: []string{"gold", "bronze"}},
+		{Kind: ProfileTags, Key: "badges", Op: ProfileRemoveTags, Value: []string{"silver"}},
+	}, upd.Changes)
+
+	upd.SetString("badges", "none")
+	assert.Equal(t, []ProfileChange{{Kind: ProfileString, Key: "badges", Op: ProfileSet, Value: "none"}}, upd.Changes)
+}
+
+func TestProfileUpdate_SetIfAbsent(t *testing.T) {
+	upd := NewProfileUpdate().
+		SetIfAbsent("level", 1).
+		SetIfAbsent("theme", "dark").
+		SetIfAbsent("nested", map[string]any{})
+
+	assert.Equal(t, []ProfileChange{
+		{Kind: ProfileNumber, Key: "level", Op: ProfileSetIfAbsent, Value: 1.0},
+		{Kind: ProfileString, Key: "theme", Op: ProfileSetIfAbsent, Value: "dark"},
+		{Kind: "", Key: "nested", Op: ProfileSetIfAbsent, Value: map[string]any{}},
+	}, upd.Changes)
+}
+
+func TestProfileUpdate_CompareAndSet(t *testing.T) {
+	upd := NewProfileUpdate().CompareAndSet("tier", "gold", "platinum")
+
+	assert.Equal(t, []ProfileCondition{{Kind: ProfileString, Key: "tier", Value: "gold"}}, upd.Conditions)
+	assert.Equal(t, []ProfileChange{{Kind: ProfileString, Key: "tier", Op: ProfileSet, Value: "platinum"}}, upd.Changes)
 }
 
 func TestProfileUpdate_Empty(t *testing.T) {
 	upd := NewProfileUpdate()
 
-	assert.Nil(t, upd.NumberSets)
-	assert.Nil(t, upd.NumberIncr)
-	assert.Nil(t, upd.StringSets)
+	assert.Nil(t, upd.Changes)
+	assert.Nil(t, upd.Conditions)
+}
+
+func TestProfileUpdate_IsEmpty(t *testing.T) {
+	assert.True(t, NewProfileUpdate().IsEmpty())
+	assert.False(t, NewProfileUpdate().SetString("nickname", "bob").IsEmpty())
+}
+
+func TestProfileUpdate_Keys(t *testing.T) {
+	upd := NewProfileUpdate().
+		SetString("nickname", "bob").
+		AddTags("badges", "gold").
+		RemoveTags("badges", "silver").
+		DeleteKey("bio")
+
+	assert.Equal(t, []string{"badges", "bio", "nickname"}, upd.Keys())
 }
@@ -24,6 +24,7 @@ func (m *ProfileRepository) Upsert(ctx context.Context, profile *model.Profile)
 	return m.Called(ctx, profile).Error(0)
 }
 
-func (m *ProfileRepository) Update(ctx context.Context, userID string, upd *model.ProfileUpdate) error {
-	return m.Called(ctx, userID, upd).Error(0)
+func (m *ProfileRepository) Update(ctx context.Context, userID string, upd *model.ProfileUpdate) (bool, error) {
+	args := m.Called(ctx, userID, upd)
+	return args.Bool(0), args.Error(1)
 }
@@ -12,6 +12,14 @@ type UserRepository struct {
 	mock.Mock
 }
 
+func (m *UserRepository) List(ctx context.Context, limit, offset int) ([]*model.User, int, error) {
+	args := m.Called(ctx, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*model.User), args.Int(1), args.Error(2)
+}
+
 func (m *UserRepository) FindByID(ctx context.Context, id string) (*model.User, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
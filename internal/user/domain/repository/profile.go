@@ -9,5 +9,7 @@ import (
 type ProfileRepository interface {
 	FindByUserID(ctx context.Context, userID string) (*model.Profile, error)
 	Upsert(ctx context.Context, profile *model.Profile) error
-	Update(ctx context.Context, userID string, upd *model.ProfileUpdate) error
+	// Update applies upd in one statement and reports whether it did: it
+	// does not when the profile is missing or a condition of upd fails.
+	Update(ctx context.Context, userID string, upd *model.ProfileUpdate) (bool, error)
 }
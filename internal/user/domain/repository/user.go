@@ -12,4 +12,6 @@ type UserRepository interface {
 	Create(ctx context.Context, user *model.User) error
 	Update(ctx context.Context, user *model.User) error
 	UpdatePassword(ctx context.Context, id, hash string) error
+	// List returns up to limit users ordered by email, skipping offset, and the total count.
+	List(ctx context.Context, limit, offset int) ([]*model.User, int, error)
 }
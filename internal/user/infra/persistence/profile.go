@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"starter-boilerplate/internal/user/domain/model"
@@ -12,16 +14,38 @@ import (
 	pkgdb "starter-boilerplate/pkg/db"
 
 	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
 )
 
+// profileModel stores each kind of value in its own JSONB column, nested by
+// the dotted paths of the keys.
 type profileModel struct {
 	bun.BaseModel `bun:"table:user_profiles"`
 
-	UserID    string             `bun:"user_id,pk"`
-	Numbers   map[string]float64 `bun:"numbers,type:jsonb,notnull,default:'{}'"`
-	Strings   map[string]string  `bun:"strings,type:jsonb,notnull,default:'{}'"`
-	CreatedAt int64              `bun:"created_at,notnull"`
-	UpdatedAt int64              `bun:"updated_at,notnull"`
+	UserID    string         `bun:"user_id,pk"`
+	Numbers   map[string]any `bun:"numbers,type:jsonb,notnull,default:'{}'"`
+	Strings   map[string]any `bun:"strings,type:jsonb,notnull,default:'{}'"`
+	Bools     map[string]any `bun:"bools,type:jsonb,notnull,default:'{}'"`
+	Times     map[string]any `bun:"times,type:jsonb,notnull,default:'{}'"`
+	Tags      map[string]any `bun:"tags,type:jsonb,notnull,default:'{}'"`
+	CreatedAt int64          `bun:"created_at,notnull"`
+	UpdatedAt int64          `bun:"updated_at,notnull"`
+}
+
+// profileColumn is the JSONB column of a profile kind; placeholder casts a
+// value of the kind for to_jsonb.
+type profileColumn struct {
+	kind        model.ProfileKind
+	name        string
+	placeholder string
+}
+
+var profileColumns = []profileColumn{
+	{kind: model.ProfileNumber, name: "numbers", placeholder: "?::numeric"},
+	{kind: model.ProfileString, name: "strings", placeholder: "?::text"},
+	{kind: model.ProfileBool, name: "bools", placeholder: "?::boolean"},
+	{kind: model.ProfileTime, name: "times", placeholder: "?::text"},
+	{kind: model.ProfileTags, name: "tags", placeholder: "?::text[]"},
 }
 
 type profileRepository struct {
@@ -54,82 +78,275 @@ func (r *profileRepository) Upsert(ctx context.Context, profile *model.Profile)
 	return err
 }
 
-func (r *profileRepository) Update(ctx context.Context, userID string, upd *model.ProfileUpdate) error {
+func (r *profileRepository) Update(ctx context.Context, userID string, upd *model.ProfileUpdate) (bool, error) {
+	// The service reports violations to the caller; this guards the data
+	// from writers that skip it.
+	if vs := upd.Violations(model.ProfileWriterServer); len(vs) > 0 {
+		return false, fmt.Errorf("profile update violates the schema: %s", vs[0].Message())
+	}
+
 	q := pkgdb.Conn(ctx, r.db).NewUpdate().
 		TableExpr("user_profiles").
 		Where("user_id = ?", userID).
 		Set("updated_at = ?", time.Now().Unix())
 
-	if expr, args := buildNumbersExpr(upd); expr != "" {
-		q = q.Set(expr, args...)
+	for _, col := range profileColumns {
+		if expr, args := buildColumnExpr(col, upd.Changes); expr != "" {
+			q = q.Set(col.name+" = "+expr, args...)
+		}
 	}
-	if expr, args := buildStringsExpr(upd); expr != "" {
-		q = q.Set(expr, args...)
+	for _, c := range upd.Conditions {
+		i := slices.IndexFunc(profileColumns, func(col profileColumn) bool { return col.kind == c.Kind })
+		if i < 0 {
+			return false, fmt.Errorf("profile condition on %s: unsupported value %T", c.Key, c.Value)
+		}
+		col := profileColumns[i]
+		q = q.Where(fmt.Sprintf("%s #> ?::text[] = to_jsonb(%s)", col.name, col.placeholder), jsonPath(c.Key), sqlValue(c.Value))
 	}
 
-	_, err := q.Exec(ctx)
-	return err
+	res, err := q.Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
 }
 
-// buildNumbersExpr builds a SET expression for the numbers JSONB column.
-// Every operation uses per-key jsonb_set so concurrent updates to different keys
-// don't interfere, and increments are atomic (read-modify-write in a single expression).
-func buildNumbersExpr(upd *model.ProfileUpdate) (string, []interface{}) {
-	if len(upd.NumberSets) == 0 && len(upd.NumberIncr) == 0 {
-		return "", nil
+// buildColumnExpr builds the new value of a JSONB column as one expression,
+// or "" when no change touches it. Missing parents of nested keys are
+// created first; then every change is a jsonb_set (#- for deletions) on the
+// result of the previous one. Changes read the column as it was before the
+// statement, so increments, bounds and tag edits are atomic, and concurrent
+// updates to different keys don't interfere.
+func buildColumnExpr(col profileColumn, changes []model.ProfileChange) (string, []any) {
+	expr := col.name
+	var args []any
+
+	var parents []string
+	for _, c := range changes {
+		if c.Kind != col.kind {
+			continue
+		}
+		for i, r := range c.Key {
+			if r == '.' {
+				parents = append(parents, c.Key[:i])
+			}
+		}
+	}
+	// A path sorts before the paths nested in it.
+	slices.Sort(parents)
+	for _, p := range slices.Compact(parents) {
+		expr = fmt.Sprintf("jsonb_set(%s, ?::text[], COALESCE(%s #> ?::text[], '{}'::jsonb))", expr, col.name)
+		args = append(args, jsonPath(p), jsonPath(p))
 	}
 
-	expr := "numbers"
-	var args []interface{}
+	changed := false
+	tagsDone := map[string]bool{}
+	for _, c := range changes {
+		if c.Op == model.ProfileDelete {
+			expr = fmt.Sprintf("(%s #- ?::text[])", expr)
+			args = append(args, jsonPath(c.Key))
+			changed = true
+			continue
+		}
+		if c.Kind != col.kind {
+			continue
+		}
 
-	for key, val := range upd.NumberSets {
-		expr = fmt.Sprintf("jsonb_set(%s, ?::text[], to_jsonb(?::numeric))", expr)
-		args = append(args, "{"+key+"}", val)
+		path := jsonPath(c.Key)
+		switch c.Op {
+		case model.ProfileSet:
+			expr = fmt.Sprintf("jsonb_set(%s, ?::text[], to_jsonb(%s))", expr, col.placeholder)
+			args = append(args, path, sqlValue(c.Value))
+		case model.ProfileIncr:
+			sum, sumArgs := clampExpr(fmt.Sprintf("COALESCE((%s #>> ?::text[])::numeric, 0) + ?::numeric", col.name), c.Key)
+			expr = fmt.Sprintf("jsonb_set(%s, ?::text[], to_jsonb(%s))", expr, sum)
+			args = append(append(args, path, path, c.Value), sumArgs...)
+		case model.ProfileMax, model.ProfileMin:
+			// GREATEST and LEAST ignore the NULL of a missing key.
+			fn := "GREATEST"
+			if c.Op == model.ProfileMin {
+				fn = "LEAST"
+			}
+			expr = fmt.Sprintf("jsonb_set(%s, ?::text[], to_jsonb(%s((%s #>> ?::text[])::numeric, ?::numeric)))", expr, fn, col.name)
+			args = append(args, path, path, c.Value)
+		case model.ProfileSetIfAbsent:
+			expr = fmt.Sprintf("jsonb_set(%s, ?::text[], COALESCE(%s #> ?::text[], to_jsonb(%s)))", expr, col.name, col.placeholder)
+			args = append(args, path, path, sqlValue(c.Value))
+		case model.ProfileAddTags, model.ProfileRemoveTags:
+			if tagsDone[c.Key] {
+				continue
+			}
+			tagsDone[c.Key] = true
+			add, remove := tagEdits(changes, c.Key)
+			expr = fmt.Sprintf("jsonb_set(%s, ?::text[], (SELECT COALESCE(jsonb_agg(DISTINCT e.tag ORDER BY e.tag), '[]'::jsonb)"+
+				" FROM jsonb_array_elements_text(COALESCE(%s #> ?::text[], '[]'::jsonb) || to_jsonb(?::text[])) AS e(tag)"+
+				" WHERE e.tag <> ALL(?::text[])))", expr, col.name)
+			args = append(args, path, path, pgdialect.Array(add), pgdialect.Array(remove))
+		default:
+			continue
+		}
+		changed = true
 	}
 
-	for key, delta := range upd.NumberIncr {
-		expr = fmt.Sprintf(
-			"jsonb_set(%s, ?::text[], to_jsonb(COALESCE((numbers->>?)::numeric, 0) + ?::numeric))",
-			expr,
-		)
-		args = append(args, "{"+key+"}", key, delta)
+	if !changed {
+		return "", nil
 	}
+	return expr, args
+}
 
-	return "numbers = " + expr, args
+// clampExpr bounds a numeric expression to the Min and Max of key in
+// model.ProfileSchema.
+func clampExpr(expr, key string) (string, []any) {
+	var args []any
+	k := model.ProfileSchema[key]
+	if k.Max != nil {
+		expr = fmt.Sprintf("LEAST(%s, ?::numeric)", expr)
+		args = append(args, *k.Max)
+	}
+	if k.Min != nil {
+		expr = fmt.Sprintf("GREATEST(%s, ?::numeric)", expr)
+		args = append(args, *k.Min)
+	}
+	return expr, args
 }
 
-// buildStringsExpr builds a SET expression for the strings JSONB column.
-// Uses per-key jsonb_set for safe concurrent modifications.
-func buildStringsExpr(upd *model.ProfileUpdate) (string, []interface{}) {
-	if len(upd.StringSets) == 0 {
-		return "", nil
+// tagEdits returns the tags added to and removed from key, never nil.
+func tagEdits(changes []model.ProfileChange, key string) (add, remove []string) {
+	add, remove = []string{}, []string{}
+	for _, c := range changes {
+		if c.Key != key {
+			continue
+		}
+		switch c.Op {
+		case model.ProfileAddTags:
+			add = append(add, c.Value.([]string)...)
+		case model.ProfileRemoveTags:
+			remove = append(remove, c.Value.([]string)...)
+		}
 	}
+	return add, remove
+}
 
-	expr := "strings"
-	var args []interface{}
+// jsonPath converts a dotted key to a text[] literal for jsonb_set and #>.
+func jsonPath(key string) string {
+	return "{" + strings.ReplaceAll(key, ".", ",") + "}"
+}
 
-	for key, val := range upd.StringSets {
-		expr = fmt.Sprintf("jsonb_set(%s, ?::text[], to_jsonb(?::text))", expr)
-		args = append(args, "{"+key+"}", val)
+// sqlValue converts a profile value to its SQL argument; times are stored as
+// RFC 3339 strings.
+func sqlValue(v any) any {
+	switch v := v.(type) {
+	case time.Time:
+		return formatProfileTime(v)
+	case []string:
+		return pgdialect.Array(v)
+	default:
+		return v
 	}
+}
 
-	return "strings = " + expr, args
+func formatProfileTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
 }
 
 func toProfileEntity(m *profileModel) *model.Profile {
-	return &model.Profile{
+	p := &model.Profile{
 		UserID:  m.UserID,
-		Numbers: m.Numbers,
-		Strings: m.Strings,
+		Numbers: map[string]float64{},
+		Strings: map[string]string{},
+		Bools:   map[string]bool{},
+		Times:   map[string]time.Time{},
+		Tags:    map[string][]string{},
 	}
+	flatten("", m.Numbers, func(key string, v any) {
+		if n, ok := v.(float64); ok {
+			p.Numbers[key] = n
+		}
+	})
+	flatten("", m.Strings, func(key string, v any) {
+		if s, ok := v.(string); ok {
+			p.Strings[key] = s
+		}
+	})
+	flatten("", m.Bools, func(key string, v any) {
+		if b, ok := v.(bool); ok {
+			p.Bools[key] = b
+		}
+	})
+	flatten("", m.Times, func(key string, v any) {
+		if s, ok := v.(string); ok {
+			if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+				p.Times[key] = t
+			}
+		}
+	})
+	flatten("", m.Tags, func(key string, v any) {
+		if vals, ok := v.([]any); ok {
+			tags := make([]string, 0, len(vals))
+			for _, t := range vals {
+				if s, ok := t.(string); ok {
+					tags = append(tags, s)
+				}
+			}
+			p.Tags[key] = tags
+		}
+	})
+	return p
 }
 
+// flatten calls fn for every non-object value of a decoded JSON object, with
+// its dotted path.
+func flatten(prefix string, obj map[string]any, fn func(key string, v any)) {
+	for k, v := range obj {
+		key := prefix + k
+		if nested, ok := v.(map[string]any); ok {
+			flatten(key+".", nested, fn)
+			continue
+		}
+		fn(key, v)
+	}
+}
+
+// nest is the inverse of flatten.
+func nest[V any](flat map[string]V, conv func(V) any) map[string]any {
+	obj := map[string]any{}
+	for key, v := range flat {
+		parts := strings.Split(key, ".")
+		cur := obj
+		for _, part := range parts[:len(parts)-1] {
+			next, ok := cur[part].(map[string]any)
+			if !ok {
+				next = map[string]any{}
+				cur[part] = next
+			}
+			cur = next
+		}
+		cur[parts[len(parts)-1]] = conv(v)
+	}
+	return obj
+}
+
+func identity[V any](v V) any { return v }
+
 func fromProfileEntity(p *model.Profile) *profileModel {
 	now := time.Now().Unix()
 	return &profileModel{
-		UserID:    p.UserID,
-		Numbers:   p.Numbers,
-		Strings:   p.Strings,
+		UserID:  p.UserID,
+		Numbers: nest(p.Numbers, identity[float64]),
+		Strings: nest(p.Strings, identity[string]),
+		Bools:   nest(p.Bools, identity[bool]),
+		Times: nest(p.Times, func(t time.Time) any {
+			return formatProfileTime(t)
+		}),
+		Tags: nest(p.Tags, func(tags []string) any {
+			tags = append([]string{}, tags...)
+			slices.Sort(tags)
+			return slices.Compact(tags)
+		}),
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
@@ -0,0 +1,149 @@
+//go:build integration
+
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"starter-boilerplate/internal/user/domain/model"
+)
+
+// Profile tests share the user suite's container; profiles reference users.
+
+func (s *UserRepoSuite) newProfile(id string) *profileRepository {
+	ctx := context.Background()
+	s.Require().NoError(s.repo.Create(ctx, newTestUser(id, id+"@example.com")))
+
+	repo := &profileRepository{db: s.pg.DB()}
+	s.Require().NoError(repo.Upsert(ctx, &model.Profile{
+		UserID:  id,
+		Numbers: map[string]float64{"reputation": 10},
+		Strings: map[string]string{"settings.theme": "light", "bio": "hi"},
+		Tags:    map[string][]string{"badges": {"silver", "bronze"}},
+	}))
+	return repo
+}
+
+func (s *UserRepoSuite) updateProfile(repo *profileRepository, id string, upd *model.ProfileUpdate) (*model.Profile, bool) {
+	ctx := context.Background()
+	applied, err := repo.Update(ctx, id, upd)
+	s.Require().NoError(err)
+	p, err := repo.FindByUserID(ctx, id)
+	s.Require().NoError(err)
+	return p, applied
+}
+
+func (s *UserRepoSuite) TestProfileUpsert_NestedRoundTrip() {
+	repo := s.newProfile("id-1")
+
+	p, err := repo.FindByUserID(context.Background(), "id-1")
+	s.Require().NoError(err)
+	s.Assert().Equal(map[string]string{"settings.theme": "light", "bio": "hi"}, p.Strings)
+	s.Assert().Equal(map[string][]string{"badges": {"bronze", "silver"}}, p.Tags)
+}
+
+func (s *UserRepoSuite) TestProfileUpdate_Numbers() {
+	repo := s.newProfile("id-1")
+
+	p, applied := s.updateProfile(repo, "id-1", model.NewProfileUpdate().
+		IncrNumber("reputation", 5).
+		SetMax("logins", 7).
+		IncrNumber("password_changes", 1))
+
+	s.Assert().True(applied)
+	s.Assert().Equal(map[string]float64{"reputation": 15, "logins": 7, "password_changes": 1}, p.Numbers)
+
+	p, _ = s.updateProfile(repo, "id-1", model.NewProfileUpdate().SetMax("logins", 3).SetMin("reputation", 12))
+	s.Assert().Equal(float64(7), p.Numbers["logins"])
+	s.Assert().Equal(float64(12), p.Numbers["reputation"])
+}
+
+func (s *UserRepoSuite) TestProfileUpdate_IncrementsAreClamped() {
+	repo := s.newProfile("id-1")
+
+	p, _ := s.updateProfile(repo, "id-1", model.NewProfileUpdate().
+		IncrNumber("reputation", -50).
+		IncrNumber("logins", 5))
+	s.Assert().Equal(float64(0), p.Numbers["reputation"])
+	s.Assert().Equal(float64(5), p.Numbers["logins"])
+
+	p, _ = s.updateProfile(repo, "id-1", model.NewProfileUpdate().IncrNumber("reputation", 2_000_000))
+	s.Assert().Equal(float64(1_000_000), p.Numbers["reputation"])
+}
+
+func (s *UserRepoSuite) TestProfileUpdate_RejectsSchemaViolations() {
+	repo := s.newProfile("id-1")
+
+	for _, upd := range []*model.ProfileUpdate{
+		model.NewProfileUpdate().SetNumber("score", 1),
+		model.NewProfileUpdate().SetString("password_changes", "2"),
+		model.NewProfileUpdate().SetString("settings.theme", "solarized"),
+	} {
+		_, err := repo.Update(context.Background(), "id-1", upd)
+		s.Assert().Error(err)
+	}
+}
+
+func (s *UserRepoSuite) TestProfileUpdate_NestedKeysKeepSiblings() {
+	repo := s.newProfile("id-1")
+
+	p, _ := s.updateProfile(repo, "id-1", model.NewProfileUpdate().
+		SetString("settings.theme", "dark").
+		SetBool("settings.notifications.email", true).
+		SetBool("settings.notifications.push", false))
+
+	s.Assert().Equal("dark", p.Strings["settings.theme"])
+	s.Assert().Equal(map[string]bool{"settings.notifications.email": true, "settings.notifications.push": false}, p.Bools)
+}
+
+func (s *UserRepoSuite) TestProfileUpdate_SetIfAbsentAndDelete() {
+	repo := s.newProfile("id-1")
+	seen := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	p, _ := s.updateProfile(repo, "id-1", model.NewProfileUpdate().
+		SetIfAbsent("bio", "ignored").
+		SetIfAbsent("nickname", "neo").
+		SetIfAbsent("password_changed_at", seen).
+		DeleteKey("reputation"))
+
+	s.Assert().Equal("hi", p.Strings["bio"])
+	s.Assert().Equal("neo", p.Strings["nickname"])
+	s.Assert().True(seen.Equal(p.Times["password_changed_at"]))
+	s.Assert().NotContains(p.Numbers, "reputation")
+}
+
+func (s *UserRepoSuite) TestProfileUpdate_Tags() {
+	repo := s.newProfile("id-1")
+
+	p, _ := s.updateProfile(repo, "id-1", model.NewProfileUpdate().
+		AddTags("badges", "gold", "silver").
+		RemoveTags("badges", "bronze").
+		AddTags("interests", "go"))
+
+	s.Assert().Equal(map[string][]string{"badges": {"gold", "silver"}, "interests": {"go"}}, p.Tags)
+}
+
+func (s *UserRepoSuite) TestProfileUpdate_CompareAndSet() {
+	repo := s.newProfile("id-1")
+
+	p, applied := s.updateProfile(repo, "id-1", model.NewProfileUpdate().
+		CompareAndSet("settings.theme", "dark", "system").
+		IncrNumber("reputation", 1))
+	s.Assert().False(applied)
+	s.Assert().Equal("light", p.Strings["settings.theme"])
+	s.Assert().Equal(float64(10), p.Numbers["reputation"])
+
+	p, applied = s.updateProfile(repo, "id-1", model.NewProfileUpdate().
+		CompareAndSet("settings.theme", "light", "system").
+		CompareAndSet("reputation", 10, 11))
+	s.Assert().True(applied)
+	s.Assert().Equal("system", p.Strings["settings.theme"])
+	s.Assert().Equal(float64(11), p.Numbers["reputation"])
+}
+
+func (s *UserRepoSuite) TestProfileUpdate_MissingProfile() {
+	applied, err := (&profileRepository{db: s.pg.DB()}).Update(context.Background(), "missing", model.NewProfileUpdate().IncrNumber("logins", 1))
+	s.Require().NoError(err)
+	s.Assert().False(applied)
+}
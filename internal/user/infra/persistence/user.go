@@ -76,6 +76,25 @@ func (r *userRepository) UpdatePassword(ctx context.Context, id, hash string) er
 	return err
 }
 
+func (r *userRepository) List(ctx context.Context, limit, offset int) ([]*model.User, int, error) {
+	var ms []userModel
+	total, err := pkgdb.Conn(ctx, r.db).NewSelect().
+		Model(&ms).
+		OrderExpr("email ASC").
+		Limit(limit).
+		Offset(offset).
+		ScanAndCount(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	users := make([]*model.User, 0, len(ms))
+	for i := range ms {
+		users = append(users, toEntity(&ms[i]))
+	}
+	return users, total, nil
+}
+
 func toEntity(m *userModel) *model.User {
 	return &model.User{
 		ID:           m.ID,
@@ -132,3 +132,18 @@ func (s *UserRepoSuite) TestUpdate_Success() {
 	s.Require().NotNil(found)
 	s.Assert().Equal(model.RoleAdmin, found.Role)
 }
+
+func (s *UserRepoSuite) TestList_PagesByEmail() {
+	ctx := context.Background()
+
+	s.Require().NoError(s.repo.Create(ctx, newTestUser("id-1", "carol@example.com")))
+	s.Require().NoError(s.repo.Create(ctx, newTestUser("id-2", "alice@example.com")))
+	s.Require().NoError(s.repo.Create(ctx, newTestUser("id-3", "bob@example.com")))
+
+	users, total, err := s.repo.List(ctx, 2, 1)
+	s.Require().NoError(err)
+	s.Assert().Equal(3, total)
+	s.Require().Len(users, 2)
+	s.Assert().Equal("bob@example.com", users[0].Email)
+	s.Assert().Equal("carol@example.com", users[1].Email)
+}
@@ -3,6 +3,8 @@
 package user
 
 import (
+	"net/http"
+
 	"starter-boilerplate/internal/shared/centrifugenode"
 	"starter-boilerplate/internal/shared/middleware"
 	"starter-boilerplate/internal/user/app/service"
@@ -10,9 +12,12 @@ import (
 	"starter-boilerplate/internal/user/infra/persistence"
 	"starter-boilerplate/internal/user/transport/consumer"
 	usercontract "starter-boilerplate/internal/user/transport/contract"
+	"starter-boilerplate/internal/user/transport/gateway"
 	"starter-boilerplate/internal/user/transport/handler"
 	pkgamqp "starter-boilerplate/pkg/amqp"
 	pkgdb "starter-boilerplate/pkg/db"
+	pkgevent "starter-boilerplate/pkg/event"
+	pkggrpc "starter-boilerplate/pkg/grpc"
 	pkgjwt "starter-boilerplate/pkg/jwt"
 	"starter-boilerplate/pkg/outbox"
 
@@ -24,11 +29,11 @@ import (
 
 type Module struct{}
 
-func NewModule(_ handler.HandlersInit, _ usercontract.Init, _ consumer.Init, _ consumer.BridgeInit) Module {
+func NewModule(_ handler.HandlersInit, _ usercontract.Init, _ consumer.Init, _ consumer.BridgeInit, _ gateway.Init) Module {
 	return Module{}
 }
 
-func InitializeUserModule(api huma.API, grpcSrv *gogrpc.Server, _ *pkgjwt.Manager, _ *bun.DB, _ outbox.Bus, _ *pkgamqp.Broker, _ pkgdb.UoW, _ *centrifugenode.Publisher, _ middleware.Init) Module {
+func InitializeUserModule(api huma.API, mux *http.ServeMux, grpcSrv *gogrpc.Server, _ *pkggrpc.Loopback, _ *pkgjwt.Manager, _ *bun.DB, _ outbox.Bus, _ *outbox.Repository, _ *pkgamqp.Broker, _ *pkgevent.Hub, _ pkgdb.UoW, _ *centrifugenode.Publisher, _ middleware.Init) Module {
 	wire.Build(
 		persistence.NewUserRepository,
 		persistence.NewProfileRepository,
@@ -39,15 +44,25 @@ func InitializeUserModule(api huma.API, grpcSrv *gogrpc.Server, _ *pkgjwt.Manage
 		usecase.NewGetUserUseCase,
 		usecase.NewRegisterUseCase,
 		usecase.NewChangePasswordUseCase,
+		usecase.NewListUsersUseCase,
+		usecase.NewGetProfileUseCase,
+		usecase.NewUpdateProfileUseCase,
 		service.NewProfileService,
+		service.NewEventStream,
+		usecase.NewWatchUserEventsUseCase,
 		handler.NewLoginHandler,
 		handler.NewRefreshHandler,
 		handler.NewGetUserHandler,
 		handler.NewRegisterHandler,
 		handler.NewChangePasswordHandler,
+		handler.NewGetProfileHandler,
+		handler.NewUpdateProfileHandler,
 		handler.SetupHandlers,
+		wire.Struct(new(usercontract.UseCases), "*"),
 		usercontract.SetupUserContract,
+		gateway.SetupGateway,
 		consumer.NewProfileUpdaterConsumer,
+		consumer.NewEventWatchConsumer,
 		consumer.SetupConsumers,
 		consumer.NewBridgeConsumer,
 		consumer.SetupBridgeConsumer,
@@ -14,6 +14,12 @@ import (
 
 const queueCentrifugeBridge = "centrifuge.bridge"
 
+var bridgeConfig = pkgamqp.ConsumerConfig{
+	Queue:      queueCentrifugeBridge,
+	Exchange:   event.ExchangeEvents,
+	RoutingKey: "user.#",
+}
+
 type BridgeConsumer struct {
 	publisherSvc *centrifugenode.Publisher
 }
@@ -39,16 +45,13 @@ func (c *BridgeConsumer) Register(b *pkgamqp.Broker) {
 	sharedevent.Route(r, c.onUserCreated)
 	sharedevent.Route(r, c.onPasswordChanged)
 	sharedevent.Route(r, c.onUserLoggedIn)
+	sharedevent.Route(r, c.onProfileUpdated)
 	r.Default(func(_ context.Context, _ []byte, meta pkgamqp.DeliveryMeta) error {
 		slog.Warn("centrifuge bridge: unhandled event", slog.String("routing_key", meta.RoutingKey))
 		return nil
 	})
 
-	pkgamqp.AddRawConsumer(b, pkgamqp.ConsumerConfig{
-		Queue:      queueCentrifugeBridge,
-		Exchange:   event.ExchangeEvents,
-		RoutingKey: "user.#",
-	}, r.Handler())
+	pkgamqp.AddRawConsumer(b, bridgeConfig, r.Handler())
 }
 
 func (c *BridgeConsumer) onUserCreated(ctx context.Context, e userevent.UserCreatedEvent, _ pkgamqp.DeliveryMeta) error {
@@ -65,3 +68,8 @@ func (c *BridgeConsumer) onUserLoggedIn(ctx context.Context, e userevent.UserLog
 	payload, _ := json.Marshal(e)
 	return c.publisherSvc.PublishPersonal(ctx, e.UserID, userevent.UserLoggedIn, payload)
 }
+
+func (c *BridgeConsumer) onProfileUpdated(ctx context.Context, e userevent.ProfileUpdatedEvent, _ pkgamqp.DeliveryMeta) error {
+	payload, _ := json.Marshal(e)
+	return c.publisherSvc.PublishPersonal(ctx, e.UserID, userevent.ProfileUpdated, payload)
+}
@@ -0,0 +1,75 @@
+package consumer
+
+import (
+	"context"
+	"time"
+
+	pkgamqp "starter-boilerplate/pkg/amqp"
+	"starter-boilerplate/pkg/event"
+	"starter-boilerplate/pkg/outbox"
+
+	"github.com/google/uuid"
+	amqp091 "github.com/rabbitmq/amqp091-go"
+)
+
+const queueEventWatchPrefix = "user.events.watch."
+
+// EventWatchConsumer feeds the user events hub behind WatchUserEvents. Every
+// instance consumes all user events through its own transient queue, since
+// each one serves its own streams. The queue is exclusive rather than
+// auto-delete: it goes away with the connection, but survives the consume
+// rounds that pauses and prefetch changes end, buffering events meanwhile.
+type EventWatchConsumer struct {
+	hub *event.Hub
+	cfg pkgamqp.ConsumerConfig
+}
+
+func NewEventWatchConsumer(hub *event.Hub) *EventWatchConsumer {
+	durable := false
+	return &EventWatchConsumer{
+		hub: hub,
+		cfg: pkgamqp.ConsumerConfig{
+			Queue:         queueEventWatchPrefix + uuid.NewString(),
+			Exchange:      event.ExchangeEvents,
+			RoutingKey:    "user.#",
+			Durable:       &durable,
+			Exclusive:     true,
+			PrefetchCount: 100,
+			// Live events only: nobody needs them once they are old, and
+			// clients catch up from the outbox.
+			MessageTTL: time.Minute,
+			MaxLength:  10000,
+		},
+	}
+}
+
+func (c *EventWatchConsumer) Register(b *pkgamqp.Broker) {
+	pkgamqp.AddRawConsumer(b, c.cfg, c.handle)
+}
+
+// handle never blocks: the hub drops subscribers that fall behind.
+func (c *EventWatchConsumer) handle(_ context.Context, body []byte, meta pkgamqp.DeliveryMeta) error {
+	key, _ := meta.Headers[outbox.HeaderPartitionKey].(string)
+	c.hub.Publish(event.Envelope{
+		Position: position(meta.Headers),
+		Name:     meta.RoutingKey,
+		Key:      key,
+		Payload:  body,
+	})
+	return nil
+}
+
+// position reads the outbox_position header, whose integer type depends on
+// how the table was encoded.
+func position(headers amqp091.Table) int64 {
+	switch v := headers[outbox.HeaderPosition].(type) {
+	case int64:
+		return v
+	case int32:
+		return int64(v)
+	case int:
+		return int64(v)
+	default:
+		return 0
+	}
+}
@@ -14,6 +14,15 @@ import (
 
 const queueProfileUpdater = "tag.profile"
 
+var profileUpdaterConfig = pkgamqp.ConsumerConfig{
+	Queue:    queueProfileUpdater,
+	Exchange: event.ExchangeTagged,
+	BindingArgs: amqp091.Table{
+		"x-match":     "any",
+		"tag.profile": true,
+	},
+}
+
 type ProfileUpdaterConsumer struct {
 	profileSvc *service.ProfileService
 }
@@ -25,17 +34,11 @@ func NewProfileUpdaterConsumer(ps *service.ProfileService) *ProfileUpdaterConsum
 func (c *ProfileUpdaterConsumer) Register(b *pkgamqp.Broker) {
 	r := sharedevent.NewRouter()
 	sharedevent.Route(r, c.profileSvc.OnUserCreated)
+	sharedevent.Route(r, c.profileSvc.OnUserLoggedIn)
 	sharedevent.Route(r, c.profileSvc.OnPasswordChanged)
 	r.Default(func(_ context.Context, _ []byte, meta pkgamqp.DeliveryMeta) error {
 		slog.Warn("profile updater: unhandled event", slog.String("routing_key", meta.RoutingKey))
 		return nil
 	})
-	pkgamqp.AddRawConsumer(b, pkgamqp.ConsumerConfig{
-		Queue:    queueProfileUpdater,
-		Exchange: event.ExchangeTagged,
-		BindingArgs: amqp091.Table{
-			"x-match":     "any",
-			"tag.profile": true,
-		},
-	}, r.Handler())
+	pkgamqp.AddRawConsumer(b, profileUpdaterConfig, r.Handler())
 }
@@ -6,7 +6,15 @@ import (
 
 type Init struct{}
 
-func SetupConsumers(b *pkgamqp.Broker, profileUpdater *ProfileUpdaterConsumer) Init {
+// Topology returns the queues and bindings of every user consumer, including
+// ones registered conditionally, for tooling that needs it without wiring the module.
+// The per-instance event watch queue is transient and not part of it.
+func Topology() pkgamqp.Topology {
+	return pkgamqp.MergeTopologies(profileUpdaterConfig.Topology(), bridgeConfig.Topology())
+}
+
+func SetupConsumers(b *pkgamqp.Broker, profileUpdater *ProfileUpdaterConsumer, eventWatch *EventWatchConsumer) Init {
 	profileUpdater.Register(b)
+	eventWatch.Register(b)
 	return Init{}
 }
@@ -2,37 +2,310 @@ package contract
 
 import (
 	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/mail"
 
 	gen "starter-boilerplate/gen/user"
-	"starter-boilerplate/internal/user/domain/repository"
-	"starter-boilerplate/internal/user/transport/dto"
+	"starter-boilerplate/internal/shared/middleware"
+	"starter-boilerplate/internal/user/app/service"
+	"starter-boilerplate/internal/user/app/usecase"
+	"starter-boilerplate/internal/user/domain/model"
+	"starter-boilerplate/pkg/apperror"
+	pkgevent "starter-boilerplate/pkg/event"
+	pkggrpc "starter-boilerplate/pkg/grpc"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+const (
+	defaultListLimit  = 20
+	maxListLimit      = 100
+	minPasswordLength = 6
+)
+
+// UseCases groups the use cases behind the contract, so that every RPC runs
+// exactly what its HTTP endpoint runs.
+type UseCases struct {
+	Login          *usecase.LoginUseCase
+	Register       *usecase.RegisterUseCase
+	Refresh        *usecase.RefreshUseCase
+	ChangePassword *usecase.ChangePasswordUseCase
+	GetUser        *usecase.GetUserUseCase
+	ListUsers      *usecase.ListUsersUseCase
+	GetProfile     *usecase.GetProfileUseCase
+	UpdateProfile  *usecase.UpdateProfileUseCase
+	WatchEvents    *usecase.WatchUserEventsUseCase
+}
+
 type Contract struct {
 	gen.UnimplementedUserContractServer
-	ur repository.UserRepository
+	uc UseCases
 }
 
 type Init struct{}
 
-func SetupUserContract(grpcSrv *grpc.Server, ur repository.UserRepository) Init {
-	c := &Contract{ur: ur}
+// SetupUserContract registers the contract. Authentication and roles are
+// enforced by the server's auth interceptor from the auth.rule method options,
+// so authenticated RPCs read their claims with middleware.NewAuthCtx.
+func SetupUserContract(grpcSrv *grpc.Server, uc UseCases) Init {
+	c := &Contract{uc: uc}
 	gen.RegisterUserContractServer(grpcSrv, c)
 	return Init{}
 }
 
+func (c *Contract) Login(ctx context.Context, req *gen.LoginRequest) (*gen.TokenPair, error) {
+	if err := validateCredentials(req.Email, req.Password); err != nil {
+		return nil, err
+	}
+	ip, userAgent := clientInfo(ctx)
+	pair, err := c.uc.Login.Execute(ctx, req.Email, req.Password, ip, userAgent)
+	if err != nil {
+		return nil, err
+	}
+	return toTokenPair(pair), nil
+}
+
+func (c *Contract) Register(ctx context.Context, req *gen.RegisterRequest) (*gen.TokenPair, error) {
+	if err := validateCredentials(req.Email, req.Password); err != nil {
+		return nil, err
+	}
+	pair, err := c.uc.Register.Execute(ctx, req.Email, req.Password)
+	if err != nil {
+		return nil, err
+	}
+	return toTokenPair(pair), nil
+}
+
+func (c *Contract) Refresh(ctx context.Context, req *gen.RefreshRequest) (*gen.TokenPair, error) {
+	if req.RefreshToken == "" {
+		return nil, invalidField("refresh_token", "required", "refresh_token is required", "refresh_token")
+	}
+	pair, err := c.uc.Refresh.Execute(ctx, req.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+	return toTokenPair(pair), nil
+}
+
+func (c *Contract) ChangePassword(ctx context.Context, req *gen.ChangePasswordRequest) (*gen.ChangePasswordResponse, error) {
+	authCtx := middleware.NewAuthCtx(ctx)
+	if len(req.OldPassword) < minPasswordLength {
+		return nil, invalidField("old_password", "min_length", "passwords must be at least 6 characters", 6)
+	}
+	if len(req.NewPassword) < minPasswordLength {
+		return nil, invalidField("new_password", "min_length", "passwords must be at least 6 characters", 6)
+	}
+	if err := c.uc.ChangePassword.Execute(authCtx, req.OldPassword, req.NewPassword); err != nil {
+		return nil, err
+	}
+	return &gen.ChangePasswordResponse{}, nil
+}
+
 func (c *Contract) GetUser(ctx context.Context, req *gen.GetUserRequest) (*gen.GetUserResponse, error) {
-	u, err := c.ur.FindByID(ctx, req.Id)
+	authCtx := middleware.NewAuthCtx(ctx)
+	u, err := c.uc.GetUser.Execute(authCtx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &gen.GetUserResponse{Id: u.ID, Email: u.Email, Role: string(u.Role)}, nil
+}
+
+func (c *Contract) ListUsers(ctx context.Context, req *gen.ListUsersRequest) (*gen.ListUsersResponse, error) {
+	authCtx := middleware.NewAuthCtx(ctx)
+	limit := int(req.Limit)
+	if limit == 0 {
+		limit = defaultListLimit
+	}
+	if limit < 0 || limit > maxListLimit || req.Offset < 0 {
+		return nil, invalidArgument("limit must be between 1 and 100 and offset must not be negative")
+	}
+
+	users, total, err := c.uc.ListUsers.Execute(authCtx, limit, int(req.Offset))
 	if err != nil {
-		return nil, status.Error(codes.NotFound, "user not found")
+		return nil, err
+	}
+	resp := &gen.ListUsersResponse{Users: make([]*gen.User, 0, len(users)), Total: int64(total)}
+	for _, u := range users {
+		resp.Users = append(resp.Users, toUser(u))
+	}
+	return resp, nil
+}
+
+func (c *Contract) GetProfile(ctx context.Context, req *gen.GetProfileRequest) (*gen.Profile, error) {
+	authCtx := middleware.NewAuthCtx(ctx)
+	p, err := c.uc.GetProfile.Execute(authCtx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	return toProfile(p), nil
+}
+
+func (c *Contract) UpdateProfile(ctx context.Context, req *gen.UpdateProfileRequest) (*gen.Profile, error) {
+	authCtx := middleware.NewAuthCtx(ctx)
+	upd := model.NewProfileUpdate()
+	for k, v := range req.SetNumbers {
+		upd.SetNumber(k, v)
+	}
+	for k, v := range req.IncrNumbers {
+		upd.IncrNumber(k, v)
+	}
+	for k, v := range req.SetStrings {
+		upd.SetString(k, v)
 	}
-	if u == nil {
-		return nil, status.Error(codes.NotFound, "user not found")
+	for k, v := range req.MaxNumbers {
+		upd.SetMax(k, v)
+	}
+	for k, v := range req.MinNumbers {
+		upd.SetMin(k, v)
+	}
+	for k, v := range req.SetBools {
+		upd.SetBool(k, v)
+	}
+	for k, v := range req.SetTimes {
+		upd.SetTime(k, v.AsTime())
+	}
+	for k, v := range req.AddTags {
+		upd.AddTags(k, v.GetValues()...)
+	}
+	for k, v := range req.RemoveTags {
+		upd.RemoveTags(k, v.GetValues()...)
+	}
+	for k, v := range req.SetIfAbsent {
+		upd.SetIfAbsent(k, fromProfileValue(v))
+	}
+	for _, k := range req.DeleteKeys {
+		upd.DeleteKey(k)
+	}
+	for _, cas := range req.CompareAndSet {
+		upd.CompareAndSet(cas.Key, fromProfileValue(cas.Expected), fromProfileValue(cas.Value))
+	}
+
+	p, err := c.uc.UpdateProfile.Execute(authCtx, req.UserId, upd)
+	if err != nil {
+		return nil, err
+	}
+	return toProfile(p), nil
+}
+
+// WatchUserEvents streams until the client cancels. A client that falls
+// behind gets ResourceExhausted, and every client gets Unavailable on
+// shutdown; both resume with the last position received. A position the
+// outbox no longer reaches back to gets OutOfRange: the client resyncs and
+// watches from the current state.
+func (c *Contract) WatchUserEvents(req *gen.WatchUserEventsRequest, stream gen.UserContract_WatchUserEventsServer) error {
+	after := int64(service.LiveOnly)
+	if req.AfterPosition != nil {
+		if after = req.GetAfterPosition(); after < 0 {
+			return invalidField("after_position", "minimum", "after_position must not be negative", 0)
+		}
+	}
+
+	authCtx := middleware.NewAuthCtx(stream.Context())
+	err := c.uc.WatchEvents.Execute(authCtx, req.UserId, after, func(e pkgevent.Envelope) error {
+		return stream.Send(&gen.UserEvent{Position: e.Position, Name: e.Name, UserId: e.Key, Payload: e.Payload})
+	})
+	switch {
+	case errors.Is(err, pkgevent.ErrSlowSubscriber):
+		return status.Error(codes.ResourceExhausted, "client too slow, resume from the last position")
+	case errors.Is(err, pkgevent.ErrHubClosed):
+		return status.Error(codes.Unavailable, "server shutting down, resume from the last position")
+	case errors.Is(err, service.ErrPositionExpired):
+		return status.Error(codes.OutOfRange, "after_position is older than the retained events, resync and watch without it")
+	}
+	return err
+}
+
+func validateCredentials(email, password string) error {
+	if _, err := mail.ParseAddress(email); err != nil {
+		return invalidField("email", "format", "email must be a valid email address", "email")
+	}
+	if len(password) < minPasswordLength {
+		return invalidField("password", "min_length", "password must be at least 6 characters", 6)
+	}
+	return nil
+}
+
+func invalidArgument(msg string) error {
+	return apperror.New(http.StatusBadRequest, msg)
+}
+
+// invalidField reports one invalid request field; reason names the violated
+// constraint like the JSON Schema keyword of the HTTP input, and args are
+// what translations of the reason need, as for the HTTP input.
+func invalidField(field, reason, msg string, args ...any) error {
+	return apperror.New(http.StatusBadRequest, msg).WithField(field, reason, msg, args...)
+}
+
+// clientInfo returns the caller's IP and user agent, preferring proxy metadata
+// like the HTTP login handler, and the HTTP client's user agent on calls from
+// the gateways.
+func clientInfo(ctx context.Context) (ip, userAgent string) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if v := md.Get("x-forwarded-for"); len(v) > 0 {
+		ip = v[0]
+	} else if v := md.Get("x-real-ip"); len(v) > 0 {
+		ip = v[0]
+	} else if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		host, _, err := net.SplitHostPort(p.Addr.String())
+		if err != nil {
+			host = p.Addr.String()
+		}
+		ip = host
+	}
+	if v := md.Get(pkggrpc.ForwardedUserAgentKey); len(v) > 0 {
+		userAgent = v[0]
+	} else if v := md.Get("user-agent"); len(v) > 0 {
+		userAgent = v[0]
+	}
+	return ip, userAgent
+}
+
+func toTokenPair(tp *model.TokenPair) *gen.TokenPair {
+	return &gen.TokenPair{AccessToken: tp.AccessToken, RefreshToken: tp.RefreshToken}
+}
+
+func toUser(u *model.User) *gen.User {
+	return &gen.User{Id: u.ID, Email: u.Email, Role: string(u.Role)}
+}
+
+func toProfile(p *model.Profile) *gen.Profile {
+	out := &gen.Profile{
+		UserId:  p.UserID,
+		Numbers: p.Numbers,
+		Strings: p.Strings,
+		Bools:   p.Bools,
+		Times:   make(map[string]*timestamppb.Timestamp, len(p.Times)),
+		Tags:    make(map[string]*gen.StringList, len(p.Tags)),
+	}
+	for k, v := range p.Times {
+		out.Times[k] = timestamppb.New(v)
+	}
+	for k, v := range p.Tags {
+		out.Tags[k] = &gen.StringList{Values: v}
+	}
+	return out
+}
+
+// fromProfileValue returns the Go value of a ProfileValue; an unset one is
+// nil, which the use case rejects as a value of the wrong type.
+func fromProfileValue(v *gen.ProfileValue) any {
+	switch k := v.GetKind().(type) {
+	case *gen.ProfileValue_Number:
+		return k.Number
+	case *gen.ProfileValue_String_:
+		return k.String_
+	case *gen.ProfileValue_Bool:
+		return k.Bool
+	case *gen.ProfileValue_Time:
+		return k.Time.AsTime()
+	default:
+		return nil
 	}
-	d := dto.NewUserDTO(u)
-	return &gen.GetUserResponse{Id: d.ID, Email: d.Email, Role: d.Role}, nil
 }
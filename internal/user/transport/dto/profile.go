@@ -0,0 +1,35 @@
+package dto
+
+import (
+	"time"
+
+	"starter-boilerplate/internal/user/domain/model"
+)
+
+type ProfileDTO struct {
+	UserID  string               `json:"user_id"`
+	Numbers map[string]float64   `json:"numbers"`
+	Strings map[string]string    `json:"strings"`
+	Bools   map[string]bool      `json:"bools"`
+	Times   map[string]time.Time `json:"times"`
+	Tags    map[string][]string  `json:"tags"`
+}
+
+func NewProfileDTO(p *model.Profile) ProfileDTO {
+	return ProfileDTO{
+		UserID:  p.UserID,
+		Numbers: nonNil(p.Numbers),
+		Strings: nonNil(p.Strings),
+		Bools:   nonNil(p.Bools),
+		Times:   nonNil(p.Times),
+		Tags:    nonNil(p.Tags),
+	}
+}
+
+// nonNil makes missing columns encode as {} rather than null.
+func nonNil[V any](m map[string]V) map[string]V {
+	if m == nil {
+		return map[string]V{}
+	}
+	return m
+}
@@ -0,0 +1,35 @@
+//go:build unit
+
+package dto
+
+import (
+	"testing"
+
+	"starter-boilerplate/internal/user/domain/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewProfileDTO(t *testing.T) {
+	p := &model.Profile{
+		UserID:  "user-123",
+		Numbers: map[string]float64{"reputation": 10},
+		Strings: map[string]string{"nickname": "neo"},
+	}
+
+	dto := NewProfileDTO(p)
+
+	assert.Equal(t, "user-123", dto.UserID)
+	assert.Equal(t, map[string]float64{"reputation": 10}, dto.Numbers)
+	assert.Equal(t, map[string]string{"nickname": "neo"}, dto.Strings)
+}
+
+func TestNewProfileDTO_NilMaps(t *testing.T) {
+	dto := NewProfileDTO(&model.Profile{UserID: "user-123"})
+
+	assert.NotNil(t, dto.Numbers)
+	assert.NotNil(t, dto.Strings)
+	assert.NotNil(t, dto.Bools)
+	assert.NotNil(t, dto.Times)
+	assert.NotNil(t, dto.Tags)
+}
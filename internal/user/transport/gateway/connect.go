@@ -0,0 +1,52 @@
+package gateway
+
+import (
+	"context"
+
+	gen "starter-boilerplate/gen/user"
+	pkggrpc "starter-boilerplate/pkg/grpc"
+
+	"connectrpc.com/connect"
+)
+
+// connectContract serves the Connect handler by forwarding every call to the
+// gRPC contract.
+type connectContract struct {
+	client gen.UserContractClient
+}
+
+func (c *connectContract) Login(ctx context.Context, req *connect.Request[gen.LoginRequest]) (*connect.Response[gen.TokenPair], error) {
+	return pkggrpc.ForwardUnary(ctx, req, c.client.Login)
+}
+
+func (c *connectContract) Register(ctx context.Context, req *connect.Request[gen.RegisterRequest]) (*connect.Response[gen.TokenPair], error) {
+	return pkggrpc.ForwardUnary(ctx, req, c.client.Register)
+}
+
+func (c *connectContract) Refresh(ctx context.Context, req *connect.Request[gen.RefreshRequest]) (*connect.Response[gen.TokenPair], error) {
+	return pkggrpc.ForwardUnary(ctx, req, c.client.Refresh)
+}
+
+func (c *connectContract) ChangePassword(ctx context.Context, req *connect.Request[gen.ChangePasswordRequest]) (*connect.Response[gen.ChangePasswordResponse], error) {
+	return pkggrpc.ForwardUnary(ctx, req, c.client.ChangePassword)
+}
+
+func (c *connectContract) GetUser(ctx context.Context, req *connect.Request[gen.GetUserRequest]) (*connect.Response[gen.GetUserResponse], error) {
+	return pkggrpc.ForwardUnary(ctx, req, c.client.GetUser)
+}
+
+func (c *connectContract) ListUsers(ctx context.Context, req *connect.Request[gen.ListUsersRequest]) (*connect.Response[gen.ListUsersResponse], error) {
+	return pkggrpc.ForwardUnary(ctx, req, c.client.ListUsers)
+}
+
+func (c *connectContract) GetProfile(ctx context.Context, req *connect.Request[gen.GetProfileRequest]) (*connect.Response[gen.Profile], error) {
+	return pkggrpc.ForwardUnary(ctx, req, c.client.GetProfile)
+}
+
+func (c *connectContract) UpdateProfile(ctx context.Context, req *connect.Request[gen.UpdateProfileRequest]) (*connect.Response[gen.Profile], error) {
+	return pkggrpc.ForwardUnary(ctx, req, c.client.UpdateProfile)
+}
+
+func (c *connectContract) WatchUserEvents(ctx context.Context, req *connect.Request[gen.WatchUserEventsRequest], stream *connect.ServerStream[gen.UserEvent]) error {
+	return pkggrpc.ForwardServerStream(ctx, req, stream, c.client.WatchUserEvents)
+}
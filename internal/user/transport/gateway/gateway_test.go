@@ -0,0 +1,309 @@
+//go:build unit
+
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	gen "starter-boilerplate/gen/user"
+	"starter-boilerplate/gen/user/userconnect"
+	"starter-boilerplate/internal/shared/i18n"
+	"starter-boilerplate/internal/shared/middleware"
+	"starter-boilerplate/pkg/apperror"
+	pkggrpc "starter-boilerplate/pkg/grpc"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// fakeContract fails GetUser with the AppError status named by the ID and
+// echoes the forwarded metadata from Login.
+type fakeContract struct {
+	gen.UnimplementedUserContractServer
+}
+
+var errorsByID = map[string]error{
+	"bad":       apperror.New(http.StatusBadRequest, "bad"),
+	"anonymous": apperror.New(http.StatusUnauthorized, "anonymous"),
+	"forbidden": apperror.New(http.StatusForbidden, "forbidden"),
+	"missing":   apperror.New(http.StatusNotFound, "missing"),
+	"taken":     apperror.New(http.StatusConflict, "taken"),
+	"limited":   apperror.New(http.StatusTooManyRequests, "limited"),
+	"invalid":   apperror.New(http.StatusUnprocessableEntity, "invalid"),
+	"down":      apperror.New(http.StatusServiceUnavailable, "down"),
+	"broken":    errors.New("db is down"),
+	"malformed": apperror.New(http.StatusBadRequest, "id is malformed").WithCode("invalid_id").WithField("id", "format", "id is malformed"),
+}
+
+func (fakeContract) GetUser(_ context.Context, req *gen.GetUserRequest) (*gen.GetUserResponse, error) {
+	if err, ok := errorsByID[req.Id]; ok {
+		return nil, err
+	}
+	return &gen.GetUserResponse{Id: req.Id, Email: "a@b.c", Role: "user"}, nil
+}
+
+func (fakeContract) Login(ctx context.Context, _ *gen.LoginRequest) (*gen.TokenPair, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	_ = grpc.SetHeader(ctx, metadata.Pairs("x-request-id", first(md, "x-request-id")))
+	return &gen.TokenPair{
+		AccessToken:  first(md, "authorization"),
+		RefreshToken: first(md, pkggrpc.ForwardedUserAgentKey),
+	}, nil
+}
+
+func (fakeContract) WatchUserEvents(req *gen.WatchUserEventsRequest, stream gen.UserContract_WatchUserEventsServer) error {
+	for i := int64(1); i <= 2; i++ {
+		if err := stream.Send(&gen.UserEvent{Position: i, Name: "user.test", UserId: req.UserId}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func first(md metadata.MD, key string) string {
+	if v := md.Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+func setupServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	catalog := i18n.NewCatalog()
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(middleware.NewGRPCLanguageInterceptor(catalog), pkggrpc.ErrorInterceptor(apperror.Config{}, nil)),
+		grpc.ChainStreamInterceptor(middleware.NewGRPCStreamLanguageInterceptor(catalog), pkggrpc.StreamErrorInterceptor(apperror.Config{}, nil)),
+	)
+	gen.RegisterUserContractServer(srv, fakeContract{})
+	loopback := pkggrpc.SetupLoopback(pkggrpc.GRPCConfig{})
+	go func() { _ = loopback.Serve(srv) }()
+
+	mux := http.NewServeMux()
+	SetupGateway(mux, loopback)
+	ts := httptest.NewServer(mux)
+
+	t.Cleanup(func() {
+		ts.Close()
+		srv.Stop()
+		_ = loopback.Close()
+	})
+	return ts
+}
+
+func TestGateway_ErrorStatusesMatchHTTPAPI(t *testing.T) {
+	ts := setupServer(t)
+
+	tests := []struct {
+		id     string
+		status int
+	}{
+		{"u1", http.StatusOK},
+		{"bad", http.StatusBadRequest},
+		{"anonymous", http.StatusUnauthorized},
+		{"forbidden", http.StatusForbidden},
+		{"missing", http.StatusNotFound},
+		{"taken", http.StatusConflict},
+		{"limited", http.StatusTooManyRequests},
+		{"down", http.StatusServiceUnavailable},
+		{"broken", http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			jsonResp, err := http.Get(ts.URL + "/v1/users/" + tt.id)
+			require.NoError(t, err)
+			defer jsonResp.Body.Close()
+			assert.Equal(t, tt.status, jsonResp.StatusCode, "gateway")
+
+			connectResp, err := http.Post(ts.URL+"/user.UserContract/GetUser", "application/json", strings.NewReader(`{"id":"`+tt.id+`"}`))
+			require.NoError(t, err)
+			defer connectResp.Body.Close()
+			assert.Equal(t, tt.status, connectResp.StatusCode, "connect")
+		})
+	}
+}
+
+// Connect has a fixed status per code; the HTTP/JSON gateway restores 422
+// from the ErrorInfo.
+func TestGateway_UnprocessableKeepsItsStatus(t *testing.T) {
+	ts := setupServer(t)
+
+	jsonResp, err := http.Get(ts.URL + "/v1/users/invalid")
+	require.NoError(t, err)
+	defer jsonResp.Body.Close()
+	assert.Equal(t, http.StatusUnprocessableEntity, jsonResp.StatusCode)
+
+	connectResp, err := http.Post(ts.URL+"/user.UserContract/GetUser", "application/json", strings.NewReader(`{"id":"invalid"}`))
+	require.NoError(t, err)
+	defer connectResp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, connectResp.StatusCode)
+}
+
+func TestGateway_InternalErrorIsHidden(t *testing.T) {
+	ts := setupServer(t)
+
+	resp, err := http.Get(ts.URL + "/v1/users/broken")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var body apperror.Problem
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "internal server error", body.Detail)
+}
+
+func TestGateway_ProblemDetails(t *testing.T) {
+	ts := setupServer(t)
+
+	resp, err := http.Get(ts.URL + "/v1/users/malformed")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "application/problem+json", resp.Header.Get("Content-Type"))
+	var body apperror.Problem
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "invalid_id", body.Code)
+	assert.Equal(t, "about:blank", body.Type)
+	assert.Equal(t, "Bad Request", body.Title)
+	assert.Equal(t, []apperror.FieldViolation{{Field: "id", Reason: "format", Message: "id is malformed"}}, body.Errors)
+}
+
+func TestGateway_ConnectErrorDetails(t *testing.T) {
+	ts := setupServer(t)
+	client := userconnect.NewUserContractClient(ts.Client(), ts.URL)
+
+	_, err := client.GetUser(context.Background(), connect.NewRequest(&gen.GetUserRequest{Id: "malformed"}))
+
+	var cerr *connect.Error
+	require.ErrorAs(t, err, &cerr)
+	assert.Equal(t, connect.CodeInvalidArgument, cerr.Code())
+	var reasons []string
+	for _, d := range cerr.Details() {
+		if v, err := d.Value(); err == nil {
+			if info, ok := v.(*errdetails.ErrorInfo); ok {
+				reasons = append(reasons, info.Reason)
+			}
+		}
+	}
+	assert.Equal(t, []string{"invalid_id"}, reasons)
+}
+
+func TestGateway_LocalizedErrors(t *testing.T) {
+	ts := setupServer(t)
+
+	req, err := http.NewRequest(http.MethodGet, ts.URL+"/v1/users/missing", nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept-Language", "ru-RU,ru;q=0.9")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, "ru", resp.Header.Get("Content-Language"))
+	var body apperror.Problem
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "не найдено", body.Detail)
+}
+
+func TestGateway_ConnectLocalizedErrors(t *testing.T) {
+	ts := setupServer(t)
+	client := userconnect.NewUserContractClient(ts.Client(), ts.URL)
+	req := connect.NewRequest(&gen.GetUserRequest{Id: "missing"})
+	req.Header().Set("Accept-Language", "es")
+
+	_, err := client.GetUser(context.Background(), req)
+
+	var cerr *connect.Error
+	require.ErrorAs(t, err, &cerr)
+	assert.Equal(t, "missing", cerr.Message())
+	var localized []string
+	for _, d := range cerr.Details() {
+		if v, err := d.Value(); err == nil {
+			if lm, ok := v.(*errdetails.LocalizedMessage); ok {
+				localized = append(localized, lm.Locale+": "+lm.Message)
+			}
+		}
+	}
+	assert.Equal(t, []string{"es: no encontrado"}, localized)
+}
+
+func TestGateway_ForwardsHeaders(t *testing.T) {
+	ts := setupServer(t)
+
+	for _, path := range []string{"/v1/auth/login", "/user.UserContract/Login"} {
+		t.Run(path, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, ts.URL+path, strings.NewReader(`{"email":"a@b.c","password":"secret"}`))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer token")
+			req.Header.Set("User-Agent", "curl/8.0")
+			req.Header.Set("X-Request-Id", "req-1")
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var pair struct {
+				AccessToken  string `json:"accessToken"`
+				RefreshToken string `json:"refreshToken"`
+			}
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&pair))
+			assert.Equal(t, "Bearer token", pair.AccessToken)
+			assert.Equal(t, "curl/8.0", pair.RefreshToken)
+			assert.Equal(t, "req-1", resp.Header.Get("X-Request-Id"))
+		})
+	}
+}
+
+func TestGateway_ConnectServerStream(t *testing.T) {
+	ts := setupServer(t)
+	client := userconnect.NewUserContractClient(ts.Client(), ts.URL)
+
+	stream, err := client.WatchUserEvents(context.Background(), connect.NewRequest(&gen.WatchUserEventsRequest{UserId: "u1"}))
+	require.NoError(t, err)
+	defer stream.Close()
+
+	var positions []int64
+	for stream.Receive() {
+		assert.Equal(t, "u1", stream.Msg().UserId)
+		positions = append(positions, stream.Msg().Position)
+	}
+	require.NoError(t, stream.Err())
+	assert.Equal(t, []int64{1, 2}, positions)
+}
+
+func TestGateway_JSONServerStream(t *testing.T) {
+	ts := setupServer(t)
+
+	resp, err := http.Get(ts.URL + "/v1/users/u1/events")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var positions []string
+	dec := json.NewDecoder(resp.Body)
+	for dec.More() {
+		var line struct {
+			Result struct {
+				Position string `json:"position"`
+				UserID   string `json:"userId"`
+			} `json:"result"`
+		}
+		require.NoError(t, dec.Decode(&line))
+		assert.Equal(t, "u1", line.Result.UserID)
+		positions = append(positions, line.Result.Position)
+	}
+	assert.Equal(t, []string{"1", "2"}, positions)
+}
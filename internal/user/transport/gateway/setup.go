@@ -0,0 +1,48 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	gen "starter-boilerplate/gen/user"
+	"starter-boilerplate/gen/user/userconnect"
+	pkggrpc "starter-boilerplate/pkg/grpc"
+)
+
+type Init struct{}
+
+// SetupGateway mounts UserContract on the HTTP mux twice: as HTTP/JSON under
+// /v1/ (gRPC-Gateway, routes from proto/user/user_gateway.yaml) and under
+// /user.UserContract/ for the Connect, gRPC and gRPC-Web protocols. Both call
+// the gRPC server through the loopback, so auth and errors behave exactly as
+// on the gRPC port.
+func SetupGateway(mux *http.ServeMux, loopback *pkggrpc.Loopback) Init {
+	client := gen.NewUserContractClient(loopback.Conn())
+
+	gwmux := pkggrpc.NewGatewayMux()
+	if err := gen.RegisterUserContractHandlerClient(context.Background(), gwmux, client); err != nil {
+		panic(fmt.Sprintf("failed to register user gateway: %v", err))
+	}
+	mux.Handle("/v1/", gwmux)
+	mux.Handle("GET /v1/events", withoutWriteTimeout(gwmux))
+	mux.Handle("GET /v1/users/{user_id}/events", withoutWriteTimeout(gwmux))
+
+	path, handler := userconnect.NewUserContractHandler(&connectContract{client: client})
+	mux.Handle(path, handler)
+	mux.Handle(userconnect.UserContractWatchUserEventsProcedure, withoutWriteTimeout(handler))
+
+	slog.Info("user gateway mounted", slog.String("json", "/v1/"), slog.String("connect", path))
+	return Init{}
+}
+
+// withoutWriteTimeout lifts the server's write timeout for streaming RPCs,
+// which last until the client or the server ends them.
+func withoutWriteTimeout(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+		next.ServeHTTP(w, r)
+	})
+}
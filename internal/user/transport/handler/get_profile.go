@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"starter-boilerplate/internal/shared/middleware"
+	"starter-boilerplate/internal/user/app/usecase"
+	"starter-boilerplate/internal/user/transport/dto"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+type getProfileInput struct {
+	ID string `path:"id"`
+}
+
+type GetProfileHandler struct {
+	uc *usecase.GetProfileUseCase
+}
+
+func NewGetProfileHandler(uc *usecase.GetProfileUseCase) *GetProfileHandler {
+	return &GetProfileHandler{uc: uc}
+}
+
+func (h *GetProfileHandler) Register(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID: "get-user-profile",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/users/{id}/profile",
+		Summary:     "Get user profile",
+		Description: "Users and admins see the public and owner keys of a profile; other users see its public keys only.",
+		Tags:        []string{"users"},
+		Security: []map[string][]string{
+			{"bearerAuth": {}},
+		},
+	}, h.handle)
+}
+
+func (h *GetProfileHandler) handle(ctx context.Context, input *getProfileInput) (*profileOutput, error) {
+	p, err := h.uc.Execute(middleware.NewAuthCtx(ctx), input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &profileOutput{}
+	out.Body.Profile = dto.NewProfileDTO(p)
+	return out, nil
+}
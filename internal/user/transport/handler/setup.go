@@ -6,11 +6,13 @@ import (
 
 type HandlersInit struct{}
 
-func SetupHandlers(api huma.API, loginH *LoginHandler, refreshH *RefreshHandler, getUserH *GetUserHandler, registerH *RegisterHandler, changePasswordH *ChangePasswordHandler) HandlersInit {
+func SetupHandlers(api huma.API, loginH *LoginHandler, refreshH *RefreshHandler, getUserH *GetUserHandler, registerH *RegisterHandler, changePasswordH *ChangePasswordHandler, getProfileH *GetProfileHandler, updateProfileH *UpdateProfileHandler) HandlersInit {
 	loginH.Register(api)
 	refreshH.Register(api)
 	getUserH.Register(api)
 	registerH.Register(api)
 	changePasswordH.Register(api)
+	getProfileH.Register(api)
+	updateProfileH.Register(api)
 	return HandlersInit{}
 }
@@ -5,3 +5,9 @@ import "starter-boilerplate/internal/user/transport/dto"
 type tokenOutput struct {
 	Body dto.TokenPairDTO
 }
+
+type profileOutput struct {
+	Body struct {
+		Profile dto.ProfileDTO `json:"profile"`
+	}
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"starter-boilerplate/internal/shared/middleware"
+	"starter-boilerplate/internal/user/app/usecase"
+	"starter-boilerplate/internal/user/domain/model"
+	"starter-boilerplate/internal/user/transport/dto"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+type updateProfileInput struct {
+	ID   string `path:"id"`
+	Body struct {
+		SetNumbers    map[string]float64     `json:"set_numbers,omitempty" doc:"Number keys to set"`
+		IncrNumbers   map[string]float64     `json:"incr_numbers,omitempty" doc:"Number keys to increment atomically; missing keys start at 0"`
+		MaxNumbers    map[string]float64     `json:"max_numbers,omitempty" doc:"Number keys to raise to the value if missing or below it"`
+		MinNumbers    map[string]float64     `json:"min_numbers,omitempty" doc:"Number keys to lower to the value if missing or above it"`
+		SetStrings    map[string]string      `json:"set_strings,omitempty" doc:"String keys to set"`
+		SetBools      map[string]bool        `json:"set_bools,omitempty" doc:"Boolean keys to set"`
+		SetTimes      map[string]time.Time   `json:"set_times,omitempty" doc:"Timestamp keys to set"`
+		AddTags       map[string][]string    `json:"add_tags,omitempty" doc:"Values to add to string-set keys"`
+		RemoveTags    map[string][]string    `json:"remove_tags,omitempty" doc:"Values to remove from string-set keys; removals win over additions"`
+		SetIfAbsent   map[string]any         `json:"set_if_absent,omitempty" doc:"Keys to set unless they have a value"`
+		Delete        []string               `json:"delete,omitempty" doc:"Keys to remove"`
+		CompareAndSet []profileCompareAndSet `json:"compare_and_set,omitempty" doc:"Keys to set if they hold the expected value; otherwise nothing is applied (409)"`
+	}
+}
+
+type profileCompareAndSet struct {
+	Key      string `json:"key"`
+	Expected any    `json:"expected"`
+	Value    any    `json:"value"`
+}
+
+type UpdateProfileHandler struct {
+	uc *usecase.UpdateProfileUseCase
+}
+
+func NewUpdateProfileHandler(uc *usecase.UpdateProfileUseCase) *UpdateProfileHandler {
+	return &UpdateProfileHandler{uc: uc}
+}
+
+func (h *UpdateProfileHandler) Register(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID: "update-user-profile",
+		Method:      http.MethodPatch,
+		Path:        "/api/v1/users/{id}/profile",
+		Summary:     "Update user profile",
+		Description: "Applies all operations atomically. Users change their own profile keys; admins also change admin keys. Server keys are read-only; values must be within the bounds of their key.",
+		Tags:        []string{"users"},
+		Security: []map[string][]string{
+			{"bearerAuth": {}},
+		},
+	}, h.handle)
+}
+
+func (h *UpdateProfileHandler) handle(ctx context.Context, input *updateProfileInput) (*profileOutput, error) {
+	upd := model.NewProfileUpdate()
+	for k, v := range input.Body.SetNumbers {
+		upd.SetNumber(k, v)
+	}
+	for k, v := range input.Body.IncrNumbers {
+		upd.IncrNumber(k, v)
+	}
+	for k, v := range input.Body.SetStrings {
+		upd.SetString(k, v)
+	}
+	for k, v := range input.Body.MaxNumbers {
+		upd.SetMax(k, v)
+	}
+	for k, v := range input.Body.MinNumbers {
+		upd.SetMin(k, v)
+	}
+	for k, v := range input.Body.SetBools {
+		upd.SetBool(k, v)
+	}
+	for k, v := range input.Body.SetTimes {
+		upd.SetTime(k, v)
+	}
+	for k, v := range input.Body.AddTags {
+		upd.AddTags(k, v...)
+	}
+	for k, v := range input.Body.RemoveTags {
+		upd.RemoveTags(k, v...)
+	}
+	for k, v := range input.Body.SetIfAbsent {
+		upd.SetIfAbsent(k, profileValue(k, v))
+	}
+	for _, k := range input.Body.Delete {
+		upd.DeleteKey(k)
+	}
+	for _, c := range input.Body.CompareAndSet {
+		upd.CompareAndSet(c.Key, profileValue(c.Key, c.Expected), profileValue(c.Key, c.Value))
+	}
+
+	p, err := h.uc.Execute(middleware.NewAuthCtx(ctx), input.ID, upd)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &profileOutput{}
+	out.Body.Profile = dto.NewProfileDTO(p)
+	return out, nil
+}
+
+// profileValue converts a JSON value to the kind of key: timestamps come as
+// RFC 3339 strings. Other values keep their JSON type.
+func profileValue(key string, v any) any {
+	if s, ok := v.(string); ok && model.ProfileSchema[key].Kind == model.ProfileTime {
+		if t, err := time.Parse(time.RFC3339, s); err == nil {
+			return t
+		}
+	}
+	return v
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/uptrace/bun"
 	"google.golang.org/grpc"
+	"net/http"
 	"starter-boilerplate/internal/shared/centrifugenode"
 	"starter-boilerplate/internal/shared/middleware"
 	"starter-boilerplate/internal/user/app/service"
@@ -17,16 +18,19 @@ import (
 	"starter-boilerplate/internal/user/infra/persistence"
 	"starter-boilerplate/internal/user/transport/consumer"
 	"starter-boilerplate/internal/user/transport/contract"
+	"starter-boilerplate/internal/user/transport/gateway"
 	"starter-boilerplate/internal/user/transport/handler"
 	"starter-boilerplate/pkg/amqp"
 	"starter-boilerplate/pkg/db"
+	"starter-boilerplate/pkg/event"
+	grpc2 "starter-boilerplate/pkg/grpc"
 	"starter-boilerplate/pkg/jwt"
 	"starter-boilerplate/pkg/outbox"
 )
 
 // Injectors from initialize.go:
 
-func InitializeUserModule(api huma.API, grpcSrv *grpc.Server, manager *jwt.Manager, bunDB *bun.DB, bus outbox.Bus, broker *amqp.Broker, uoW db.UoW, publisher *centrifugenode.Publisher, init middleware.Init) Module {
+func InitializeUserModule(api huma.API, mux *http.ServeMux, grpcSrv *grpc.Server, loopback *grpc2.Loopback, manager *jwt.Manager, bunDB *bun.DB, bus outbox.Bus, repository *outbox.Repository, broker *amqp.Broker, hub *event.Hub, uoW db.UoW, publisher *centrifugenode.Publisher, init middleware.Init) Module {
 	userRepository := persistence.NewUserRepository(bunDB)
 	userService := service.NewUserService(userRepository)
 	tokenService := service.NewTokenService(manager)
@@ -40,15 +44,35 @@ func InitializeUserModule(api huma.API, grpcSrv *grpc.Server, manager *jwt.Manag
 	registerHandler := handler.NewRegisterHandler(registerUseCase)
 	changePasswordUseCase := usecase.NewChangePasswordUseCase(userService, bus, uoW)
 	changePasswordHandler := handler.NewChangePasswordHandler(changePasswordUseCase)
-	handlersInit := handler.SetupHandlers(api, loginHandler, refreshHandler, getUserHandler, registerHandler, changePasswordHandler)
-	contractInit := contract.SetupUserContract(grpcSrv, userRepository)
 	profileRepository := persistence.NewProfileRepository(bunDB)
-	profileService := service.NewProfileService(profileRepository)
+	profileService := service.NewProfileService(profileRepository, bus, uoW)
+	getProfileUseCase := usecase.NewGetProfileUseCase(profileService)
+	getProfileHandler := handler.NewGetProfileHandler(getProfileUseCase)
+	updateProfileUseCase := usecase.NewUpdateProfileUseCase(profileService)
+	updateProfileHandler := handler.NewUpdateProfileHandler(updateProfileUseCase)
+	handlersInit := handler.SetupHandlers(api, loginHandler, refreshHandler, getUserHandler, registerHandler, changePasswordHandler, getProfileHandler, updateProfileHandler)
+	listUsersUseCase := usecase.NewListUsersUseCase(userService)
+	eventStream := service.NewEventStream(hub, repository)
+	watchUserEventsUseCase := usecase.NewWatchUserEventsUseCase(eventStream)
+	useCases := contract.UseCases{
+		Login:          loginUseCase,
+		Register:       registerUseCase,
+		Refresh:        refreshUseCase,
+		ChangePassword: changePasswordUseCase,
+		GetUser:        getUserUseCase,
+		ListUsers:      listUsersUseCase,
+		GetProfile:     getProfileUseCase,
+		UpdateProfile:  updateProfileUseCase,
+		WatchEvents:    watchUserEventsUseCase,
+	}
+	contractInit := contract.SetupUserContract(grpcSrv, useCases)
 	profileUpdaterConsumer := consumer.NewProfileUpdaterConsumer(profileService)
-	consumerInit := consumer.SetupConsumers(broker, profileUpdaterConsumer)
+	eventWatchConsumer := consumer.NewEventWatchConsumer(hub)
+	consumerInit := consumer.SetupConsumers(broker, profileUpdaterConsumer, eventWatchConsumer)
 	bridgeConsumer := consumer.NewBridgeConsumer(publisher)
 	bridgeInit := consumer.SetupBridgeConsumer(broker, bridgeConsumer)
-	module := NewModule(handlersInit, contractInit, consumerInit, bridgeInit)
+	gatewayInit := gateway.SetupGateway(mux, loopback)
+	module := NewModule(handlersInit, contractInit, consumerInit, bridgeInit, gatewayInit)
 	return module
 }
 
@@ -56,6 +80,6 @@ func InitializeUserModule(api huma.API, grpcSrv *grpc.Server, manager *jwt.Manag
 
 type Module struct{}
 
-func NewModule(_ handler.HandlersInit, _ contract.Init, _ consumer.Init, _ consumer.BridgeInit) Module {
+func NewModule(_ handler.HandlersInit, _ contract.Init, _ consumer.Init, _ consumer.BridgeInit, _ gateway.Init) Module {
 	return Module{}
 }
@@ -11,14 +11,18 @@ import (
 	centrifuge2 "github.com/centrifugal/centrifuge"
 	huma2 "github.com/danielgtaylor/huma/v2"
 	redis2 "github.com/redis/go-redis/v9"
+	kafka2 "github.com/segmentio/kafka-go"
 	grpc2 "google.golang.org/grpc"
 	"log/slog"
 	"net/http"
+	"starter-boilerplate/internal/shared/admin"
 	"starter-boilerplate/internal/shared/app"
 	"starter-boilerplate/internal/shared/centrifugenode"
 	"starter-boilerplate/internal/shared/config"
 	"starter-boilerplate/internal/shared/consumer"
+	"starter-boilerplate/internal/shared/cron"
 	"starter-boilerplate/internal/shared/huma"
+	"starter-boilerplate/internal/shared/i18n"
 	"starter-boilerplate/internal/shared/jwt"
 	"starter-boilerplate/internal/shared/logger"
 	"starter-boilerplate/internal/shared/middleware"
@@ -29,8 +33,12 @@ import (
 	"starter-boilerplate/pkg/db"
 	"starter-boilerplate/pkg/event"
 	"starter-boilerplate/pkg/grpc"
+	"starter-boilerplate/pkg/jobs"
+	"starter-boilerplate/pkg/kafka"
 	"starter-boilerplate/pkg/outbox"
 	"starter-boilerplate/pkg/redis"
+	"starter-boilerplate/pkg/saga"
+	"starter-boilerplate/pkg/scheduler"
 )
 
 // Injectors from initialize.go:
@@ -40,39 +48,70 @@ func InitializeApp(ctx context.Context) *app.App {
 	configConfig := config.SetupConfig()
 	appConfig := configConfig.App
 	httpServer := server.SetupHTTPServer(serveMux, appConfig)
-	api := huma.Setup(serveMux, appConfig)
+	apperrorConfig := configConfig.Errors
+	api := huma.Setup(serveMux, appConfig, apperrorConfig)
 	grpcConfig := configConfig.GRPC
 	loggerConfig := configConfig.Logger
 	slogLogger := logger.SetupLogger(loggerConfig)
-	grpcServer := grpc.Setup(grpcConfig, slogLogger)
 	jwtConfig := configConfig.JWT
 	manager := jwt.NewJWTManager(jwtConfig)
+	grpcAuth := middleware.NewGRPCAuth(manager, grpcConfig)
+	catalog := i18n.NewCatalog()
+	interceptors := middleware.NewGRPCInterceptors(grpcAuth, apperrorConfig, catalog)
 	dbConfig := configConfig.DB
 	bunDB := db.Setup(ctx, dbConfig, slogLogger)
-	repository := outbox.NewRepository(bunDB)
-	outboxBus := outbox.NewOutboxBus(repository)
+	redisConfig := configConfig.Redis
+	client := redis.Setup(ctx, redisConfig, slogLogger)
 	amqpConfig := configConfig.AMQP
 	connection := amqp.Setup(amqpConfig, slogLogger)
 	broker := consumer.Setup(connection, amqpConfig)
+	healthChecks := server.NewHealthChecks(bunDB, client, broker)
+	health := grpc.NewHealth(grpcConfig, healthChecks)
+	grpcServer := grpc.Setup(grpcConfig, slogLogger, interceptors, health)
+	loopback := grpc.SetupLoopback(grpcConfig)
+	repository := outbox.NewRepository(bunDB)
+	outboxBus := outbox.NewOutboxBus(repository)
+	hub := event.NewHub()
 	unitOfWork := db.NewUnitOfWork(bunDB)
 	centrifugeConfig := configConfig.Centrifuge
-	redisConfig := configConfig.Redis
-	client := redis.Setup(ctx, redisConfig, slogLogger)
 	node := centrifuge.Setup(ctx, centrifugeConfig, client, slogLogger)
 	publisher := centrifugenode.NewPublisher(node, centrifugeConfig)
-	init := middleware.Setup(httpServer, api, manager)
-	module := user.InitializeUserModule(api, grpcServer, manager, bunDB, outboxBus, broker, unitOfWork, publisher, init)
-	bus := event.NewEventBus(connection, broker)
+	init := middleware.Setup(httpServer, api, manager, apperrorConfig, catalog)
+	module := user.InitializeUserModule(api, serveMux, grpcServer, loopback, manager, bunDB, outboxBus, repository, broker, hub, unitOfWork, publisher, init)
+	kafkaConfig := configConfig.Kafka
+	bus := event.NewEventBus(broker)
 	outboxPublisher := event.NewDefaultOutboxPublisher(bus, broker)
+	writer := kafka.Setup(kafkaConfig, slogLogger)
+	publisher2 := newOutboxPublisher(kafkaConfig, outboxPublisher, writer)
 	relayConfig := configConfig.Outbox
-	relay := outbox.NewRelay(bunDB, repository, outboxPublisher, relayConfig)
+	relay := outbox.NewRelay(bunDB, repository, publisher2, relayConfig)
+	sagaRepository := saga.NewRepository(bunDB)
+	sagaConfig := configConfig.Saga
+	sagaManager := saga.NewManager(unitOfWork, sagaRepository, outboxBus, sagaConfig)
+	schedulerRepository := scheduler.NewRepository(bunDB)
+	schedulerConfig := configConfig.Scheduler
+	schedulerScheduler := scheduler.NewScheduler(bunDB, schedulerRepository, schedulerConfig)
+	jobsRepository := jobs.NewRepository(bunDB)
+	jobsConfig := configConfig.Jobs
+	worker := jobs.NewWorker(jobsRepository, jobsConfig)
 	centrifugenodeInit := centrifugenode.Setup(node, serveMux, manager)
-	appApp := newApp(httpServer, configConfig, module, init, slogLogger, client, grpcServer, api, broker, relay, node, centrifugenodeInit)
+	adminInit := admin.Setup(api, broker, sagaRepository, schedulerRepository, jobsRepository)
+	cronConfig := configConfig.Cron
+	cronInit := cron.Setup(schedulerScheduler, repository, jobsRepository, cronConfig)
+	appApp := newApp(httpServer, configConfig, module, init, slogLogger, client, grpcServer, loopback, api, broker, relay, sagaManager, schedulerScheduler, worker, health, hub, node, centrifugenodeInit, adminInit, cronInit)
 	return appApp
 }
 
 // initialize.go:
 
-func newApp(httpSrv *http.Server, cfg *config.Config, _ user.Module, _ middleware.Init, _ *slog.Logger, _ *redis2.Client, grpcSrv *grpc2.Server, api huma2.API, broker *amqp.Broker, relay *outbox.Relay, centrifugeNode *centrifuge2.Node, _ centrifugenode.Init) *app.App {
-	return app.New(httpSrv, cfg, grpcSrv, api, broker, relay, centrifugeNode)
+func newApp(httpSrv *http.Server, cfg *config.Config, _ user.Module, _ middleware.Init, _ *slog.Logger, _ *redis2.Client, grpcSrv *grpc2.Server, loopback *grpc.Loopback, api huma2.API, broker *amqp.Broker, relay *outbox.Relay, sagas *saga.Manager, sched *scheduler.Scheduler, jobWorker *jobs.Worker, grpcHealth *grpc.Health, eventHub *event.Hub, centrifugeNode *centrifuge2.Node, _ centrifugenode.Init, _ admin.Init, _ cron.Init) *app.App {
+	return app.New(httpSrv, cfg, grpcSrv, loopback, api, broker, relay, sagas, sched, jobWorker, grpcHealth, eventHub, centrifugeNode)
+}
+
+// newOutboxPublisher relays outbox entries to Kafka when it is enabled and to AMQP otherwise.
+func newOutboxPublisher(cfg kafka.Config, amqpPub *event.OutboxPublisher, writer *kafka2.Writer) outbox.Publisher {
+	if writer != nil {
+		return kafka.NewOutboxPublisher(writer, cfg.EventsTopic())
+	}
+	return amqpPub
 }
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS saga_instances (
+    id             BIGSERIAL PRIMARY KEY,
+    saga           VARCHAR(255) NOT NULL,
+    correlation_id VARCHAR(255) NOT NULL,
+    status         VARCHAR(32) NOT NULL,
+    step           VARCHAR(255) NOT NULL DEFAULT '',
+    completed      JSONB NOT NULL DEFAULT '[]',
+    data           JSONB NOT NULL DEFAULT '{}',
+    error          TEXT NOT NULL DEFAULT '',
+    deadline       BIGINT NOT NULL DEFAULT 0,
+    created_at     BIGINT NOT NULL DEFAULT 0,
+    updated_at     BIGINT NOT NULL DEFAULT 0,
+    UNIQUE (saga, correlation_id)
+);
+
+CREATE INDEX idx_saga_instances_deadline ON saga_instances (deadline) WHERE status = 'running' AND deadline > 0;
+CREATE INDEX idx_saga_instances_stuck ON saga_instances (updated_at) WHERE status IN ('running', 'failed');
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_outbox_scheduled;
+ALTER TABLE outbox DROP COLUMN deliver_at;
@@ -0,0 +1,3 @@
+ALTER TABLE outbox ADD COLUMN deliver_at BIGINT NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_outbox_scheduled ON outbox (deliver_at) WHERE published = FALSE AND deliver_at > 0;
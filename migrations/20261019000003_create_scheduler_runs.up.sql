@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS scheduler_runs (
+    id          BIGSERIAL PRIMARY KEY,
+    job         VARCHAR(255) NOT NULL,
+    node        VARCHAR(255) NOT NULL DEFAULT '',
+    status      VARCHAR(32) NOT NULL,
+    error       TEXT NOT NULL DEFAULT '',
+    started_at  BIGINT NOT NULL,
+    duration_ms BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_scheduler_runs_job_started ON scheduler_runs (job, started_at);
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS jobs (
+    id           BIGSERIAL PRIMARY KEY,
+    queue        VARCHAR(255) NOT NULL,
+    kind         VARCHAR(255) NOT NULL,
+    payload      JSONB NOT NULL DEFAULT '{}',
+    priority     INT NOT NULL DEFAULT 0,
+    unique_key   VARCHAR(255),
+    state        VARCHAR(32) NOT NULL,
+    attempts     INT NOT NULL DEFAULT 0,
+    max_attempts INT NOT NULL,
+    run_at       BIGINT NOT NULL DEFAULT 0,
+    locked_until BIGINT NOT NULL DEFAULT 0,
+    last_error   TEXT NOT NULL DEFAULT '',
+    created_at   BIGINT NOT NULL DEFAULT 0,
+    updated_at   BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX idx_jobs_unique_key ON jobs (unique_key) WHERE state IN ('pending', 'running');
+CREATE INDEX idx_jobs_claim ON jobs (queue, priority DESC, run_at, id) WHERE state IN ('pending', 'running');
+CREATE INDEX idx_jobs_failed ON jobs (updated_at) WHERE state = 'failed';
@@ -0,0 +1,4 @@
+ALTER TABLE user_profiles
+    DROP COLUMN IF EXISTS bools,
+    DROP COLUMN IF EXISTS times,
+    DROP COLUMN IF EXISTS tags;
@@ -0,0 +1,4 @@
+ALTER TABLE user_profiles
+    ADD COLUMN IF NOT EXISTS bools JSONB NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS times JSONB NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS tags  JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1,2 @@
+ALTER TABLE outbox DROP COLUMN error;
+ALTER TABLE outbox DROP COLUMN failed;
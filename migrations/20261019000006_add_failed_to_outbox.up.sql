@@ -0,0 +1,2 @@
+ALTER TABLE outbox ADD COLUMN failed BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE outbox ADD COLUMN error TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_outbox_position;
+DROP SEQUENCE IF EXISTS outbox_position_seq;
+ALTER TABLE outbox DROP COLUMN published_at;
+ALTER TABLE outbox DROP COLUMN position;
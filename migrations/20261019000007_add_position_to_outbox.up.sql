@@ -0,0 +1,10 @@
+ALTER TABLE outbox ADD COLUMN position BIGINT;
+ALTER TABLE outbox ADD COLUMN published_at BIGINT NOT NULL DEFAULT 0;
+
+CREATE SEQUENCE outbox_position_seq;
+
+-- Entries published so far keep their ID as position; new ones follow them.
+UPDATE outbox SET position = id, published_at = created_at WHERE published = TRUE;
+SELECT setval('outbox_position_seq', COALESCE((SELECT MAX(id) FROM outbox), 0) + 1, false);
+
+CREATE UNIQUE INDEX idx_outbox_position ON outbox (position);
@@ -0,0 +1,25 @@
+package amqp
+
+import amqp091 "github.com/rabbitmq/amqp091-go"
+
+// BatchMessage is a single message of [Broker.PublishBatch].
+type BatchMessage struct {
+	Exchange   string
+	RoutingKey string
+	Headers    amqp091.Table
+	Body       []byte
+	Options    []PublishOption
+}
+
+func (m BatchMessage) envelope() envelope {
+	return newEnvelope(m.Exchange, m.RoutingKey, m.Headers, m.Body, m.Options)
+}
+
+// fillErrors returns n copies of err, for failures that affect the whole batch.
+func fillErrors(n int, err error) []error {
+	errs := make([]error, n)
+	for i := range errs {
+		errs[i] = err
+	}
+	return errs
+}
@@ -0,0 +1,40 @@
+//go:build unit
+
+package amqp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBroker_PublishBatch_StandaloneUnknownExchange(t *testing.T) {
+	b := NewBroker(nil, PoolConfig{})
+
+	msgs := []BatchMessage{{Exchange: "missing"}, {Exchange: "missing"}, {Exchange: "missing"}}
+	errs := b.PublishBatch(context.Background(), msgs, AtLeastOnce)
+
+	require.Len(t, errs, 3)
+	for _, err := range errs {
+		assert.Error(t, err)
+	}
+}
+
+func TestBroker_PublishBatch_BuildsMessages(t *testing.T) {
+	pub := &fakePublisher{}
+	b := &Broker{publishers: &publisherManager{atLeastOnce: pub}}
+
+	errs := b.PublishBatch(context.Background(), []BatchMessage{
+		{Exchange: "events", RoutingKey: "a", Body: []byte(`{}`)},
+		{Exchange: "events", RoutingKey: "b", Body: []byte("x"), Options: []PublishOption{WithContentType(ContentTypeMsgpack)}},
+	}, AtLeastOnce)
+
+	assert.Equal(t, []error{nil, nil}, errs)
+	require.Len(t, pub.published, 2)
+	assert.Equal(t, "a", pub.published[0].routingKey)
+	assert.Equal(t, ContentTypeJSON, pub.published[0].msg.ContentType)
+	assert.Equal(t, "b", pub.published[1].routingKey)
+	assert.Equal(t, ContentTypeMsgpack, pub.published[1].msg.ContentType)
+}
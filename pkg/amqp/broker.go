@@ -2,8 +2,11 @@ package amqp
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
+
+	"starter-boilerplate/pkg/i18n"
 
 	amqp091 "github.com/rabbitmq/amqp091-go"
 )
@@ -16,6 +19,12 @@ import (
 //
 //	broker.Publish(ctx, exchange, key, headers, body)
 //	broker.PublishJSON(ctx, exchange, key, headers, payload)  // with validation + marshal
+//	broker.PublishEncoded(ctx, exchange, key, headers, payload, amqp.ProtoCodec{})
+//	broker.PublishBatch(ctx, msgs, amqp.AtLeastOnce)  // pipelined confirms, per-message errors
+//
+// Topology — exchanges are declared up front, consumer queues on Run:
+//
+//	broker.Declare(amqp.Topology{Exchanges: ...})
 //
 // Consuming — register handlers before calling Run:
 //
@@ -23,46 +32,184 @@ import (
 //	broker.Use(amqp.WithRecover(), amqp.WithLogging())  // group middlewares
 //	broker.Run(ctx)  // blocks until ctx is done or a consumer fails
 //
-// Standalone mode: if conn is nil, Publish returns an error
-// and Run blocks until ctx is done without starting consumers.
+// Shutdown — drain consumers first, then close publishers:
+//
+//	report := broker.Drain(ctx)  // bounded by ctx; leftovers are requeued
+//	broker.Shutdown()
+//
+// Request/reply — see [Broker.Call] and [AddRPCHandler]:
+//
+//	reply, err := broker.Call(ctx, exchange, key, req)
+//	resp, err := amqp.CallAs[RespT](ctx, broker, exchange, key, req)
+//
+// Standalone mode: if conn is nil, the broker runs on an in-memory transport
+// with RabbitMQ-compatible routing (see memory.go): published messages reach
+// the consumers registered on the same Broker, with the same middleware,
+// retry and dead-letter behaviour.
 type Broker struct {
+	conn       *amqp091.Connection
+	mem        *memoryBroker // nil unless conn is nil
+	declared   Topology
 	publishers *publisherManager
 	consumers  *consumerGroup
+	rpc        *rpcClient
+}
+
+// BrokerOption configures optional Broker behaviour.
+type BrokerOption func(*brokerOptions)
+
+type brokerOptions struct {
+	rpcTimeout time.Duration
+}
+
+// WithRPCTimeout sets the timeout applied to [Broker.Call] when the context
+// has no deadline (default: 10s).
+func WithRPCTimeout(d time.Duration) BrokerOption {
+	return func(o *brokerOptions) {
+		o.rpcTimeout = d
+	}
 }
 
 // NewBroker creates a ready-to-use Broker backed by the given connection.
-// Pass nil for standalone mode (no AMQP server required).
-func NewBroker(conn *amqp091.Connection, poolCfg PoolConfig) *Broker {
+// Pass nil for standalone mode: no AMQP server is required and messages are
+// routed in memory, which also makes the Broker usable in unit tests.
+func NewBroker(conn *amqp091.Connection, poolCfg PoolConfig, opts ...BrokerOption) *Broker {
 	poolCfg = poolCfg.withDefaults()
 
+	var o brokerOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var mem *memoryBroker
+	if conn == nil {
+		mem = newMemoryBroker()
+	}
+
 	return &Broker{
-		publishers: newPublisherManager(conn, poolCfg),
-		consumers:  newConsumerGroup(conn),
+		conn:       conn,
+		mem:        mem,
+		publishers: newPublisherManager(conn, mem, poolCfg),
+		consumers:  newConsumerGroup(conn, mem),
+		rpc:        newRPCClient(conn, mem, o.rpcTimeout),
 	}
 }
 
 // Publish sends a raw message to the given exchange with the specified routing key.
-func (b *Broker) Publish(ctx context.Context, exchange, routingKey string, headers amqp091.Table, body []byte, g DeliveryGuarantee) error {
+// The content type defaults to application/json; override it with [WithContentType].
+func (b *Broker) Publish(ctx context.Context, exchange, routingKey string, headers amqp091.Table, body []byte, g DeliveryGuarantee, opts ...PublishOption) error {
 	p, err := b.publishers.get(g)
 	if err != nil {
 		return err
 	}
 
-	return p.Publish(ctx, exchange, routingKey, headers, body)
+	return p.Publish(ctx, newEnvelope(exchange, routingKey, headers, body, opts))
+}
+
+// PublishBatch publishes msgs on a single pooled channel and returns one error
+// per message, in order (nil on success). With AtLeastOnce all messages are
+// sent before waiting, and confirms are collected together.
+func (b *Broker) PublishBatch(ctx context.Context, msgs []BatchMessage, g DeliveryGuarantee) []error {
+	p, err := b.publishers.get(g)
+	if err != nil {
+		return fillErrors(len(msgs), err)
+	}
+
+	batch := make([]envelope, len(msgs))
+	for i, m := range msgs {
+		batch[i] = m.envelope()
+	}
+	return p.PublishBatch(ctx, batch)
 }
 
 // PublishJSON validates the payload struct, marshals it to JSON, and publishes.
-func (b *Broker) PublishJSON(ctx context.Context, exchange, routingKey string, headers amqp091.Table, payload any, g DeliveryGuarantee) error {
-	if err := validate.StructCtx(ctx, payload); err != nil {
+func (b *Broker) PublishJSON(ctx context.Context, exchange, routingKey string, headers amqp091.Table, payload any, g DeliveryGuarantee, opts ...PublishOption) error {
+	return b.PublishEncoded(ctx, exchange, routingKey, headers, payload, JSONCodec{}, g, opts...)
+}
+
+// PublishEncoded validates the payload struct, encodes it with codec, and publishes
+// with the codec's content type so consumers can pick the matching decoder.
+// The language of the caller's [i18n.Localizer], if any, travels in the
+// [HeaderLanguage] header.
+func (b *Broker) PublishEncoded(ctx context.Context, exchange, routingKey string, headers amqp091.Table, payload any, codec Codec, g DeliveryGuarantee, opts ...PublishOption) error {
+	if err := Validate(ctx, payload); err != nil {
 		return fmt.Errorf("amqp: validate: %w", err)
 	}
 
-	body, err := json.Marshal(payload)
+	body, err := codec.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("amqp: marshal: %w", err)
 	}
 
-	return b.Publish(ctx, exchange, routingKey, headers, body, g)
+	defaults := []PublishOption{WithContentType(codec.ContentType())}
+	if l := i18n.FromContext(ctx); l != nil {
+		defaults = append(defaults, WithLanguage(l.Tag()))
+	}
+	opts = append(defaults, opts...)
+	return b.Publish(ctx, exchange, routingKey, headers, body, g, opts...)
+}
+
+// Call sends req as an RPC request and waits for the reply of an [AddRPCHandler]
+// consumer. The request is encoded with the codec matching the content type
+// (JSON unless overridden with [WithContentType]); replies arrive via direct reply-to.
+// The call fails when ctx is done or, if ctx has no deadline, after the broker's
+// RPC timeout. A handler error is returned as [*RPCError].
+func (b *Broker) Call(ctx context.Context, exchange, routingKey string, req any, opts ...PublishOption) (*RPCReply, error) {
+	if err := Validate(ctx, req); err != nil {
+		return nil, fmt.Errorf("amqp: validate: %w", err)
+	}
+
+	msg := newEnvelope(exchange, routingKey, nil, nil, opts).msg
+	codec, err := CodecFor(msg.ContentType)
+	if err != nil {
+		return nil, err
+	}
+
+	msg.Body, err = codec.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("amqp: marshal: %w", err)
+	}
+
+	return b.rpc.call(ctx, exchange, routingKey, msg)
+}
+
+// CallAs is [Broker.Call] with the reply decoded into Resp.
+func CallAs[Resp any](ctx context.Context, b *Broker, exchange, routingKey string, req any, opts ...PublishOption) (Resp, error) {
+	var resp Resp
+
+	reply, err := b.Call(ctx, exchange, routingKey, req, opts...)
+	if err != nil {
+		return resp, err
+	}
+	if err := reply.Decode(&resp); err != nil {
+		return resp, fmt.Errorf("amqp: unmarshal reply: %w", err)
+	}
+	return resp, nil
+}
+
+// Declare validates t and applies it right away, so exchanges exist before
+// the first publish. Declaring is idempotent; a conflicting redeclaration fails.
+// In standalone mode t is declared on the in-memory broker. Declared topologies
+// are part of [Broker.Topology].
+func (b *Broker) Declare(t Topology) error {
+	var err error
+	if b.conn != nil {
+		err = declareTopology(b.conn, t)
+	} else {
+		err = b.mem.declare(t)
+	}
+	if err != nil {
+		return fmt.Errorf("amqp: %w", err)
+	}
+
+	b.declared = MergeTopologies(b.declared, t)
+	return nil
+}
+
+// Topology returns everything the broker declares: topologies passed to
+// Declare plus the queues and bindings of registered consumers.
+func (b *Broker) Topology() Topology {
+	return MergeTopologies(b.declared, b.consumers.topology())
 }
 
 // Use appends group-level middlewares that apply to every registered consumer.
@@ -73,14 +220,33 @@ func (b *Broker) Use(mws ...Middleware) {
 
 // Run declares all queues/bindings, then starts consuming on dedicated channels.
 // It blocks until ctx is cancelled, Shutdown is called, or a consumer returns an error.
-// On shutdown the delivery loop stops immediately, but in-flight handlers finish.
+// On shutdown the delivery loop stops immediately and prefetched messages are
+// requeued; Run returns once in-flight handlers finish.
 func (b *Broker) Run(ctx context.Context) error {
 	return b.consumers.Run(ctx)
 }
 
-// Shutdown gracefully stops consumers (no new deliveries, in-flight handlers finish)
-// and closes all publisher channels.
+// Drain stops fetching new deliveries and waits for in-flight handlers until
+// they finish or ctx is done. At the deadline the remaining handlers get their
+// context cancelled and their deliveries are nacked with requeue.
+// Publishers stay open, so handlers can still publish while draining.
+func (b *Broker) Drain(ctx context.Context) DrainReport {
+	return b.consumers.Drain(ctx)
+}
+
+// Ping reports whether the AMQP connection is open. The in-memory transport
+// is always available.
+func (b *Broker) Ping() error {
+	if b.conn != nil && b.conn.IsClosed() {
+		return errors.New("amqp: connection closed")
+	}
+	return nil
+}
+
+// Shutdown stops consumers without waiting for in-flight handlers (call [Broker.Drain]
+// first for a bounded wait) and closes all publisher and RPC reply channels.
 func (b *Broker) Shutdown() {
 	b.consumers.Shutdown()
+	b.rpc.close()
 	b.publishers.closeAll()
 }
@@ -0,0 +1,92 @@
+package amqp
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Content types of the built-in codecs.
+const (
+	ContentTypeJSON     = "application/json"
+	ContentTypeProtobuf = "application/x-protobuf"
+	ContentTypeMsgpack  = "application/msgpack"
+)
+
+// Codec serializes message payloads. The content type is written to the
+// AMQP ContentType property on publish and used to pick the codec on consume.
+type Codec interface {
+	ContentType() string
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+var (
+	codecsMu sync.RWMutex
+	codecs   = map[string]Codec{}
+)
+
+func init() {
+	RegisterCodec(JSONCodec{})
+	RegisterCodec(ProtoCodec{})
+	RegisterCodec(MsgpackCodec{})
+	registerCodecAlias("application/x-msgpack", MsgpackCodec{})
+	registerCodecAlias("application/protobuf", ProtoCodec{})
+}
+
+// RegisterCodec makes a codec available for decoding by its content type.
+// Registering a codec with an existing content type replaces the previous one.
+func RegisterCodec(c Codec) {
+	registerCodecAlias(c.ContentType(), c)
+}
+
+func registerCodecAlias(contentType string, c Codec) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+	codecs[normalizeContentType(contentType)] = c
+}
+
+// CodecFor returns the codec registered for contentType.
+// An empty content type resolves to JSON for compatibility with untyped publishers.
+func CodecFor(contentType string) (Codec, error) {
+	ct := normalizeContentType(contentType)
+	if ct == "" {
+		return JSONCodec{}, nil
+	}
+
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+
+	c, ok := codecs[ct]
+	if !ok {
+		return nil, fmt.Errorf("amqp: no codec for content type %q", contentType)
+	}
+	return c, nil
+}
+
+// Decode unmarshals data into v using the codec registered for contentType.
+func Decode(contentType string, data []byte, v any) error {
+	c, err := CodecFor(contentType)
+	if err != nil {
+		return err
+	}
+	return c.Unmarshal(data, v)
+}
+
+// normalizeContentType strips parameters (e.g. "; charset=utf-8") and lowercases.
+func normalizeContentType(ct string) string {
+	if i := strings.IndexByte(ct, ';'); i >= 0 {
+		ct = ct[:i]
+	}
+	return strings.ToLower(strings.TrimSpace(ct))
+}
+
+// JSONCodec encodes payloads with encoding/json.
+type JSONCodec struct{}
+
+func (JSONCodec) ContentType() string { return ContentTypeJSON }
+
+func (JSONCodec) Marshal(v any) ([]byte, error) { return json.Marshal(v) }
+
+func (JSONCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
@@ -0,0 +1,30 @@
+package amqp
+
+import (
+	"bytes"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// MsgpackCodec encodes payloads with MessagePack.
+// Struct fields are keyed by their json tags so the same event types
+// serialize identically across JSON and msgpack.
+type MsgpackCodec struct{}
+
+func (MsgpackCodec) ContentType() string { return ContentTypeMsgpack }
+
+func (MsgpackCodec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (MsgpackCodec) Unmarshal(data []byte, v any) error {
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+	return dec.Decode(v)
+}
@@ -0,0 +1,44 @@
+package amqp
+
+import (
+	"fmt"
+	"reflect"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// ProtoCodec encodes payloads in protobuf wire format.
+// Payloads must implement proto.Message (e.g. types generated into gen/).
+type ProtoCodec struct{}
+
+func (ProtoCodec) ContentType() string { return ContentTypeProtobuf }
+
+func (ProtoCodec) Marshal(v any) ([]byte, error) {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("amqp: proto codec: %T is not a proto.Message", v)
+	}
+	return proto.Marshal(m)
+}
+
+// Unmarshal accepts either a proto.Message or a pointer to a nil message
+// pointer (the shape typed handlers produce for T = *pb.Message).
+func (ProtoCodec) Unmarshal(data []byte, v any) error {
+	if m, ok := v.(proto.Message); ok {
+		return proto.Unmarshal(data, m)
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() && rv.Elem().Kind() == reflect.Ptr {
+		elem := reflect.New(rv.Elem().Type().Elem())
+		if m, ok := elem.Interface().(proto.Message); ok {
+			if err := proto.Unmarshal(data, m); err != nil {
+				return err
+			}
+			rv.Elem().Set(elem)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("amqp: proto codec: %T is not a proto.Message", v)
+}
@@ -0,0 +1,117 @@
+//go:build unit
+
+package amqp
+
+import (
+	"context"
+	"testing"
+
+	amqp091 "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type codecPayload struct {
+	UserID string `json:"user_id" validate:"required"`
+	Count  int    `json:"count"`
+}
+
+func TestCodecFor_EmptyContentTypeIsJSON(t *testing.T) {
+	c, err := CodecFor("")
+	require.NoError(t, err)
+	assert.Equal(t, ContentTypeJSON, c.ContentType())
+}
+
+func TestCodecFor_IgnoresParametersAndCase(t *testing.T) {
+	c, err := CodecFor("Application/JSON; charset=utf-8")
+	require.NoError(t, err)
+	assert.Equal(t, ContentTypeJSON, c.ContentType())
+}
+
+func TestCodecFor_Aliases(t *testing.T) {
+	c, err := CodecFor("application/x-msgpack")
+	require.NoError(t, err)
+	assert.Equal(t, ContentTypeMsgpack, c.ContentType())
+}
+
+func TestCodecFor_Unknown(t *testing.T) {
+	_, err := CodecFor("text/plain")
+	assert.Error(t, err)
+}
+
+func TestJSONCodec_RoundTrip(t *testing.T) {
+	in := codecPayload{UserID: "u-1", Count: 3}
+
+	data, err := JSONCodec{}.Marshal(in)
+	require.NoError(t, err)
+
+	var out codecPayload
+	require.NoError(t, JSONCodec{}.Unmarshal(data, &out))
+	assert.Equal(t, in, out)
+}
+
+func TestMsgpackCodec_RoundTripUsesJSONTags(t *testing.T) {
+	in := codecPayload{UserID: "u-1", Count: 3}
+
+	data, err := MsgpackCodec{}.Marshal(in)
+	require.NoError(t, err)
+
+	var generic map[string]any
+	require.NoError(t, MsgpackCodec{}.Unmarshal(data, &generic))
+	assert.Contains(t, generic, "user_id")
+
+	var out codecPayload
+	require.NoError(t, MsgpackCodec{}.Unmarshal(data, &out))
+	assert.Equal(t, in, out)
+}
+
+func TestProtoCodec_RoundTrip(t *testing.T) {
+	data, err := ProtoCodec{}.Marshal(wrapperspb.String("hello"))
+	require.NoError(t, err)
+
+	out := &wrapperspb.StringValue{}
+	require.NoError(t, ProtoCodec{}.Unmarshal(data, out))
+	assert.Equal(t, "hello", out.GetValue())
+}
+
+func TestProtoCodec_UnmarshalIntoNilPointer(t *testing.T) {
+	data, err := ProtoCodec{}.Marshal(wrapperspb.String("hello"))
+	require.NoError(t, err)
+
+	var out *wrapperspb.StringValue
+	require.NoError(t, ProtoCodec{}.Unmarshal(data, &out))
+	require.NotNil(t, out)
+	assert.Equal(t, "hello", out.GetValue())
+}
+
+func TestProtoCodec_RejectsNonProto(t *testing.T) {
+	_, err := ProtoCodec{}.Marshal(codecPayload{})
+	assert.Error(t, err)
+}
+
+func TestTypedHandler_DecodesByContentType(t *testing.T) {
+	body, err := MsgpackCodec{}.Marshal(codecPayload{UserID: "u-1", Count: 7})
+	require.NoError(t, err)
+
+	var got codecPayload
+	h := typedHandler(func(_ context.Context, p codecPayload, meta DeliveryMeta) error {
+		got = p
+		assert.Equal(t, ContentTypeMsgpack, meta.ContentType)
+		return nil
+	})
+
+	err = h(context.Background(), amqp091.Delivery{ContentType: ContentTypeMsgpack, Body: body})
+	require.NoError(t, err)
+	assert.Equal(t, codecPayload{UserID: "u-1", Count: 7}, got)
+}
+
+func TestTypedHandler_UnknownContentType(t *testing.T) {
+	h := typedHandler(func(_ context.Context, _ codecPayload, _ DeliveryMeta) error {
+		t.Fatal("handler should not be called")
+		return nil
+	})
+
+	err := h(context.Background(), amqp091.Delivery{ContentType: "text/plain", Body: []byte("x")})
+	assert.Error(t, err)
+}
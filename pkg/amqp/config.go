@@ -1,9 +1,45 @@
 package amqp
 
+import (
+	"net/url"
+	"strconv"
+	"time"
+
+	amqp091 "github.com/rabbitmq/amqp091-go"
+)
+
+const defaultManagementPort = 15672
+
 type AMQPConfig struct {
-	Standalone bool       `yaml:"standalone"`
-	URL        string     `yaml:"url" validate:"required_unless=Standalone true"`
-	Pool       PoolConfig `yaml:"pool"`
+	Standalone    bool       `yaml:"standalone"`
+	URL           string     `yaml:"url" validate:"required_unless=Standalone true"`
+	ManagementURL string     `yaml:"management_url"` // optional, management HTTP API for topology diff
+	Pool          PoolConfig `yaml:"pool"`
+
+	Middleware MiddlewareConfig `yaml:"middleware"`
+}
+
+// ManagementEndpoint returns the management API address and the vhost to inspect.
+// ManagementURL is used when set, otherwise the address is derived from URL
+// (same host and credentials, port 15672). The vhost always comes from URL.
+func (c AMQPConfig) ManagementEndpoint() (managementURL, vhost string, err error) {
+	uri, err := amqp091.ParseURI(c.URL)
+	if err != nil {
+		return "", "", err
+	}
+	if c.ManagementURL != "" {
+		return c.ManagementURL, uri.Vhost, nil
+	}
+
+	u := url.URL{
+		Scheme: "http",
+		User:   url.UserPassword(uri.Username, uri.Password),
+		Host:   uri.Host + ":" + strconv.Itoa(defaultManagementPort),
+	}
+	if uri.Scheme == "amqps" {
+		u.Scheme = "https"
+	}
+	return u.String(), uri.Vhost, nil
 }
 
 type PoolConfig struct {
@@ -23,3 +59,40 @@ func (c PoolConfig) withDefaults() PoolConfig {
 	}
 	return c
 }
+
+// MiddlewareConfig configures the optional consumer middlewares.
+// Zero values disable the corresponding middleware.
+type MiddlewareConfig struct {
+	Timeout        time.Duration        `yaml:"timeout"`       // per-message handler timeout
+	MaxInFlight    int                  `yaml:"max_in_flight"` // handlers running at once, shared by all consumers
+	CircuitBreaker CircuitBreakerConfig `yaml:"circuit_breaker"`
+	DebugBody      DebugBodyConfig      `yaml:"debug_body"`
+}
+
+// DebugBodyConfig configures [WithDebugBody].
+type DebugBodyConfig struct {
+	SampleRate float64 `yaml:"sample_rate"` // 0..1
+	MaxBytes   int     `yaml:"max_bytes"`   // default: 1024
+}
+
+// Middlewares builds the enabled middlewares for [Broker.Use], outermost first:
+// debug body, circuit breaker, concurrency limit, timeout. An open circuit
+// rejects messages before they take a concurrency slot, and the timeout
+// starts once the handler may run.
+// Call it once per group: the concurrency limit is shared only within one call.
+func (c MiddlewareConfig) Middlewares() []Middleware {
+	var mws []Middleware
+	if c.DebugBody.SampleRate > 0 {
+		mws = append(mws, WithDebugBody(c.DebugBody.SampleRate, c.DebugBody.MaxBytes))
+	}
+	if c.CircuitBreaker.Failures > 0 {
+		mws = append(mws, WithCircuitBreaker(c.CircuitBreaker))
+	}
+	if c.MaxInFlight > 0 {
+		mws = append(mws, WithConcurrencyLimit(c.MaxInFlight))
+	}
+	if c.Timeout > 0 {
+		mws = append(mws, WithTimeout(c.Timeout))
+	}
+	return mws
+}
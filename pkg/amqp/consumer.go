@@ -2,16 +2,18 @@ package amqp
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
 
-	"github.com/go-playground/validator/v10"
 	amqp091 "github.com/rabbitmq/amqp091-go"
 )
 
-var validate = validator.New()
+var validate = newValidator()
 
 // HandlerFunc processes a single AMQP delivery.
 // Return nil to ack, return error to nack.
@@ -31,6 +33,16 @@ type ConsumerConfig struct {
 	RetryOnError         *bool         // nack+requeue on error, default: false
 	DeadLetterExchange   string        // optional, x-dead-letter-exchange
 	DeadLetterRoutingKey string        // optional, x-dead-letter-routing-key
+
+	// Queue features, see queue_options.go. Checked by Validate on Run.
+	QueueType            QueueType     // default: classic
+	MaxPriority          int           // optional, x-max-priority (classic only, 1..255)
+	MessageTTL           time.Duration // optional, x-message-ttl
+	MaxLength            int64         // optional, x-max-length
+	MaxLengthBytes       int64         // optional, x-max-length-bytes
+	Overflow             Overflow      // optional, x-overflow (default: drop-head)
+	SingleActiveConsumer bool          // optional, x-single-active-consumer
+	Lazy                 bool          // optional, x-queue-mode=lazy (classic only)
 }
 
 func (c ConsumerConfig) durable() bool {
@@ -61,15 +73,79 @@ func (c ConsumerConfig) retryOnError() bool {
 	return *c.RetryOnError
 }
 
-// consumer handles queue declaration, binding, prefetch, consume loop, and ack/nack
-// for a single AMQP queue. Created internally by [AddConsumer].
+// Topology returns the queue, binding and dead-letter objects the consumer needs.
+// With a DeadLetterExchange, a "<queue>.dlq" queue is bound to it with
+// DeadLetterRoutingKey (default: the queue name).
+func (c ConsumerConfig) Topology() Topology {
+	var t Topology
+
+	if c.DeadLetterExchange != "" {
+		dlq := c.Queue + ".dlq"
+		routingKey := c.DeadLetterRoutingKey
+		if routingKey == "" {
+			routingKey = c.Queue
+		}
+		t.Exchanges = append(t.Exchanges, ExchangeSpec{Name: c.DeadLetterExchange, Type: ExchangeTopic})
+		t.Queues = append(t.Queues, QueueSpec{Name: dlq})
+		t.Bindings = append(t.Bindings, BindingSpec{Source: c.DeadLetterExchange, Destination: dlq, RoutingKey: routingKey})
+	}
+
+	t.Queues = append(t.Queues, QueueSpec{
+		Name:       c.Queue,
+		Durable:    c.Durable,
+		AutoDelete: c.AutoDelete,
+		Exclusive:  c.Exclusive,
+		Args:       c.queueArgs(),
+	})
+
+	if c.Exchange != "" {
+		t.Bindings = append(t.Bindings, BindingSpec{
+			Source:      c.Exchange,
+			Destination: c.Queue,
+			RoutingKey:  c.RoutingKey,
+			Args:        c.BindingArgs,
+		})
+	}
+	return t
+}
+
+// consumer handles prefetch, consume loop, and ack/nack for a single AMQP queue.
+// Its queue and bindings are declared from [ConsumerConfig.Topology].
+// Runtime control (pause/resume, prefetch) and stats live in consumer_runtime.go,
+// in-flight tracking for shutdown in consumer_drain.go.
+// Created internally by [AddConsumer].
 type consumer struct {
 	cfg     ConsumerConfig
 	handler HandlerFunc
+
+	prefetch atomic.Int32
+	stats    consumerStats
+
+	mu       sync.Mutex
+	paused   bool
+	resumed  chan struct{}      // closed on resume, set while paused
+	stop     context.CancelFunc // ends the current consume round
+	restart  bool               // the round was stopped by a prefetch change
+	inflight map[*inflightDelivery]struct{}
 }
 
 func newConsumer(cfg ConsumerConfig, handler HandlerFunc, mws ...Middleware) *consumer {
-	return &consumer{cfg: cfg, handler: Chain(handler, mws...)}
+	c := &consumer{
+		cfg:      cfg,
+		handler:  Chain(handler, mws...),
+		inflight: make(map[*inflightDelivery]struct{}),
+	}
+	c.prefetch.Store(int32(cfg.prefetchCount()))
+	return c
+}
+
+type consumerCtxKey struct{}
+
+// consumerFromCtx returns the consumer handling the current delivery, or nil
+// outside of a consumer (e.g. when a middleware is called directly).
+func consumerFromCtx(ctx context.Context) *consumer {
+	c, _ := ctx.Value(consumerCtxKey{}).(*consumer)
+	return c
 }
 
 // DeliveryMeta holds AMQP message metadata extracted from amqp091.Delivery.
@@ -80,6 +156,10 @@ type DeliveryMeta struct {
 	MessageID   string
 	ContentType string
 	Timestamp   int64
+
+	// CorrelationID and ReplyTo are set on RPC requests (see [AddRPCHandler]).
+	CorrelationID string
+	ReplyTo       string
 }
 
 func newDeliveryMeta(msg *amqp091.Delivery) DeliveryMeta {
@@ -90,21 +170,26 @@ func newDeliveryMeta(msg *amqp091.Delivery) DeliveryMeta {
 		MessageID:   msg.MessageId,
 		ContentType: msg.ContentType,
 		Timestamp:   msg.Timestamp.Unix(),
+
+		CorrelationID: msg.CorrelationId,
+		ReplyTo:       msg.ReplyTo,
 	}
 }
 
 // typedHandler wraps a typed handler function into a HandlerFunc.
-// It unmarshals the message body into T and validates it before calling fn.
+// It decodes the message body into T with the codec matching the message
+// content type and validates it before calling fn.
+// Validation errors are translated into the publisher's language (see [HeaderLanguage]).
 // The handler receives the deserialized payload followed by DeliveryMeta.
 func typedHandler[T any](fn func(ctx context.Context, payload T, meta DeliveryMeta) error) HandlerFunc {
 	return func(ctx context.Context, msg amqp091.Delivery) error {
 		var payload T
-		if err := json.Unmarshal(msg.Body, &payload); err != nil {
+		if err := Decode(msg.ContentType, msg.Body, &payload); err != nil {
 			return fmt.Errorf("unmarshal: %w", err)
 		}
 		if isStruct(payload) {
 			if err := validate.StructCtx(ctx, payload); err != nil {
-				return fmt.Errorf("validate: %w", err)
+				return fmt.Errorf("validate: %w", translateValidation(ctx, msg.Headers, err))
 			}
 		}
 		return fn(ctx, payload, newDeliveryMeta(&msg))
@@ -119,10 +204,11 @@ func rawHandler(fn func(ctx context.Context, body []byte, meta DeliveryMeta) err
 	}
 }
 
-// Validate runs struct validation on v if it is a struct.
+// Validate runs struct validation on v if it is a struct. Errors are translated
+// into the language of the [i18n.Localizer] of ctx, if any.
 func Validate(ctx context.Context, v any) error {
 	if isStruct(v) {
-		return validate.StructCtx(ctx, v)
+		return translateValidation(ctx, nil, validate.StructCtx(ctx, v))
 	}
 	return nil
 }
@@ -138,15 +224,8 @@ func isStruct(v any) bool {
 	return t.Kind() == reflect.Struct
 }
 
-func (c *consumer) Declare(ch *amqp091.Channel) error {
-	if err := c.declare(ch); err != nil {
-		return err
-	}
-	return c.bind(ch)
-}
-
 func (c *consumer) Consume(ctx context.Context, ch *amqp091.Channel) error {
-	if err := ch.Qos(c.cfg.prefetchCount(), 0, false); err != nil {
+	if err := ch.Qos(int(c.prefetch.Load()), 0, false); err != nil {
 		return err
 	}
 
@@ -156,66 +235,32 @@ func (c *consumer) Consume(ctx context.Context, ch *amqp091.Channel) error {
 	}
 
 	for msg := range msgs {
+		// The library keeps delivering prefetched messages after cancellation.
+		// Leave them unacked: they are requeued when the channel closes.
+		if ctx.Err() != nil {
+			return nil
+		}
 		c.process(ctx, msg)
 	}
 
 	return nil
 }
 
-func (c *consumer) declare(ch *amqp091.Channel) error {
-	var args amqp091.Table
-	if c.cfg.DeadLetterExchange != "" {
-		if err := c.declareDLQ(ch); err != nil {
-			return fmt.Errorf("declare dlq: %w", err)
-		}
-		args = amqp091.Table{"x-dead-letter-exchange": c.cfg.DeadLetterExchange}
-		if c.cfg.DeadLetterRoutingKey != "" {
-			args["x-dead-letter-routing-key"] = c.cfg.DeadLetterRoutingKey
-		}
-	}
-
-	_, err := ch.QueueDeclare(
-		c.cfg.Queue,
-		c.cfg.durable(),
-		c.cfg.AutoDelete,
-		c.cfg.Exclusive,
-		false, // noWait
-		args,
-	)
-	return err
-}
-
-func (c *consumer) declareDLQ(ch *amqp091.Channel) error {
-	if err := ch.ExchangeDeclare(c.cfg.DeadLetterExchange, "topic", true, false, false, false, nil); err != nil {
-		return err
-	}
-
-	dlqQueue := c.cfg.Queue + ".dlq"
-	if _, err := ch.QueueDeclare(dlqQueue, true, false, false, false, nil); err != nil {
-		return err
-	}
-
-	routingKey := c.cfg.DeadLetterRoutingKey
-	if routingKey == "" {
-		routingKey = c.cfg.Queue
-	}
-	return ch.QueueBind(dlqQueue, routingKey, c.cfg.DeadLetterExchange, false, nil)
-}
-
-func (c *consumer) bind(ch *amqp091.Channel) error {
-	if c.cfg.Exchange == "" {
-		return nil
-	}
-	return ch.QueueBind(c.cfg.Queue, c.cfg.RoutingKey, c.cfg.Exchange, false, c.cfg.BindingArgs)
-}
-
 func (c *consumer) process(parent context.Context, msg amqp091.Delivery) {
 	ctx, cancel := context.WithCancel(context.WithoutCancel(parent))
 	defer cancel()
+	ctx = context.WithValue(ctx, consumerCtxKey{}, c)
 
+	d := c.track(msg, cancel)
+	done := c.stats.begin()
 	err := c.handler(ctx, msg)
+	done(err)
+
+	if !c.untrack(d) {
+		return // aborted by Drain, already requeued
+	}
 	if err != nil {
-		_ = msg.Nack(false, c.cfg.retryOnError())
+		_ = msg.Nack(false, c.cfg.retryOnError() || errors.Is(err, ErrCircuitOpen))
 		return
 	}
 	if ackErr := msg.Ack(false); ackErr != nil {
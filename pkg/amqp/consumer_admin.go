@@ -0,0 +1,66 @@
+package amqp
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrConsumerNotFound is returned by the consumer admin methods for an unknown queue.
+var ErrConsumerNotFound = errors.New("amqp: consumer not found")
+
+// ConsumerInfo is a runtime snapshot of a registered consumer.
+type ConsumerInfo struct {
+	Queue         string
+	Concurrency   int
+	Prefetch      int
+	Paused        bool
+	InFlight      int64
+	Processed     uint64
+	Failed        uint64
+	LastMessageAt time.Time // zero if no message was received yet
+	LastError     string
+	LastErrorAt   time.Time
+}
+
+// Consumers returns a snapshot of every registered consumer, in registration order.
+func (b *Broker) Consumers() []ConsumerInfo {
+	out := make([]ConsumerInfo, len(b.consumers.consumers))
+	for i, c := range b.consumers.consumers {
+		out[i] = c.info()
+	}
+	return out
+}
+
+// PauseConsumer stops fetching new deliveries for the consumer of queue.
+// In-flight handlers finish; prefetched but unprocessed deliveries are requeued.
+// Other consumers keep running. Pausing a paused consumer is a no-op.
+func (b *Broker) PauseConsumer(queue string) error {
+	c, err := b.consumers.find(queue)
+	if err != nil {
+		return err
+	}
+	c.pause()
+	return nil
+}
+
+// ResumeConsumer restarts a consumer paused with [Broker.PauseConsumer].
+func (b *Broker) ResumeConsumer(queue string) error {
+	c, err := b.consumers.find(queue)
+	if err != nil {
+		return err
+	}
+	c.resume()
+	return nil
+}
+
+// SetConsumerPrefetch changes the prefetch count of the consumer of queue.
+// The current consume round ends like on pause (in-flight handlers finish,
+// prefetched deliveries are requeued) and a new one starts right away on new
+// channels with the new prefetch.
+func (b *Broker) SetConsumerPrefetch(queue string, prefetch int) error {
+	c, err := b.consumers.find(queue)
+	if err != nil {
+		return err
+	}
+	return c.setPrefetch(prefetch)
+}
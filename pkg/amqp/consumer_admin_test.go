@@ -0,0 +1,176 @@
+//go:build unit
+
+package amqp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	amqp091 "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAcknowledger struct {
+	acked, nacked int
+}
+
+func (a *fakeAcknowledger) Ack(uint64, bool) error        { a.acked++; return nil }
+func (a *fakeAcknowledger) Nack(uint64, bool, bool) error { a.nacked++; return nil }
+func (a *fakeAcknowledger) Reject(uint64, bool) error     { return nil }
+
+func newAdminTestBroker(t *testing.T) *Broker {
+	t.Helper()
+	b := NewBroker(nil, PoolConfig{})
+	AddRawConsumer(b, ConsumerConfig{Queue: "q", Concurrency: 2, PrefetchCount: 5}, func(_ context.Context, body []byte, _ DeliveryMeta) error {
+		if string(body) == "fail" {
+			return errors.New("downstream unavailable")
+		}
+		return nil
+	})
+	return b
+}
+
+func TestBroker_Consumers_Snapshot(t *testing.T) {
+	b := newAdminTestBroker(t)
+	c := b.consumers.consumers[0]
+	ack := &fakeAcknowledger{}
+
+	c.process(context.Background(), amqp091.Delivery{Acknowledger: ack, Body: []byte("ok")})
+	c.process(context.Background(), amqp091.Delivery{Acknowledger: ack, Body: []byte("fail")})
+
+	infos := b.Consumers()
+	require.Len(t, infos, 1)
+	info := infos[0]
+	assert.Equal(t, "q", info.Queue)
+	assert.Equal(t, 2, info.Concurrency)
+	assert.Equal(t, 5, info.Prefetch)
+	assert.False(t, info.Paused)
+	assert.Equal(t, int64(0), info.InFlight)
+	assert.Equal(t, uint64(1), info.Processed)
+	assert.Equal(t, uint64(1), info.Failed)
+	assert.Equal(t, "downstream unavailable", info.LastError)
+	assert.False(t, info.LastMessageAt.IsZero())
+	assert.Equal(t, 1, ack.acked)
+	assert.Equal(t, 1, ack.nacked)
+}
+
+func TestBroker_PauseResumeConsumer(t *testing.T) {
+	b := newAdminTestBroker(t)
+	c := b.consumers.consumers[0]
+
+	require.NoError(t, b.PauseConsumer("q"))
+	assert.True(t, b.Consumers()[0].Paused)
+
+	started := make(chan struct{})
+	go func() {
+		_, ok := c.startRound(context.Background())
+		assert.True(t, ok)
+		close(started)
+	}()
+
+	select {
+	case <-started:
+		t.Fatal("paused consumer must not start a consume round")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	require.NoError(t, b.ResumeConsumer("q"))
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("resumed consumer did not start a consume round")
+	}
+	assert.False(t, b.Consumers()[0].Paused)
+}
+
+func TestBroker_PauseConsumer_EndsCurrentRound(t *testing.T) {
+	b := newAdminTestBroker(t)
+	c := b.consumers.consumers[0]
+
+	roundCtx, ok := c.startRound(context.Background())
+	require.True(t, ok)
+
+	require.NoError(t, b.PauseConsumer("q"))
+	assert.ErrorIs(t, roundCtx.Err(), context.Canceled)
+}
+
+func TestBroker_PausedConsumer_StopsOnContextDone(t *testing.T) {
+	b := newAdminTestBroker(t)
+	require.NoError(t, b.PauseConsumer("q"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, ok := b.consumers.consumers[0].startRound(ctx)
+	assert.False(t, ok)
+}
+
+func TestBroker_SetConsumerPrefetch(t *testing.T) {
+	b := newAdminTestBroker(t)
+
+	require.NoError(t, b.SetConsumerPrefetch("q", 20))
+	assert.Equal(t, 20, b.Consumers()[0].Prefetch)
+
+	assert.Error(t, b.SetConsumerPrefetch("q", 0))
+	assert.Equal(t, 20, b.Consumers()[0].Prefetch)
+}
+
+// roundTransport records the prefetch each consume round starts with, as
+// Consume applies it to a new channel, and blocks until the round ends.
+type roundTransport struct {
+	rounds chan int
+}
+
+func (t roundTransport) consume(ctx context.Context, c *consumer) error {
+	t.rounds <- int(c.prefetch.Load())
+	<-ctx.Done()
+	return nil
+}
+
+func TestBroker_SetConsumerPrefetch_RestartsRound(t *testing.T) {
+	b := newAdminTestBroker(t)
+	c := b.consumers.consumers[0]
+	tr := roundTransport{rounds: make(chan int, 2*c.cfg.concurrency())}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- c.run(ctx, tr) }()
+
+	nextRound := func() []int {
+		t.Helper()
+		var got []int
+		for range c.cfg.concurrency() {
+			select {
+			case n := <-tr.rounds:
+				got = append(got, n)
+			case <-time.After(time.Second):
+				t.Fatal("no consume round started")
+			}
+		}
+		return got
+	}
+
+	assert.Equal(t, []int{5, 5}, nextRound())
+
+	require.NoError(t, b.SetConsumerPrefetch("q", 20))
+	assert.Equal(t, []int{20, 20}, nextRound(), "channels of the new round consume with the new prefetch")
+
+	cancel()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("run did not return after ctx was done")
+	}
+}
+
+func TestBroker_ConsumerAdmin_NotFound(t *testing.T) {
+	b := newAdminTestBroker(t)
+
+	assert.ErrorIs(t, b.PauseConsumer("missing"), ErrConsumerNotFound)
+	assert.ErrorIs(t, b.ResumeConsumer("missing"), ErrConsumerNotFound)
+	assert.ErrorIs(t, b.SetConsumerPrefetch("missing", 10), ErrConsumerNotFound)
+}
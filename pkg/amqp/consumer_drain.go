@@ -0,0 +1,106 @@
+package amqp
+
+import (
+	"context"
+	"log/slog"
+
+	amqp091 "github.com/rabbitmq/amqp091-go"
+)
+
+// DrainReport summarizes [Broker.Drain].
+type DrainReport struct {
+	Drained int // in-flight handlers that finished before the deadline
+	Aborted int // handlers still running at the deadline; their deliveries were requeued
+}
+
+// inflightDelivery is a delivery whose handler is running.
+type inflightDelivery struct {
+	msg    amqp091.Delivery
+	cancel context.CancelFunc
+}
+
+// track registers msg as in flight. cancel cancels the handler context.
+func (c *consumer) track(msg amqp091.Delivery, cancel context.CancelFunc) *inflightDelivery {
+	d := &inflightDelivery{msg: msg, cancel: cancel}
+	c.mu.Lock()
+	c.inflight[d] = struct{}{}
+	c.mu.Unlock()
+	return d
+}
+
+// untrack removes d and reports whether the caller still owns its ack.
+// It returns false if the delivery was already requeued by abortInFlight.
+func (c *consumer) untrack(d *inflightDelivery) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.inflight[d]; !ok {
+		return false
+	}
+	delete(c.inflight, d)
+	return true
+}
+
+func (c *consumer) inFlight() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.inflight)
+}
+
+// abortInFlight cancels every running handler and nacks its delivery with requeue.
+// The handlers' own ack/nack is skipped when they return. It returns the number of aborted deliveries.
+func (c *consumer) abortInFlight() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := 0
+	for d := range c.inflight {
+		delete(c.inflight, d)
+		d.cancel()
+		if err := d.msg.Nack(false, true); err != nil {
+			slog.Error("failed to requeue aborted message",
+				slog.String("queue", c.cfg.Queue),
+				slog.Any("error", err),
+			)
+		}
+		n++
+	}
+	return n
+}
+
+// Drain stops fetching new deliveries and waits for in-flight handlers until
+// they finish or ctx is done. Handlers still running at the deadline get their
+// context cancelled, and their deliveries are nacked with requeue.
+// It returns immediately if the group is not running.
+func (g *consumerGroup) Drain(ctx context.Context) DrainReport {
+	g.mu.Lock()
+	cancel, done := g.cancel, g.done
+	g.mu.Unlock()
+
+	if cancel == nil {
+		return DrainReport{}
+	}
+	cancel()
+
+	inFlight := 0
+	for _, c := range g.consumers {
+		inFlight += c.inFlight()
+	}
+
+	var report DrainReport
+	select {
+	case <-done:
+		report.Drained = inFlight
+	case <-ctx.Done():
+		for _, c := range g.consumers {
+			report.Aborted += c.abortInFlight()
+		}
+		// A handler may have started right after the snapshot.
+		report.Drained = max(inFlight-report.Aborted, 0)
+	}
+
+	slog.Info("amqp consumers drained",
+		slog.Int("drained", report.Drained),
+		slog.Int("aborted", report.Aborted),
+	)
+	return report
+}
@@ -0,0 +1,106 @@
+//go:build unit
+
+package amqp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	amqp091 "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingAcknowledger struct {
+	acked, nacked int
+	requeue       bool
+}
+
+func (a *recordingAcknowledger) Ack(uint64, bool) error { a.acked++; return nil }
+func (a *recordingAcknowledger) Nack(_ uint64, _ bool, requeue bool) error {
+	a.nacked++
+	a.requeue = requeue
+	return nil
+}
+func (a *recordingAcknowledger) Reject(uint64, bool) error { return nil }
+
+// newDrainTestGroup returns a running group (as after Run) with one consumer
+// whose handler blocks until release is closed or its context is cancelled.
+func newDrainTestGroup(t *testing.T, release <-chan struct{}) (*consumerGroup, *consumer, chan struct{}) {
+	t.Helper()
+	b := NewBroker(nil, PoolConfig{})
+	AddRawConsumer(b, ConsumerConfig{Queue: "q"}, func(ctx context.Context, _ []byte, _ DeliveryMeta) error {
+		select {
+		case <-release:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+
+	g := b.consumers
+	done := make(chan struct{})
+	g.cancel = func() {}
+	g.done = done
+	return g, g.consumers[0], done
+}
+
+func waitInFlight(t *testing.T, c *consumer, n int) {
+	t.Helper()
+	require.Eventually(t, func() bool { return c.inFlight() == n }, time.Second, time.Millisecond)
+}
+
+func TestConsumerGroup_Drain_WaitsForInFlight(t *testing.T) {
+	release := make(chan struct{})
+	g, c, done := newDrainTestGroup(t, release)
+	ack := &recordingAcknowledger{}
+
+	processed := make(chan struct{})
+	go func() {
+		c.process(context.Background(), amqp091.Delivery{Acknowledger: ack})
+		close(processed)
+	}()
+	waitInFlight(t, c, 1)
+
+	go func() {
+		close(release)
+		<-processed
+		close(done) // Run returns once handlers finish
+	}()
+
+	report := g.Drain(context.Background())
+
+	assert.Equal(t, DrainReport{Drained: 1}, report)
+	assert.Equal(t, 1, ack.acked)
+	assert.Equal(t, 0, ack.nacked)
+}
+
+func TestConsumerGroup_Drain_RequeuesAtDeadline(t *testing.T) {
+	g, c, _ := newDrainTestGroup(t, make(chan struct{}))
+	ack := &recordingAcknowledger{}
+
+	processed := make(chan struct{})
+	go func() {
+		c.process(context.Background(), amqp091.Delivery{Acknowledger: ack})
+		close(processed)
+	}()
+	waitInFlight(t, c, 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	report := g.Drain(ctx)
+	<-processed // handler context was cancelled
+
+	assert.Equal(t, DrainReport{Aborted: 1}, report)
+	assert.Equal(t, 0, ack.acked)
+	assert.Equal(t, 1, ack.nacked, "aborted delivery is nacked once, by Drain only")
+	assert.True(t, ack.requeue)
+	assert.Equal(t, 0, c.inFlight())
+}
+
+func TestConsumerGroup_Drain_NotRunning(t *testing.T) {
+	b := NewBroker(nil, PoolConfig{})
+	assert.Equal(t, DrainReport{}, b.Drain(context.Background()))
+}
@@ -14,15 +14,17 @@ import (
 // Not intended for direct use — access via [Broker].
 type consumerGroup struct {
 	conn      *amqp091.Connection
+	mem       *memoryBroker // used when conn is nil
 	consumers []*consumer
 	mws       []Middleware
 
 	mu     sync.Mutex
 	cancel context.CancelFunc
+	done   chan struct{} // closed when Run returns
 }
 
-func newConsumerGroup(conn *amqp091.Connection) *consumerGroup {
-	return &consumerGroup{conn: conn}
+func newConsumerGroup(conn *amqp091.Connection, mem *memoryBroker) *consumerGroup {
+	return &consumerGroup{conn: conn, mem: mem}
 }
 
 func (g *consumerGroup) Use(mws ...Middleware) {
@@ -47,27 +49,29 @@ func AddRawConsumer(b *Broker, cfg ConsumerConfig, fn func(ctx context.Context,
 }
 
 func (g *consumerGroup) Run(ctx context.Context) error {
-	if g.conn == nil {
-		slog.Warn("standalone mode: skipping amqp consumers")
-		<-ctx.Done()
-		return nil
+	for _, c := range g.consumers {
+		if err := c.cfg.Validate(); err != nil {
+			return err
+		}
 	}
 
-	slog.Info("amqp consumers started", slog.Int("count", len(g.consumers)))
-
-	// Declare queues/bindings once per consumer before starting goroutines.
-	for _, c := range g.consumers {
-		ch, err := g.conn.Channel()
-		if err != nil {
-			return fmt.Errorf("open channel for declare: %w", err)
+	// Declare queues/bindings once before starting goroutines.
+	var transport consumeTransport
+	if g.conn != nil {
+		if err := declareTopology(g.conn, g.topology()); err != nil {
+			return fmt.Errorf("declare consumers: %w", err)
 		}
-		if err := c.Declare(ch); err != nil {
-			ch.Close()
-			return fmt.Errorf("declare consumer %q: %w", c.cfg.Queue, err)
+		transport = connTransport{conn: g.conn}
+	} else {
+		slog.Warn("standalone mode: consuming from the in-memory broker")
+		if err := g.mem.declare(g.topology()); err != nil {
+			return fmt.Errorf("declare consumers: %w", err)
 		}
-		ch.Close()
+		transport = g.mem
 	}
 
+	slog.Info("amqp consumers started", slog.Int("count", len(g.consumers)))
+
 	eg, egCtx := errgroup.WithContext(ctx)
 
 	// consumeCtx controls message delivery.
@@ -75,22 +79,18 @@ func (g *consumerGroup) Run(ctx context.Context) error {
 	consumeCtx, consumeCancel := context.WithCancel(egCtx)
 	defer consumeCancel()
 
+	done := make(chan struct{})
+	defer close(done)
+
 	g.mu.Lock()
 	g.cancel = consumeCancel
+	g.done = done
 	g.mu.Unlock()
 
 	for _, c := range g.consumers {
-		for range c.cfg.concurrency() {
-			eg.Go(func() error {
-				ch, err := g.conn.Channel()
-				if err != nil {
-					return err
-				}
-				defer ch.Close()
-
-				return c.Consume(consumeCtx, ch)
-			})
-		}
+		eg.Go(func() error {
+			return c.run(consumeCtx, transport)
+		})
 	}
 
 	err := eg.Wait()
@@ -98,6 +98,25 @@ func (g *consumerGroup) Run(ctx context.Context) error {
 	return err
 }
 
+// find returns the consumer registered for queue.
+func (g *consumerGroup) find(queue string) (*consumer, error) {
+	for _, c := range g.consumers {
+		if c.cfg.Queue == queue {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q", ErrConsumerNotFound, queue)
+}
+
+// topology merges the topologies of all registered consumers.
+func (g *consumerGroup) topology() Topology {
+	ts := make([]Topology, len(g.consumers))
+	for i, c := range g.consumers {
+		ts[i] = c.cfg.Topology()
+	}
+	return MergeTopologies(ts...)
+}
+
 func (g *consumerGroup) Shutdown() {
 	g.mu.Lock()
 	defer g.mu.Unlock()
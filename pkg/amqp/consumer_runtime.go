@@ -0,0 +1,213 @@
+package amqp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	amqp091 "github.com/rabbitmq/amqp091-go"
+	"golang.org/x/sync/errgroup"
+)
+
+// consumerStats tracks handler activity of one consumer.
+type consumerStats struct {
+	inFlight  atomic.Int64
+	processed atomic.Uint64
+	failed    atomic.Uint64
+
+	mu            sync.Mutex
+	lastMessageAt time.Time
+	lastError     string
+	lastErrorAt   time.Time
+}
+
+// begin records the start of a handler call and returns the function that records its end.
+func (s *consumerStats) begin() func(err error) {
+	s.inFlight.Add(1)
+	s.mu.Lock()
+	s.lastMessageAt = time.Now()
+	s.mu.Unlock()
+
+	return func(err error) {
+		s.inFlight.Add(-1)
+		if err == nil {
+			s.processed.Add(1)
+			return
+		}
+		s.failed.Add(1)
+		s.mu.Lock()
+		s.lastError = err.Error()
+		s.lastErrorAt = time.Now()
+		s.mu.Unlock()
+	}
+}
+
+// consumeTransport runs one delivery loop of a consumer until ctx is done.
+// It is implemented by connTransport and by the in-memory broker.
+type consumeTransport interface {
+	consume(ctx context.Context, c *consumer) error
+}
+
+// connTransport consumes from a RabbitMQ connection on a dedicated channel per loop.
+type connTransport struct {
+	conn *amqp091.Connection
+}
+
+func (t connTransport) consume(ctx context.Context, c *consumer) error {
+	ch, err := t.conn.Channel()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = ch.Close() }()
+
+	return c.Consume(ctx, ch)
+}
+
+// run consumes with cfg.concurrency() goroutines until ctx is done.
+// Each consume "round" lasts until ctx is done, the consumer is paused or its
+// prefetch is changed; ending a round lets in-flight handlers finish and
+// requeues unacked prefetched deliveries when the channels close. After a
+// pause the next round waits for resume; after a prefetch change it starts
+// right away on new channels with the new prefetch.
+func (c *consumer) run(ctx context.Context, t consumeTransport) error {
+	for {
+		roundCtx, ok := c.startRound(ctx)
+		if !ok {
+			return nil
+		}
+
+		err := c.consumeRound(roundCtx, t)
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil || !c.nextRound() {
+			return nil
+		}
+	}
+}
+
+// startRound blocks while the consumer is paused. It returns false when ctx is done first.
+func (c *consumer) startRound(ctx context.Context) (context.Context, bool) {
+	for {
+		c.mu.Lock()
+		if !c.paused {
+			roundCtx, cancel := context.WithCancel(ctx)
+			c.stop = cancel
+			c.mu.Unlock()
+			return roundCtx, true
+		}
+		resumed := c.resumed
+		c.mu.Unlock()
+
+		select {
+		case <-resumed:
+		case <-ctx.Done():
+			return nil, false
+		}
+	}
+}
+
+func (c *consumer) consumeRound(ctx context.Context, t consumeTransport) error {
+	defer c.endRound()
+
+	eg, egCtx := errgroup.WithContext(ctx)
+	for range c.cfg.concurrency() {
+		eg.Go(func() error {
+			return t.consume(egCtx, c)
+		})
+	}
+	return eg.Wait()
+}
+
+func (c *consumer) endRound() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stop != nil {
+		c.stop()
+		c.stop = nil
+	}
+}
+
+func (c *consumer) isPaused() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.paused
+}
+
+// nextRound reports whether the round that just ended was stopped by a pause
+// or a prefetch change, i.e. whether run must start another one.
+func (c *consumer) nextRound() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	restart := c.restart
+	c.restart = false
+	return restart || c.paused
+}
+
+// pause stops fetching new deliveries. It returns immediately; in-flight
+// handlers finish in the background.
+func (c *consumer) pause() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.paused {
+		return
+	}
+	c.paused = true
+	c.resumed = make(chan struct{})
+	if c.stop != nil {
+		c.stop()
+	}
+}
+
+func (c *consumer) resume() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.paused {
+		return
+	}
+	c.paused = false
+	close(c.resumed)
+}
+
+// setPrefetch stores the new prefetch and ends the current consume round.
+// RabbitMQ applies a channel's basic.qos only to consumers started after it,
+// so run starts a new round whose channels set the new prefetch before
+// consuming.
+func (c *consumer) setPrefetch(n int) error {
+	if n <= 0 || n > 65535 {
+		return fmt.Errorf("amqp: prefetch must be between 1 and 65535, got %d", n)
+	}
+	c.prefetch.Store(int32(n))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stop != nil {
+		c.restart = true
+		c.stop()
+	}
+	return nil
+}
+
+func (c *consumer) info() ConsumerInfo {
+	c.mu.Lock()
+	paused := c.paused
+	c.mu.Unlock()
+
+	c.stats.mu.Lock()
+	defer c.stats.mu.Unlock()
+
+	return ConsumerInfo{
+		Queue:         c.cfg.Queue,
+		Concurrency:   c.cfg.concurrency(),
+		Prefetch:      int(c.prefetch.Load()),
+		Paused:        paused,
+		InFlight:      c.stats.inFlight.Load(),
+		Processed:     c.stats.processed.Load(),
+		Failed:        c.stats.failed.Load(),
+		LastMessageAt: c.stats.lastMessageAt,
+		LastError:     c.stats.lastError,
+		LastErrorAt:   c.stats.lastErrorAt,
+	}
+}
@@ -0,0 +1,51 @@
+package amqp
+
+import (
+	"context"
+	"maps"
+
+	"starter-boilerplate/pkg/i18n"
+
+	"github.com/go-playground/validator/v10"
+	amqp091 "github.com/rabbitmq/amqp091-go"
+	"golang.org/x/text/language"
+)
+
+// HeaderLanguage is the message header with the publisher's language: PublishEncoded
+// sets it from the caller's [i18n.Localizer], and consumers translate validation
+// errors of the message into it.
+const HeaderLanguage = "accept-language"
+
+// WithLanguage sets the language header of the message (see [HeaderLanguage]).
+func WithLanguage(tag language.Tag) PublishOption {
+	return func(e *envelope) {
+		headers := maps.Clone(e.msg.Headers)
+		if headers == nil {
+			headers = amqp091.Table{}
+		}
+		headers[HeaderLanguage] = tag.String()
+		e.msg.Headers = headers
+	}
+}
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	if err := i18n.RegisterValidationTranslations(v); err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// translateValidation translates validation errors into the language of ctx
+// or, failing that, of the message headers; without either they stay as is.
+func translateValidation(ctx context.Context, headers amqp091.Table, err error) error {
+	if l := i18n.FromContext(ctx); l != nil {
+		return i18n.TranslateValidation(l.Tag(), err)
+	}
+	if v, ok := headers[HeaderLanguage].(string); ok {
+		if tag, parseErr := language.Parse(v); parseErr == nil {
+			return i18n.TranslateValidation(tag, err)
+		}
+	}
+	return err
+}
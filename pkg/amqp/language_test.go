@@ -0,0 +1,76 @@
+//go:build unit
+
+package amqp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"starter-boilerplate/pkg/i18n"
+
+	"github.com/go-playground/validator/v10"
+	amqp091 "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func russianContext() context.Context {
+	catalog := i18n.NewCatalog(language.English, map[language.Tag]i18n.Messages{language.Russian: {}})
+	return i18n.WithLocalizer(context.Background(), catalog.Localizer(language.Russian))
+}
+
+func TestValidate_TranslatesIntoCallerLanguage(t *testing.T) {
+	err := Validate(russianContext(), codecPayload{})
+
+	require.Error(t, err)
+	assert.Equal(t, "UserID обязательное поле", err.Error())
+	var verrs validator.ValidationErrors
+	assert.True(t, errors.As(err, &verrs), "validation errors stay reachable")
+}
+
+func TestValidate_WithoutLanguageKeepsMessages(t *testing.T) {
+	err := Validate(context.Background(), codecPayload{})
+
+	var translated *i18n.ValidationError
+	assert.False(t, errors.As(err, &translated))
+}
+
+func TestTypedHandler_TranslatesIntoPublisherLanguage(t *testing.T) {
+	h := typedHandler(func(_ context.Context, _ codecPayload, _ DeliveryMeta) error {
+		t.Fatal("handler should not be called")
+		return nil
+	})
+
+	err := h(context.Background(), amqp091.Delivery{
+		ContentType: ContentTypeJSON,
+		Headers:     amqp091.Table{HeaderLanguage: "es"},
+		Body:        []byte(`{}`),
+	})
+	require.Error(t, err)
+	assert.Equal(t, "validate: UserID es un campo requerido", err.Error())
+}
+
+func TestPublishJSON_CarriesCallerLanguage(t *testing.T) {
+	b := NewBroker(nil, PoolConfig{})
+	received := make(chan amqp091.Table, 1)
+	AddRawConsumer(b, ConsumerConfig{Queue: "users", Exchange: "events", RoutingKey: "user.*"},
+		func(_ context.Context, _ []byte, meta DeliveryMeta) error {
+			received <- meta.Headers
+			return nil
+		})
+	runMemoryBroker(t, b)
+
+	headers := amqp091.Table{"x-trace": "abc"}
+	require.NoError(t, b.PublishJSON(russianContext(), "events", "user.created", headers, codecPayload{UserID: "u-1"}, AtLeastOnce))
+
+	select {
+	case got := <-received:
+		assert.Equal(t, amqp091.Table{"x-trace": "abc", HeaderLanguage: "ru"}, got)
+	case <-time.After(time.Second):
+		t.Fatal("handler was not called")
+	}
+	assert.Equal(t, amqp091.Table{"x-trace": "abc"}, headers, "caller's headers are not modified")
+}
@@ -0,0 +1,465 @@
+package amqp
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	amqp091 "github.com/rabbitmq/amqp091-go"
+)
+
+// memoryBroker is the in-process transport a [Broker] uses without a connection
+// (standalone mode, unit tests). It routes like RabbitMQ:
+//   - the default exchange ("") delivers to the queue named by the routing key;
+//   - direct, fanout, topic ("*" one word, "#" zero or more) and headers
+//     (x-match all/any/all-with-x/any-with-x) exchanges, including exchange-to-exchange bindings;
+//   - mandatory messages that match no queue fail with [*UnroutableError];
+//   - a rejected or expired message (x-message-ttl, Expiration) goes to the
+//     queue's x-dead-letter-exchange with an x-death header; a requeued one
+//     returns to the head of the queue marked Redelivered.
+//
+// Durability, max length, priorities and stream semantics are not emulated.
+type memoryBroker struct {
+	mu        sync.Mutex
+	exchanges map[string]ExchangeSpec
+	queues    map[string]*memoryQueue
+	bindings  []BindingSpec
+	tag       uint64
+
+	// replies receives messages published to direct reply-to (see rpcClient).
+	replies func(amqp091.Delivery)
+}
+
+func newMemoryBroker() *memoryBroker {
+	return &memoryBroker{
+		exchanges: make(map[string]ExchangeSpec),
+		queues:    make(map[string]*memoryQueue),
+	}
+}
+
+// declare applies t. Like RabbitMQ, redeclaring an object with the same
+// properties is a no-op and a conflicting redeclaration fails.
+func (m *memoryBroker) declare(t Topology) error {
+	if err := t.Validate(); err != nil {
+		return fmt.Errorf("invalid topology: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, e := range t.Exchanges {
+		if prev, ok := m.exchanges[e.Name]; ok && prev.Type != e.Type {
+			return fmt.Errorf("declare exchange %q: PRECONDITION_FAILED: type %s, existing %s", e.Name, e.Type, prev.Type)
+		}
+		m.exchanges[e.Name] = e
+	}
+	for _, q := range t.Queues {
+		if prev, ok := m.queues[q.Name]; ok {
+			if argsKey(prev.spec.Args) != argsKey(q.Args) {
+				return fmt.Errorf("declare queue %q: PRECONDITION_FAILED: inequivalent args", q.Name)
+			}
+			continue
+		}
+		m.queues[q.Name] = newMemoryQueue(q)
+	}
+	for _, b := range t.Bindings {
+		if b.destinationType() == DestinationExchange {
+			if _, ok := m.exchanges[b.Destination]; !ok {
+				return fmt.Errorf("bind %s: NOT_FOUND: no exchange %q", b.key(), b.Destination)
+			}
+		} else if _, ok := m.queues[b.Destination]; !ok {
+			return fmt.Errorf("bind %s: NOT_FOUND: no queue %q", b.key(), b.Destination)
+		}
+		if _, ok := m.exchanges[b.Source]; !ok {
+			return fmt.Errorf("bind %s: NOT_FOUND: no exchange %q", b.key(), b.Source)
+		}
+		if !m.hasBinding(b) {
+			m.bindings = append(m.bindings, b)
+		}
+	}
+	return nil
+}
+
+func (m *memoryBroker) hasBinding(b BindingSpec) bool {
+	for _, existing := range m.bindings {
+		if existing.key() == b.key() {
+			return true
+		}
+	}
+	return false
+}
+
+// Publish implements publisher. Both delivery guarantees behave the same:
+// routing is synchronous, so a nil error means the message is enqueued.
+func (m *memoryBroker) Publish(_ context.Context, e envelope) error {
+	if e.exchange == "" && strings.HasPrefix(e.routingKey, directReplyTo) {
+		m.reply(e)
+		return nil
+	}
+
+	m.mu.Lock()
+	if _, ok := m.exchanges[e.exchange]; !ok && e.exchange != "" {
+		m.mu.Unlock()
+		return fmt.Errorf("amqp: publish: NOT_FOUND: no exchange %q", e.exchange)
+	}
+	queues := m.route(e.exchange, e.routingKey, e.msg.Headers, map[string]bool{})
+	m.mu.Unlock()
+
+	if len(queues) == 0 {
+		if e.mandatory {
+			return &UnroutableError{Exchange: e.exchange, RoutingKey: e.routingKey, ReplyCode: amqp091.NoRoute, ReplyText: "NO_ROUTE"}
+		}
+		return nil
+	}
+	for _, q := range queues {
+		q.push(m.newMessage(e), false)
+	}
+	return nil
+}
+
+func (m *memoryBroker) PublishBatch(ctx context.Context, batch []envelope) []error {
+	errs := make([]error, len(batch))
+	for i, e := range batch {
+		errs[i] = m.Publish(ctx, e)
+	}
+	return errs
+}
+
+func (m *memoryBroker) Close() error { return nil }
+
+func (m *memoryBroker) reply(e envelope) {
+	m.mu.Lock()
+	replies := m.replies
+	m.mu.Unlock()
+	if replies != nil {
+		replies(m.newMessage(e).delivery(nil))
+	}
+}
+
+// route returns the queues a message reaches from exchange. m.mu is held.
+func (m *memoryBroker) route(exchange, routingKey string, headers amqp091.Table, visited map[string]bool) []*memoryQueue {
+	if exchange == "" {
+		if q, ok := m.queues[routingKey]; ok {
+			return []*memoryQueue{q}
+		}
+		return nil
+	}
+	if visited[exchange] {
+		return nil
+	}
+	visited[exchange] = true
+
+	kind := m.exchanges[exchange].Type
+	var out []*memoryQueue
+	seen := map[*memoryQueue]bool{}
+	for _, b := range m.bindings {
+		if b.Source != exchange || !bindingMatches(kind, b, routingKey, headers) {
+			continue
+		}
+		var targets []*memoryQueue
+		if b.destinationType() == DestinationExchange {
+			targets = m.route(b.Destination, routingKey, headers, visited)
+		} else if q, ok := m.queues[b.Destination]; ok {
+			targets = []*memoryQueue{q}
+		}
+		for _, q := range targets {
+			if !seen[q] {
+				seen[q] = true
+				out = append(out, q)
+			}
+		}
+	}
+	return out
+}
+
+func bindingMatches(kind string, b BindingSpec, routingKey string, headers amqp091.Table) bool {
+	switch kind {
+	case ExchangeFanout:
+		return true
+	case ExchangeTopic:
+		return topicMatches(strings.Split(b.RoutingKey, "."), strings.Split(routingKey, "."))
+	case ExchangeHeaders:
+		return headersMatch(b.Args, headers)
+	default:
+		return b.RoutingKey == routingKey
+	}
+}
+
+// topicMatches matches routing key words against a binding pattern,
+// where "*" is exactly one word and "#" is zero or more words.
+func topicMatches(pattern, words []string) bool {
+	if len(pattern) == 0 {
+		return len(words) == 0
+	}
+	switch pattern[0] {
+	case "#":
+		for i := 0; i <= len(words); i++ {
+			if topicMatches(pattern[1:], words[i:]) {
+				return true
+			}
+		}
+		return false
+	case "*":
+		return len(words) > 0 && topicMatches(pattern[1:], words[1:])
+	default:
+		return len(words) > 0 && pattern[0] == words[0] && topicMatches(pattern[1:], words[1:])
+	}
+}
+
+// headersMatch implements the headers exchange. x-match is all (default), any,
+// all-with-x or any-with-x; without -with-x, "x-" arguments are not compared.
+func headersMatch(args, headers amqp091.Table) bool {
+	mode, _ := args["x-match"].(string)
+	if mode == "" {
+		mode = "all"
+	}
+	withX := strings.HasSuffix(mode, "-with-x")
+	matchAny := strings.HasPrefix(mode, "any")
+
+	for k, want := range args {
+		if k == "x-match" || (!withX && strings.HasPrefix(k, "x-")) {
+			continue
+		}
+		got, ok := headers[k]
+		// A void argument only requires the header to be present.
+		matched := ok && (want == nil || fmt.Sprint(got) == fmt.Sprint(want))
+		if matchAny && matched {
+			return true
+		}
+		if !matchAny && !matched {
+			return false
+		}
+	}
+	return !matchAny
+}
+
+func (m *memoryBroker) newMessage(e envelope) memoryMessage {
+	m.mu.Lock()
+	m.tag++
+	tag := m.tag
+	m.mu.Unlock()
+
+	msg := memoryMessage{
+		exchange:   e.exchange,
+		routingKey: e.routingKey,
+		publishing: e.msg,
+		tag:        tag,
+		enqueued:   time.Now(),
+	}
+	if e.msg.Expiration != "" {
+		if ms, err := strconv.ParseInt(e.msg.Expiration, 10, 64); err == nil {
+			msg.expiration = time.Duration(ms) * time.Millisecond
+		}
+	}
+	return msg
+}
+
+// deadLetter republishes msg to the queue's dead letter exchange, if any.
+func (m *memoryBroker) deadLetter(q *memoryQueue, msg memoryMessage, reason string) {
+	dlx, ok := q.spec.Args["x-dead-letter-exchange"].(string)
+	if !ok {
+		return
+	}
+	routingKey := msg.routingKey
+	if key, ok := q.spec.Args["x-dead-letter-routing-key"].(string); ok {
+		routingKey = key
+	}
+
+	p := msg.publishing
+	headers := amqp091.Table{}
+	for k, v := range p.Headers {
+		headers[k] = v
+	}
+	headers["x-death"] = appendDeath(p.Headers, q.spec.Name, reason, msg)
+	p.Headers = headers
+	p.Expiration = "" // per-message TTL is removed on dead-lettering
+
+	_ = m.Publish(context.Background(), envelope{exchange: dlx, routingKey: routingKey, msg: p})
+}
+
+// appendDeath adds or updates the x-death entry for queue and reason.
+func appendDeath(headers amqp091.Table, queue, reason string, msg memoryMessage) []any {
+	deaths, _ := headers["x-death"].([]any)
+	out := make([]any, 0, len(deaths)+1)
+	count := int64(1)
+	for _, d := range deaths {
+		t, ok := d.(amqp091.Table)
+		if ok && t["queue"] == queue && t["reason"] == reason {
+			if n, ok := t["count"].(int64); ok {
+				count = n + 1
+			}
+			continue
+		}
+		out = append(out, d)
+	}
+	death := amqp091.Table{
+		"queue":        queue,
+		"reason":       reason,
+		"count":        count,
+		"exchange":     msg.exchange,
+		"routing-keys": []any{msg.routingKey},
+		"time":         time.Now(),
+	}
+	return append([]any{death}, out...)
+}
+
+// consume runs c's delivery loop on the queue until ctx is done.
+func (m *memoryBroker) consume(ctx context.Context, c *consumer) error {
+	m.mu.Lock()
+	q, ok := m.queues[c.cfg.Queue]
+	m.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("amqp: consume: NOT_FOUND: no queue %q", c.cfg.Queue)
+	}
+
+	for {
+		msg, ok := q.pop(ctx, m)
+		if !ok {
+			return nil
+		}
+		c.process(ctx, msg.delivery(&memoryAcknowledger{broker: m, queue: q, msg: msg}))
+	}
+}
+
+// memoryMessage is a message stored in a memoryQueue.
+type memoryMessage struct {
+	exchange    string
+	routingKey  string
+	publishing  amqp091.Publishing
+	tag         uint64
+	redelivered bool
+	enqueued    time.Time
+	expiration  time.Duration // per-message TTL, 0 if none
+}
+
+func (msg memoryMessage) delivery(ack amqp091.Acknowledger) amqp091.Delivery {
+	p := msg.publishing
+	return amqp091.Delivery{
+		Acknowledger:    ack,
+		Headers:         p.Headers,
+		ContentType:     p.ContentType,
+		ContentEncoding: p.ContentEncoding,
+		DeliveryMode:    p.DeliveryMode,
+		Priority:        p.Priority,
+		CorrelationId:   p.CorrelationId,
+		ReplyTo:         p.ReplyTo,
+		Expiration:      p.Expiration,
+		MessageId:       p.MessageId,
+		Timestamp:       p.Timestamp,
+		Type:            p.Type,
+		UserId:          p.UserId,
+		AppId:           p.AppId,
+		DeliveryTag:     msg.tag,
+		Redelivered:     msg.redelivered,
+		Exchange:        msg.exchange,
+		RoutingKey:      msg.routingKey,
+		Body:            p.Body,
+	}
+}
+
+func (msg memoryMessage) expired(queueTTL time.Duration, now time.Time) bool {
+	ttl := msg.expiration
+	if queueTTL > 0 && (ttl == 0 || queueTTL < ttl) {
+		ttl = queueTTL
+	}
+	return ttl > 0 && now.Sub(msg.enqueued) >= ttl
+}
+
+type memoryQueue struct {
+	spec QueueSpec
+	ttl  time.Duration // x-message-ttl
+
+	mu       sync.Mutex
+	messages []memoryMessage
+	wake     chan struct{} // closed and replaced on every push
+}
+
+func newMemoryQueue(spec QueueSpec) *memoryQueue {
+	q := &memoryQueue{spec: spec, wake: make(chan struct{})}
+	switch ttl := spec.Args["x-message-ttl"].(type) {
+	case int32:
+		q.ttl = time.Duration(ttl) * time.Millisecond
+	case int64:
+		q.ttl = time.Duration(ttl) * time.Millisecond
+	case int:
+		q.ttl = time.Duration(ttl) * time.Millisecond
+	}
+	return q
+}
+
+// push appends msg, or puts it back at the head when requeued.
+func (q *memoryQueue) push(msg memoryMessage, requeue bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if requeue {
+		q.messages = append([]memoryMessage{msg}, q.messages...)
+	} else {
+		q.messages = append(q.messages, msg)
+	}
+	close(q.wake)
+	q.wake = make(chan struct{})
+}
+
+// pop waits for the next live message. Expired messages are dead-lettered.
+// It returns false when ctx is done first.
+func (q *memoryQueue) pop(ctx context.Context, m *memoryBroker) (memoryMessage, bool) {
+	for {
+		if ctx.Err() != nil {
+			return memoryMessage{}, false
+		}
+
+		q.mu.Lock()
+		if len(q.messages) > 0 {
+			msg := q.messages[0]
+			q.messages = q.messages[1:]
+			q.mu.Unlock()
+
+			if msg.expired(q.ttl, time.Now()) {
+				m.deadLetter(q, msg, "expired")
+				continue
+			}
+			return msg, true
+		}
+		wake := q.wake
+		q.mu.Unlock()
+
+		select {
+		case <-wake:
+		case <-ctx.Done():
+			return memoryMessage{}, false
+		}
+	}
+}
+
+func (q *memoryQueue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.messages)
+}
+
+// memoryAcknowledger settles a delivery of a memoryQueue.
+type memoryAcknowledger struct {
+	broker *memoryBroker
+	queue  *memoryQueue
+	msg    memoryMessage
+}
+
+func (a *memoryAcknowledger) Ack(uint64, bool) error { return nil }
+
+func (a *memoryAcknowledger) Nack(_ uint64, _ bool, requeue bool) error {
+	return a.Reject(0, requeue)
+}
+
+func (a *memoryAcknowledger) Reject(_ uint64, requeue bool) error {
+	if requeue {
+		msg := a.msg
+		msg.redelivered = true
+		a.queue.push(msg, true)
+		return nil
+	}
+	a.broker.deadLetter(a.queue, a.msg, "rejected")
+	return nil
+}
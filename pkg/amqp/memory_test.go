@@ -0,0 +1,273 @@
+//go:build unit
+
+package amqp
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	amqp091 "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopicMatches(t *testing.T) {
+	tests := []struct {
+		pattern, key string
+		want         bool
+	}{
+		{"user.created", "user.created", true},
+		{"user.*", "user.created", true},
+		{"user.*", "user.profile.updated", false},
+		{"user.#", "user.profile.updated", true},
+		{"user.#", "user", true},
+		{"#", "anything.at.all", true},
+		{"*.updated", "profile.updated", true},
+		{"#.updated", "user.profile.updated", true},
+		{"user.#.updated", "user.updated", true},
+		{"user.#.updated", "user.profile.created", false},
+		{"*", "", true},
+		{"user.created", "user.deleted", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.key, func(t *testing.T) {
+			got := topicMatches(strings.Split(tt.pattern, "."), strings.Split(tt.key, "."))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestHeadersMatch(t *testing.T) {
+	headers := amqp091.Table{"type": "user", "version": int32(2), "x-trace": "abc"}
+
+	tests := []struct {
+		name string
+		args amqp091.Table
+		want bool
+	}{
+		{"all matches", amqp091.Table{"type": "user", "version": 2}, true},
+		{"all mismatch", amqp091.Table{"type": "user", "version": 3}, false},
+		{"default is all", amqp091.Table{"x-match": "all", "type": "order"}, false},
+		{"any matches one", amqp091.Table{"x-match": "any", "type": "order", "version": 2}, true},
+		{"any matches none", amqp091.Table{"x-match": "any", "type": "order"}, false},
+		{"void value needs presence", amqp091.Table{"type": nil}, true},
+		{"x- args ignored", amqp091.Table{"type": "user", "x-trace": "other"}, true},
+		{"all-with-x compares x- args", amqp091.Table{"x-match": "all-with-x", "x-trace": "other"}, false},
+		{"any-with-x", amqp091.Table{"x-match": "any-with-x", "x-trace": "abc"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, headersMatch(tt.args, headers))
+		})
+	}
+}
+
+func TestMemoryBroker_Routing(t *testing.T) {
+	m := newMemoryBroker()
+	require.NoError(t, m.declare(Topology{
+		Exchanges: []ExchangeSpec{
+			{Name: "events", Type: ExchangeTopic},
+			{Name: "tagged", Type: ExchangeHeaders},
+			{Name: "audit", Type: ExchangeFanout},
+		},
+		Queues: []QueueSpec{{Name: "users"}, {Name: "vip"}, {Name: "audit.log"}},
+		Bindings: []BindingSpec{
+			{Source: "events", Destination: "users", RoutingKey: "user.#"},
+			{Source: "events", Destination: "audit", DestinationType: DestinationExchange, RoutingKey: "#"},
+			{Source: "audit", Destination: "audit.log"},
+			{Source: "tagged", Destination: "vip", Args: amqp091.Table{"tier": "vip"}},
+		},
+	}))
+
+	publish := func(exchange, key string, headers amqp091.Table) error {
+		return m.Publish(context.Background(), envelope{exchange: exchange, routingKey: key, msg: amqp091.Publishing{Headers: headers}})
+	}
+
+	require.NoError(t, publish("events", "user.created", nil))
+	require.NoError(t, publish("events", "order.created", nil))
+	require.NoError(t, publish("tagged", "", amqp091.Table{"tier": "vip"}))
+	require.NoError(t, publish("tagged", "", amqp091.Table{"tier": "basic"}))
+	require.NoError(t, publish("", "users", nil)) // default exchange
+
+	assert.Equal(t, 2, m.queues["users"].len())
+	assert.Equal(t, 2, m.queues["audit.log"].len(), "exchange-to-exchange binding")
+	assert.Equal(t, 1, m.queues["vip"].len())
+
+	assert.ErrorContains(t, publish("missing", "a", nil), `no exchange "missing"`)
+}
+
+func TestMemoryBroker_MandatoryUnroutable(t *testing.T) {
+	b := NewBroker(nil, PoolConfig{})
+	require.NoError(t, b.Declare(Topology{Exchanges: []ExchangeSpec{{Name: "events", Type: ExchangeTopic}}}))
+
+	err := b.Publish(context.Background(), "events", "nobody.listens", nil, []byte(`{}`), AtLeastOnce, WithMandatory())
+
+	var unroutable *UnroutableError
+	require.ErrorAs(t, err, &unroutable)
+	assert.Equal(t, uint16(amqp091.NoRoute), unroutable.ReplyCode)
+
+	assert.NoError(t, b.Publish(context.Background(), "events", "nobody.listens", nil, []byte(`{}`), AtLeastOnce))
+}
+
+func TestMemoryBroker_Declare_Conflict(t *testing.T) {
+	m := newMemoryBroker()
+	require.NoError(t, m.declare(Topology{Exchanges: []ExchangeSpec{{Name: "events", Type: ExchangeTopic}}}))
+
+	err := m.declare(Topology{Exchanges: []ExchangeSpec{{Name: "events", Type: ExchangeFanout}}})
+	assert.ErrorContains(t, err, "PRECONDITION_FAILED")
+}
+
+type memoryEvent struct {
+	Name string `json:"name" validate:"required"`
+}
+
+// runMemoryBroker declares an events exchange, runs b until the test ends,
+// and waits until the consumer queues exist.
+func runMemoryBroker(t *testing.T, b *Broker) {
+	t.Helper()
+	require.NoError(t, b.Declare(Topology{Exchanges: []ExchangeSpec{{Name: "events", Type: ExchangeTopic}}}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- b.Run(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, <-done)
+	})
+
+	require.Eventually(t, func() bool {
+		b.mem.mu.Lock()
+		defer b.mem.mu.Unlock()
+		for _, c := range b.consumers.consumers {
+			if _, ok := b.mem.queues[c.cfg.Queue]; !ok {
+				return false
+			}
+		}
+		return true
+	}, time.Second, time.Millisecond)
+}
+
+func TestMemoryBroker_ConsumesTypedEvents(t *testing.T) {
+	b := NewBroker(nil, PoolConfig{})
+	received := make(chan string, 1)
+	var seen []string
+	b.Use(func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, msg amqp091.Delivery) error {
+			seen = append(seen, msg.RoutingKey)
+			return next(ctx, msg)
+		}
+	})
+	AddConsumer(b, ConsumerConfig{Queue: "users", Exchange: "events", RoutingKey: "user.*"},
+		func(_ context.Context, e memoryEvent, meta DeliveryMeta) error {
+			received <- e.Name + "@" + meta.RoutingKey
+			return nil
+		})
+	runMemoryBroker(t, b)
+
+	require.NoError(t, b.PublishJSON(context.Background(), "events", "user.created", nil, memoryEvent{Name: "alice"}, AtLeastOnce))
+
+	select {
+	case got := <-received:
+		assert.Equal(t, "alice@user.created", got)
+	case <-time.After(time.Second):
+		t.Fatal("handler was not called")
+	}
+	assert.Equal(t, []string{"user.created"}, seen, "group middlewares run in memory mode")
+}
+
+func TestMemoryBroker_RetryRequeues(t *testing.T) {
+	b := NewBroker(nil, PoolConfig{})
+	retry := true
+	var attempts atomic.Int32
+	redelivered := make(chan bool, 1)
+	AddRawConsumer(b, ConsumerConfig{Queue: "jobs", Exchange: "events", RoutingKey: "job", RetryOnError: &retry},
+		func(_ context.Context, _ []byte, meta DeliveryMeta) error {
+			if attempts.Add(1) == 1 {
+				return errors.New("transient")
+			}
+			return nil
+		}, func(next HandlerFunc) HandlerFunc {
+			return func(ctx context.Context, msg amqp091.Delivery) error {
+				if msg.Redelivered {
+					redelivered <- true
+				}
+				return next(ctx, msg)
+			}
+		})
+	runMemoryBroker(t, b)
+
+	require.NoError(t, b.Publish(context.Background(), "events", "job", nil, []byte(`{}`), AtLeastOnce))
+
+	select {
+	case <-redelivered:
+	case <-time.After(time.Second):
+		t.Fatal("failed message was not redelivered")
+	}
+	require.Eventually(t, func() bool { return attempts.Load() == 2 }, time.Second, time.Millisecond)
+}
+
+func TestMemoryBroker_DeadLetters(t *testing.T) {
+	b := NewBroker(nil, PoolConfig{})
+	AddRawConsumer(b, ConsumerConfig{Queue: "jobs", Exchange: "events", RoutingKey: "job", DeadLetterExchange: "dlx", DeadLetterRoutingKey: "jobs"},
+		func(context.Context, []byte, DeliveryMeta) error {
+			return errors.New("poison")
+		})
+	dead := make(chan DeliveryMeta, 1)
+	AddRawConsumer(b, ConsumerConfig{Queue: "jobs.dlq"}, func(_ context.Context, _ []byte, meta DeliveryMeta) error {
+		dead <- meta
+		return nil
+	})
+	runMemoryBroker(t, b)
+
+	require.NoError(t, b.Publish(context.Background(), "events", "job", nil, []byte(`{}`), AtLeastOnce))
+
+	select {
+	case meta := <-dead:
+		deaths, ok := meta.Headers["x-death"].([]any)
+		require.True(t, ok)
+		require.Len(t, deaths, 1)
+		death := deaths[0].(amqp091.Table)
+		assert.Equal(t, "jobs", death["queue"])
+		assert.Equal(t, "rejected", death["reason"])
+		assert.Equal(t, int64(1), death["count"])
+	case <-time.After(time.Second):
+		t.Fatal("rejected message was not dead-lettered")
+	}
+}
+
+func TestMemoryBroker_ExpiredMessageIsDeadLettered(t *testing.T) {
+	m := newMemoryBroker()
+	require.NoError(t, m.declare(MergeTopologies(
+		Topology{Exchanges: []ExchangeSpec{{Name: "events", Type: ExchangeTopic}}},
+		ConsumerConfig{Queue: "jobs", Exchange: "events", RoutingKey: "job", DeadLetterExchange: "dlx", DeadLetterRoutingKey: "jobs", MessageTTL: time.Millisecond}.Topology(),
+	)))
+
+	require.NoError(t, m.Publish(context.Background(), envelope{exchange: "events", routingKey: "job"}))
+	time.Sleep(5 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, ok := m.queues["jobs"].pop(ctx, m)
+
+	assert.False(t, ok, "expired message is not delivered")
+	assert.Equal(t, 1, m.queues["jobs.dlq"].len())
+}
+
+func TestMemoryBroker_Call(t *testing.T) {
+	b := NewBroker(nil, PoolConfig{})
+	require.NoError(t, b.Declare(Topology{Exchanges: []ExchangeSpec{{Name: "rpc", Type: ExchangeDirect}}}))
+	AddRPCHandler(b, ConsumerConfig{Queue: "rpc.greet", Exchange: "rpc", RoutingKey: "greet"}, greet)
+	runMemoryBroker(t, b)
+
+	resp, err := CallAs[rpcResponse](context.Background(), b, "rpc", "greet", rpcRequest{Name: "bob"})
+	require.NoError(t, err)
+	assert.Equal(t, "hello bob", resp.Greeting)
+
+	_, err = b.Call(context.Background(), "rpc", "unknown", rpcRequest{Name: "bob"})
+	var unroutable *UnroutableError
+	assert.ErrorAs(t, err, &unroutable)
+}
@@ -0,0 +1,157 @@
+package amqp
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	amqp091 "github.com/rabbitmq/amqp091-go"
+)
+
+// ErrCircuitOpen is returned instead of calling the handler while the circuit is open.
+// Messages rejected with it are always requeued, regardless of RetryOnError.
+var ErrCircuitOpen = errors.New("amqp: circuit open")
+
+const defaultBreakerCooldown = 30 * time.Second
+
+// CircuitBreakerConfig configures [WithCircuitBreaker].
+type CircuitBreakerConfig struct {
+	Failures int           `yaml:"failures"` // consecutive handler errors that open the circuit; 0 disables
+	Cooldown time.Duration `yaml:"cooldown"` // how long consumption stays paused, default: 30s
+}
+
+func (c CircuitBreakerConfig) cooldown() time.Duration {
+	if c.Cooldown <= 0 {
+		return defaultBreakerCooldown
+	}
+	return c.Cooldown
+}
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// WithCircuitBreaker returns a middleware that pauses the consumer after
+// cfg.Failures consecutive handler errors, for example when a downstream is down.
+// After the cooldown the consumer resumes with one trial message: success closes
+// the circuit, failure pauses it again. Every consumer gets its own breaker.
+//
+// The breaker resumes the consumer it paused, even if it was also paused
+// through [Broker.PauseConsumer] in the meantime.
+func WithCircuitBreaker(cfg CircuitBreakerConfig) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		if cfg.Failures <= 0 {
+			return next
+		}
+		b := &circuitBreaker{cfg: cfg}
+		return func(ctx context.Context, msg amqp091.Delivery) error {
+			if !b.allow(ctx) {
+				return ErrCircuitOpen
+			}
+			err := next(ctx, msg)
+			b.record(ctx, err)
+			return err
+		}
+	}
+}
+
+type circuitBreaker struct {
+	cfg CircuitBreakerConfig
+
+	mu        sync.Mutex
+	state     breakerState
+	failures  int
+	trial     bool          // a half-open trial message is running
+	trialDone chan struct{} // closed when the trial finishes
+}
+
+// allow reports whether a message may be handled. While a half-open trial
+// runs, other messages wait for its outcome instead of being requeued in a loop.
+func (b *circuitBreaker) allow(ctx context.Context) bool {
+	for {
+		b.mu.Lock()
+		switch b.state {
+		case breakerClosed:
+			b.mu.Unlock()
+			return true
+		case breakerOpen:
+			b.mu.Unlock()
+			return false
+		}
+
+		if !b.trial {
+			b.trial = true
+			b.trialDone = make(chan struct{})
+			b.mu.Unlock()
+			return true
+		}
+		wait := b.trialDone
+		b.mu.Unlock()
+
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+func (b *circuitBreaker) record(ctx context.Context, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == breakerHalfOpen && b.trial {
+		b.trial = false
+		close(b.trialDone)
+	}
+
+	if err == nil {
+		if b.state != breakerClosed {
+			slog.Info("circuit breaker closed")
+		}
+		b.state = breakerClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == breakerHalfOpen || (b.state == breakerClosed && b.failures >= b.cfg.Failures) {
+		b.trip(ctx, err)
+	}
+}
+
+// trip opens the circuit, pauses the consumer from ctx and schedules the half-open state.
+// The caller holds b.mu.
+func (b *circuitBreaker) trip(ctx context.Context, err error) {
+	b.state = breakerOpen
+	c := consumerFromCtx(ctx)
+
+	queue := ""
+	if c != nil {
+		queue = c.cfg.Queue
+		c.pause()
+	}
+	slog.Warn("circuit breaker opened, consumer paused",
+		slog.String("queue", queue),
+		slog.Int("failures", b.failures),
+		slog.Duration("cooldown", b.cfg.cooldown()),
+		slog.Any("error", err),
+	)
+
+	time.AfterFunc(b.cfg.cooldown(), func() {
+		b.mu.Lock()
+		if b.state == breakerOpen {
+			b.state = breakerHalfOpen
+		}
+		b.mu.Unlock()
+		if c != nil {
+			c.resume()
+		}
+	})
+}
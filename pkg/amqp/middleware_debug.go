@@ -0,0 +1,42 @@
+package amqp
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+
+	amqp091 "github.com/rabbitmq/amqp091-go"
+)
+
+const defaultDebugBodyMaxBytes = 1024
+
+// WithDebugBody returns a middleware that logs the body of a sampled fraction
+// of messages at debug level. rate is between 0 and 1; bodies are cut to
+// maxBytes (default: 1024). A non-positive rate disables the middleware.
+func WithDebugBody(rate float64, maxBytes int) Middleware {
+	if maxBytes <= 0 {
+		maxBytes = defaultDebugBodyMaxBytes
+	}
+	return func(next HandlerFunc) HandlerFunc {
+		if rate <= 0 {
+			return next
+		}
+		return func(ctx context.Context, msg amqp091.Delivery) error {
+			if rate >= 1 || rand.Float64() < rate {
+				body := msg.Body
+				truncated := len(body) > maxBytes
+				if truncated {
+					body = body[:maxBytes]
+				}
+				slog.DebugContext(ctx, "message body",
+					slog.String("routing_key", msg.RoutingKey),
+					slog.String("message_id", msg.MessageId),
+					slog.String("content_type", msg.ContentType),
+					slog.String("body", string(body)),
+					slog.Bool("truncated", truncated),
+				)
+			}
+			return next(ctx, msg)
+		}
+	}
+}
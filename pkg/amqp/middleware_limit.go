@@ -0,0 +1,31 @@
+package amqp
+
+import (
+	"context"
+
+	amqp091 "github.com/rabbitmq/amqp091-go"
+)
+
+// WithConcurrencyLimit returns a middleware that allows at most n handlers to
+// run at once. The limit is shared by every consumer the returned middleware
+// is applied to, so it caps the total load on a common downstream.
+// A non-positive n disables the middleware.
+func WithConcurrencyLimit(n int) Middleware {
+	if n <= 0 {
+		return func(next HandlerFunc) HandlerFunc { return next }
+	}
+
+	sem := make(chan struct{}, n)
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, msg amqp091.Delivery) error {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			defer func() { <-sem }()
+
+			return next(ctx, msg)
+		}
+	}
+}
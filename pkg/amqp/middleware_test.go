@@ -4,7 +4,11 @@ package amqp
 
 import (
 	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	amqp091 "github.com/rabbitmq/amqp091-go"
 	"github.com/stretchr/testify/assert"
@@ -78,3 +82,138 @@ func TestChain_SingleMiddleware(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, []string{"mw", "handler"}, trace)
 }
+
+func TestWithTimeout_CancelsHandler(t *testing.T) {
+	handler := func(ctx context.Context, _ amqp091.Delivery) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	err := Chain(handler, WithTimeout(10*time.Millisecond))(context.Background(), amqp091.Delivery{})
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), "handler timed out after 10ms")
+}
+
+func TestWithTimeout_Disabled(t *testing.T) {
+	handler := func(ctx context.Context, _ amqp091.Delivery) error {
+		_, ok := ctx.Deadline()
+		assert.False(t, ok)
+		return nil
+	}
+
+	require.NoError(t, Chain(handler, WithTimeout(0))(context.Background(), amqp091.Delivery{}))
+}
+
+func TestWithConcurrencyLimit_SharedAcrossConsumers(t *testing.T) {
+	var running, peak atomic.Int32
+	handler := func(_ context.Context, _ amqp091.Delivery) error {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		running.Add(-1)
+		return nil
+	}
+
+	limit := WithConcurrencyLimit(2)
+	a, b := Chain(handler, limit), Chain(handler, limit)
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		h := a
+		if i%2 == 1 {
+			h = b
+		}
+		wg.Go(func() { _ = h(context.Background(), amqp091.Delivery{}) })
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(2), peak.Load())
+}
+
+func TestWithConcurrencyLimit_ContextDone(t *testing.T) {
+	block := make(chan struct{})
+	handler := func(_ context.Context, _ amqp091.Delivery) error {
+		<-block
+		return nil
+	}
+	h := Chain(handler, WithConcurrencyLimit(1))
+
+	go func() { _ = h(context.Background(), amqp091.Delivery{}) }()
+	time.Sleep(10 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, h(ctx, amqp091.Delivery{}), context.DeadlineExceeded)
+	close(block)
+}
+
+func TestWithDebugBody_PassesThrough(t *testing.T) {
+	called := false
+	handler := func(_ context.Context, msg amqp091.Delivery) error {
+		called = true
+		assert.Equal(t, "payload", string(msg.Body))
+		return nil
+	}
+
+	err := Chain(handler, WithDebugBody(1, 3))(context.Background(), amqp091.Delivery{Body: []byte("payload")})
+	require.NoError(t, err)
+	assert.True(t, called)
+}
+
+func TestWithCircuitBreaker_PausesConsumerAndRecovers(t *testing.T) {
+	var fail atomic.Bool
+	fail.Store(true)
+	calls := 0
+
+	b := NewBroker(nil, PoolConfig{})
+	AddRawConsumer(b, ConsumerConfig{Queue: "q"}, func(_ context.Context, _ []byte, _ DeliveryMeta) error {
+		calls++
+		if fail.Load() {
+			return errors.New("downstream down")
+		}
+		return nil
+	}, WithCircuitBreaker(CircuitBreakerConfig{Failures: 2, Cooldown: 20 * time.Millisecond}))
+	c := b.consumers.consumers[0]
+	ack := &recordingAcknowledger{}
+	deliver := func() { c.process(context.Background(), amqp091.Delivery{Acknowledger: ack}) }
+
+	deliver()
+	assert.False(t, c.isPaused())
+	deliver()
+	assert.True(t, c.isPaused(), "second consecutive failure opens the circuit")
+
+	deliver() // prefetched before the pause took effect
+	assert.Equal(t, 2, calls, "open circuit does not call the handler")
+	assert.True(t, ack.requeue, "rejected message is requeued")
+
+	require.Eventually(t, func() bool { return !c.isPaused() }, time.Second, time.Millisecond)
+
+	fail.Store(false)
+	deliver() // half-open trial succeeds
+	assert.Equal(t, 3, calls)
+	deliver()
+	assert.Equal(t, 4, calls, "circuit is closed again")
+	assert.False(t, c.isPaused())
+}
+
+func TestWithCircuitBreaker_FailedTrialReopens(t *testing.T) {
+	b := NewBroker(nil, PoolConfig{})
+	AddRawConsumer(b, ConsumerConfig{Queue: "q"}, func(_ context.Context, _ []byte, _ DeliveryMeta) error {
+		return errors.New("downstream down")
+	}, WithCircuitBreaker(CircuitBreakerConfig{Failures: 1, Cooldown: 10 * time.Millisecond}))
+	c := b.consumers.consumers[0]
+	deliver := func() { c.process(context.Background(), amqp091.Delivery{Acknowledger: &recordingAcknowledger{}}) }
+
+	deliver()
+	require.True(t, c.isPaused())
+	require.Eventually(t, func() bool { return !c.isPaused() }, time.Second, time.Millisecond)
+
+	deliver()
+	assert.True(t, c.isPaused(), "failed trial pauses the consumer again")
+}
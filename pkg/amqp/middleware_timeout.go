@@ -0,0 +1,31 @@
+package amqp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	amqp091 "github.com/rabbitmq/amqp091-go"
+)
+
+// WithTimeout returns a middleware that cancels the handler context after d.
+// The handler must respect ctx; an error returned after the deadline is wrapped
+// with the timeout. A non-positive d disables the middleware.
+func WithTimeout(d time.Duration) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		if d <= 0 {
+			return next
+		}
+		return func(ctx context.Context, msg amqp091.Delivery) error {
+			ctx, cancel := context.WithTimeout(ctx, d)
+			defer cancel()
+
+			err := next(ctx, msg)
+			if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return fmt.Errorf("handler timed out after %s: %w", d, err)
+			}
+			return err
+		}
+	}
+}
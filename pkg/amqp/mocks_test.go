@@ -0,0 +1,36 @@
+//go:build unit
+
+package amqp
+
+import (
+	"context"
+
+	amqp091 "github.com/rabbitmq/amqp091-go"
+)
+
+type recordedPublish struct {
+	exchange   string
+	routingKey string
+	mandatory  bool
+	msg        amqp091.Publishing
+}
+
+type fakePublisher struct {
+	published []recordedPublish
+	err       error
+}
+
+func (p *fakePublisher) Publish(_ context.Context, e envelope) error {
+	p.published = append(p.published, recordedPublish{exchange: e.exchange, routingKey: e.routingKey, mandatory: e.mandatory, msg: e.msg})
+	return p.err
+}
+
+func (p *fakePublisher) PublishBatch(ctx context.Context, batch []envelope) []error {
+	errs := make([]error, len(batch))
+	for i, e := range batch {
+		errs[i] = p.Publish(ctx, e)
+	}
+	return errs
+}
+
+func (p *fakePublisher) Close() error { return nil }
@@ -72,14 +72,25 @@ func (p *publisherPool) put(pub publisher) {
 	p.slots <- pub
 }
 
-func (p *publisherPool) Publish(ctx context.Context, exchange, routingKey string, headers amqp091.Table, body []byte) error {
+func (p *publisherPool) Publish(ctx context.Context, e envelope) error {
 	pub, err := p.get(ctx)
 	if err != nil {
 		return err
 	}
 	defer p.put(pub)
 
-	return pub.Publish(ctx, exchange, routingKey, headers, body)
+	return pub.Publish(ctx, e)
+}
+
+// PublishBatch sends the whole batch on a single pooled channel.
+func (p *publisherPool) PublishBatch(ctx context.Context, batch []envelope) []error {
+	pub, err := p.get(ctx)
+	if err != nil {
+		return fillErrors(len(batch), err)
+	}
+	defer p.put(pub)
+
+	return pub.PublishBatch(ctx, batch)
 }
 
 func (p *publisherPool) Close() error {
@@ -0,0 +1,61 @@
+package amqp
+
+import (
+	"strconv"
+	"time"
+
+	amqp091 "github.com/rabbitmq/amqp091-go"
+)
+
+// PublishOption customizes an outgoing message.
+type PublishOption func(*envelope)
+
+// WithContentType overrides the message content type (default: application/json).
+func WithContentType(contentType string) PublishOption {
+	return func(e *envelope) {
+		e.msg.ContentType = contentType
+	}
+}
+
+// WithPriority sets the message priority. It only takes effect on queues
+// declared with MaxPriority; values above the queue maximum are capped by the broker.
+func WithPriority(priority uint8) PublishOption {
+	return func(e *envelope) {
+		e.msg.Priority = priority
+	}
+}
+
+// WithExpiration sets a per-message TTL. An expired message is dropped
+// (or dead-lettered) instead of being delivered. Sub-millisecond values round down to 0,
+// which expires the message unless it can be delivered immediately.
+func WithExpiration(ttl time.Duration) PublishOption {
+	return func(e *envelope) {
+		e.msg.Expiration = strconv.FormatInt(max(ttl.Milliseconds(), 0), 10)
+	}
+}
+
+// WithMandatory publishes with the mandatory flag: a message that matches no
+// binding is returned by the broker instead of being silently dropped.
+// With AtLeastOnce the publish fails with [*UnroutableError]; with AtMostOnce
+// there is no confirm to wait for, so returned messages are only logged.
+func WithMandatory() PublishOption {
+	return func(e *envelope) {
+		e.mandatory = true
+	}
+}
+
+func newEnvelope(exchange, routingKey string, headers amqp091.Table, body []byte, opts []PublishOption) envelope {
+	e := envelope{
+		exchange:   exchange,
+		routingKey: routingKey,
+		msg: amqp091.Publishing{
+			Headers:     headers,
+			ContentType: ContentTypeJSON,
+			Body:        body,
+		},
+	}
+	for _, o := range opts {
+		o(&e)
+	}
+	return e
+}
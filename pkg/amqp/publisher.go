@@ -3,20 +3,32 @@ package amqp
 import (
 	"context"
 	"fmt"
+	"log/slog"
 
+	"github.com/google/uuid"
 	amqp091 "github.com/rabbitmq/amqp091-go"
 )
 
 // publisher is the internal interface for message publishing.
 type publisher interface {
-	Publish(ctx context.Context, exchange, routingKey string, headers amqp091.Table, body []byte) error
+	Publish(ctx context.Context, e envelope) error
+	// PublishBatch publishes every envelope and returns one error slot per envelope (nil on success).
+	PublishBatch(ctx context.Context, batch []envelope) []error
 	Close() error
 }
 
+// envelope is a fully built message together with its destination.
+type envelope struct {
+	exchange   string
+	routingKey string
+	mandatory  bool
+	msg        amqp091.Publishing
+}
+
 // --- fire-and-forget (AtMostOnce) ----------------------------------------
 
 // fireAndForgetPublisher publishes messages using a single long-lived AMQP
-// channel with no delivery confirmation.
+// channel with no delivery confirmation. Returned mandatory messages are logged.
 type fireAndForgetPublisher struct {
 	ch *amqp091.Channel
 }
@@ -26,9 +38,22 @@ func newSingleFireAndForget(conn *amqp091.Connection) *fireAndForgetPublisher {
 	if err != nil {
 		panic(fmt.Sprintf("amqp: open publisher channel: %v", err))
 	}
+
+	go logReturns(ch.NotifyReturn(make(chan amqp091.Return, 1)))
+
 	return &fireAndForgetPublisher{ch: ch}
 }
 
+func logReturns(returns <-chan amqp091.Return) {
+	for r := range returns {
+		slog.Warn("amqp: message returned as unroutable",
+			slog.String("exchange", r.Exchange),
+			slog.String("routing_key", r.RoutingKey),
+			slog.String("reply_text", r.ReplyText),
+		)
+	}
+}
+
 func (p *fireAndForgetPublisher) Close() error {
 	if p.ch == nil {
 		return nil
@@ -36,20 +61,32 @@ func (p *fireAndForgetPublisher) Close() error {
 	return p.ch.Close()
 }
 
-func (p *fireAndForgetPublisher) Publish(ctx context.Context, exchange, routingKey string, headers amqp091.Table, body []byte) error {
-	return p.ch.PublishWithContext(ctx, exchange, routingKey, false, false, amqp091.Publishing{
-		Headers:     headers,
-		ContentType: "application/json",
-		Body:        body,
-	})
+func (p *fireAndForgetPublisher) Publish(ctx context.Context, e envelope) error {
+	return p.ch.PublishWithContext(ctx, e.exchange, e.routingKey, e.mandatory, false, e.msg)
+}
+
+func (p *fireAndForgetPublisher) PublishBatch(ctx context.Context, batch []envelope) []error {
+	errs := make([]error, len(batch))
+	for i, e := range batch {
+		errs[i] = p.Publish(ctx, e)
+	}
+	return errs
 }
 
 // --- confirmed (AtLeastOnce) ---------------------------------------------
 
 // confirmedPublisher opens a channel in confirm mode.
 // Every publish waits for an ack from the broker and sets DeliveryMode to Persistent.
+//
+// Mandatory messages are tagged with a MessageId (generated if empty). The broker
+// sends basic.return before the ack of the same message, so once a confirm
+// arrives any return for that message has already been collected.
+// A publisher is used by one goroutine at a time (see publisherPool), so the
+// returned map needs no locking.
 type confirmedPublisher struct {
-	ch *amqp091.Channel
+	ch       *amqp091.Channel
+	returns  chan amqp091.Return
+	returned map[string]amqp091.Return
 }
 
 func newSingleConfirmed(conn *amqp091.Connection) *confirmedPublisher {
@@ -63,7 +100,11 @@ func newSingleConfirmed(conn *amqp091.Connection) *confirmedPublisher {
 		panic(fmt.Sprintf("amqp: enable confirm mode: %v", err))
 	}
 
-	return &confirmedPublisher{ch: ch}
+	return &confirmedPublisher{
+		ch:       ch,
+		returns:  ch.NotifyReturn(make(chan amqp091.Return, 64)),
+		returned: make(map[string]amqp091.Return),
+	}
 }
 
 func (p *confirmedPublisher) Close() error {
@@ -73,21 +114,89 @@ func (p *confirmedPublisher) Close() error {
 	return p.ch.Close()
 }
 
-func (p *confirmedPublisher) Publish(ctx context.Context, exchange, routingKey string, headers amqp091.Table, body []byte) error {
-	conf, err := p.ch.PublishWithDeferredConfirmWithContext(ctx, exchange, routingKey, false, false, amqp091.Publishing{
-		Headers:      headers,
-		ContentType:  "application/json",
-		DeliveryMode: amqp091.Persistent,
-		Body:         body,
-	})
-	if err != nil {
-		return fmt.Errorf("amqp: confirmed publish: %w", err)
+func (p *confirmedPublisher) Publish(ctx context.Context, e envelope) error {
+	return p.PublishBatch(ctx, []envelope{e})[0]
+}
+
+// PublishBatch pipelines the batch: every message is sent before waiting,
+// then all confirms are collected together, so the batch costs one round trip
+// instead of one per message.
+func (p *confirmedPublisher) PublishBatch(ctx context.Context, batch []envelope) []error {
+	clear(p.returned)
+
+	errs := make([]error, len(batch))
+	confs := make([]*amqp091.DeferredConfirmation, len(batch))
+	ids := make([]string, len(batch))
+
+	for i, e := range batch {
+		e.msg.DeliveryMode = amqp091.Persistent
+		if e.mandatory {
+			if e.msg.MessageId == "" {
+				e.msg.MessageId = uuid.NewString()
+			}
+			ids[i] = e.msg.MessageId
+		}
+
+		conf, err := p.ch.PublishWithDeferredConfirmWithContext(ctx, e.exchange, e.routingKey, e.mandatory, false, e.msg)
+		if err != nil {
+			errs[i] = fmt.Errorf("amqp: confirmed publish: %w", err)
+			continue
+		}
+		confs[i] = conf
+	}
+
+	for i, conf := range confs {
+		if conf == nil {
+			continue
+		}
+		acked, err := p.await(ctx, conf)
+		switch {
+		case err != nil:
+			errs[i] = fmt.Errorf("amqp: wait confirm: %w", err)
+		case !acked:
+			errs[i] = fmt.Errorf("amqp: publish nacked by broker")
+		case ids[i] != "":
+			if r, ok := p.returned[ids[i]]; ok {
+				errs[i] = newUnroutableError(r)
+			}
+		}
 	}
+	return errs
+}
+
+// await waits for conf while collecting returned messages, so the
+// connection's reader never blocks on a full returns channel.
+func (p *confirmedPublisher) await(ctx context.Context, conf *amqp091.DeferredConfirmation) (bool, error) {
+	for {
+		select {
+		case <-conf.Done():
+			p.drainReturns()
+			return conf.Acked(), nil
+		case r, ok := <-p.returns:
+			if !ok {
+				p.returns = nil // channel closed; conf.Done fires as well
+				continue
+			}
+			p.returned[r.MessageId] = r
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+	}
+}
 
-	if !conf.Wait() {
-		return fmt.Errorf("amqp: publish nacked by broker")
+func (p *confirmedPublisher) drainReturns() {
+	for {
+		select {
+		case r, ok := <-p.returns:
+			if !ok {
+				p.returns = nil
+				return
+			}
+			p.returned[r.MessageId] = r
+		default:
+			return
+		}
 	}
-	return nil
 }
 
 // --- factory -------------------------------------------------------------
@@ -13,9 +13,10 @@ type publisherManager struct {
 	atMostOnce  publisher
 }
 
-func newPublisherManager(conn *amqp091.Connection, poolCfg PoolConfig) *publisherManager {
+// Without a connection both guarantees publish to the in-memory broker mem.
+func newPublisherManager(conn *amqp091.Connection, mem *memoryBroker, poolCfg PoolConfig) *publisherManager {
 	if conn == nil {
-		return &publisherManager{}
+		return &publisherManager{atLeastOnce: mem, atMostOnce: mem}
 	}
 
 	return &publisherManager{
@@ -0,0 +1,139 @@
+package amqp
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	amqp091 "github.com/rabbitmq/amqp091-go"
+)
+
+// QueueType selects the RabbitMQ queue implementation (x-queue-type).
+type QueueType string
+
+const (
+	QueueClassic QueueType = "classic" // default
+	QueueQuorum  QueueType = "quorum"  // replicated, for HA
+	QueueStream  QueueType = "stream"  // append-only log
+)
+
+// Overflow is the behaviour when a queue reaches MaxLength or MaxLengthBytes (x-overflow).
+type Overflow string
+
+const (
+	OverflowDropHead         Overflow = "drop-head" // default: discard the oldest messages
+	OverflowRejectPublish    Overflow = "reject-publish"
+	OverflowRejectPublishDLX Overflow = "reject-publish-dlx"
+)
+
+// queueArgs builds the x-arguments for the consumer queue.
+func (c ConsumerConfig) queueArgs() amqp091.Table {
+	args := amqp091.Table{}
+	if c.DeadLetterExchange != "" {
+		args["x-dead-letter-exchange"] = c.DeadLetterExchange
+		if c.DeadLetterRoutingKey != "" {
+			args["x-dead-letter-routing-key"] = c.DeadLetterRoutingKey
+		}
+	}
+	if c.QueueType != "" && c.QueueType != QueueClassic {
+		args["x-queue-type"] = string(c.QueueType)
+	}
+	if c.MaxPriority > 0 {
+		args["x-max-priority"] = int32(c.MaxPriority)
+	}
+	if c.MessageTTL > 0 {
+		args["x-message-ttl"] = c.MessageTTL.Milliseconds()
+	}
+	if c.MaxLength > 0 {
+		args["x-max-length"] = c.MaxLength
+	}
+	if c.MaxLengthBytes > 0 {
+		args["x-max-length-bytes"] = c.MaxLengthBytes
+	}
+	if c.Overflow != "" {
+		args["x-overflow"] = string(c.Overflow)
+	}
+	if c.SingleActiveConsumer {
+		args["x-single-active-consumer"] = true
+	}
+	if c.Lazy {
+		args["x-queue-mode"] = "lazy"
+	}
+	if len(args) == 0 {
+		return nil
+	}
+	return args
+}
+
+// Validate reports configuration errors, including queue features that the
+// selected QueueType does not support. It runs for every consumer on [Broker.Run].
+func (c ConsumerConfig) Validate() error {
+	var errs []error
+	fail := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if c.Queue == "" {
+		fail("queue is required")
+	}
+	if c.MessageTTL < 0 || (c.MessageTTL > 0 && c.MessageTTL < time.Millisecond) {
+		fail("message ttl must be at least 1ms")
+	}
+	if c.MaxLength < 0 || c.MaxLengthBytes < 0 {
+		fail("max length must not be negative")
+	}
+	if c.MaxPriority < 0 || c.MaxPriority > 255 {
+		fail("max priority must be between 1 and 255")
+	}
+	if c.SingleActiveConsumer && c.concurrency() > 1 {
+		fail("single active consumer with concurrency %d: only one goroutine would receive messages", c.concurrency())
+	}
+
+	switch c.Overflow {
+	case "", OverflowDropHead, OverflowRejectPublish:
+	case OverflowRejectPublishDLX:
+		if c.DeadLetterExchange == "" {
+			fail("overflow %q requires a dead letter exchange", c.Overflow)
+		}
+	default:
+		fail("unknown overflow %q", c.Overflow)
+	}
+
+	switch c.QueueType {
+	case "", QueueClassic:
+	case QueueQuorum, QueueStream:
+		if !c.durable() || c.AutoDelete || c.Exclusive {
+			fail("%s queues must be durable, non-exclusive and not auto-delete", c.QueueType)
+		}
+		if c.MaxPriority > 0 {
+			fail("%s queues do not support max priority", c.QueueType)
+		}
+		if c.Lazy {
+			fail("lazy mode applies to classic queues only")
+		}
+		if c.QueueType == QueueQuorum && c.Overflow == OverflowRejectPublishDLX {
+			fail("quorum queues do not support overflow %q", c.Overflow)
+		}
+		if c.QueueType == QueueStream {
+			if c.DeadLetterExchange != "" {
+				fail("stream queues do not support dead lettering")
+			}
+			if c.Overflow != "" || c.MaxLength > 0 {
+				fail("stream queues do not support max length or overflow, use retention (max length bytes)")
+			}
+			if c.MessageTTL > 0 {
+				fail("stream queues do not support message ttl")
+			}
+			if c.retryOnError() {
+				fail("stream queues do not support requeue on error")
+			}
+		}
+	default:
+		fail("unknown queue type %q", c.QueueType)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("consumer %q: %w", c.Queue, err)
+	}
+	return nil
+}
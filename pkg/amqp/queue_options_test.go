@@ -0,0 +1,107 @@
+//go:build unit
+
+package amqp
+
+import (
+	"testing"
+	"time"
+
+	amqp091 "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsumerConfig_QueueArgs_Empty(t *testing.T) {
+	assert.Nil(t, ConsumerConfig{Queue: "q"}.queueArgs())
+}
+
+func TestConsumerConfig_QueueArgs_All(t *testing.T) {
+	cfg := ConsumerConfig{
+		Queue:                "q",
+		QueueType:            QueueQuorum,
+		MessageTTL:           time.Minute,
+		MaxLength:            1000,
+		MaxLengthBytes:       1 << 20,
+		Overflow:             OverflowRejectPublish,
+		SingleActiveConsumer: true,
+	}
+
+	assert.Equal(t, amqp091.Table{
+		"x-queue-type":             "quorum",
+		"x-message-ttl":            int64(60000),
+		"x-max-length":             int64(1000),
+		"x-max-length-bytes":       int64(1 << 20),
+		"x-overflow":               "reject-publish",
+		"x-single-active-consumer": true,
+	}, cfg.queueArgs())
+}
+
+func TestConsumerConfig_QueueArgs_ClassicPriorityLazy(t *testing.T) {
+	cfg := ConsumerConfig{Queue: "q", QueueType: QueueClassic, MaxPriority: 10, Lazy: true}
+
+	assert.Equal(t, amqp091.Table{
+		"x-max-priority": int32(10),
+		"x-queue-mode":   "lazy",
+	}, cfg.queueArgs())
+}
+
+func TestConsumerConfig_Topology_UsesQueueArgs(t *testing.T) {
+	topo := ConsumerConfig{Queue: "q", QueueType: QueueQuorum}.Topology()
+
+	require.Len(t, topo.Queues, 1)
+	assert.Equal(t, amqp091.Table{"x-queue-type": "quorum"}, topo.Queues[0].Args)
+}
+
+func TestConsumerConfig_Validate_Valid(t *testing.T) {
+	cfgs := []ConsumerConfig{
+		{Queue: "q"},
+		{Queue: "q", QueueType: QueueQuorum, MessageTTL: time.Second, MaxLength: 10, Overflow: OverflowRejectPublish},
+		{Queue: "q", QueueType: QueueStream, MaxLengthBytes: 1 << 30},
+		{Queue: "q", MaxPriority: 10, Lazy: true, DeadLetterExchange: "dlx", Overflow: OverflowRejectPublishDLX},
+	}
+	for _, cfg := range cfgs {
+		assert.NoError(t, cfg.Validate())
+	}
+}
+
+func TestConsumerConfig_Validate_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  ConsumerConfig
+		want string
+	}{
+		{"missing queue", ConsumerConfig{}, "queue is required"},
+		{"unknown type", ConsumerConfig{Queue: "q", QueueType: "lazy"}, `unknown queue type "lazy"`},
+		{"quorum not durable", ConsumerConfig{Queue: "q", QueueType: QueueQuorum, Durable: boolPtr(false)}, "must be durable"},
+		{"quorum exclusive", ConsumerConfig{Queue: "q", QueueType: QueueQuorum, Exclusive: true}, "must be durable"},
+		{"quorum priority", ConsumerConfig{Queue: "q", QueueType: QueueQuorum, MaxPriority: 5}, "do not support max priority"},
+		{"quorum reject-publish-dlx", ConsumerConfig{Queue: "q", QueueType: QueueQuorum, DeadLetterExchange: "dlx", Overflow: OverflowRejectPublishDLX}, "quorum queues do not support overflow"},
+		{"stream dlx", ConsumerConfig{Queue: "q", QueueType: QueueStream, DeadLetterExchange: "dlx"}, "dead lettering"},
+		{"stream ttl", ConsumerConfig{Queue: "q", QueueType: QueueStream, MessageTTL: time.Second}, "message ttl"},
+		{"stream requeue", ConsumerConfig{Queue: "q", QueueType: QueueStream, RetryOnError: boolPtr(true)}, "requeue"},
+		{"lazy quorum", ConsumerConfig{Queue: "q", QueueType: QueueQuorum, Lazy: true}, "classic queues only"},
+		{"priority range", ConsumerConfig{Queue: "q", MaxPriority: 300}, "between 1 and 255"},
+		{"ttl too small", ConsumerConfig{Queue: "q", MessageTTL: time.Microsecond}, "at least 1ms"},
+		{"negative length", ConsumerConfig{Queue: "q", MaxLength: -1}, "must not be negative"},
+		{"unknown overflow", ConsumerConfig{Queue: "q", Overflow: "block"}, `unknown overflow "block"`},
+		{"reject-publish-dlx without dlx", ConsumerConfig{Queue: "q", Overflow: OverflowRejectPublishDLX}, "requires a dead letter exchange"},
+		{"single active with concurrency", ConsumerConfig{Queue: "q", SingleActiveConsumer: true, Concurrency: 3}, "single active consumer"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestPublishOptions_PriorityAndExpiration(t *testing.T) {
+	e := newEnvelope("events", "a", nil, nil, []PublishOption{
+		WithPriority(7),
+		WithExpiration(1500 * time.Millisecond),
+	})
+
+	assert.Equal(t, uint8(7), e.msg.Priority)
+	assert.Equal(t, "1500", e.msg.Expiration)
+}
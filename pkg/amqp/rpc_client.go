@@ -0,0 +1,250 @@
+package amqp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	amqp091 "github.com/rabbitmq/amqp091-go"
+)
+
+// directReplyTo is RabbitMQ's pseudo-queue for request/reply without declaring
+// a reply queue. Replies are delivered to the channel that published the request.
+const directReplyTo = "amq.rabbitmq.reply-to"
+
+// headerRPCError carries the handler error message in an RPC reply.
+const headerRPCError = "x-rpc-error"
+
+const defaultRPCTimeout = 10 * time.Second
+
+// RPCError is returned by [Broker.Call] when the remote handler failed.
+type RPCError struct {
+	Message string
+}
+
+func (e *RPCError) Error() string {
+	return "amqp: rpc: " + e.Message
+}
+
+// RPCReply is the raw response of [Broker.Call].
+type RPCReply struct {
+	Body        []byte
+	ContentType string
+	Headers     amqp091.Table
+}
+
+// Decode unmarshals the reply body with the codec matching its content type.
+func (r *RPCReply) Decode(v any) error {
+	return Decode(r.ContentType, r.Body, v)
+}
+
+// rpcOutcome is either a reply delivery or an error (e.g. the request was unroutable).
+type rpcOutcome struct {
+	reply amqp091.Delivery
+	err   error
+}
+
+// rpcClient owns a single channel consuming from direct reply-to and
+// dispatches replies to waiting callers by correlation ID.
+// Requests are published as mandatory, so a request no handler is bound for
+// fails immediately with [*UnroutableError] instead of waiting for the timeout.
+// The channel is opened lazily and reopened after it closes.
+//
+// Without a connection, requests go through the in-memory broker, which hands
+// replies to dispatch directly.
+type rpcClient struct {
+	conn    *amqp091.Connection
+	mem     *memoryBroker
+	timeout time.Duration
+
+	mu      sync.Mutex
+	ch      *amqp091.Channel
+	pending map[string]chan rpcOutcome
+}
+
+func newRPCClient(conn *amqp091.Connection, mem *memoryBroker, timeout time.Duration) *rpcClient {
+	if timeout <= 0 {
+		timeout = defaultRPCTimeout
+	}
+	c := &rpcClient{
+		conn:    conn,
+		mem:     mem,
+		timeout: timeout,
+		pending: make(map[string]chan rpcOutcome),
+	}
+	if mem != nil {
+		mem.replies = func(d amqp091.Delivery) {
+			c.dispatch(d.CorrelationId, rpcOutcome{reply: d})
+		}
+	}
+	return c
+}
+
+// call publishes msg with a fresh correlation ID and waits for the reply.
+// If ctx has no deadline, the client's default timeout applies; the request
+// expires in the queue after the same duration so stale calls are not processed.
+func (c *rpcClient) call(ctx context.Context, exchange, routingKey string, msg amqp091.Publishing) (*RPCReply, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	deadline, _ := ctx.Deadline()
+
+	id := uuid.NewString()
+	replies := make(chan rpcOutcome, 1)
+
+	msg.CorrelationId = id
+	msg.ReplyTo = directReplyTo
+	if ttl := time.Until(deadline).Milliseconds(); ttl > 0 {
+		msg.Expiration = strconv.FormatInt(ttl, 10)
+	}
+
+	if err := c.publish(ctx, id, replies, envelope{exchange: exchange, routingKey: routingKey, mandatory: true, msg: msg}); err != nil {
+		return nil, err
+	}
+	defer c.unregister(id)
+
+	select {
+	case out, ok := <-replies:
+		if !ok {
+			return nil, errors.New("amqp: rpc: reply channel closed")
+		}
+		if out.err != nil {
+			return nil, out.err
+		}
+		return newRPCReply(out.reply)
+	case <-ctx.Done():
+		return nil, fmt.Errorf("amqp: rpc: %w", ctx.Err())
+	}
+}
+
+// publish registers the waiter and sends the request. On error the waiter is removed.
+func (c *rpcClient) publish(ctx context.Context, id string, replies chan rpcOutcome, e envelope) error {
+	if c.mem != nil {
+		c.mu.Lock()
+		c.pending[id] = replies
+		c.mu.Unlock()
+
+		if err := c.mem.Publish(ctx, e); err != nil {
+			c.unregister(id)
+			return err
+		}
+		return nil
+	}
+
+	ch, err := c.register(id, replies)
+	if err != nil {
+		return err
+	}
+	if err := ch.PublishWithContext(ctx, e.exchange, e.routingKey, e.mandatory, false, e.msg); err != nil {
+		c.unregister(id)
+		return fmt.Errorf("amqp: rpc publish: %w", err)
+	}
+	return nil
+}
+
+func newRPCReply(d amqp091.Delivery) (*RPCReply, error) {
+	if msg, ok := d.Headers[headerRPCError].(string); ok {
+		return nil, &RPCError{Message: msg}
+	}
+	return &RPCReply{Body: d.Body, ContentType: d.ContentType, Headers: d.Headers}, nil
+}
+
+// register adds a waiter and returns the reply channel, opening it if needed.
+func (c *rpcClient) register(id string, replies chan rpcOutcome) (*amqp091.Channel, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.ch == nil || c.ch.IsClosed() {
+		if err := c.open(); err != nil {
+			return nil, err
+		}
+	}
+
+	c.pending[id] = replies
+	return c.ch, nil
+}
+
+func (c *rpcClient) unregister(id string) {
+	c.mu.Lock()
+	delete(c.pending, id)
+	c.mu.Unlock()
+}
+
+// open must be called with c.mu held.
+func (c *rpcClient) open() error {
+	ch, err := c.conn.Channel()
+	if err != nil {
+		return fmt.Errorf("amqp: rpc: open channel: %w", err)
+	}
+
+	// Direct reply-to requires no-ack consumption.
+	deliveries, err := ch.Consume(directReplyTo, "", true, false, false, false, nil)
+	if err != nil {
+		_ = ch.Close()
+		return fmt.Errorf("amqp: rpc: consume replies: %w", err)
+	}
+
+	returns := ch.NotifyReturn(make(chan amqp091.Return, 1))
+
+	c.ch = ch
+	go c.dispatchLoop(ch, deliveries, returns)
+	return nil
+}
+
+func (c *rpcClient) dispatchLoop(ch *amqp091.Channel, deliveries <-chan amqp091.Delivery, returns <-chan amqp091.Return) {
+	for deliveries != nil || returns != nil {
+		select {
+		case d, ok := <-deliveries:
+			if !ok {
+				deliveries = nil
+				continue
+			}
+			c.dispatch(d.CorrelationId, rpcOutcome{reply: d})
+		case r, ok := <-returns:
+			if !ok {
+				returns = nil
+				continue
+			}
+			c.dispatch(r.CorrelationId, rpcOutcome{err: newUnroutableError(r)})
+		}
+	}
+
+	// Channel closed: fail everyone waiting on it so they don't hang until timeout.
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ch != ch {
+		return
+	}
+	for id, w := range c.pending {
+		close(w)
+		delete(c.pending, id)
+	}
+	c.ch = nil
+}
+
+func (c *rpcClient) dispatch(correlationID string, out rpcOutcome) {
+	c.mu.Lock()
+	w, ok := c.pending[correlationID]
+	if ok {
+		delete(c.pending, correlationID)
+	}
+	c.mu.Unlock()
+
+	if ok {
+		w <- out // buffered, never blocks
+	}
+}
+
+func (c *rpcClient) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ch != nil {
+		_ = c.ch.Close()
+	}
+}
@@ -0,0 +1,108 @@
+package amqp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	amqp091 "github.com/rabbitmq/amqp091-go"
+)
+
+type rpcResultKey struct{}
+
+// rpcResult carries the handler response from the innermost handler back to
+// the reply sender, past any middlewares in between.
+type rpcResult struct {
+	body []byte
+	set  bool
+}
+
+// AddRPCHandler registers a request/reply consumer in the broker.
+// The handler receives a decoded request of type Req; its response (or error)
+// is sent back to the caller's ReplyTo address with the request's correlation ID,
+// encoded with the same codec as the request.
+// Group-level middlewares (set via [Broker].Use) are applied before per-handler mws.
+// Failed requests are answered with an error reply and acked, never requeued.
+func AddRPCHandler[Req, Resp any](b *Broker, cfg ConsumerConfig, fn func(ctx context.Context, req Req, meta DeliveryMeta) (Resp, error), mws ...Middleware) {
+	g := b.consumers
+	allMws := append(g.mws[:len(g.mws):len(g.mws)], mws...)
+	handler := Chain(rpcHandler(fn), allMws...)
+	g.consumers = append(g.consumers, newConsumer(cfg, rpcReplier(b.publishers, handler)))
+}
+
+// rpcHandler decodes the request, calls fn and encodes the response into the
+// rpcResult held by ctx.
+func rpcHandler[Req, Resp any](fn func(ctx context.Context, req Req, meta DeliveryMeta) (Resp, error)) HandlerFunc {
+	return func(ctx context.Context, msg amqp091.Delivery) error {
+		var req Req
+		if err := Decode(msg.ContentType, msg.Body, &req); err != nil {
+			return fmt.Errorf("unmarshal: %w", err)
+		}
+		if isStruct(req) {
+			if err := validate.StructCtx(ctx, req); err != nil {
+				return fmt.Errorf("validate: %w", translateValidation(ctx, msg.Headers, err))
+			}
+		}
+
+		resp, err := fn(ctx, req, newDeliveryMeta(&msg))
+		if err != nil {
+			return err
+		}
+
+		body, err := replyCodec(msg.ContentType).Marshal(resp)
+		if err != nil {
+			return fmt.Errorf("marshal reply: %w", err)
+		}
+
+		if res, ok := ctx.Value(rpcResultKey{}).(*rpcResult); ok {
+			res.body, res.set = body, true
+		}
+		return nil
+	}
+}
+
+// rpcReplier runs next and publishes its outcome to msg.ReplyTo.
+// It wraps the whole middleware chain, so errors converted by WithRecover
+// still reach the caller as an error reply.
+func rpcReplier(publishers *publisherManager, next HandlerFunc) HandlerFunc {
+	return func(ctx context.Context, msg amqp091.Delivery) error {
+		res := &rpcResult{}
+		err := next(context.WithValue(ctx, rpcResultKey{}, res), msg)
+
+		if msg.ReplyTo == "" {
+			return err
+		}
+
+		reply := amqp091.Publishing{
+			CorrelationId: msg.CorrelationId,
+			ContentType:   replyCodec(msg.ContentType).ContentType(),
+		}
+		switch {
+		case err != nil:
+			reply.Headers = amqp091.Table{headerRPCError: err.Error()}
+		case !res.set:
+			reply.Headers = amqp091.Table{headerRPCError: "handler produced no reply"}
+		default:
+			reply.Body = res.body
+		}
+
+		p, pErr := publishers.get(AtMostOnce)
+		if pErr != nil {
+			return errors.Join(err, pErr)
+		}
+		if pErr := p.Publish(ctx, envelope{routingKey: msg.ReplyTo, msg: reply}); pErr != nil {
+			return errors.Join(err, fmt.Errorf("publish reply: %w", pErr))
+		}
+		return nil
+	}
+}
+
+// replyCodec picks the codec matching the request content type, falling back
+// to JSON when the request used an unknown one.
+func replyCodec(contentType string) Codec {
+	c, err := CodecFor(contentType)
+	if err != nil {
+		return JSONCodec{}
+	}
+	return c
+}
@@ -0,0 +1,200 @@
+//go:build unit
+
+package amqp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	amqp091 "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type rpcRequest struct {
+	Name string `json:"name" validate:"required"`
+}
+
+type rpcResponse struct {
+	Greeting string `json:"greeting"`
+}
+
+func newRPCTestBroker(pub *fakePublisher) *Broker {
+	return &Broker{
+		publishers: &publisherManager{atMostOnce: pub},
+		consumers:  newConsumerGroup(nil, nil),
+	}
+}
+
+func greet(_ context.Context, req rpcRequest, _ DeliveryMeta) (rpcResponse, error) {
+	return rpcResponse{Greeting: "hello " + req.Name}, nil
+}
+
+func TestAddRPCHandler_RepliesWithCorrelationID(t *testing.T) {
+	pub := &fakePublisher{}
+	b := newRPCTestBroker(pub)
+	AddRPCHandler(b, ConsumerConfig{Queue: "rpc.greet"}, greet)
+
+	err := b.consumers.consumers[0].handler(context.Background(), amqp091.Delivery{
+		ContentType:   ContentTypeJSON,
+		CorrelationId: "corr-1",
+		ReplyTo:       directReplyTo,
+		Body:          []byte(`{"name":"bob"}`),
+	})
+	require.NoError(t, err)
+
+	require.Len(t, pub.published, 1)
+	got := pub.published[0]
+	assert.Equal(t, "", got.exchange)
+	assert.Equal(t, directReplyTo, got.routingKey)
+	assert.Equal(t, "corr-1", got.msg.CorrelationId)
+	assert.Equal(t, ContentTypeJSON, got.msg.ContentType)
+	assert.JSONEq(t, `{"greeting":"hello bob"}`, string(got.msg.Body))
+}
+
+func TestAddRPCHandler_ReplyUsesRequestCodec(t *testing.T) {
+	pub := &fakePublisher{}
+	b := newRPCTestBroker(pub)
+	AddRPCHandler(b, ConsumerConfig{Queue: "rpc.greet"}, greet)
+
+	body, err := MsgpackCodec{}.Marshal(rpcRequest{Name: "bob"})
+	require.NoError(t, err)
+
+	err = b.consumers.consumers[0].handler(context.Background(), amqp091.Delivery{
+		ContentType: ContentTypeMsgpack,
+		ReplyTo:     directReplyTo,
+		Body:        body,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, pub.published, 1)
+	reply := &RPCReply{Body: pub.published[0].msg.Body, ContentType: pub.published[0].msg.ContentType}
+	var resp rpcResponse
+	require.NoError(t, reply.Decode(&resp))
+	assert.Equal(t, ContentTypeMsgpack, reply.ContentType)
+	assert.Equal(t, "hello bob", resp.Greeting)
+}
+
+func TestAddRPCHandler_ErrorReply(t *testing.T) {
+	pub := &fakePublisher{}
+	b := newRPCTestBroker(pub)
+	AddRPCHandler(b, ConsumerConfig{Queue: "rpc.greet"}, func(_ context.Context, _ rpcRequest, _ DeliveryMeta) (rpcResponse, error) {
+		return rpcResponse{}, errors.New("no such user")
+	})
+
+	err := b.consumers.consumers[0].handler(context.Background(), amqp091.Delivery{
+		CorrelationId: "corr-1",
+		ReplyTo:       directReplyTo,
+		Body:          []byte(`{"name":"bob"}`),
+	})
+	require.NoError(t, err, "error is delivered to the caller, message is acked")
+
+	require.Len(t, pub.published, 1)
+	_, err = newRPCReply(amqp091.Delivery{Headers: pub.published[0].msg.Headers})
+	var rpcErr *RPCError
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, "no such user", rpcErr.Message)
+}
+
+func TestAddRPCHandler_ValidationErrorReply(t *testing.T) {
+	pub := &fakePublisher{}
+	b := newRPCTestBroker(pub)
+	AddRPCHandler(b, ConsumerConfig{Queue: "rpc.greet"}, greet)
+
+	err := b.consumers.consumers[0].handler(context.Background(), amqp091.Delivery{
+		ReplyTo: directReplyTo,
+		Body:    []byte(`{}`),
+	})
+	require.NoError(t, err)
+
+	require.Len(t, pub.published, 1)
+	assert.Contains(t, pub.published[0].msg.Headers[headerRPCError], "validate")
+}
+
+func TestAddRPCHandler_PanicRecoveredIntoErrorReply(t *testing.T) {
+	pub := &fakePublisher{}
+	b := newRPCTestBroker(pub)
+	b.Use(WithRecover())
+	AddRPCHandler(b, ConsumerConfig{Queue: "rpc.greet"}, func(_ context.Context, _ rpcRequest, _ DeliveryMeta) (rpcResponse, error) {
+		panic("boom")
+	})
+
+	err := b.consumers.consumers[0].handler(context.Background(), amqp091.Delivery{
+		ReplyTo: directReplyTo,
+		Body:    []byte(`{"name":"bob"}`),
+	})
+	require.NoError(t, err)
+
+	require.Len(t, pub.published, 1)
+	assert.Equal(t, "panic: boom", pub.published[0].msg.Headers[headerRPCError])
+}
+
+func TestAddRPCHandler_AppliesMiddlewares(t *testing.T) {
+	pub := &fakePublisher{}
+	b := newRPCTestBroker(pub)
+
+	var called bool
+	mw := func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, msg amqp091.Delivery) error {
+			called = true
+			return next(ctx, msg)
+		}
+	}
+	AddRPCHandler(b, ConsumerConfig{Queue: "rpc.greet"}, greet, mw)
+
+	err := b.consumers.consumers[0].handler(context.Background(), amqp091.Delivery{
+		ReplyTo: directReplyTo,
+		Body:    []byte(`{"name":"bob"}`),
+	})
+	require.NoError(t, err)
+	assert.True(t, called)
+	assert.Len(t, pub.published, 1)
+}
+
+func TestAddRPCHandler_NoReplyToReturnsError(t *testing.T) {
+	pub := &fakePublisher{}
+	b := newRPCTestBroker(pub)
+	AddRPCHandler(b, ConsumerConfig{Queue: "rpc.greet"}, greet)
+
+	err := b.consumers.consumers[0].handler(context.Background(), amqp091.Delivery{Body: []byte(`{}`)})
+	assert.Error(t, err)
+	assert.Empty(t, pub.published)
+}
+
+func TestAddRPCHandler_ReplyPublishFailure(t *testing.T) {
+	pub := &fakePublisher{err: errors.New("channel closed")}
+	b := newRPCTestBroker(pub)
+	AddRPCHandler(b, ConsumerConfig{Queue: "rpc.greet"}, greet)
+
+	err := b.consumers.consumers[0].handler(context.Background(), amqp091.Delivery{
+		ReplyTo: directReplyTo,
+		Body:    []byte(`{"name":"bob"}`),
+	})
+	assert.ErrorContains(t, err, "publish reply")
+}
+
+func TestRPCClient_DispatchByCorrelationID(t *testing.T) {
+	c := newRPCClient(nil, nil, 0)
+	replies := make(chan rpcOutcome, 1)
+	c.pending["corr-1"] = replies
+
+	c.dispatch("unknown", rpcOutcome{})
+	c.dispatch("corr-1", rpcOutcome{reply: amqp091.Delivery{Body: []byte("ok")}})
+
+	out := <-replies
+	assert.Equal(t, []byte("ok"), out.reply.Body)
+	assert.Empty(t, c.pending)
+}
+
+func TestBroker_Call_StandaloneUnknownExchange(t *testing.T) {
+	b := NewBroker(nil, PoolConfig{})
+	_, err := b.Call(context.Background(), "rpc", "greet", rpcRequest{Name: "bob"})
+	assert.ErrorContains(t, err, `no exchange "rpc"`)
+}
+
+func TestBroker_Call_ValidatesRequest(t *testing.T) {
+	b := NewBroker(nil, PoolConfig{})
+	_, err := b.Call(context.Background(), "rpc", "greet", rpcRequest{})
+	assert.ErrorContains(t, err, "validate")
+}
@@ -0,0 +1,183 @@
+package amqp
+
+import (
+	"errors"
+	"fmt"
+
+	amqp091 "github.com/rabbitmq/amqp091-go"
+)
+
+// Exchange types supported by RabbitMQ out of the box.
+const (
+	ExchangeDirect  = "direct"
+	ExchangeFanout  = "fanout"
+	ExchangeTopic   = "topic"
+	ExchangeHeaders = "headers"
+)
+
+// Binding destination types.
+const (
+	DestinationQueue    = "queue"
+	DestinationExchange = "exchange"
+)
+
+// Topology is a declarative description of exchanges, queues and bindings.
+// It is applied idempotently with [Broker.Declare]: redeclaring an existing
+// object with the same properties is a no-op, while a conflicting one fails
+// with a PRECONDITION_FAILED channel error.
+type Topology struct {
+	Exchanges []ExchangeSpec `yaml:"exchanges"`
+	Queues    []QueueSpec    `yaml:"queues"`
+	Bindings  []BindingSpec  `yaml:"bindings"`
+}
+
+// ExchangeSpec declares an exchange.
+type ExchangeSpec struct {
+	Name       string        `yaml:"name"`
+	Type       string        `yaml:"type"`              // direct, fanout, topic, headers
+	Durable    *bool         `yaml:"durable,omitempty"` // default: true
+	AutoDelete bool          `yaml:"auto_delete,omitempty"`
+	Internal   bool          `yaml:"internal,omitempty"`
+	Args       amqp091.Table `yaml:"args,omitempty"`
+}
+
+func (e ExchangeSpec) durable() bool {
+	return e.Durable == nil || *e.Durable
+}
+
+// QueueSpec declares a queue. Args carries x-arguments such as
+// x-queue-type, x-message-ttl, x-max-length or x-dead-letter-exchange.
+type QueueSpec struct {
+	Name       string        `yaml:"name"`
+	Durable    *bool         `yaml:"durable,omitempty"` // default: true
+	AutoDelete bool          `yaml:"auto_delete,omitempty"`
+	Exclusive  bool          `yaml:"exclusive,omitempty"`
+	Args       amqp091.Table `yaml:"args,omitempty"`
+}
+
+func (q QueueSpec) durable() bool {
+	return q.Durable == nil || *q.Durable
+}
+
+// BindingSpec binds a queue or an exchange (exchange-to-exchange) to a source exchange.
+type BindingSpec struct {
+	Source          string        `yaml:"source"`
+	Destination     string        `yaml:"destination"`
+	DestinationType string        `yaml:"destination_type,omitempty"` // queue (default) or exchange
+	RoutingKey      string        `yaml:"routing_key,omitempty"`
+	Args            amqp091.Table `yaml:"args,omitempty"`
+}
+
+func (b BindingSpec) destinationType() string {
+	if b.DestinationType == "" {
+		return DestinationQueue
+	}
+	return b.DestinationType
+}
+
+func (b BindingSpec) key() string {
+	return fmt.Sprintf("%s -> %s %s [%s] %s", b.Source, b.destinationType(), b.Destination, b.RoutingKey, argsKey(b.Args))
+}
+
+// MergeTopologies concatenates topologies, dropping duplicates.
+// The first declaration of an exchange or queue with a given name wins.
+func MergeTopologies(ts ...Topology) Topology {
+	var out Topology
+	exchanges := map[string]bool{}
+	queues := map[string]bool{}
+	bindings := map[string]bool{}
+
+	for _, t := range ts {
+		for _, e := range t.Exchanges {
+			if !exchanges[e.Name] {
+				exchanges[e.Name] = true
+				out.Exchanges = append(out.Exchanges, e)
+			}
+		}
+		for _, q := range t.Queues {
+			if !queues[q.Name] {
+				queues[q.Name] = true
+				out.Queues = append(out.Queues, q)
+			}
+		}
+		for _, b := range t.Bindings {
+			if k := b.key(); !bindings[k] {
+				bindings[k] = true
+				out.Bindings = append(out.Bindings, b)
+			}
+		}
+	}
+	return out
+}
+
+// Validate checks that every object is well-formed.
+func (t Topology) Validate() error {
+	var errs []error
+	for _, e := range t.Exchanges {
+		if e.Name == "" {
+			errs = append(errs, errors.New("exchange: name is required"))
+		}
+		switch e.Type {
+		case ExchangeDirect, ExchangeFanout, ExchangeTopic, ExchangeHeaders:
+		default:
+			errs = append(errs, fmt.Errorf("exchange %q: unknown type %q", e.Name, e.Type))
+		}
+	}
+	for _, q := range t.Queues {
+		if q.Name == "" {
+			errs = append(errs, errors.New("queue: name is required"))
+		}
+	}
+	for _, b := range t.Bindings {
+		if b.Source == "" || b.Destination == "" {
+			errs = append(errs, fmt.Errorf("binding %s: source and destination are required", b.key()))
+		}
+		switch b.destinationType() {
+		case DestinationQueue, DestinationExchange:
+		default:
+			errs = append(errs, fmt.Errorf("binding %s: unknown destination type %q", b.key(), b.DestinationType))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// apply declares exchanges, then queues, then bindings on ch.
+func (t Topology) apply(ch *amqp091.Channel) error {
+	for _, e := range t.Exchanges {
+		if err := ch.ExchangeDeclare(e.Name, e.Type, e.durable(), e.AutoDelete, e.Internal, false, e.Args); err != nil {
+			return fmt.Errorf("declare exchange %q: %w", e.Name, err)
+		}
+	}
+	for _, q := range t.Queues {
+		if _, err := ch.QueueDeclare(q.Name, q.durable(), q.AutoDelete, q.Exclusive, false, q.Args); err != nil {
+			return fmt.Errorf("declare queue %q: %w", q.Name, err)
+		}
+	}
+	for _, b := range t.Bindings {
+		var err error
+		if b.destinationType() == DestinationExchange {
+			err = ch.ExchangeBind(b.Destination, b.RoutingKey, b.Source, false, b.Args)
+		} else {
+			err = ch.QueueBind(b.Destination, b.RoutingKey, b.Source, false, b.Args)
+		}
+		if err != nil {
+			return fmt.Errorf("bind %s: %w", b.key(), err)
+		}
+	}
+	return nil
+}
+
+// declareTopology validates t and applies it on a dedicated channel.
+func declareTopology(conn *amqp091.Connection, t Topology) error {
+	if err := t.Validate(); err != nil {
+		return fmt.Errorf("invalid topology: %w", err)
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		return fmt.Errorf("open channel for declare: %w", err)
+	}
+	defer func() { _ = ch.Close() }()
+
+	return t.apply(ch)
+}
@@ -0,0 +1,216 @@
+//go:build unit
+
+package amqp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	amqp091 "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsumerConfig_Topology_QueueAndBinding(t *testing.T) {
+	topo := ConsumerConfig{Queue: "q", Exchange: "events", RoutingKey: "user.#"}.Topology()
+
+	require.Len(t, topo.Queues, 1)
+	assert.Equal(t, "q", topo.Queues[0].Name)
+	assert.True(t, topo.Queues[0].durable())
+	assert.Empty(t, topo.Exchanges)
+	assert.Equal(t, []BindingSpec{{Source: "events", Destination: "q", RoutingKey: "user.#"}}, topo.Bindings)
+}
+
+func TestConsumerConfig_Topology_DeadLetter(t *testing.T) {
+	topo := ConsumerConfig{Queue: "q", DeadLetterExchange: "dlx"}.Topology()
+
+	assert.Equal(t, []ExchangeSpec{{Name: "dlx", Type: ExchangeTopic}}, topo.Exchanges)
+	require.Len(t, topo.Queues, 2)
+	assert.Equal(t, "q.dlq", topo.Queues[0].Name)
+	assert.Equal(t, "q", topo.Queues[1].Name)
+	assert.Equal(t, amqp091.Table{"x-dead-letter-exchange": "dlx"}, topo.Queues[1].Args)
+	assert.Equal(t, []BindingSpec{{Source: "dlx", Destination: "q.dlq", RoutingKey: "q"}}, topo.Bindings)
+}
+
+func TestMergeTopologies_DropsDuplicates(t *testing.T) {
+	a := Topology{
+		Exchanges: []ExchangeSpec{{Name: "events", Type: ExchangeTopic}},
+		Bindings:  []BindingSpec{{Source: "events", Destination: "q", RoutingKey: "a"}},
+	}
+	b := Topology{
+		Exchanges: []ExchangeSpec{{Name: "events", Type: ExchangeTopic}, {Name: "dlx", Type: ExchangeTopic}},
+		Bindings: []BindingSpec{
+			{Source: "events", Destination: "q", RoutingKey: "a"},
+			{Source: "events", Destination: "q", RoutingKey: "b"},
+		},
+	}
+
+	merged := MergeTopologies(a, b)
+
+	assert.Len(t, merged.Exchanges, 2)
+	assert.Len(t, merged.Bindings, 2)
+}
+
+func TestTopology_Validate(t *testing.T) {
+	valid := Topology{
+		Exchanges: []ExchangeSpec{{Name: "events", Type: ExchangeTopic}},
+		Queues:    []QueueSpec{{Name: "q"}},
+		Bindings:  []BindingSpec{{Source: "events", Destination: "q"}},
+	}
+	assert.NoError(t, valid.Validate())
+
+	invalid := Topology{
+		Exchanges: []ExchangeSpec{{Name: "events", Type: "x-delayed"}},
+		Queues:    []QueueSpec{{}},
+		Bindings:  []BindingSpec{{Source: "events", Destination: "q", DestinationType: "stream"}},
+	}
+	err := invalid.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown type "x-delayed"`)
+	assert.Contains(t, err.Error(), "queue: name is required")
+	assert.Contains(t, err.Error(), `unknown destination type "stream"`)
+}
+
+func TestDiffTopology(t *testing.T) {
+	durable := true
+	desired := Topology{
+		Exchanges: []ExchangeSpec{
+			{Name: "events", Type: ExchangeTopic},
+			{Name: "events.tagged", Type: ExchangeHeaders},
+		},
+		Queues: []QueueSpec{
+			{Name: "q", Args: amqp091.Table{"x-message-ttl": int32(60000)}},
+			{Name: "new"},
+		},
+		Bindings: []BindingSpec{
+			{Source: "events", Destination: "q", RoutingKey: "user.#"},
+		},
+	}
+	live := Topology{
+		Exchanges: []ExchangeSpec{
+			{Name: "", Type: ExchangeDirect, Durable: &durable},
+			{Name: "amq.topic", Type: ExchangeTopic, Durable: &durable},
+			{Name: "events", Type: ExchangeTopic, Durable: &durable},
+			{Name: "events.tagged", Type: ExchangeTopic, Durable: &durable},
+			{Name: "legacy", Type: ExchangeFanout, Durable: &durable},
+		},
+		Queues: []QueueSpec{
+			// Management API returns numbers as JSON (float64).
+			{Name: "q", Durable: &durable, Args: amqp091.Table{"x-message-ttl": float64(60000)}},
+			{Name: "amq.gen-abc", Durable: &durable},
+		},
+		Bindings: []BindingSpec{
+			{Source: "", Destination: "q", RoutingKey: "q"},
+			{Source: "events", Destination: "q", DestinationType: DestinationQueue, RoutingKey: "old.#"},
+		},
+	}
+
+	var got []string
+	for _, c := range DiffTopology(desired, live) {
+		got = append(got, c.String())
+	}
+
+	assert.Equal(t, []string{
+		"~ exchange events.tagged: type topic -> headers",
+		"- exchange legacy: fanout",
+		"+ queue new",
+		"+ binding events -> queue q [user.#] {}",
+		"- binding events -> queue q [old.#] {}",
+	}, got)
+}
+
+func TestDiffTopology_IgnoresTransientQueues(t *testing.T) {
+	durable := false
+	desired := Topology{
+		Exchanges: []ExchangeSpec{{Name: "events", Type: ExchangeTopic}},
+		Queues:    []QueueSpec{{Name: "q"}},
+		Bindings:  []BindingSpec{{Source: "events", Destination: "q", RoutingKey: "a"}},
+	}
+	live := desired
+	live.Queues = append(live.Queues,
+		QueueSpec{Name: "user.events.watch.1", Durable: &durable, AutoDelete: true, Exclusive: true},
+		QueueSpec{Name: "reply.2", Durable: &durable, AutoDelete: true},
+		QueueSpec{Name: "stale", Durable: &durable},
+	)
+	live.Bindings = append(live.Bindings,
+		BindingSpec{Source: "events", Destination: "user.events.watch.1", DestinationType: DestinationQueue, RoutingKey: "user.#"},
+	)
+
+	var got []string
+	for _, c := range DiffTopology(desired, live) {
+		got = append(got, c.String())
+	}
+
+	assert.Equal(t, []string{"- queue stale"}, got)
+}
+
+func TestDiffTopology_NoChanges(t *testing.T) {
+	topo := Topology{
+		Exchanges: []ExchangeSpec{{Name: "events", Type: ExchangeTopic}},
+		Queues:    []QueueSpec{{Name: "q"}},
+		Bindings:  []BindingSpec{{Source: "events", Destination: "q", RoutingKey: "a"}},
+	}
+	assert.Empty(t, DiffTopology(topo, topo))
+}
+
+func TestFetchTopology(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "guest", user)
+		assert.Equal(t, "secret", pass)
+
+		switch r.URL.EscapedPath() {
+		case "/api/exchanges/%2F":
+			_, _ = w.Write([]byte(`[{"name":"events","type":"topic","durable":true,"arguments":{}}]`))
+		case "/api/queues/%2F":
+			_, _ = w.Write([]byte(`[{"name":"q","durable":true,"exclusive":false,"arguments":{"x-queue-type":"quorum"}}]`))
+		case "/api/bindings/%2F":
+			_, _ = w.Write([]byte(`[{"source":"events","destination":"q","destination_type":"queue","routing_key":"a","arguments":{}}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	mgmtURL := "http://guest:secret@" + srv.Listener.Addr().String()
+	topo, err := FetchTopology(context.Background(), srv.Client(), mgmtURL, "/")
+	require.NoError(t, err)
+
+	require.Len(t, topo.Exchanges, 1)
+	assert.Equal(t, "events", topo.Exchanges[0].Name)
+	require.Len(t, topo.Queues, 1)
+	assert.Equal(t, "quorum", topo.Queues[0].Args["x-queue-type"])
+	require.Len(t, topo.Bindings, 1)
+	assert.Equal(t, "a", topo.Bindings[0].RoutingKey)
+}
+
+func TestFetchTopology_HTTPError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	_, err := FetchTopology(context.Background(), srv.Client(), srv.URL, "/")
+	assert.ErrorContains(t, err, "401")
+}
+
+func TestBroker_Topology_IncludesDeclaredAndConsumers(t *testing.T) {
+	b := NewBroker(nil, PoolConfig{})
+	require.NoError(t, b.Declare(Topology{Exchanges: []ExchangeSpec{{Name: "events", Type: ExchangeTopic}}}))
+	AddRawConsumer(b, ConsumerConfig{Queue: "q", Exchange: "events", RoutingKey: "a"}, nil)
+
+	topo := b.Topology()
+
+	assert.Len(t, topo.Exchanges, 1)
+	assert.Len(t, topo.Queues, 1)
+	assert.Len(t, topo.Bindings, 1)
+}
+
+func TestBroker_Declare_InvalidTopology(t *testing.T) {
+	b := NewBroker(nil, PoolConfig{})
+	err := b.Declare(Topology{Exchanges: []ExchangeSpec{{Name: "events"}}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,30 @@
+package amqp
+
+import (
+	"fmt"
+
+	amqp091 "github.com/rabbitmq/amqp091-go"
+)
+
+// UnroutableError is returned when a mandatory message matched no queue
+// and the broker returned it (see [WithMandatory]).
+type UnroutableError struct {
+	Exchange   string
+	RoutingKey string
+	ReplyCode  uint16
+	ReplyText  string
+}
+
+func newUnroutableError(r amqp091.Return) *UnroutableError {
+	return &UnroutableError{
+		Exchange:   r.Exchange,
+		RoutingKey: r.RoutingKey,
+		ReplyCode:  r.ReplyCode,
+		ReplyText:  r.ReplyText,
+	}
+}
+
+func (e *UnroutableError) Error() string {
+	return fmt.Sprintf("amqp: message unroutable (exchange %q, routing key %q): %d %s",
+		e.Exchange, e.RoutingKey, e.ReplyCode, e.ReplyText)
+}
@@ -0,0 +1,42 @@
+//go:build unit
+
+package amqp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	amqp091 "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnroutableError_FromReturn(t *testing.T) {
+	err := fmt.Errorf("publish: %w", newUnroutableError(amqp091.Return{
+		ReplyCode:  312,
+		ReplyText:  "NO_ROUTE",
+		Exchange:   "events",
+		RoutingKey: "user.craeted",
+	}))
+
+	var unroutable *UnroutableError
+	require.True(t, errors.As(err, &unroutable))
+	assert.Equal(t, "events", unroutable.Exchange)
+	assert.Equal(t, "user.craeted", unroutable.RoutingKey)
+	assert.Equal(t, uint16(312), unroutable.ReplyCode)
+	assert.Contains(t, err.Error(), "NO_ROUTE")
+}
+
+func TestBroker_Publish_WithMandatory(t *testing.T) {
+	pub := &fakePublisher{}
+	b := &Broker{publishers: &publisherManager{atLeastOnce: pub}}
+
+	require.NoError(t, b.Publish(context.Background(), "events", "a", nil, nil, AtLeastOnce))
+	require.NoError(t, b.Publish(context.Background(), "events", "b", nil, nil, AtLeastOnce, WithMandatory()))
+
+	require.Len(t, pub.published, 2)
+	assert.False(t, pub.published[0].mandatory)
+	assert.True(t, pub.published[1].mandatory)
+}
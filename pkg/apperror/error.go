@@ -1,10 +1,35 @@
 package apperror
 
-import "fmt"
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
 
+// AppError is an error with an HTTP status, a machine-readable code and
+// optional field-level details. The With* methods return a copy, so
+// sentinels can be refined per call site; the copy keeps the code, so
+// errors.Is still matches the sentinel.
 type AppError struct {
 	Status  int
+	Code    string
 	Message string
+	Details []FieldViolation
+	cause   error
+}
+
+// FieldViolation describes what is wrong with one request field.
+type FieldViolation struct {
+	Field   string `json:"field"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message"`
+	args    []any
+}
+
+// Args returns the values the message is about, e.g. the minimum length, for
+// translations of the reason to format.
+func (v FieldViolation) Args() []any {
+	return v.args
 }
 
 func (e *AppError) Error() string {
@@ -15,10 +40,60 @@ func (e *AppError) GetStatus() int {
 	return e.Status
 }
 
+// Unwrap returns the cause, so errors.Is and errors.As see through AppError.
+func (e *AppError) Unwrap() error {
+	return e.cause
+}
+
+// Is reports whether target is an AppError with the same code.
+func (e *AppError) Is(target error) bool {
+	t, ok := target.(*AppError)
+	return ok && t.Code == e.Code
+}
+
+// New creates an AppError whose code is derived from the status, e.g.
+// "not_found" for 404 (see CodeForStatus).
 func New(status int, message string) *AppError {
-	return &AppError{Status: status, Message: message}
+	return &AppError{Status: status, Code: CodeForStatus(status), Message: message}
 }
 
+// Wrap creates an AppError caused by err; the message includes err's.
 func Wrap(err error, status int, message string) *AppError {
-	return &AppError{Status: status, Message: fmt.Sprintf("%s: %v", message, err)}
+	return &AppError{Status: status, Code: CodeForStatus(status), Message: fmt.Sprintf("%s: %v", message, err), cause: err}
+}
+
+// CodeForStatus returns the default code of a status: its text in snake case.
+func CodeForStatus(status int) string {
+	text := http.StatusText(status)
+	if text == "" {
+		return "unknown"
+	}
+	return strings.ToLower(strings.NewReplacer(" ", "_", "-", "_", "'", "").Replace(text))
+}
+
+func (e *AppError) WithCode(code string) *AppError {
+	c := *e
+	c.Code = code
+	return &c
+}
+
+func (e *AppError) WithMessage(message string) *AppError {
+	c := *e
+	c.Message = message
+	return &c
+}
+
+func (e *AppError) WithCause(err error) *AppError {
+	c := *e
+	c.cause = err
+	return &c
+}
+
+// WithField adds a violation of field; reason is a machine-readable code
+// and may be empty. args are the values message is about (see
+// FieldViolation.Args).
+func (e *AppError) WithField(field, reason, message string, args ...any) *AppError {
+	c := *e
+	c.Details = append(append([]FieldViolation(nil), e.Details...), FieldViolation{Field: field, Reason: reason, Message: message, args: args})
+	return &c
 }
@@ -4,6 +4,7 @@ package apperror
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"testing"
 
@@ -46,3 +47,59 @@ func TestAppError_ImplementsError(t *testing.T) {
 	require.True(t, errors.As(err, &appErr))
 	assert.Equal(t, http.StatusBadRequest, appErr.GetStatus())
 }
+
+func TestNew_DefaultCode(t *testing.T) {
+	assert.Equal(t, "not_found", New(http.StatusNotFound, "x").Code)
+	assert.Equal(t, "too_many_requests", New(http.StatusTooManyRequests, "x").Code)
+	assert.Equal(t, "im_a_teapot", CodeForStatus(http.StatusTeapot))
+	assert.Equal(t, "unknown", CodeForStatus(999))
+}
+
+func TestAppError_IsMatchesCode(t *testing.T) {
+	sentinel := New(http.StatusNotFound, "not found").WithCode("user_not_found")
+	refined := sentinel.WithMessage("user 42 not found").WithField("id", "", "no such user")
+
+	assert.ErrorIs(t, refined, sentinel)
+	assert.ErrorIs(t, fmt.Errorf("get user: %w", refined), sentinel)
+	assert.NotErrorIs(t, New(http.StatusNotFound, "not found"), sentinel)
+	assert.Empty(t, sentinel.Details, "With* must not modify the receiver")
+}
+
+func TestAppError_UnwrapsCause(t *testing.T) {
+	cause := errors.New("connection refused")
+
+	assert.ErrorIs(t, Wrap(cause, http.StatusInternalServerError, "db error"), cause)
+	assert.ErrorIs(t, New(http.StatusBadGateway, "upstream").WithCause(cause), cause)
+}
+
+func TestAppError_Problem(t *testing.T) {
+	cfg := Config{TypeBaseURI: "https://errors.example.com/"}
+	err := New(http.StatusBadRequest, "invalid input").WithField("email", "format", "must be an email")
+
+	p := err.Problem(cfg, "req-1")
+
+	assert.Equal(t, &Problem{
+		Type:      "https://errors.example.com/bad_request",
+		Title:     "Bad Request",
+		Status:    http.StatusBadRequest,
+		Detail:    "invalid input",
+		Code:      "bad_request",
+		RequestID: "req-1",
+		Errors:    []FieldViolation{{Field: "email", Reason: "format", Message: "must be an email"}},
+	}, p)
+	assert.Equal(t, "about:blank", err.Problem(Config{}, "").Type)
+}
+
+func TestAppError_ProblemHidesServerErrors(t *testing.T) {
+	err := Wrap(errors.New("connection refused"), http.StatusInternalServerError, "db error")
+
+	p := err.Problem(Config{}, "")
+
+	assert.Equal(t, "internal server error", p.Detail)
+	assert.Equal(t, "internal_server_error", p.Code)
+}
+
+func TestAppError_ContentType(t *testing.T) {
+	assert.Equal(t, "application/problem+json", New(http.StatusBadRequest, "x").ContentType("application/json"))
+	assert.Equal(t, "application/cbor", New(http.StatusBadRequest, "x").ContentType("application/cbor"))
+}
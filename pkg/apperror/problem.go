@@ -0,0 +1,81 @@
+package apperror
+
+import "net/http"
+
+const internalMessage = "internal server error"
+
+// Config describes how errors are identified to clients.
+type Config struct {
+	// TypeBaseURI prefixes the code to form the problem type URI, e.g.
+	// "https://errors.example.com/" gives "https://errors.example.com/not_found".
+	// Without it, the type is "about:blank".
+	TypeBaseURI string `yaml:"type_base_uri"`
+	// Domain is the google.rpc.ErrorInfo domain of gRPC errors.
+	Domain string `yaml:"domain"`
+}
+
+// TypeURI returns the problem type URI of code.
+func (c Config) TypeURI(code string) string {
+	if c.TypeBaseURI == "" {
+		return "about:blank"
+	}
+	return c.TypeBaseURI + code
+}
+
+// Problem is an RFC 9457 problem details body with the code, field violations
+// and request ID as extension members.
+type Problem struct {
+	Type      string           `json:"type" doc:"URI identifying the error type"`
+	Title     string           `json:"title" doc:"Status text of the error type"`
+	Status    int              `json:"status" doc:"HTTP status code"`
+	Detail    string           `json:"detail,omitempty" doc:"Explanation of this occurrence"`
+	Code      string           `json:"code" doc:"Machine-readable error code"`
+	RequestID string           `json:"request_id,omitempty" doc:"Request ID to correlate with logs"`
+	Errors    []FieldViolation `json:"errors,omitempty" doc:"Field-level violations"`
+}
+
+func (p *Problem) Error() string {
+	return p.Detail
+}
+
+func (p *Problem) GetStatus() int {
+	return p.Status
+}
+
+// ContentType makes huma serve problems as application/problem+json.
+func (p *Problem) ContentType(ct string) string {
+	if ct == "application/json" {
+		return "application/problem+json"
+	}
+	return ct
+}
+
+// ContentType makes huma serve AppErrors as application/problem+json; the
+// body is their Problem.
+func (e *AppError) ContentType(ct string) string {
+	return (&Problem{}).ContentType(ct)
+}
+
+// Problem returns the problem details of e. Server errors (5xx) keep only
+// their status and code, so that causes never reach the client.
+func (e *AppError) Problem(cfg Config, requestID string) *Problem {
+	s := e.Sanitized()
+	return &Problem{
+		Type:      cfg.TypeURI(s.Code),
+		Title:     http.StatusText(s.Status),
+		Status:    s.Status,
+		Detail:    s.Message,
+		Code:      s.Code,
+		RequestID: requestID,
+		Errors:    s.Details,
+	}
+}
+
+// Sanitized returns e, or for server errors (5xx) a copy without the message,
+// details and cause.
+func (e *AppError) Sanitized() *AppError {
+	if e.Status < http.StatusInternalServerError {
+		return e
+	}
+	return &AppError{Status: e.Status, Code: e.Code, Message: internalMessage}
+}
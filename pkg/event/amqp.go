@@ -20,58 +20,55 @@ const (
 type AMQPBus struct {
 	broker   *pkgamqp.Broker
 	exchange string
+	codec    pkgamqp.Codec
 }
 
 // AMQPBusOption configures an AMQPBus.
 type AMQPBusOption func(*AMQPBus)
 
+// WithCodec sets the payload codec (default: JSON).
+func WithCodec(c pkgamqp.Codec) AMQPBusOption {
+	return func(b *AMQPBus) {
+		b.codec = c
+	}
+}
+
 func NewAMQPBus(broker *pkgamqp.Broker, exchange string, opts ...AMQPBusOption) *AMQPBus {
-	b := &AMQPBus{broker: broker, exchange: exchange}
+	b := &AMQPBus{broker: broker, exchange: exchange, codec: pkgamqp.JSONCodec{}}
 	for _, o := range opts {
 		o(b)
 	}
 	return b
 }
 
+// Topology returns the exchanges shared by all event publishers and consumers:
+// the "events" topic exchange, the "events.tagged" headers exchange fed from it
+// by an exchange-to-exchange binding, and the "dlx" dead-letter exchange.
+func Topology() pkgamqp.Topology {
+	return pkgamqp.Topology{
+		Exchanges: []pkgamqp.ExchangeSpec{
+			{Name: ExchangeEvents, Type: pkgamqp.ExchangeTopic},
+			{Name: ExchangeTagged, Type: pkgamqp.ExchangeHeaders},
+			{Name: ExchangeDLX, Type: pkgamqp.ExchangeTopic},
+		},
+		Bindings: []pkgamqp.BindingSpec{
+			// Forward all messages from events → events.tagged.
+			{Source: ExchangeEvents, Destination: ExchangeTagged, DestinationType: pkgamqp.DestinationExchange, RoutingKey: "#"},
+		},
+	}
+}
+
 // NewEventBus is a Wire provider that creates a Bus backed by AMQP
 // with the "events" topic exchange.
-// It declares the exchange on startup so both publishers and consumers
+// It declares the event [Topology] on startup so both publishers and consumers
 // can rely on it regardless of initialization order.
-func NewEventBus(conn *amqp091.Connection, broker *pkgamqp.Broker) Bus {
-	if conn != nil {
-		if err := declareExchange(conn, ExchangeEvents); err != nil {
-			panic(fmt.Sprintf("event bus: declare exchange: %v", err))
-		}
-		if err := declareTaggedExchange(conn); err != nil {
-			panic(fmt.Sprintf("event bus: declare tagged exchange: %v", err))
-		}
+func NewEventBus(broker *pkgamqp.Broker) Bus {
+	if err := broker.Declare(Topology()); err != nil {
+		panic(fmt.Sprintf("event bus: declare topology: %v", err))
 	}
 	return NewAMQPBus(broker, ExchangeEvents)
 }
 
-func declareExchange(conn *amqp091.Connection, name string) error {
-	ch, err := conn.Channel()
-	if err != nil {
-		return err
-	}
-	defer func() { _ = ch.Close() }()
-	return ch.ExchangeDeclare(name, "topic", true, false, false, false, nil)
-}
-
-func declareTaggedExchange(conn *amqp091.Connection) error {
-	ch, err := conn.Channel()
-	if err != nil {
-		return err
-	}
-	defer func() { _ = ch.Close() }()
-
-	if err := ch.ExchangeDeclare(ExchangeTagged, "headers", true, false, false, false, nil); err != nil {
-		return err
-	}
-	// Exchange-to-exchange binding: forward all messages from events → events.tagged.
-	return ch.ExchangeBind(ExchangeTagged, "#", ExchangeEvents, false, nil)
-}
-
 func (b *AMQPBus) Publish(ctx context.Context, e Event) error {
 	var headers amqp091.Table
 	if t, ok := e.(Taggable); ok {
@@ -83,5 +80,5 @@ func (b *AMQPBus) Publish(ctx context.Context, e Event) error {
 			}
 		}
 	}
-	return b.broker.PublishJSON(ctx, b.exchange, e.EventName(), headers, e, pkgamqp.AtLeastOnce)
+	return b.broker.PublishEncoded(ctx, b.exchange, e.EventName(), headers, e, b.codec, pkgamqp.AtLeastOnce)
 }
@@ -13,6 +13,12 @@ type Taggable interface {
 	Tags() []string
 }
 
+// Partitioned is an optional interface for events that carry a partition key
+// (usually the aggregate ID). Kafka keeps events with the same key in order.
+type Partitioned interface {
+	PartitionKey() string
+}
+
 // Bus publishes domain events. Implementations handle serialization and transport.
 type Bus interface {
 	Publish(ctx context.Context, event Event) error
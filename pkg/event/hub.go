@@ -0,0 +1,135 @@
+package event
+
+import (
+	"errors"
+	"sync"
+)
+
+const defaultHubBuffer = 256
+
+var (
+	// ErrSlowSubscriber ends a subscription whose buffer overflowed. Events
+	// were dropped, so the subscriber should resume from its last position.
+	ErrSlowSubscriber = errors.New("event: subscriber too slow, events dropped")
+	// ErrHubClosed ends the subscriptions of a closed hub.
+	ErrHubClosed = errors.New("event: hub closed")
+)
+
+// Envelope is an event as delivered by a Hub.
+type Envelope struct {
+	Position int64 // outbox entry ID; 0 when unknown
+	Name     string
+	Key      string // partition key, usually the aggregate ID
+	Payload  []byte
+}
+
+// Hub fans events out to in-process subscribers, such as streaming RPCs.
+// Publish never blocks: each subscription has a bounded buffer, and a
+// subscription that falls behind is ended with ErrSlowSubscriber instead of
+// slowing down the publisher or the other subscribers.
+type Hub struct {
+	buffer int
+
+	mu     sync.RWMutex
+	subs   map[*Subscription]struct{}
+	closed bool
+}
+
+func NewHub() *Hub {
+	return &Hub{buffer: defaultHubBuffer, subs: make(map[*Subscription]struct{})}
+}
+
+// Subscribe returns a subscription to the events for which filter returns
+// true (all events if filter is nil). Close it when done.
+func (h *Hub) Subscribe(filter func(Envelope) bool) *Subscription {
+	s := &Subscription{
+		hub:    h,
+		filter: filter,
+		events: make(chan Envelope, h.buffer),
+		done:   make(chan struct{}),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		s.end(ErrHubClosed)
+		return s
+	}
+	h.subs[s] = struct{}{}
+	return s
+}
+
+// Publish delivers e to every matching subscription.
+func (h *Hub) Publish(e Envelope) {
+	var slow []*Subscription
+
+	h.mu.RLock()
+	for s := range h.subs {
+		if s.filter != nil && !s.filter(e) {
+			continue
+		}
+		select {
+		case s.events <- e:
+		default:
+			slow = append(slow, s)
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, s := range slow {
+		h.remove(s, ErrSlowSubscriber)
+	}
+}
+
+// Close ends every subscription with ErrHubClosed, e.g. on shutdown so that
+// streaming RPCs return and let the server stop gracefully.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for s := range h.subs {
+		delete(h.subs, s)
+		s.end(ErrHubClosed)
+	}
+}
+
+func (h *Hub) remove(s *Subscription, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.subs[s]; ok {
+		delete(h.subs, s)
+		s.end(err)
+	}
+}
+
+// Subscription receives events from a Hub until it is closed or ended.
+type Subscription struct {
+	hub    *Hub
+	filter func(Envelope) bool
+	events chan Envelope
+	done   chan struct{}
+	once   sync.Once
+	err    error
+}
+
+// Events returns the buffered events. It is never closed: select on Done too.
+func (s *Subscription) Events() <-chan Envelope { return s.events }
+
+// Done is closed when the subscription ends.
+func (s *Subscription) Done() <-chan struct{} { return s.done }
+
+// Err returns why the subscription ended: ErrSlowSubscriber, ErrHubClosed,
+// or nil after Close. Only valid once Done is closed.
+func (s *Subscription) Err() error { return s.err }
+
+// Close ends the subscription.
+func (s *Subscription) Close() {
+	s.hub.remove(s, nil)
+}
+
+func (s *Subscription) end(err error) {
+	s.once.Do(func() {
+		s.err = err
+		close(s.done)
+	})
+}
@@ -0,0 +1,74 @@
+//go:build unit
+
+package event
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHub_DeliversMatchingEvents(t *testing.T) {
+	h := NewHub()
+	all := h.Subscribe(nil)
+	onlyA := h.Subscribe(func(e Envelope) bool { return e.Key == "a" })
+	defer all.Close()
+	defer onlyA.Close()
+
+	h.Publish(Envelope{Position: 1, Key: "a"})
+	h.Publish(Envelope{Position: 2, Key: "b"})
+
+	assert.Equal(t, int64(1), (<-all.Events()).Position)
+	assert.Equal(t, int64(2), (<-all.Events()).Position)
+	assert.Equal(t, int64(1), (<-onlyA.Events()).Position)
+	assert.Empty(t, onlyA.Events())
+}
+
+func TestHub_SlowSubscriberIsDropped(t *testing.T) {
+	h := NewHub()
+	slow := h.Subscribe(nil)
+	fast := h.Subscribe(func(e Envelope) bool { return e.Position > defaultHubBuffer })
+	defer fast.Close()
+
+	for i := range defaultHubBuffer + 1 {
+		h.Publish(Envelope{Position: int64(i + 1)})
+	}
+
+	<-slow.Done()
+	require.ErrorIs(t, slow.Err(), ErrSlowSubscriber)
+	assert.Len(t, slow.Events(), defaultHubBuffer)
+
+	select {
+	case <-fast.Done():
+		t.Fatal("fast subscriber must not be dropped")
+	default:
+	}
+	assert.Equal(t, int64(defaultHubBuffer+1), (<-fast.Events()).Position)
+}
+
+func TestHub_Close(t *testing.T) {
+	h := NewHub()
+	s := h.Subscribe(nil)
+
+	h.Close()
+
+	<-s.Done()
+	require.ErrorIs(t, s.Err(), ErrHubClosed)
+
+	late := h.Subscribe(nil)
+	<-late.Done()
+	require.ErrorIs(t, late.Err(), ErrHubClosed)
+}
+
+func TestSubscription_Close(t *testing.T) {
+	h := NewHub()
+	s := h.Subscribe(nil)
+
+	s.Close()
+	h.Publish(Envelope{Position: 1})
+
+	<-s.Done()
+	assert.NoError(t, s.Err())
+	assert.Empty(t, s.Events())
+}
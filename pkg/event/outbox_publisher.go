@@ -2,6 +2,8 @@ package event
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	pkgamqp "starter-boilerplate/pkg/amqp"
 	"starter-boilerplate/pkg/outbox"
@@ -10,6 +12,9 @@ import (
 )
 
 // OutboxPublisher adapts the outbox.Publisher interface to AMQP via Broker.
+// Entries are published as mandatory: an entry whose event name matches no
+// binding fails with *pkgamqp.UnroutableError wrapped in
+// outbox.ErrUndeliverable, so the relay marks it failed and goes on.
 type OutboxPublisher struct {
 	broker   *pkgamqp.Broker
 	exchange string
@@ -26,7 +31,35 @@ func NewDefaultOutboxPublisher(_ Bus, broker *pkgamqp.Broker) *OutboxPublisher {
 }
 
 func (p *OutboxPublisher) Publish(ctx context.Context, entry outbox.Entry) error {
-	return p.broker.Publish(ctx, p.exchange, entry.EventName, toAMQPTable(entry.Headers), entry.Payload, pkgamqp.AtLeastOnce)
+	return undeliverable(p.broker.Publish(ctx, p.exchange, entry.EventName, toAMQPTable(entry.Headers), entry.Payload, pkgamqp.AtLeastOnce, pkgamqp.WithMandatory()))
+}
+
+// PublishBatch publishes all entries with pipelined publisher confirms.
+func (p *OutboxPublisher) PublishBatch(ctx context.Context, entries []outbox.Entry) []error {
+	msgs := make([]pkgamqp.BatchMessage, len(entries))
+	for i, e := range entries {
+		msgs[i] = pkgamqp.BatchMessage{
+			Exchange:   p.exchange,
+			RoutingKey: e.EventName,
+			Headers:    toAMQPTable(e.Headers),
+			Body:       e.Payload,
+			Options:    []pkgamqp.PublishOption{pkgamqp.WithMandatory()},
+		}
+	}
+	errs := p.broker.PublishBatch(ctx, msgs, pkgamqp.AtLeastOnce)
+	for i, err := range errs {
+		errs[i] = undeliverable(err)
+	}
+	return errs
+}
+
+// undeliverable marks unroutable errors as outbox.ErrUndeliverable.
+func undeliverable(err error) error {
+	var unroutable *pkgamqp.UnroutableError
+	if errors.As(err, &unroutable) {
+		return fmt.Errorf("%w: %w", outbox.ErrUndeliverable, err)
+	}
+	return err
 }
 
 func toAMQPTable(headers map[string]any) amqp091.Table {
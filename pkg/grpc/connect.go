@@ -0,0 +1,124 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"connectrpc.com/connect"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// ForwardedUserAgentKey is the metadata key of the HTTP client's user agent on
+// requests from the gateways; gRPC clients replace "user-agent" with their own.
+const ForwardedUserAgentKey = "grpcgateway-user-agent"
+
+// ForwardUnary calls a unary RPC of the loopback client on behalf of a
+// Connect request, forwarding the caller's headers as metadata and the
+// response header back.
+func ForwardUnary[Req, Res any](ctx context.Context, req *connect.Request[Req], call func(context.Context, *Req, ...grpc.CallOption) (*Res, error)) (*connect.Response[Res], error) {
+	var header metadata.MD
+	msg, err := call(outgoingContext(ctx, req.Header(), req.Peer()), req.Msg, grpc.Header(&header))
+	if err != nil {
+		return nil, connectError(err, header)
+	}
+	resp := connect.NewResponse(msg)
+	copyMetadata(resp.Header(), header)
+	return resp, nil
+}
+
+// ForwardServerStream is ForwardUnary for server-streaming RPCs: it relays
+// messages until the server ends the stream or the caller goes away.
+func ForwardServerStream[Req, Res any](ctx context.Context, req *connect.Request[Req], stream *connect.ServerStream[Res], call func(context.Context, *Req, ...grpc.CallOption) (grpc.ServerStreamingClient[Res], error)) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	client, err := call(outgoingContext(ctx, req.Header(), req.Peer()), req.Msg)
+	if err != nil {
+		return connectError(err, nil)
+	}
+	// The server sends its header before the first message, or with the
+	// status when it fails first.
+	header, err := client.Header()
+	if err != nil {
+		return connectError(err, nil)
+	}
+	copyMetadata(stream.ResponseHeader(), header)
+
+	for {
+		msg, err := client.Recv()
+		if err != nil {
+			return connectError(err, nil)
+		}
+		if err := stream.Send(msg); err != nil {
+			return err
+		}
+	}
+}
+
+// outgoingContext carries the headers the server reads (credentials, request
+// ID, client address, user agent and languages) over to gRPC metadata.
+func outgoingContext(ctx context.Context, h http.Header, peer connect.Peer) context.Context {
+	md := metadata.MD{}
+	if v := h.Get("Authorization"); v != "" {
+		md.Set("authorization", v)
+	}
+	for key := range forwardedHeaders {
+		if v := h.Get(key); v != "" {
+			md.Set(key, v)
+		}
+	}
+	if v := h.Get("User-Agent"); v != "" {
+		md.Set(ForwardedUserAgentKey, v)
+	}
+	if v := h.Get("Accept-Language"); v != "" {
+		md.Set(LanguageMetadataKey, v)
+	}
+
+	xff := h.Values("X-Forwarded-For")
+	if host, _, err := net.SplitHostPort(peer.Addr); err == nil {
+		xff = append(xff, host)
+	}
+	if len(xff) > 0 {
+		md.Set("x-forwarded-for", strings.Join(xff, ", "))
+	}
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// connectError converts a status error to the Connect error of the same code,
+// with the same details; the numeric codes are shared. The end of a stream is
+// not an error.
+func connectError(err error, header metadata.MD) error {
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	st := status.Convert(err)
+	if st.Code() == codes.OK {
+		return nil
+	}
+	cerr := connect.NewError(connect.Code(st.Code()), errors.New(st.Message()))
+	for _, d := range st.Proto().GetDetails() {
+		if detail, err := connect.NewErrorDetail(d); err == nil {
+			cerr.AddDetail(detail)
+		}
+	}
+	copyMetadata(cerr.Meta(), header)
+	return cerr
+}
+
+func copyMetadata(dst http.Header, md metadata.MD) {
+	for key, vals := range md {
+		if key == "content-type" {
+			continue
+		}
+		for _, v := range vals {
+			dst.Add(key, v)
+		}
+	}
+}
@@ -5,32 +5,117 @@ import (
 	"errors"
 	"log/slog"
 	"net/http"
+	"strconv"
 
 	"starter-boilerplate/pkg/apperror"
+	"starter-boilerplate/pkg/i18n"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
 )
 
-func ErrorInterceptor() grpc.UnaryServerInterceptor {
+// RequestIDFunc returns the request ID of a call, or "" when it has none.
+type RequestIDFunc func(ctx context.Context) string
+
+// ErrorInterceptor converts errors to rich statuses (see toStatus). requestID
+// may be nil.
+func ErrorInterceptor(cfg apperror.Config, requestID RequestIDFunc) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
 		resp, err := handler(ctx, req)
 		if err == nil {
 			return resp, nil
 		}
+		return nil, toStatus(ctx, cfg, requestID, info.FullMethod, err)
+	}
+}
 
-		var appErr *apperror.AppError
-		if errors.As(err, &appErr) {
-			return nil, status.Error(httpToGRPC(appErr.Status), appErr.Message)
+// StreamErrorInterceptor is ErrorInterceptor for streaming RPCs.
+func StreamErrorInterceptor(cfg apperror.Config, requestID RequestIDFunc) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := handler(srv, ss); err != nil {
+			return toStatus(ss.Context(), cfg, requestID, info.FullMethod, err)
 		}
+		return nil
+	}
+}
 
+// toStatus converts AppErrors to their gRPC status and hides anything else
+// behind codes.Internal. Errors that already are statuses pass through.
+// Server errors are logged with their cause and sent without it.
+func toStatus(ctx context.Context, cfg apperror.Config, requestID RequestIDFunc, method string, err error) error {
+	var appErr *apperror.AppError
+	if !errors.As(err, &appErr) {
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		appErr = apperror.New(http.StatusInternalServerError, err.Error()).WithCause(err)
+	}
+
+	var id string
+	if requestID != nil {
+		id = requestID(ctx)
+	}
+	if appErr.Status >= http.StatusInternalServerError {
 		slog.Error("internal error",
-			slog.String("method", info.FullMethod),
+			slog.String("method", method),
+			slog.String("request_id", id),
 			slog.Any("error", err),
 		)
-		return nil, status.Error(codes.Internal, "internal server error")
 	}
+	return richStatus(appErr.Sanitized(), cfg, id, i18n.FromContext(ctx)).Err()
+}
+
+// AppErrorStatus returns the status ErrorInterceptor sends for e, for errors
+// raised outside of it, e.g. by panic recovery wrapped around it.
+func AppErrorStatus(ctx context.Context, cfg apperror.Config, requestID string, e *apperror.AppError) *status.Status {
+	return richStatus(e.Sanitized(), cfg, requestID, i18n.FromContext(ctx))
+}
+
+// richStatus carries the code as google.rpc.ErrorInfo (reason, with the
+// problem type and the HTTP status in the metadata), field violations as google.rpc.BadRequest
+// and the request ID as google.rpc.RequestInfo. With a localizer, the
+// translated messages come as google.rpc.LocalizedMessage, next to the
+// original ones.
+func richStatus(e *apperror.AppError, cfg apperror.Config, requestID string, loc *i18n.Localizer) *status.Status {
+	st := status.New(httpToGRPC(e.Status), e.Message)
+	localized := loc.Error(e)
+	locale := loc.Tag().String()
+
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{
+		Reason:   e.Code,
+		Domain:   cfg.Domain,
+		Metadata: map[string]string{"type": cfg.TypeURI(e.Code), "status": strconv.Itoa(e.Status)},
+	}}
+	if len(e.Details) > 0 {
+		br := &errdetails.BadRequest{}
+		for i, v := range e.Details {
+			fv := &errdetails.BadRequest_FieldViolation{
+				Field:       v.Field,
+				Reason:      v.Reason,
+				Description: v.Message,
+			}
+			if loc != nil {
+				fv.LocalizedMessage = &errdetails.LocalizedMessage{Locale: locale, Message: localized.Details[i].Message}
+			}
+			br.FieldViolations = append(br.FieldViolations, fv)
+		}
+		details = append(details, br)
+	}
+	if requestID != "" {
+		details = append(details, &errdetails.RequestInfo{RequestId: requestID})
+	}
+	if loc != nil {
+		details = append(details, &errdetails.LocalizedMessage{Locale: locale, Message: localized.Message})
+	}
+
+	withDetails, err := st.WithDetails(details...)
+	if err != nil {
+		return st
+	}
+	return withDetails
 }
 
 func httpToGRPC(httpStatus int) codes.Code {
@@ -44,11 +129,13 @@ func httpToGRPC(httpStatus int) codes.Code {
 	case http.StatusNotFound:
 		return codes.NotFound
 	case http.StatusConflict:
-		return codes.AlreadyExists
+		return codes.Aborted
 	case http.StatusUnprocessableEntity:
-		return codes.InvalidArgument
+		return codes.FailedPrecondition
 	case http.StatusTooManyRequests:
 		return codes.ResourceExhausted
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
 	default:
 		return codes.Internal
 	}
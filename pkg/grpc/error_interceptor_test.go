@@ -9,9 +9,13 @@ import (
 	"testing"
 
 	"starter-boilerplate/pkg/apperror"
+	"starter-boilerplate/pkg/i18n"
 
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -26,11 +30,12 @@ func TestHttpToGRPC(t *testing.T) {
 		{http.StatusUnauthorized, codes.Unauthenticated},
 		{http.StatusForbidden, codes.PermissionDenied},
 		{http.StatusNotFound, codes.NotFound},
-		{http.StatusConflict, codes.AlreadyExists},
-		{http.StatusUnprocessableEntity, codes.InvalidArgument},
+		{http.StatusConflict, codes.Aborted},
+		{http.StatusUnprocessableEntity, codes.FailedPrecondition},
 		{http.StatusTooManyRequests, codes.ResourceExhausted},
 		{http.StatusInternalServerError, codes.Internal},
-		{http.StatusServiceUnavailable, codes.Internal},
+		{http.StatusServiceUnavailable, codes.Unavailable},
+		{http.StatusBadGateway, codes.Internal},
 	}
 
 	for _, tt := range tests {
@@ -40,8 +45,38 @@ func TestHttpToGRPC(t *testing.T) {
 	}
 }
 
+// Connect answers with the status of the code, so every AppError must come
+// back with its own status; 422 is the only one folded into another (the
+// HTTP/JSON gateway restores it, see httpStatusOf).
+func TestHttpToGRPC_RoundTripsThroughGateway(t *testing.T) {
+	statuses := []int{
+		http.StatusBadRequest,
+		http.StatusUnauthorized,
+		http.StatusForbidden,
+		http.StatusNotFound,
+		http.StatusConflict,
+		http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusServiceUnavailable,
+	}
+
+	for _, s := range statuses {
+		t.Run(http.StatusText(s), func(t *testing.T) {
+			assert.Equal(t, s, runtime.HTTPStatusFromCode(httpToGRPC(s)))
+		})
+	}
+	assert.Equal(t, http.StatusBadRequest, runtime.HTTPStatusFromCode(httpToGRPC(http.StatusUnprocessableEntity)))
+}
+
+func TestHttpStatusOf_PrefersRecordedStatus(t *testing.T) {
+	st := richStatus(apperror.New(http.StatusUnprocessableEntity, "bad range"), apperror.Config{}, "", nil)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, httpStatusOf(st))
+	assert.Equal(t, http.StatusBadRequest, httpStatusOf(status.New(codes.FailedPrecondition, "no details")))
+}
+
 func TestErrorInterceptor_NoError(t *testing.T) {
-	interceptor := ErrorInterceptor()
+	interceptor := ErrorInterceptor(apperror.Config{}, nil)
 	handler := func(_ context.Context, _ any) (any, error) {
 		return "ok", nil
 	}
@@ -54,7 +89,7 @@ func TestErrorInterceptor_NoError(t *testing.T) {
 }
 
 func TestErrorInterceptor_AppError(t *testing.T) {
-	interceptor := ErrorInterceptor()
+	interceptor := ErrorInterceptor(apperror.Config{}, nil)
 	handler := func(_ context.Context, _ any) (any, error) {
 		return nil, apperror.New(http.StatusNotFound, "user not found")
 	}
@@ -72,7 +107,7 @@ func TestErrorInterceptor_AppError(t *testing.T) {
 }
 
 func TestErrorInterceptor_GenericError(t *testing.T) {
-	interceptor := ErrorInterceptor()
+	interceptor := ErrorInterceptor(apperror.Config{}, nil)
 	handler := func(_ context.Context, _ any) (any, error) {
 		return nil, errors.New("unexpected failure")
 	}
@@ -88,3 +123,135 @@ func TestErrorInterceptor_GenericError(t *testing.T) {
 	assert.Equal(t, codes.Internal, st.Code())
 	assert.Equal(t, "internal server error", st.Message())
 }
+
+func TestErrorInterceptor_StatusPassesThrough(t *testing.T) {
+	interceptor := ErrorInterceptor(apperror.Config{}, nil)
+	handler := func(_ context.Context, _ any) (any, error) {
+		return nil, status.Error(codes.Unavailable, "try later")
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: "/test/Method"}
+
+	_, err := interceptor(context.Background(), nil, info, handler)
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, codes.Unavailable, st.Code())
+	assert.Equal(t, "try later", st.Message())
+}
+
+func TestStreamErrorInterceptor_AppError(t *testing.T) {
+	interceptor := StreamErrorInterceptor(apperror.Config{}, nil)
+	handler := func(_ any, _ grpc.ServerStream) error {
+		return apperror.New(http.StatusForbidden, "insufficient permissions")
+	}
+	info := &grpc.StreamServerInfo{FullMethod: "/test/Stream"}
+
+	err := interceptor(nil, &fakeServerStream{ctx: context.Background()}, info, handler)
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, codes.PermissionDenied, st.Code())
+	assert.Equal(t, "insufficient permissions", st.Message())
+}
+
+func TestStreamErrorInterceptor_GenericError(t *testing.T) {
+	interceptor := StreamErrorInterceptor(apperror.Config{}, nil)
+	handler := func(_ any, _ grpc.ServerStream) error {
+		return errors.New("unexpected failure")
+	}
+	info := &grpc.StreamServerInfo{FullMethod: "/test/Stream"}
+
+	err := interceptor(nil, &fakeServerStream{ctx: context.Background()}, info, handler)
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, codes.Internal, st.Code())
+}
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeServerStream) Context() context.Context { return s.ctx }
+
+func TestErrorInterceptor_RichDetails(t *testing.T) {
+	cfg := apperror.Config{TypeBaseURI: "https://errors.example.com/", Domain: "example.com"}
+	requestID := func(context.Context) string { return "req-1" }
+	interceptor := ErrorInterceptor(cfg, requestID)
+	handler := func(_ context.Context, _ any) (any, error) {
+		return nil, apperror.New(http.StatusBadRequest, "email must be a valid email address").
+			WithCode("invalid_email").
+			WithField("email", "format", "email must be a valid email address")
+	}
+
+	_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/test/Method"}, handler)
+
+	st := status.Convert(err)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	require.Len(t, st.Details(), 3)
+
+	info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+	require.True(t, ok)
+	assert.Equal(t, "invalid_email", info.Reason)
+	assert.Equal(t, "example.com", info.Domain)
+	assert.Equal(t, "https://errors.example.com/invalid_email", info.Metadata["type"])
+
+	br, ok := st.Details()[1].(*errdetails.BadRequest)
+	require.True(t, ok)
+	require.Len(t, br.FieldViolations, 1)
+	assert.Equal(t, "email", br.FieldViolations[0].Field)
+	assert.Equal(t, "format", br.FieldViolations[0].Reason)
+
+	ri, ok := st.Details()[2].(*errdetails.RequestInfo)
+	require.True(t, ok)
+	assert.Equal(t, "req-1", ri.RequestId)
+}
+
+func TestErrorInterceptor_ServerAppErrorIsSanitized(t *testing.T) {
+	interceptor := ErrorInterceptor(apperror.Config{}, nil)
+	handler := func(_ context.Context, _ any) (any, error) {
+		return nil, apperror.Wrap(errors.New("connection refused"), http.StatusInternalServerError, "db error")
+	}
+
+	_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/test/Method"}, handler)
+
+	st := status.Convert(err)
+	assert.Equal(t, codes.Internal, st.Code())
+	assert.Equal(t, "internal server error", st.Message())
+}
+
+func TestErrorInterceptor_LocalizedMessages(t *testing.T) {
+	catalog := i18n.NewCatalog(language.English, map[language.Tag]i18n.Messages{language.Russian: {
+		Errors:     map[string]string{"bad_request": "некорректный запрос"},
+		Violations: map[string]string{"min_length": "длина должна быть не меньше %v"},
+	}})
+	ctx := i18n.WithLocalizer(context.Background(), catalog.Localizer(language.Russian))
+	interceptor := ErrorInterceptor(apperror.Config{}, nil)
+	handler := func(_ context.Context, _ any) (any, error) {
+		return nil, apperror.New(http.StatusBadRequest, "password is too short").
+			WithField("password", "min_length", "password must be at least 6 characters", 6)
+	}
+
+	_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/test/Method"}, handler)
+
+	st := status.Convert(err)
+	assert.Equal(t, "password is too short", st.Message(), "the status message stays as is")
+	require.Len(t, st.Details(), 3)
+
+	br, ok := st.Details()[1].(*errdetails.BadRequest)
+	require.True(t, ok)
+	assert.Equal(t, "password must be at least 6 characters", br.FieldViolations[0].Description)
+	assert.Equal(t, "ru", br.FieldViolations[0].LocalizedMessage.GetLocale())
+	assert.Equal(t, "длина должна быть не меньше 6", br.FieldViolations[0].LocalizedMessage.GetMessage())
+
+	lm, ok := st.Details()[2].(*errdetails.LocalizedMessage)
+	require.True(t, ok)
+	assert.Equal(t, "ru", lm.Locale)
+	assert.Equal(t, "некорректный запрос", lm.Message)
+
+	p, locale := problemFromStatus(st, http.StatusBadRequest)
+	assert.Equal(t, "ru", locale)
+	assert.Equal(t, "некорректный запрос", p.Detail)
+	assert.Equal(t, "длина должна быть не меньше 6", p.Errors[0].Message)
+}
@@ -0,0 +1,148 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/textproto"
+	"strconv"
+
+	"starter-boilerplate/pkg/apperror"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+)
+
+// forwardedHeaders are the HTTP headers, besides Authorization, that reach
+// the server as metadata of the same name.
+var forwardedHeaders = map[string]bool{
+	"X-Request-Id": true,
+	"X-Real-Ip":    true,
+}
+
+// LanguageMetadataKey is the metadata key of the caller's preferred
+// languages, in the format of Accept-Language, which the gateways forward
+// under this key.
+const LanguageMetadataKey = "grpc-accept-language"
+
+// NewGatewayMux creates a gRPC-Gateway mux that transcodes HTTP/JSON to the
+// HTTP rules of the proto services. Errors are problem details, like on the
+// HTTP API, with the HTTP status ErrorInterceptor recorded (see
+// httpStatusOf).
+func NewGatewayMux() *runtime.ServeMux {
+	return runtime.NewServeMux(
+		runtime.WithIncomingHeaderMatcher(incomingHeaderMatcher),
+		runtime.WithOutgoingHeaderMatcher(outgoingHeaderMatcher),
+		runtime.WithErrorHandler(writeProblem),
+	)
+}
+
+// writeProblem renders an error as application/problem+json, rebuilt from
+// the status details set by ErrorInterceptor, in the language they were
+// translated into, if any (Content-Language).
+func writeProblem(ctx context.Context, _ *runtime.ServeMux, _ runtime.Marshaler, w http.ResponseWriter, _ *http.Request, err error) {
+	httpStatus := 0
+	var statusErr *runtime.HTTPStatusError
+	if errors.As(err, &statusErr) {
+		httpStatus = statusErr.HTTPStatus
+		err = statusErr.Err
+	}
+	st := status.Convert(err)
+	if httpStatus == 0 {
+		httpStatus = httpStatusOf(st)
+	}
+
+	if md, ok := runtime.ServerMetadataFromContext(ctx); ok {
+		for key, vals := range md.HeaderMD {
+			if h, ok := outgoingHeaderMatcher(key); ok {
+				for _, v := range vals {
+					w.Header().Add(h, v)
+				}
+			}
+		}
+	}
+	p, locale := problemFromStatus(st, httpStatus)
+	if locale != "" {
+		w.Header().Set("Content-Language", locale)
+	}
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(httpStatus)
+	_ = json.NewEncoder(w).Encode(p)
+}
+
+// httpStatusOf returns the HTTP status ErrorInterceptor recorded in the
+// google.rpc.ErrorInfo of st, or runtime.HTTPStatusFromCode of its code:
+// some statuses, like 422, have no gRPC code that maps back to them.
+func httpStatusOf(st *status.Status) int {
+	for _, d := range st.Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			if s, err := strconv.Atoi(info.Metadata["status"]); err == nil {
+				return s
+			}
+		}
+	}
+	return runtime.HTTPStatusFromCode(st.Code())
+}
+
+// problemFromStatus prefers the google.rpc.LocalizedMessage of the status and
+// its field violations to their original messages, and returns their locale.
+func problemFromStatus(st *status.Status, httpStatus int) (*apperror.Problem, string) {
+	p := &apperror.Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(httpStatus),
+		Status: httpStatus,
+		Detail: st.Message(),
+		Code:   apperror.CodeForStatus(httpStatus),
+	}
+	var locale string
+	for _, d := range st.Details() {
+		switch d := d.(type) {
+		case *errdetails.ErrorInfo:
+			p.Code = d.Reason
+			if t := d.Metadata["type"]; t != "" {
+				p.Type = t
+			}
+		case *errdetails.BadRequest:
+			for _, v := range d.FieldViolations {
+				msg := v.Description
+				if lm := v.GetLocalizedMessage(); lm != nil {
+					msg = lm.Message
+				}
+				p.Errors = append(p.Errors, apperror.FieldViolation{Field: v.Field, Reason: v.Reason, Message: msg})
+			}
+		case *errdetails.RequestInfo:
+			p.RequestID = d.RequestId
+		case *errdetails.LocalizedMessage:
+			p.Detail = d.Message
+			locale = d.Locale
+		}
+	}
+	return p, locale
+}
+
+// incomingHeaderMatcher forwards the request ID and proxy headers as is,
+// Accept-Language as LanguageMetadataKey, and the rest like
+// runtime.DefaultHeaderMatcher: User-Agent, for instance, arrives as
+// ForwardedUserAgentKey because gRPC clients set their own.
+// X-Forwarded-For is always added by the gateway itself.
+func incomingHeaderMatcher(key string) (string, bool) {
+	key = textproto.CanonicalMIMEHeaderKey(key)
+	if forwardedHeaders[key] {
+		return key, true
+	}
+	if key == "Accept-Language" {
+		return LanguageMetadataKey, true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// outgoingHeaderMatcher returns the request ID as X-Request-Id, like the
+// HTTP API, and other response metadata with the Grpc-Metadata- prefix.
+func outgoingHeaderMatcher(key string) (string, bool) {
+	if key == "x-request-id" {
+		return http.CanonicalHeaderKey(key), true
+	}
+	return runtime.MetadataHeaderPrefix + key, true
+}
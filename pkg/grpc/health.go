@@ -0,0 +1,110 @@
+package grpc
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const defaultHealthInterval = 5 * time.Second
+
+// HealthCheck reports whether a dependency is ready; a nil error means ready.
+type HealthCheck func(ctx context.Context) error
+
+// HealthChecks are the named dependencies the server's health depends on.
+type HealthChecks map[string]HealthCheck
+
+// Health serves grpc.health.v1. It runs the checks every interval and reports
+// SERVING, for the server ("") and every registered service, only while all of
+// them pass.
+type Health struct {
+	server   *health.Server
+	checks   HealthChecks
+	interval time.Duration
+	grpcSrv  *grpc.Server
+	failing  map[string]bool // only touched by Run
+}
+
+func NewHealth(cfg GRPCConfig, checks HealthChecks) *Health {
+	interval := cfg.HealthInterval
+	if interval <= 0 {
+		interval = defaultHealthInterval
+	}
+	return &Health{
+		server:   health.NewServer(),
+		checks:   checks,
+		interval: interval,
+		failing:  make(map[string]bool),
+	}
+}
+
+func (h *Health) register(srv *grpc.Server) {
+	h.grpcSrv = srv
+	healthpb.RegisterHealthServer(srv, h.server)
+}
+
+// Run checks the dependencies until ctx is cancelled.
+func (h *Health) Run(ctx context.Context) error {
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	for {
+		h.check(ctx)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Shutdown reports NOT_SERVING for good, so that load balancers stop routing
+// to the server before it stops.
+func (h *Health) Shutdown() {
+	h.server.Shutdown()
+}
+
+func (h *Health) check(ctx context.Context) {
+	checkCtx, cancel := context.WithTimeout(ctx, h.interval)
+	defer cancel()
+
+	errs := make(map[string]error, len(h.checks))
+	for name, check := range h.checks {
+		errs[name] = check(checkCtx)
+	}
+	if ctx.Err() != nil {
+		return // shutting down: failures are not the dependencies' fault
+	}
+
+	status := healthpb.HealthCheckResponse_SERVING
+	for name, err := range errs {
+		if err != nil {
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+		h.logTransition(name, err)
+	}
+
+	h.server.SetServingStatus("", status)
+	if h.grpcSrv != nil {
+		for service := range h.grpcSrv.GetServiceInfo() {
+			if service != healthpb.Health_ServiceDesc.ServiceName {
+				h.server.SetServingStatus(service, status)
+			}
+		}
+	}
+}
+
+func (h *Health) logTransition(name string, err error) {
+	switch {
+	case err != nil && !h.failing[name]:
+		h.failing[name] = true
+		slog.Error("health check failed", slog.String("dependency", name), slog.Any("error", err))
+	case err == nil && h.failing[name]:
+		delete(h.failing, name)
+		slog.Info("health check recovered", slog.String("dependency", name))
+	}
+}
@@ -0,0 +1,83 @@
+//go:build unit
+
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func servingStatus(t *testing.T, h *Health, service string) healthpb.HealthCheckResponse_ServingStatus {
+	t.Helper()
+	resp, err := h.server.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	require.NoError(t, err)
+	return resp.Status
+}
+
+func TestHealth_ServingWhenAllChecksPass(t *testing.T) {
+	h := NewHealth(GRPCConfig{}, HealthChecks{
+		"postgres": func(context.Context) error { return nil },
+		"redis":    func(context.Context) error { return nil },
+	})
+
+	h.check(context.Background())
+
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, servingStatus(t, h, ""))
+}
+
+func TestHealth_NotServingWhenACheckFails(t *testing.T) {
+	var redisErr error
+	h := NewHealth(GRPCConfig{}, HealthChecks{
+		"postgres": func(context.Context) error { return nil },
+		"redis":    func(context.Context) error { return redisErr },
+	})
+
+	redisErr = errors.New("connection refused")
+	h.check(context.Background())
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, servingStatus(t, h, ""))
+
+	redisErr = nil
+	h.check(context.Background())
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, servingStatus(t, h, ""))
+}
+
+func TestHealth_ReportsRegisteredServices(t *testing.T) {
+	h := NewHealth(GRPCConfig{}, HealthChecks{
+		"amqp": func(context.Context) error { return errors.New("connection closed") },
+	})
+	srv := grpc.NewServer()
+	h.register(srv)
+	srv.RegisterService(&grpc.ServiceDesc{ServiceName: "test.Service", HandlerType: (*any)(nil)}, struct{}{})
+
+	h.check(context.Background())
+
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, servingStatus(t, h, "test.Service"))
+}
+
+func TestHealth_SkipsUpdateWhenCancelled(t *testing.T) {
+	h := NewHealth(GRPCConfig{}, HealthChecks{
+		"postgres": func(ctx context.Context) error { return ctx.Err() },
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	h.check(ctx)
+
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, servingStatus(t, h, ""))
+}
+
+func TestHealth_Shutdown(t *testing.T) {
+	h := NewHealth(GRPCConfig{}, HealthChecks{})
+	h.check(context.Background())
+
+	h.Shutdown()
+	h.check(context.Background())
+
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, servingStatus(t, h, ""))
+}
@@ -0,0 +1,30 @@
+package grpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// PeerIdentity returns the identity of a verified mTLS client certificate:
+// its first URI SAN (e.g. a SPIFFE ID) or, failing that, its common name.
+func PeerIdentity(ctx context.Context) (string, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.AuthInfo == nil {
+		return "", false
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return "", false
+	}
+
+	leaf := info.State.VerifiedChains[0][0]
+	if len(leaf.URIs) > 0 {
+		return leaf.URIs[0].String(), true
+	}
+	if leaf.Subject.CommonName != "" {
+		return leaf.Subject.CommonName, true
+	}
+	return "", false
+}
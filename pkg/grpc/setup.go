@@ -1,20 +1,97 @@
 package grpc
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
 	"log/slog"
+	"os"
+	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/reflection"
 )
 
 type GRPCConfig struct {
 	Port int `yaml:"port" validate:"required"`
+	// Reflection registers the server reflection service (for grpcurl and
+	// similar tools).
+	Reflection bool `yaml:"reflection"`
+	// HealthInterval is how often dependencies are checked (default: 5s).
+	HealthInterval time.Duration `yaml:"health_interval"`
+	TLS            TLSConfig     `yaml:"tls"`
 }
 
-// Setup creates a new gRPC server with the default error interceptor.
+// TLSConfig enables TLS when CertFile is set. With ClientCAFile, clients may
+// present a certificate signed by that CA (mTLS); its identity is available
+// through PeerIdentity.
+type TLSConfig struct {
+	CertFile     string `yaml:"cert_file"`
+	KeyFile      string `yaml:"key_file"`
+	ClientCAFile string `yaml:"client_ca_file"`
+	// ServiceRoles maps a client certificate identity (URI SAN or common name)
+	// to the role the service is granted.
+	ServiceRoles map[string]string `yaml:"service_roles"`
+}
+
+// Interceptors are chained in order, outermost first. They are expected to
+// include ErrorInterceptor and StreamErrorInterceptor.
+type Interceptors struct {
+	Unary  []grpc.UnaryServerInterceptor
+	Stream []grpc.StreamServerInterceptor
+}
+
+// Setup creates a new gRPC server with the given interceptors, the health
+// service and, when enabled, reflection.
 // *slog.Logger parameter ensures Wire initializes the logger before gRPC.
-func Setup(cfg GRPCConfig, _ *slog.Logger) *grpc.Server {
-	slog.Info("grpc server created", slog.Int("port", cfg.Port))
-	return grpc.NewServer(
-		grpc.ChainUnaryInterceptor(ErrorInterceptor()),
+func Setup(cfg GRPCConfig, _ *slog.Logger, interceptors Interceptors, health *Health) *grpc.Server {
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(interceptors.Unary...),
+		grpc.ChainStreamInterceptor(interceptors.Stream...),
+	}
+	if cfg.TLS.CertFile != "" {
+		creds, err := serverCredentials(cfg.TLS)
+		if err != nil {
+			panic(fmt.Sprintf("failed to load grpc tls: %v", err))
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+
+	srv := grpc.NewServer(opts...)
+	health.register(srv)
+	if cfg.Reflection {
+		reflection.Register(srv)
+	}
+
+	slog.Info("grpc server created",
+		slog.Int("port", cfg.Port),
+		slog.Bool("tls", cfg.TLS.CertFile != ""),
+		slog.Bool("reflection", cfg.Reflection),
 	)
+	return srv
+}
+
+func serverCredentials(cfg TLSConfig) (credentials.TransportCredentials, error) {
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	tlsCfg := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+
+	if cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(cfg.ClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates in %s", cfg.ClientCAFile)
+		}
+		tlsCfg.ClientCAs = pool
+		// Users authenticate with bearer tokens, so a certificate is optional;
+		// when one is presented it must verify.
+		tlsCfg.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	return credentials.NewTLS(tlsCfg), nil
 }
@@ -0,0 +1,57 @@
+package i18n
+
+import (
+	"cmp"
+	"slices"
+
+	"golang.org/x/text/language"
+)
+
+// Messages are the translations of one language. Violation messages may use
+// fmt verbs for the arguments of the violation (see apperror.FieldViolation.Args).
+type Messages struct {
+	Errors     map[string]string // by AppError code
+	Violations map[string]string // by FieldViolation reason
+}
+
+// Catalog holds the messages of the supported languages and negotiates the
+// language of a request among them. Messages a language lacks keep their
+// original text.
+type Catalog struct {
+	tags     []language.Tag
+	matcher  language.Matcher
+	messages map[language.Tag]Messages
+}
+
+// NewCatalog creates a catalog of fallback, the language of the original
+// messages, and the languages of messages.
+func NewCatalog(fallback language.Tag, messages map[language.Tag]Messages) *Catalog {
+	tags := []language.Tag{fallback}
+	for tag := range messages {
+		if tag != fallback {
+			tags = append(tags, tag)
+		}
+	}
+	slices.SortFunc(tags[1:], func(a, b language.Tag) int {
+		return cmp.Compare(a.String(), b.String())
+	})
+	return &Catalog{tags: tags, matcher: language.NewMatcher(tags), messages: messages}
+}
+
+// Languages returns the supported languages, the fallback first.
+func (c *Catalog) Languages() []language.Tag {
+	return c.tags
+}
+
+// Match returns the supported language that best fits an Accept-Language
+// header, or the fallback when none does or the header is malformed.
+func (c *Catalog) Match(acceptLanguage string) language.Tag {
+	desired, _, _ := language.ParseAcceptLanguage(acceptLanguage)
+	_, i, _ := c.matcher.Match(desired...)
+	return c.tags[i]
+}
+
+// Localizer returns the localizer of tag, a supported language.
+func (c *Catalog) Localizer(tag language.Tag) *Localizer {
+	return &Localizer{tag: tag, messages: c.messages[tag]}
+}
@@ -0,0 +1,135 @@
+//go:build unit
+
+package i18n
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"starter-boilerplate/pkg/apperror"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func testCatalog() *Catalog {
+	return NewCatalog(language.English, map[language.Tag]Messages{
+		language.Russian: {
+			Errors:     map[string]string{"not_found": "не найдено"},
+			Violations: map[string]string{"min_length": "длина должна быть не меньше %v"},
+		},
+		language.Spanish: {
+			Errors: map[string]string{"not_found": "no encontrado"},
+		},
+	})
+}
+
+func TestCatalog_Match(t *testing.T) {
+	c := testCatalog()
+
+	tests := []struct {
+		header string
+		want   language.Tag
+	}{
+		{"ru-RU,ru;q=0.9,en;q=0.8", language.Russian},
+		{"es-MX", language.Spanish},
+		{"de, es;q=0.5", language.Spanish},
+		{"fr", language.English},
+		{"", language.English},
+		{"not a header;;", language.English},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			assert.Equal(t, tt.want, c.Match(tt.header))
+		})
+	}
+}
+
+func TestLocalizer_Error(t *testing.T) {
+	l := testCatalog().Localizer(language.Russian)
+	e := apperror.New(http.StatusNotFound, "user 42 not found").
+		WithField("password", "min_length", "password must be at least 6 characters", 6).
+		WithField("name", "unknown_reason", "name is odd")
+
+	got := l.Error(e)
+
+	assert.Equal(t, "не найдено", got.Message)
+	assert.Equal(t, "длина должна быть не меньше 6", got.Details[0].Message)
+	assert.Equal(t, "name is odd", got.Details[1].Message, "untranslated reasons keep their message")
+	assert.Equal(t, "user 42 not found", e.Message, "the original is not modified")
+	assert.True(t, errors.Is(got, e))
+}
+
+func TestLocalizer_MissingArgsKeepOriginal(t *testing.T) {
+	l := testCatalog().Localizer(language.Russian)
+
+	got := l.Error(apperror.New(http.StatusBadRequest, "bad").WithField("password", "min_length", "too short"))
+
+	assert.Equal(t, "too short", got.Details[0].Message)
+}
+
+func TestLocalizer_Problem(t *testing.T) {
+	l := testCatalog().Localizer(language.Spanish)
+	p := apperror.New(http.StatusNotFound, "not found").Problem(apperror.Config{}, "req-1")
+
+	got := l.Problem(p)
+
+	assert.Equal(t, "no encontrado", got.Detail)
+	assert.Equal(t, "not_found", got.Code)
+	assert.Equal(t, "req-1", got.RequestID)
+}
+
+func TestLocalizer_NilLeavesErrors(t *testing.T) {
+	var l *Localizer
+	e := apperror.New(http.StatusNotFound, "not found")
+
+	assert.Same(t, e, l.Error(e))
+	assert.Equal(t, language.Und, l.Tag())
+	assert.Nil(t, FromContext(context.Background()))
+}
+
+func TestFromContext(t *testing.T) {
+	l := testCatalog().Localizer(language.Russian)
+
+	assert.Same(t, l, FromContext(WithLocalizer(context.Background(), l)))
+}
+
+type signup struct {
+	Email string `validate:"required,email"`
+}
+
+func TestTranslateValidation(t *testing.T) {
+	v := validator.New()
+	require.NoError(t, RegisterValidationTranslations(v))
+	err := v.Struct(signup{Email: "nope"})
+
+	tests := []struct {
+		tag  language.Tag
+		want string
+	}{
+		{language.English, "Email must be a valid email address"},
+		{language.Russian, "Email должен быть email адресом"},
+		{language.Spanish, "Email debe ser una dirección de correo electrónico válida"},
+		{language.German, "Email must be a valid email address"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag.String(), func(t *testing.T) {
+			got := TranslateValidation(tt.tag, err)
+
+			assert.Equal(t, tt.want, got.Error())
+			var verrs validator.ValidationErrors
+			assert.True(t, errors.As(got, &verrs))
+		})
+	}
+}
+
+func TestTranslateValidation_OtherErrors(t *testing.T) {
+	err := errors.New("boom")
+
+	assert.Same(t, err, TranslateValidation(language.Russian, err))
+	assert.NoError(t, TranslateValidation(language.Russian, nil))
+}
@@ -0,0 +1,86 @@
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"starter-boilerplate/pkg/apperror"
+
+	"golang.org/x/text/language"
+)
+
+// Localizer translates errors into one language. A nil Localizer leaves them
+// as they are.
+type Localizer struct {
+	tag      language.Tag
+	messages Messages
+}
+
+type localizerKey struct{}
+
+// WithLocalizer stores the localizer of the caller's language in ctx.
+func WithLocalizer(ctx context.Context, l *Localizer) context.Context {
+	return context.WithValue(ctx, localizerKey{}, l)
+}
+
+// FromContext returns the localizer stored by WithLocalizer, or nil.
+func FromContext(ctx context.Context) *Localizer {
+	l, _ := ctx.Value(localizerKey{}).(*Localizer)
+	return l
+}
+
+// Tag returns the language of l, or language.Und for nil.
+func (l *Localizer) Tag() language.Tag {
+	if l == nil {
+		return language.Und
+	}
+	return l.tag
+}
+
+// Error returns a copy of e with the message of its code and the messages of
+// its violations' reasons.
+func (l *Localizer) Error(e *apperror.AppError) *apperror.AppError {
+	if l == nil {
+		return e
+	}
+	c := e.WithMessage(l.message(e.Code, e.Message))
+	c.Details = l.violations(e.Details)
+	return c
+}
+
+// Problem is Error for problem details.
+func (l *Localizer) Problem(p *apperror.Problem) *apperror.Problem {
+	if l == nil {
+		return p
+	}
+	c := *p
+	c.Detail = l.message(p.Code, p.Detail)
+	c.Errors = l.violations(p.Errors)
+	return &c
+}
+
+func (l *Localizer) message(code, original string) string {
+	if msg, ok := l.messages.Errors[code]; ok {
+		return msg
+	}
+	return original
+}
+
+func (l *Localizer) violations(vs []apperror.FieldViolation) []apperror.FieldViolation {
+	if len(vs) == 0 {
+		return vs
+	}
+	out := make([]apperror.FieldViolation, len(vs))
+	for i, v := range vs {
+		out[i] = v
+		if msg, ok := l.messages.Violations[v.Reason]; ok && v.Reason != "" {
+			// A translation expecting other arguments than the violation has
+			// would render "%!"-markers; the original reads better.
+			if s := fmt.Sprintf(msg, v.Args()...); !strings.Contains(s, "%!") {
+				out[i].Message = s
+			}
+		}
+	}
+	return out
+}
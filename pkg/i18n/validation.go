@@ -0,0 +1,68 @@
+package i18n
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/es"
+	"github.com/go-playground/locales/ru"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	entranslations "github.com/go-playground/validator/v10/translations/en"
+	estranslations "github.com/go-playground/validator/v10/translations/es"
+	rutranslations "github.com/go-playground/validator/v10/translations/ru"
+	"golang.org/x/text/language"
+)
+
+// translators holds the validator/v10 translators; English is the fallback.
+var translators = ut.New(en.New(), en.New(), es.New(), ru.New())
+
+var validationTranslations = map[string]func(*validator.Validate, ut.Translator) error{
+	"en": entranslations.RegisterDefaultTranslations,
+	"es": estranslations.RegisterDefaultTranslations,
+	"ru": rutranslations.RegisterDefaultTranslations,
+}
+
+// RegisterValidationTranslations registers the English, Spanish and Russian
+// messages of v's validation errors, which TranslateValidation needs.
+func RegisterValidationTranslations(v *validator.Validate) error {
+	for locale, register := range validationTranslations {
+		trans, _ := translators.GetTranslator(locale)
+		if err := register(v, trans); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidationError is validator.ValidationErrors with translated messages.
+type ValidationError struct {
+	Errors   validator.ValidationErrors
+	Messages []string // one per error
+}
+
+func (e *ValidationError) Error() string {
+	return strings.Join(e.Messages, "; ")
+}
+
+// Unwrap returns the validation errors, so errors.As still finds them.
+func (e *ValidationError) Unwrap() error {
+	return e.Errors
+}
+
+// TranslateValidation translates validation errors into the language of tag,
+// or English when it has no translations; other errors are returned as is.
+func TranslateValidation(tag language.Tag, err error) error {
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return err
+	}
+	base, _ := tag.Base()
+	trans, _ := translators.GetTranslator(base.String())
+	msgs := make([]string, len(verrs))
+	for i, fe := range verrs {
+		msgs[i] = fe.Translate(trans)
+	}
+	return &ValidationError{Errors: verrs, Messages: msgs}
+}
@@ -0,0 +1,116 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultQueue is the queue of jobs enqueued without WithQueue.
+const DefaultQueue = "default"
+
+// Args is a job payload that knows its kind. The kind selects the handler
+// registered with Register; the payload is stored as JSON.
+type Args interface {
+	Kind() string
+}
+
+// Enqueuer stores jobs for background processing.
+type Enqueuer interface {
+	Enqueue(ctx context.Context, args Args, opts ...Option) (int64, error)
+}
+
+// ErrDuplicate is returned by Enqueue when a pending or running job already
+// holds the unique key.
+var ErrDuplicate = errors.New("jobs: a job with this unique key is already pending or running")
+
+// Option customizes an enqueued job.
+type Option func(*Job)
+
+// WithQueue puts the job on a queue other than DefaultQueue. Each queue has
+// its own workers (Config.Queues), so slow jobs do not hold up other queues.
+func WithQueue(queue string) Option {
+	return func(j *Job) {
+		j.Queue = queue
+	}
+}
+
+// WithPriority sets the job priority. Within a queue, higher priorities are
+// claimed first. Default: 0.
+func WithPriority(priority int) Option {
+	return func(j *Job) {
+		j.Priority = priority
+	}
+}
+
+// WithUniqueKey rejects the job with ErrDuplicate while another job with the
+// same key is pending or running. Finished jobs release the key.
+func WithUniqueKey(key string) Option {
+	return func(j *Job) {
+		j.UniqueKey = key
+	}
+}
+
+// WithRunAt delays the job until t. It is rounded up to a whole second.
+func WithRunAt(t time.Time) Option {
+	return func(j *Job) {
+		j.RunAt = t.Add(time.Second - time.Nanosecond).Unix()
+	}
+}
+
+// WithMaxAttempts overrides Config.MaxAttempts for the job.
+func WithMaxAttempts(n int) Option {
+	return func(j *Job) {
+		j.MaxAttempts = n
+	}
+}
+
+type jobInserter interface {
+	Insert(ctx context.Context, job *Job) (bool, error)
+}
+
+// Client implements Enqueuer by inserting jobs into the jobs table.
+// It relies on the transaction being present in context (via pkgdb.WithTx),
+// so a job is stored only if the use case commits.
+type Client struct {
+	repo jobInserter
+	cfg  Config
+	now  func() time.Time
+}
+
+func NewClient(repo *Repository, cfg Config) *Client {
+	return &Client{repo: repo, cfg: cfg.withDefaults(), now: time.Now}
+}
+
+func (c *Client) Enqueue(ctx context.Context, args Args, opts ...Option) (int64, error) {
+	payload, err := json.Marshal(args)
+	if err != nil {
+		return 0, fmt.Errorf("jobs: marshal %s: %w", args.Kind(), err)
+	}
+
+	now := c.now().Unix()
+	job := &Job{
+		Queue:       DefaultQueue,
+		Kind:        args.Kind(),
+		Payload:     payload,
+		State:       StatePending,
+		MaxAttempts: c.cfg.MaxAttempts,
+		RunAt:       now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	for _, opt := range opts {
+		opt(job)
+	}
+
+	inserted, err := c.repo.Insert(ctx, job)
+	if err != nil {
+		return 0, fmt.Errorf("jobs: enqueue %s: %w", job.Kind, err)
+	}
+	if !inserted {
+		return 0, fmt.Errorf("jobs: enqueue %s (key %s): %w", job.Kind, job.UniqueKey, ErrDuplicate)
+	}
+	return job.ID, nil
+}
@@ -0,0 +1,114 @@
+//go:build unit
+
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type exportArgs struct {
+	UserID string `json:"user_id"`
+}
+
+func (exportArgs) Kind() string { return "export" }
+
+type fakeInserter struct {
+	jobs []Job
+	keys map[string]bool
+}
+
+func (f *fakeInserter) Insert(_ context.Context, job *Job) (bool, error) {
+	if job.UniqueKey != "" {
+		if f.keys[job.UniqueKey] {
+			return false, nil
+		}
+		if f.keys == nil {
+			f.keys = make(map[string]bool)
+		}
+		f.keys[job.UniqueKey] = true
+	}
+	job.ID = int64(len(f.jobs) + 1)
+	f.jobs = append(f.jobs, *job)
+	return true, nil
+}
+
+func newTestClient(repo *fakeInserter) *Client {
+	return &Client{
+		repo: repo,
+		cfg:  Config{MaxAttempts: 3}.withDefaults(),
+		now:  func() time.Time { return time.Unix(1000, 0) },
+	}
+}
+
+func TestEnqueue_Defaults(t *testing.T) {
+	repo := &fakeInserter{}
+	c := newTestClient(repo)
+
+	id, err := c.Enqueue(context.Background(), exportArgs{UserID: "u1"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), id)
+
+	require.Len(t, repo.jobs, 1)
+	job := repo.jobs[0]
+	assert.Equal(t, DefaultQueue, job.Queue)
+	assert.Equal(t, "export", job.Kind)
+	assert.JSONEq(t, `{"user_id":"u1"}`, string(job.Payload))
+	assert.Equal(t, StatePending, job.State)
+	assert.Equal(t, 3, job.MaxAttempts)
+	assert.Equal(t, int64(1000), job.RunAt)
+	assert.Empty(t, job.UniqueKey)
+}
+
+func TestEnqueue_Options(t *testing.T) {
+	repo := &fakeInserter{}
+	c := newTestClient(repo)
+
+	_, err := c.Enqueue(context.Background(), exportArgs{},
+		WithQueue("exports"),
+		WithPriority(5),
+		WithMaxAttempts(1),
+		WithRunAt(time.Unix(2000, 1)),
+	)
+	require.NoError(t, err)
+
+	job := repo.jobs[0]
+	assert.Equal(t, "exports", job.Queue)
+	assert.Equal(t, 5, job.Priority)
+	assert.Equal(t, 1, job.MaxAttempts)
+	assert.Equal(t, int64(2001), job.RunAt, "rounded up to a whole second")
+}
+
+func TestEnqueue_UniqueKey(t *testing.T) {
+	repo := &fakeInserter{}
+	c := newTestClient(repo)
+
+	_, err := c.Enqueue(context.Background(), exportArgs{UserID: "u1"}, WithUniqueKey("export:u1"))
+	require.NoError(t, err)
+
+	_, err = c.Enqueue(context.Background(), exportArgs{UserID: "u1"}, WithUniqueKey("export:u1"))
+	assert.ErrorIs(t, err, ErrDuplicate)
+
+	_, err = c.Enqueue(context.Background(), exportArgs{UserID: "u2"}, WithUniqueKey("export:u2"))
+	require.NoError(t, err)
+	assert.Len(t, repo.jobs, 2)
+}
+
+func TestEnqueue_MarshalError(t *testing.T) {
+	c := newTestClient(&fakeInserter{})
+
+	_, err := c.Enqueue(context.Background(), badArgs{Ch: make(chan int)})
+	var jsonErr *json.UnsupportedTypeError
+	assert.ErrorAs(t, err, &jsonErr)
+}
+
+type badArgs struct {
+	Ch chan int
+}
+
+func (badArgs) Kind() string { return "bad" }
@@ -0,0 +1,37 @@
+package jobs
+
+import (
+	"encoding/json"
+
+	"github.com/uptrace/bun"
+)
+
+// State is the lifecycle state of a job.
+type State string
+
+const (
+	StatePending   State = "pending"   // waiting for RunAt, including between retries
+	StateRunning   State = "running"   // claimed by a worker until LockedUntil
+	StateSucceeded State = "succeeded" // handler returned nil
+	StateFailed    State = "failed"    // attempts exhausted or permanent error
+)
+
+// Job is a jobs table row.
+type Job struct {
+	bun.BaseModel `bun:"table:jobs"`
+
+	ID          int64           `bun:"id,pk,autoincrement"`
+	Queue       string          `bun:"queue,notnull"`
+	Kind        string          `bun:"kind,notnull"`
+	Payload     json.RawMessage `bun:"payload,type:jsonb,notnull"`
+	Priority    int             `bun:"priority,notnull"`    // higher is claimed first within a queue
+	UniqueKey   string          `bun:"unique_key,nullzero"` // at most one pending or running job per key
+	State       State           `bun:"state,notnull"`
+	Attempts    int             `bun:"attempts,notnull"`
+	MaxAttempts int             `bun:"max_attempts,notnull"`
+	RunAt       int64           `bun:"run_at,notnull"`       // unix seconds; not claimed before
+	LockedUntil int64           `bun:"locked_until,notnull"` // unix seconds; a running job is reclaimed after
+	LastError   string          `bun:"last_error,notnull"`
+	CreatedAt   int64           `bun:"created_at,notnull"`
+	UpdatedAt   int64           `bun:"updated_at,notnull"`
+}
@@ -0,0 +1,129 @@
+package jobs
+
+import (
+	"context"
+
+	pkgdb "starter-boilerplate/pkg/db"
+
+	"github.com/uptrace/bun"
+)
+
+// Repository handles jobs table operations.
+type Repository struct {
+	db *bun.DB
+}
+
+func NewRepository(db *bun.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// Insert adds job within the current tx (or fallback db), sets its ID and
+// reports whether it was inserted. A job whose unique key is held by a pending
+// or running job is not inserted.
+func (r *Repository) Insert(ctx context.Context, job *Job) (bool, error) {
+	res, err := pkgdb.Conn(ctx, r.db).NewInsert().
+		Model(job).
+		ExcludeColumn("id").
+		On("CONFLICT (unique_key) WHERE state IN ('pending', 'running') DO NOTHING").
+		Returning("id").
+		Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// Claim marks up to limit jobs of queue with one of kinds as running until
+// lockedUntil and returns them, highest priority first. A job is claimable when
+// it is pending and due at now, or running with an expired lock (its worker
+// died or hung) and attempts left. Claiming counts an attempt. Expired jobs
+// without attempts left are failed first, so a job that keeps killing its
+// worker is not retried forever.
+func (r *Repository) Claim(ctx context.Context, queue string, kinds []string, now, lockedUntil int64, limit int) ([]Job, error) {
+	conn := pkgdb.Conn(ctx, r.db)
+	_, err := conn.NewUpdate().
+		Model((*Job)(nil)).
+		Set("state = ?", StateFailed).
+		Set("last_error = ?", "lock expired on the last attempt").
+		Set("updated_at = ?", now).
+		Where("queue = ?", queue).
+		Where("kind IN (?)", bun.In(kinds)).
+		Where("state = ? AND locked_until <= ?", StateRunning, now).
+		Where("attempts >= max_attempts").
+		Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := conn.NewSelect().
+		Model((*Job)(nil)).
+		Column("id").
+		Where("queue = ?", queue).
+		Where("kind IN (?)", bun.In(kinds)).
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.
+				Where("state = ? AND run_at <= ?", StatePending, now).
+				WhereOr("state = ? AND locked_until <= ? AND attempts < max_attempts", StateRunning, now)
+		}).
+		OrderExpr("priority DESC, run_at ASC, id ASC").
+		Limit(limit).
+		For("UPDATE SKIP LOCKED")
+
+	var jobs []Job
+	_, err = conn.NewUpdate().
+		Model((*Job)(nil)).
+		Set("state = ?", StateRunning).
+		Set("attempts = attempts + 1").
+		Set("locked_until = ?", lockedUntil).
+		Set("updated_at = ?", now).
+		Where("id IN (?)", ids).
+		Returning("*").
+		Exec(ctx, &jobs)
+	return jobs, err
+}
+
+// Update saves the outcome of a run of job.
+func (r *Repository) Update(ctx context.Context, job *Job) error {
+	_, err := pkgdb.Conn(ctx, r.db).NewUpdate().
+		Model(job).
+		Column("state", "attempts", "run_at", "locked_until", "last_error", "updated_at").
+		WherePK().
+		Exec(ctx)
+	return err
+}
+
+// Get returns the job with the given ID. Returns sql.ErrNoRows if there is none.
+func (r *Repository) Get(ctx context.Context, id int64) (*Job, error) {
+	job := new(Job)
+	if err := pkgdb.Conn(ctx, r.db).NewSelect().Model(job).Where("id = ?", id).Scan(ctx); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// ListFailed returns up to limit failed jobs, of queue only if it is not
+// empty, most recently failed first.
+func (r *Repository) ListFailed(ctx context.Context, queue string, limit int) ([]Job, error) {
+	var jobs []Job
+	q := pkgdb.Conn(ctx, r.db).NewSelect().Model(&jobs).Where("state = ?", StateFailed)
+	if queue != "" {
+		q = q.Where("queue = ?", queue)
+	}
+	err := q.OrderExpr("updated_at DESC, id DESC").Limit(limit).Scan(ctx)
+	return jobs, err
+}
+
+// DeleteSucceededBefore removes succeeded jobs finished before the given unix
+// time and returns how many were deleted. Failed jobs are kept for inspection.
+func (r *Repository) DeleteSucceededBefore(ctx context.Context, before int64) (int64, error) {
+	res, err := pkgdb.Conn(ctx, r.db).NewDelete().
+		Model((*Job)(nil)).
+		Where("state = ?", StateSucceeded).
+		Where("updated_at < ?", before).
+		Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,216 @@
+//go:build integration
+
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	pkgdb "starter-boilerplate/pkg/db"
+	"starter-boilerplate/pkg/testcontainer"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type JobsRepoSuite struct {
+	suite.Suite
+	pg   *testcontainer.PgContainer
+	repo *Repository
+}
+
+func TestJobsRepository(t *testing.T) {
+	if err := os.Chdir("../.."); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+
+	pg, err := testcontainer.SetupPgContainer(context.Background(), &testcontainer.PgContainer{
+		Database: "testdb",
+		Username: "testuser",
+		Password: "testpass",
+		HostPort: "25434",
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "setup pg container: %v\n", err)
+		os.Exit(1)
+	}
+
+	suite.Run(t, &JobsRepoSuite{pg: pg, repo: NewRepository(pg.DB())})
+}
+
+func (s *JobsRepoSuite) TearDownSuite() {
+	s.pg.Close()
+	s.pg.Terminate(context.Background())
+}
+
+func (s *JobsRepoSuite) SetupTest() {
+	s.Require().NoError(s.pg.Clean(context.Background()))
+}
+
+func newRepoJob(kind string, priority int, runAt int64) *Job {
+	return &Job{
+		Queue:       DefaultQueue,
+		Kind:        kind,
+		Payload:     []byte(`{}`),
+		Priority:    priority,
+		State:       StatePending,
+		MaxAttempts: 3,
+		RunAt:       runAt,
+	}
+}
+
+func (s *JobsRepoSuite) insert(job *Job) {
+	inserted, err := s.repo.Insert(context.Background(), job)
+	s.Require().NoError(err)
+	s.Require().True(inserted)
+}
+
+func (s *JobsRepoSuite) TestInsert_UniqueKeyWhilePendingOrRunning() {
+	ctx := context.Background()
+
+	first := newRepoJob("export", 0, 0)
+	first.UniqueKey = "export:u1"
+	s.insert(first)
+
+	dup := newRepoJob("export", 0, 0)
+	dup.UniqueKey = "export:u1"
+	inserted, err := s.repo.Insert(ctx, dup)
+	s.Require().NoError(err)
+	s.False(inserted)
+
+	first.State = StateSucceeded
+	s.Require().NoError(s.repo.Update(ctx, first))
+
+	inserted, err = s.repo.Insert(ctx, dup)
+	s.Require().NoError(err)
+	s.True(inserted, "a finished job releases its key")
+}
+
+func (s *JobsRepoSuite) TestInsert_JobsWithoutKeyDoNotConflict() {
+	s.insert(newRepoJob("export", 0, 0))
+	s.insert(newRepoJob("export", 0, 0))
+}
+
+func (s *JobsRepoSuite) TestClaim_DueByPriority() {
+	ctx := context.Background()
+	low := newRepoJob("export", 0, 10)
+	high := newRepoJob("export", 5, 10)
+	later := newRepoJob("export", 9, 200)
+	otherKind := newRepoJob("crm_sync", 9, 10)
+	for _, j := range []*Job{low, high, later, otherKind} {
+		s.insert(j)
+	}
+
+	claimed, err := s.repo.Claim(ctx, DefaultQueue, []string{"export"}, 100, 500, 1)
+	s.Require().NoError(err)
+	s.Require().Len(claimed, 1)
+	s.Equal(high.ID, claimed[0].ID)
+	s.Equal(StateRunning, claimed[0].State)
+	s.Equal(1, claimed[0].Attempts)
+	s.Equal(int64(500), claimed[0].LockedUntil)
+
+	claimed, err = s.repo.Claim(ctx, DefaultQueue, []string{"export"}, 100, 500, 10)
+	s.Require().NoError(err)
+	s.Require().Len(claimed, 1)
+	s.Equal(low.ID, claimed[0].ID)
+}
+
+func (s *JobsRepoSuite) TestClaim_OtherQueue() {
+	job := newRepoJob("export", 0, 0)
+	job.Queue = "exports"
+	s.insert(job)
+
+	claimed, err := s.repo.Claim(context.Background(), DefaultQueue, []string{"export"}, 100, 500, 10)
+	s.Require().NoError(err)
+	s.Empty(claimed)
+}
+
+func (s *JobsRepoSuite) TestClaim_ReclaimsExpiredLock() {
+	ctx := context.Background()
+	s.insert(newRepoJob("export", 0, 0))
+
+	claimed, err := s.repo.Claim(ctx, DefaultQueue, []string{"export"}, 100, 200, 10)
+	s.Require().NoError(err)
+	s.Require().Len(claimed, 1)
+
+	claimed, err = s.repo.Claim(ctx, DefaultQueue, []string{"export"}, 150, 300, 10)
+	s.Require().NoError(err)
+	s.Empty(claimed, "still locked")
+
+	claimed, err = s.repo.Claim(ctx, DefaultQueue, []string{"export"}, 200, 300, 10)
+	s.Require().NoError(err)
+	s.Require().Len(claimed, 1)
+	s.Equal(2, claimed[0].Attempts)
+}
+
+func (s *JobsRepoSuite) TestClaim_FailsExpiredLockWithoutAttemptsLeft() {
+	ctx := context.Background()
+	job := newRepoJob("export", 0, 0)
+	s.insert(job)
+
+	// The worker dies on every attempt: each claim expires unfinished.
+	for i, now := range []int64{100, 200, 300} {
+		claimed, err := s.repo.Claim(ctx, DefaultQueue, []string{"export"}, now, now+50, 10)
+		s.Require().NoError(err)
+		s.Require().Len(claimed, 1)
+		s.Equal(i+1, claimed[0].Attempts)
+	}
+
+	claimed, err := s.repo.Claim(ctx, DefaultQueue, []string{"export"}, 400, 450, 10)
+	s.Require().NoError(err)
+	s.Empty(claimed, "max_attempts reached")
+
+	got, err := s.repo.Get(ctx, job.ID)
+	s.Require().NoError(err)
+	s.Equal(StateFailed, got.State)
+	s.Equal(3, got.Attempts)
+	s.Equal("lock expired on the last attempt", got.LastError)
+}
+
+func (s *JobsRepoSuite) TestClaim_SkipsLockedRows() {
+	ctx := context.Background()
+	s.insert(newRepoJob("export", 0, 0))
+	s.insert(newRepoJob("export", 0, 0))
+
+	uow := pkgdb.NewUnitOfWork(s.pg.DB())
+	err := uow.Do(ctx, func(txCtx context.Context) error {
+		claimed, err := s.repo.Claim(txCtx, DefaultQueue, []string{"export"}, 100, 500, 1)
+		s.Require().NoError(err)
+		s.Require().Len(claimed, 1)
+
+		other, err := s.repo.Claim(ctx, DefaultQueue, []string{"export"}, 100, 500, 10)
+		s.Require().NoError(err)
+		s.Require().Len(other, 1)
+		s.NotEqual(claimed[0].ID, other[0].ID)
+		return nil
+	})
+	s.Require().NoError(err)
+}
+
+func (s *JobsRepoSuite) TestListFailed_AndDeleteSucceeded() {
+	ctx := context.Background()
+	failed := newRepoJob("export", 0, 0)
+	failed.State, failed.UpdatedAt = StateFailed, 10
+	succeeded := newRepoJob("export", 0, 0)
+	succeeded.State, succeeded.UpdatedAt = StateSucceeded, 10
+	s.insert(failed)
+	s.insert(succeeded)
+
+	list, err := s.repo.ListFailed(ctx, "", 10)
+	s.Require().NoError(err)
+	s.Require().Len(list, 1)
+	s.Equal(failed.ID, list[0].ID)
+
+	list, err = s.repo.ListFailed(ctx, "exports", 10)
+	s.Require().NoError(err)
+	s.Empty(list)
+
+	n, err := s.repo.DeleteSucceededBefore(ctx, 20)
+	s.Require().NoError(err)
+	s.Equal(int64(1), n)
+
+	got, err := s.repo.Get(ctx, failed.ID)
+	s.Require().NoError(err)
+	s.Equal(StateFailed, got.State)
+}
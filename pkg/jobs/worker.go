@@ -0,0 +1,284 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"runtime/debug"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Config controls enqueueing defaults and the workers.
+type Config struct {
+	// Queues maps each queue to the number of jobs processed concurrently
+	// from it. Default: {default: 10}.
+	Queues       map[string]int `yaml:"queues"`
+	PollInterval time.Duration  `yaml:"poll_interval"` // default: 1s
+	// JobTimeout cancels a handler's context. A running job whose worker
+	// died is reclaimed one minute after it. Default: 5m.
+	JobTimeout  time.Duration `yaml:"job_timeout"`
+	MaxAttempts int           `yaml:"max_attempts"` // default: 10
+	// A failed attempt n is retried after BackoffMin * 2^(n-1), capped at
+	// BackoffMax. Defaults: 10s, 1h.
+	BackoffMin time.Duration `yaml:"backoff_min"`
+	BackoffMax time.Duration `yaml:"backoff_max"`
+}
+
+func (c Config) withDefaults() Config {
+	if len(c.Queues) == 0 {
+		c.Queues = map[string]int{DefaultQueue: 10}
+	}
+	if c.PollInterval == 0 {
+		c.PollInterval = time.Second
+	}
+	if c.JobTimeout == 0 {
+		c.JobTimeout = 5 * time.Minute
+	}
+	if c.MaxAttempts == 0 {
+		c.MaxAttempts = 10
+	}
+	if c.BackoffMin == 0 {
+		c.BackoffMin = 10 * time.Second
+	}
+	if c.BackoffMax == 0 {
+		c.BackoffMax = time.Hour
+	}
+	return c
+}
+
+// backoff returns the delay before retrying a job that failed its attempt-th attempt.
+func (c Config) backoff(attempt int) time.Duration {
+	d := c.BackoffMin
+	for i := 1; i < attempt && d < c.BackoffMax; i++ {
+		d *= 2
+	}
+	return min(d, c.BackoffMax)
+}
+
+// lockMargin is how long after JobTimeout a running job stays locked, so a
+// live worker always records the outcome before the job can be reclaimed.
+const lockMargin = time.Minute
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so that returning it from a handler fails the job
+// without further retries.
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+type handler func(ctx context.Context, job *Job) error
+
+type jobStore interface {
+	Claim(ctx context.Context, queue string, kinds []string, now, lockedUntil int64, limit int) ([]Job, error)
+	Update(ctx context.Context, job *Job) error
+}
+
+// Worker claims jobs from the queues in Config.Queues and runs the handlers
+// registered for their kinds. Jobs of kinds without a handler are left for
+// other processes, so a rolling deploy can add new kinds.
+type Worker struct {
+	store jobStore
+	cfg   Config
+	now   func() time.Time
+
+	handlers map[string]handler
+	running  sync.WaitGroup
+
+	mu         sync.Mutex
+	cancelJobs context.CancelFunc
+	idle       chan struct{} // closed when Run returns
+}
+
+func NewWorker(repo *Repository, cfg Config) *Worker {
+	return &Worker{
+		store:    repo,
+		cfg:      cfg.withDefaults(),
+		now:      time.Now,
+		handlers: make(map[string]handler),
+		idle:     make(chan struct{}),
+	}
+}
+
+// Register adds the handler for jobs of kind T.Kind(). The payload is decoded
+// into T; a payload that does not decode fails the job without retries.
+// Call it during setup, before Run. Panics if the kind already has a handler.
+func Register[T Args](w *Worker, fn func(ctx context.Context, args T, job *Job) error) {
+	var zero T
+	kind := zero.Kind()
+	if _, ok := w.handlers[kind]; ok {
+		panic(fmt.Sprintf("jobs: kind %s registered twice", kind))
+	}
+
+	w.handlers[kind] = func(ctx context.Context, job *Job) error {
+		var args T
+		if err := json.Unmarshal(job.Payload, &args); err != nil {
+			return Permanent(fmt.Errorf("unmarshal: %w", err))
+		}
+		return fn(ctx, args, job)
+	}
+}
+
+// Run polls every queue until ctx is cancelled, then waits for the started
+// jobs (see Shutdown).
+func (w *Worker) Run(ctx context.Context) error {
+	defer close(w.idle)
+
+	if len(w.handlers) == 0 {
+		return nil
+	}
+	kinds := slices.Sorted(maps.Keys(w.handlers))
+
+	jobsCtx, cancelJobs := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelJobs()
+	w.mu.Lock()
+	w.cancelJobs = cancelJobs
+	w.mu.Unlock()
+
+	slog.Info("job worker started",
+		slog.Any("queues", w.cfg.Queues),
+		slog.Any("kinds", kinds),
+		slog.Duration("poll_interval", w.cfg.PollInterval),
+	)
+
+	var pollers sync.WaitGroup
+	for queue, concurrency := range w.cfg.Queues {
+		pollers.Go(func() {
+			w.poll(ctx, jobsCtx, queue, concurrency, kinds)
+		})
+	}
+	pollers.Wait()
+	w.running.Wait()
+	return nil
+}
+
+// Shutdown waits for running jobs until ctx is done, then cancels them; they
+// are returned to their queue without counting the attempt. It returns once
+// Run has returned.
+func (w *Worker) Shutdown(ctx context.Context) {
+	select {
+	case <-w.idle:
+		return
+	case <-ctx.Done():
+	}
+
+	w.mu.Lock()
+	if w.cancelJobs != nil {
+		slog.Warn("job worker: shutdown deadline reached, cancelling running jobs")
+		w.cancelJobs()
+	}
+	w.mu.Unlock()
+	<-w.idle
+}
+
+// poll claims jobs of queue on a ticker while fewer than concurrency are running.
+func (w *Worker) poll(ctx, jobsCtx context.Context, queue string, concurrency int, kinds []string) {
+	slots := make(chan struct{}, concurrency)
+
+	ticker := time.NewTicker(w.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		free := concurrency - len(slots)
+		if free == 0 {
+			continue
+		}
+
+		now := w.now()
+		claimed, err := w.store.Claim(ctx, queue, kinds, now.Unix(), now.Add(w.cfg.JobTimeout+lockMargin).Unix(), free)
+		if err != nil {
+			if ctx.Err() == nil {
+				slog.Error("job claim failed", slog.String("queue", queue), slog.Any("error", err))
+			}
+			continue
+		}
+
+		for i := range claimed {
+			slots <- struct{}{}
+			w.running.Add(1)
+			go func() {
+				defer w.running.Done()
+				defer func() { <-slots }()
+				w.process(jobsCtx, &claimed[i])
+			}()
+		}
+	}
+}
+
+// process runs job and saves the outcome: succeeded, retried with backoff,
+// failed, or released back to the queue if the worker is shutting down.
+func (w *Worker) process(ctx context.Context, job *Job) {
+	jctx, cancel := context.WithTimeout(ctx, w.cfg.JobTimeout)
+	err := safeRun(jctx, job, w.handlers[job.Kind])
+	cancel()
+
+	log := slog.With(
+		slog.Int64("job_id", job.ID),
+		slog.String("kind", job.Kind),
+		slog.String("queue", job.Queue),
+		slog.Int("attempt", job.Attempts),
+	)
+
+	now := w.now()
+	job.LockedUntil = 0
+	job.UpdatedAt = now.Unix()
+
+	var permanent *permanentError
+	switch {
+	case err == nil:
+		job.State = StateSucceeded
+		job.LastError = ""
+		log.Debug("job succeeded")
+	case ctx.Err() != nil:
+		job.State = StatePending
+		job.Attempts--
+		job.RunAt = now.Unix()
+		log.Warn("job cancelled by shutdown, released", slog.Any("error", err))
+	case errors.As(err, &permanent) || job.Attempts >= job.MaxAttempts:
+		job.State = StateFailed
+		job.LastError = err.Error()
+		log.Error("job failed", slog.Any("error", err))
+	default:
+		job.State = StatePending
+		job.LastError = err.Error()
+		job.RunAt = now.Add(w.cfg.backoff(job.Attempts)).Unix()
+		log.Warn("job attempt failed, retrying", slog.Any("error", err), slog.Time("retry_at", time.Unix(job.RunAt, 0)))
+	}
+
+	uctx, ucancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer ucancel()
+	if err := w.store.Update(uctx, job); err != nil {
+		log.Error("job save outcome failed", slog.Any("error", err))
+	}
+}
+
+func safeRun(ctx context.Context, job *Job, fn handler) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("job panic",
+				slog.Int64("job_id", job.ID),
+				slog.String("kind", job.Kind),
+				slog.Any("panic", r),
+				slog.String("stack", string(debug.Stack())),
+			)
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return fn(ctx, job)
+}
@@ -0,0 +1,294 @@
+//go:build unit
+
+package jobs
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStore hands out the queued jobs once each and records saved outcomes.
+type fakeStore struct {
+	mu      sync.Mutex
+	pending []Job
+	limits  []int
+	saved   map[int64]Job
+}
+
+func (s *fakeStore) add(jobs ...Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending = append(s.pending, jobs...)
+}
+
+func (s *fakeStore) Claim(_ context.Context, queue string, kinds []string, _, lockedUntil int64, limit int) ([]Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limits = append(s.limits, limit)
+
+	var claimed, rest []Job
+	for _, j := range s.pending {
+		if len(claimed) < limit && j.Queue == queue && slices.Contains(kinds, j.Kind) {
+			j.State = StateRunning
+			j.Attempts++
+			j.LockedUntil = lockedUntil
+			claimed = append(claimed, j)
+			continue
+		}
+		rest = append(rest, j)
+	}
+	s.pending = rest
+	return claimed, nil
+}
+
+func (s *fakeStore) Update(_ context.Context, job *Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.saved == nil {
+		s.saved = make(map[int64]Job)
+	}
+	s.saved[job.ID] = *job
+	return nil
+}
+
+func (s *fakeStore) outcome(id int64) (Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.saved[id]
+	return j, ok
+}
+
+func newTestWorker(store *fakeStore, cfg Config) *Worker {
+	cfg.PollInterval = 5 * time.Millisecond
+	return &Worker{
+		store:    store,
+		cfg:      cfg.withDefaults(),
+		now:      func() time.Time { return time.Unix(1000, 0) },
+		handlers: make(map[string]handler),
+		idle:     make(chan struct{}),
+	}
+}
+
+func newTestJob(id int64, attempts, maxAttempts int) Job {
+	return Job{
+		ID:          id,
+		Queue:       DefaultQueue,
+		Kind:        "export",
+		Payload:     []byte(`{"user_id":"u1"}`),
+		State:       StatePending,
+		Attempts:    attempts,
+		MaxAttempts: maxAttempts,
+	}
+}
+
+// runUntil runs w until cond holds, then stops it.
+func runUntil(t *testing.T, w *Worker, cond func() bool) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- w.Run(ctx) }()
+
+	require.Eventually(t, cond, time.Second, time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestWorker_Succeeds(t *testing.T) {
+	store := &fakeStore{}
+	store.add(newTestJob(1, 0, 3))
+	w := newTestWorker(store, Config{})
+
+	var got exportArgs
+	Register(w, func(_ context.Context, args exportArgs, job *Job) error {
+		got = args
+		return nil
+	})
+
+	runUntil(t, w, func() bool { _, ok := store.outcome(1); return ok })
+
+	job, _ := store.outcome(1)
+	assert.Equal(t, StateSucceeded, job.State)
+	assert.Equal(t, 1, job.Attempts)
+	assert.Zero(t, job.LockedUntil)
+	assert.Equal(t, "u1", got.UserID)
+}
+
+func TestWorker_RetriesWithBackoff(t *testing.T) {
+	store := &fakeStore{}
+	store.add(newTestJob(1, 2, 5))
+	w := newTestWorker(store, Config{BackoffMin: 10 * time.Second})
+
+	Register(w, func(context.Context, exportArgs, *Job) error { return errors.New("third party down") })
+
+	runUntil(t, w, func() bool { _, ok := store.outcome(1); return ok })
+
+	job, _ := store.outcome(1)
+	assert.Equal(t, StatePending, job.State)
+	assert.Equal(t, 3, job.Attempts)
+	assert.Equal(t, int64(1000+40), job.RunAt, "third attempt waits 10s * 2^2")
+	assert.Equal(t, "third party down", job.LastError)
+}
+
+func TestWorker_FailsAfterMaxAttempts(t *testing.T) {
+	store := &fakeStore{}
+	store.add(newTestJob(1, 2, 3))
+	w := newTestWorker(store, Config{})
+
+	Register(w, func(context.Context, exportArgs, *Job) error { return errors.New("boom") })
+
+	runUntil(t, w, func() bool { _, ok := store.outcome(1); return ok })
+
+	job, _ := store.outcome(1)
+	assert.Equal(t, StateFailed, job.State)
+	assert.Equal(t, "boom", job.LastError)
+}
+
+func TestWorker_PermanentErrorFailsImmediately(t *testing.T) {
+	store := &fakeStore{}
+	store.add(newTestJob(1, 0, 10), Job{ID: 2, Queue: DefaultQueue, Kind: "export", Payload: []byte(`"not an object"`), MaxAttempts: 10})
+	w := newTestWorker(store, Config{})
+
+	Register(w, func(context.Context, exportArgs, *Job) error { return Permanent(errors.New("user deleted")) })
+
+	runUntil(t, w, func() bool {
+		_, ok1 := store.outcome(1)
+		_, ok2 := store.outcome(2)
+		return ok1 && ok2
+	})
+
+	job, _ := store.outcome(1)
+	assert.Equal(t, StateFailed, job.State)
+	assert.Equal(t, "user deleted", job.LastError)
+
+	job, _ = store.outcome(2)
+	assert.Equal(t, StateFailed, job.State, "undecodable payload is not retried")
+	assert.Contains(t, job.LastError, "unmarshal")
+}
+
+func TestWorker_RecoversPanic(t *testing.T) {
+	store := &fakeStore{}
+	store.add(newTestJob(1, 0, 3))
+	w := newTestWorker(store, Config{})
+
+	Register(w, func(context.Context, exportArgs, *Job) error { panic("nil map") })
+
+	runUntil(t, w, func() bool { _, ok := store.outcome(1); return ok })
+
+	job, _ := store.outcome(1)
+	assert.Equal(t, StatePending, job.State)
+	assert.Equal(t, "panic: nil map", job.LastError)
+}
+
+func TestWorker_ClaimsOnlyRegisteredKinds(t *testing.T) {
+	store := &fakeStore{}
+	other := newTestJob(2, 0, 3)
+	other.Kind = "unknown"
+	store.add(newTestJob(1, 0, 3), other)
+	w := newTestWorker(store, Config{})
+
+	Register(w, func(context.Context, exportArgs, *Job) error { return nil })
+
+	runUntil(t, w, func() bool { _, ok := store.outcome(1); return ok })
+
+	_, ok := store.outcome(2)
+	assert.False(t, ok)
+	assert.Len(t, store.pending, 1)
+}
+
+func TestWorker_RespectsQueueConcurrency(t *testing.T) {
+	store := &fakeStore{}
+	for id := int64(1); id <= 6; id++ {
+		store.add(newTestJob(id, 0, 3))
+	}
+	w := newTestWorker(store, Config{Queues: map[string]int{DefaultQueue: 2}})
+
+	var running, peak atomic.Int32
+	release := make(chan struct{})
+	Register(w, func(context.Context, exportArgs, *Job) error {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		<-release
+		running.Add(-1)
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- w.Run(ctx) }()
+
+	require.Eventually(t, func() bool { return running.Load() == 2 }, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, int32(2), peak.Load())
+
+	close(release)
+	require.Eventually(t, func() bool { _, ok := store.outcome(6); return ok }, time.Second, time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+	assert.Equal(t, int32(2), peak.Load())
+}
+
+func TestWorker_ShutdownReleasesCancelledJobs(t *testing.T) {
+	store := &fakeStore{}
+	store.add(newTestJob(1, 0, 3))
+	w := newTestWorker(store, Config{})
+
+	started := make(chan struct{})
+	Register(w, func(ctx context.Context, _ exportArgs, _ *Job) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- w.Run(ctx) }()
+	<-started
+	cancel()
+
+	sctx, scancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer scancel()
+	w.Shutdown(sctx)
+	require.NoError(t, <-done)
+
+	job, ok := store.outcome(1)
+	require.True(t, ok)
+	assert.Equal(t, StatePending, job.State)
+	assert.Equal(t, 0, job.Attempts, "the cancelled attempt is not counted")
+	assert.Empty(t, job.LastError)
+}
+
+func TestWorker_NoHandlers(t *testing.T) {
+	w := newTestWorker(&fakeStore{}, Config{})
+	require.NoError(t, w.Run(context.Background()))
+	w.Shutdown(context.Background())
+}
+
+func TestRegister_DuplicatePanics(t *testing.T) {
+	w := newTestWorker(&fakeStore{}, Config{})
+	Register(w, func(context.Context, exportArgs, *Job) error { return nil })
+	assert.Panics(t, func() {
+		Register(w, func(context.Context, exportArgs, *Job) error { return nil })
+	})
+}
+
+func TestConfig_Backoff(t *testing.T) {
+	cfg := Config{BackoffMin: time.Second, BackoffMax: 10 * time.Second}.withDefaults()
+	assert.Equal(t, time.Second, cfg.backoff(1))
+	assert.Equal(t, 2*time.Second, cfg.backoff(2))
+	assert.Equal(t, 8*time.Second, cfg.backoff(4))
+	assert.Equal(t, 10*time.Second, cfg.backoff(5))
+	assert.Equal(t, 10*time.Second, cfg.backoff(100))
+}
@@ -0,0 +1,29 @@
+package kafka
+
+import "time"
+
+const defaultTopic = "events"
+
+// Config configures the Kafka transport. Kafka is optional: with Enabled false
+// the outbox keeps publishing to AMQP.
+type Config struct {
+	Enabled      bool          `yaml:"enabled"`
+	Brokers      []string      `yaml:"brokers" validate:"required_if=Enabled true"`
+	Topic        string        `yaml:"topic"`         // topic for outbox events, default: events
+	BatchTimeout time.Duration `yaml:"batch_timeout"` // writer flush interval, default: 10ms
+}
+
+// EventsTopic returns the topic outbox events are published to.
+func (c Config) EventsTopic() string {
+	if c.Topic == "" {
+		return defaultTopic
+	}
+	return c.Topic
+}
+
+func (c Config) batchTimeout() time.Duration {
+	if c.BatchTimeout <= 0 {
+		return 10 * time.Millisecond
+	}
+	return c.BatchTimeout
+}
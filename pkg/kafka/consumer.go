@@ -0,0 +1,244 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	pkgamqp "starter-boilerplate/pkg/amqp"
+
+	amqp091 "github.com/rabbitmq/amqp091-go"
+	kafkago "github.com/segmentio/kafka-go"
+	"golang.org/x/sync/errgroup"
+)
+
+// Dead-letter headers added to messages written to ConsumerConfig.DeadLetterTopic.
+const (
+	HeaderDeadLetterError = "dead_letter_error"
+	HeaderOriginalTopic   = "original_topic"
+)
+
+const (
+	maxRetryBackoff = 30 * time.Second
+	commitTimeout   = 10 * time.Second
+)
+
+// Handler processes one Kafka message. It has the signature of a raw AMQP
+// handler, so sharedevent.Router.Handler() and existing raw handlers plug in
+// unchanged. DeliveryMeta.RoutingKey carries the event name, Exchange the topic.
+type Handler func(ctx context.Context, body []byte, meta pkgamqp.DeliveryMeta) error
+
+// Typed wraps a typed handler: the body is decoded into T with the codec
+// matching the content type header and validated before fn is called.
+func Typed[T any](fn func(ctx context.Context, payload T, meta pkgamqp.DeliveryMeta) error) Handler {
+	return func(ctx context.Context, body []byte, meta pkgamqp.DeliveryMeta) error {
+		var payload T
+		if err := pkgamqp.Decode(meta.ContentType, body, &payload); err != nil {
+			return fmt.Errorf("unmarshal: %w", err)
+		}
+		if err := pkgamqp.Validate(ctx, payload); err != nil {
+			return fmt.Errorf("validate: %w", err)
+		}
+		return fn(ctx, payload, meta)
+	}
+}
+
+// ConsumerConfig configures a consumer group member set.
+type ConsumerConfig struct {
+	Topic   string // required
+	GroupID string // required; partitions are rebalanced across all members of the group
+
+	Concurrency int // group members run by this process, default: 1
+
+	// RetryBackoff is the delay before the first retry of a failed message;
+	// it doubles on every attempt up to 30s. Default: 1s.
+	RetryBackoff time.Duration
+
+	// DeadLetterTopic receives messages that failed MaxAttempts times, after
+	// which their offset is committed. Without it a failed message is retried
+	// until it succeeds, blocking its partition to keep ordering.
+	DeadLetterTopic string
+	MaxAttempts     int // default: 5
+}
+
+func (c ConsumerConfig) concurrency() int {
+	if c.Concurrency <= 0 {
+		return 1
+	}
+	return c.Concurrency
+}
+
+func (c ConsumerConfig) retryBackoff() time.Duration {
+	if c.RetryBackoff <= 0 {
+		return time.Second
+	}
+	return c.RetryBackoff
+}
+
+func (c ConsumerConfig) maxAttempts() int {
+	if c.MaxAttempts <= 0 {
+		return 5
+	}
+	return c.MaxAttempts
+}
+
+// messageReader is the part of *kafkago.Reader a consumer uses.
+type messageReader interface {
+	FetchMessage(ctx context.Context) (kafkago.Message, error)
+	CommitMessages(ctx context.Context, msgs ...kafkago.Message) error
+	Close() error
+}
+
+// Consumer reads a topic as a member of a consumer group and feeds messages
+// to a Handler. Offsets are committed only after the handler succeeds, so a
+// message is redelivered if the process stops before finishing it.
+type Consumer struct {
+	cfg       ConsumerConfig
+	handler   Handler
+	newReader func() messageReader
+	dlq       messageWriter
+}
+
+func NewConsumer(kcfg Config, cfg ConsumerConfig, handler Handler) *Consumer {
+	c := &Consumer{
+		cfg:     cfg,
+		handler: handler,
+		newReader: func() messageReader {
+			return kafkago.NewReader(kafkago.ReaderConfig{
+				Brokers:     kcfg.Brokers,
+				GroupID:     cfg.GroupID,
+				Topic:       cfg.Topic,
+				StartOffset: kafkago.FirstOffset,
+			})
+		},
+	}
+	if cfg.DeadLetterTopic != "" {
+		c.dlq = newWriter(kcfg.Brokers, kcfg.batchTimeout())
+	}
+	return c
+}
+
+// Run starts the group members and blocks until ctx is cancelled or a member
+// fails. A message in progress when ctx is cancelled is finished and committed.
+func (c *Consumer) Run(ctx context.Context) error {
+	defer c.closeDLQ()
+
+	g, gctx := errgroup.WithContext(ctx)
+	for range c.cfg.concurrency() {
+		r := c.newReader()
+		g.Go(func() error { return c.consume(gctx, r) })
+	}
+	return g.Wait()
+}
+
+func (c *Consumer) consume(ctx context.Context, r messageReader) error {
+	defer func() {
+		if err := r.Close(); err != nil {
+			slog.Error("failed to close kafka reader", slog.String("topic", c.cfg.Topic), slog.Any("error", err))
+		}
+	}()
+
+	for {
+		msg, err := r.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("kafka: fetch %s: %w", c.cfg.Topic, err)
+		}
+
+		if err := c.handle(ctx, msg); err != nil {
+			if ctx.Err() != nil {
+				return nil // not committed, redelivered to the next owner of the partition
+			}
+			return err
+		}
+
+		commitCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), commitTimeout)
+		err = r.CommitMessages(commitCtx, msg)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("kafka: commit %s: %w", c.cfg.Topic, err)
+		}
+	}
+}
+
+// handle runs the handler until it succeeds, retrying with backoff.
+// With a dead-letter topic it gives up after MaxAttempts.
+func (c *Consumer) handle(ctx context.Context, msg kafkago.Message) error {
+	meta := newDeliveryMeta(msg)
+	backoff := c.cfg.retryBackoff()
+
+	for attempt := 1; ; attempt++ {
+		err := c.handler(context.WithoutCancel(ctx), msg.Value, meta)
+		if err == nil {
+			return nil
+		}
+
+		slog.Warn("kafka handler failed",
+			slog.String("topic", msg.Topic),
+			slog.Int("partition", msg.Partition),
+			slog.Int64("offset", msg.Offset),
+			slog.Int("attempt", attempt),
+			slog.Any("error", err))
+
+		if c.dlq != nil && attempt >= c.cfg.maxAttempts() {
+			return c.deadLetter(ctx, msg, err)
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff = min(backoff*2, maxRetryBackoff)
+	}
+}
+
+func (c *Consumer) deadLetter(ctx context.Context, msg kafkago.Message, cause error) error {
+	dead := kafkago.Message{
+		Topic: c.cfg.DeadLetterTopic,
+		Key:   msg.Key,
+		Value: msg.Value,
+		Headers: append(append([]kafkago.Header(nil), msg.Headers...),
+			kafkago.Header{Key: HeaderDeadLetterError, Value: []byte(cause.Error())},
+			kafkago.Header{Key: HeaderOriginalTopic, Value: []byte(msg.Topic)},
+		),
+	}
+	if err := c.dlq.WriteMessages(context.WithoutCancel(ctx), dead); err != nil {
+		return fmt.Errorf("kafka: dead-letter to %s: %w", c.cfg.DeadLetterTopic, err)
+	}
+	return nil
+}
+
+func (c *Consumer) closeDLQ() {
+	closer, ok := c.dlq.(interface{ Close() error })
+	if !ok {
+		return
+	}
+	if err := closer.Close(); err != nil {
+		slog.Error("failed to close kafka dead-letter writer", slog.Any("error", err))
+	}
+}
+
+func newDeliveryMeta(msg kafkago.Message) pkgamqp.DeliveryMeta {
+	meta := pkgamqp.DeliveryMeta{
+		Headers:   make(amqp091.Table, len(msg.Headers)),
+		Exchange:  msg.Topic,
+		MessageID: msg.Topic + "/" + strconv.Itoa(msg.Partition) + "/" + strconv.FormatInt(msg.Offset, 10),
+		Timestamp: msg.Time.Unix(),
+	}
+	for _, h := range msg.Headers {
+		switch h.Key {
+		case HeaderEventName:
+			meta.RoutingKey = string(h.Value)
+		case HeaderContentType:
+			meta.ContentType = string(h.Value)
+		default:
+			meta.Headers[h.Key] = string(h.Value)
+		}
+	}
+	return meta
+}
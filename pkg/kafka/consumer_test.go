@@ -0,0 +1,186 @@
+//go:build unit
+
+package kafka
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	pkgamqp "starter-boilerplate/pkg/amqp"
+
+	kafkago "github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeReader serves msgs in order, then blocks until ctx is cancelled.
+type fakeReader struct {
+	mu        sync.Mutex
+	msgs      []kafkago.Message
+	committed []int64
+	closed    bool
+}
+
+func (r *fakeReader) FetchMessage(ctx context.Context) (kafkago.Message, error) {
+	r.mu.Lock()
+	if len(r.msgs) > 0 {
+		msg := r.msgs[0]
+		r.msgs = r.msgs[1:]
+		r.mu.Unlock()
+		return msg, nil
+	}
+	r.mu.Unlock()
+	<-ctx.Done()
+	return kafkago.Message{}, ctx.Err()
+}
+
+func (r *fakeReader) CommitMessages(_ context.Context, msgs ...kafkago.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, m := range msgs {
+		r.committed = append(r.committed, m.Offset)
+	}
+	return nil
+}
+
+func (r *fakeReader) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closed = true
+	return nil
+}
+
+func (r *fakeReader) committedOffsets() []int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]int64(nil), r.committed...)
+}
+
+func newTestConsumer(r *fakeReader, cfg ConsumerConfig, h Handler) *Consumer {
+	return &Consumer{cfg: cfg, handler: h, newReader: func() messageReader { return r }}
+}
+
+// runConsumer runs c until the test ends.
+func runConsumer(t *testing.T, c *Consumer) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- c.Run(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, <-done)
+	})
+}
+
+func eventMessage(offset int64, name, body string) kafkago.Message {
+	return kafkago.Message{
+		Topic:  "events",
+		Offset: offset,
+		Value:  []byte(body),
+		Headers: []kafkago.Header{
+			{Key: HeaderEventName, Value: []byte(name)},
+			{Key: HeaderContentType, Value: []byte("application/json")},
+			{Key: "trace_id", Value: []byte("abc")},
+		},
+	}
+}
+
+type userCreated struct {
+	UserID string `json:"user_id" validate:"required"`
+}
+
+func TestConsumer_TypedHandlerAndCommit(t *testing.T) {
+	r := &fakeReader{msgs: []kafkago.Message{eventMessage(7, "user.created", `{"user_id":"u1"}`)}}
+	received := make(chan pkgamqp.DeliveryMeta, 1)
+	c := newTestConsumer(r, ConsumerConfig{Topic: "events", GroupID: "g"},
+		Typed(func(_ context.Context, e userCreated, meta pkgamqp.DeliveryMeta) error {
+			assert.Equal(t, "u1", e.UserID)
+			received <- meta
+			return nil
+		}))
+	runConsumer(t, c)
+
+	select {
+	case meta := <-received:
+		assert.Equal(t, "user.created", meta.RoutingKey)
+		assert.Equal(t, "events", meta.Exchange)
+		assert.Equal(t, "events/0/7", meta.MessageID)
+		assert.Equal(t, "abc", meta.Headers["trace_id"])
+	case <-time.After(time.Second):
+		t.Fatal("handler was not called")
+	}
+	require.Eventually(t, func() bool { return len(r.committedOffsets()) == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, []int64{7}, r.committedOffsets())
+}
+
+func TestConsumer_RetriesBeforeCommit(t *testing.T) {
+	r := &fakeReader{msgs: []kafkago.Message{eventMessage(1, "job", `{}`)}}
+	var mu sync.Mutex
+	attempts := 0
+	c := newTestConsumer(r, ConsumerConfig{Topic: "events", GroupID: "g", RetryBackoff: time.Millisecond},
+		func(context.Context, []byte, pkgamqp.DeliveryMeta) error {
+			mu.Lock()
+			defer mu.Unlock()
+			attempts++
+			if attempts < 3 {
+				return errors.New("transient")
+			}
+			return nil
+		})
+	runConsumer(t, c)
+
+	require.Eventually(t, func() bool { return len(r.committedOffsets()) == 1 }, time.Second, time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 3, attempts)
+}
+
+func TestConsumer_DeadLettersAfterMaxAttempts(t *testing.T) {
+	r := &fakeReader{msgs: []kafkago.Message{eventMessage(3, "job", `{}`)}}
+	dlq := &fakeWriter{}
+	c := newTestConsumer(r, ConsumerConfig{
+		Topic: "events", GroupID: "g", RetryBackoff: time.Millisecond,
+		DeadLetterTopic: "events.dlq", MaxAttempts: 2,
+	}, func(context.Context, []byte, pkgamqp.DeliveryMeta) error {
+		return errors.New("poison")
+	})
+	c.dlq = dlq
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- c.Run(ctx) }()
+
+	require.Eventually(t, func() bool { return len(r.committedOffsets()) == 1 }, time.Second, time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+
+	require.Len(t, dlq.msgs, 1)
+	assert.Equal(t, "events.dlq", dlq.msgs[0].Topic)
+	headers := headerMap(dlq.msgs[0].Headers)
+	assert.Equal(t, "poison", headers[HeaderDeadLetterError])
+	assert.Equal(t, "events", headers[HeaderOriginalTopic])
+	assert.Equal(t, "job", headers[HeaderEventName])
+}
+
+func TestConsumer_StopDuringRetryDoesNotCommit(t *testing.T) {
+	r := &fakeReader{msgs: []kafkago.Message{eventMessage(1, "job", `{}`)}}
+	called := make(chan struct{}, 1)
+	c := newTestConsumer(r, ConsumerConfig{Topic: "events", GroupID: "g", RetryBackoff: time.Hour},
+		func(context.Context, []byte, pkgamqp.DeliveryMeta) error {
+			called <- struct{}{}
+			return errors.New("transient")
+		})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- c.Run(ctx) }()
+	<-called
+	cancel()
+
+	require.NoError(t, <-done)
+	assert.Empty(t, r.committedOffsets())
+	assert.True(t, r.closed)
+}
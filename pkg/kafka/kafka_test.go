@@ -0,0 +1,85 @@
+//go:build integration
+
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	pkgamqp "starter-boilerplate/pkg/amqp"
+	"starter-boilerplate/pkg/outbox"
+	"starter-boilerplate/pkg/testcontainer"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type KafkaSuite struct {
+	suite.Suite
+	kafka *testcontainer.KafkaContainer
+	cfg   Config
+}
+
+func TestKafka(t *testing.T) {
+	k := &testcontainer.KafkaContainer{HostPort: "29092"}
+	if err := k.Start(context.Background()); err != nil {
+		fmt.Fprintf(os.Stderr, "setup kafka container: %v\n", err)
+		os.Exit(1)
+	}
+
+	suite.Run(t, &KafkaSuite{kafka: k, cfg: Config{Enabled: true, Brokers: k.Brokers()}})
+}
+
+func (s *KafkaSuite) TearDownSuite() {
+	s.kafka.Terminate(context.Background())
+}
+
+type receivedEvent struct {
+	name, key string
+}
+
+// consume runs a consumer of group on topic until n messages are handled.
+func (s *KafkaSuite) consume(topic, group string, n int) []receivedEvent {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	var got []receivedEvent
+	c := NewConsumer(s.cfg, ConsumerConfig{Topic: topic, GroupID: group},
+		func(_ context.Context, _ []byte, meta pkgamqp.DeliveryMeta) error {
+			got = append(got, receivedEvent{name: meta.RoutingKey, key: meta.MessageID})
+			if len(got) == n {
+				cancel()
+			}
+			return nil
+		})
+	s.Require().NoError(c.Run(ctx))
+	return got
+}
+
+func (s *KafkaSuite) TestPublishConsumeAndCommit() {
+	topic := "events.commit"
+	w := Setup(s.cfg, nil)
+	defer w.Close()
+	p := NewOutboxPublisher(w, topic)
+
+	errs := p.PublishBatch(context.Background(), []outbox.Entry{
+		{EventName: "user.created", Payload: []byte(`{"user_id":"u1"}`), Headers: map[string]any{outbox.HeaderPartitionKey: "u1"}},
+		{EventName: "user.logged_in", Payload: []byte(`{"user_id":"u1"}`), Headers: map[string]any{outbox.HeaderPartitionKey: "u1"}},
+	})
+	for _, err := range errs {
+		s.Require().NoError(err)
+	}
+
+	got := s.consume(topic, "commit-test", 2)
+	s.Require().Len(got, 2)
+	s.Equal("user.created", got[0].name, "same key keeps order")
+	s.Equal("user.logged_in", got[1].name)
+
+	s.Require().NoError(p.Publish(context.Background(), outbox.Entry{EventName: "user.password_changed", Payload: []byte(`{}`)}))
+
+	got = s.consume(topic, "commit-test", 1)
+	s.Require().Len(got, 1)
+	s.Equal("user.password_changed", got[0].name, "committed messages are not redelivered")
+}
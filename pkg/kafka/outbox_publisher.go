@@ -0,0 +1,103 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"starter-boilerplate/pkg/outbox"
+
+	kafkago "github.com/segmentio/kafka-go"
+)
+
+// Message headers set by OutboxPublisher and read by Consumer.
+const (
+	HeaderEventName   = "event_name"
+	HeaderContentType = "content_type"
+)
+
+const contentTypeJSON = "application/json"
+
+// messageWriter is the part of *kafkago.Writer the publisher uses.
+type messageWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafkago.Message) error
+}
+
+// OutboxPublisher implements outbox.Publisher and outbox.BatchPublisher on Kafka.
+// The entry's partition key (see [outbox.Partitioned]) becomes the message key,
+// so events of one aggregate land on one partition and stay in order.
+// Entries without a key are spread across partitions.
+type OutboxPublisher struct {
+	writer messageWriter
+	topic  string
+}
+
+func NewOutboxPublisher(w *kafkago.Writer, topic string) *OutboxPublisher {
+	return &OutboxPublisher{writer: w, topic: topic}
+}
+
+func (p *OutboxPublisher) Publish(ctx context.Context, entry outbox.Entry) error {
+	return p.PublishBatch(ctx, []outbox.Entry{entry})[0]
+}
+
+// PublishBatch writes all entries in one request and returns one error per entry.
+func (p *OutboxPublisher) PublishBatch(ctx context.Context, entries []outbox.Entry) []error {
+	msgs := make([]kafkago.Message, len(entries))
+	for i, e := range entries {
+		msgs[i] = p.message(e)
+	}
+
+	errs := make([]error, len(entries))
+	err := p.writer.WriteMessages(ctx, msgs...)
+	if err == nil {
+		return errs
+	}
+
+	var writeErrs kafkago.WriteErrors
+	if errors.As(err, &writeErrs) && len(writeErrs) == len(entries) {
+		for i, werr := range writeErrs {
+			if werr != nil {
+				errs[i] = fmt.Errorf("kafka: publish: %w", werr)
+			}
+		}
+		return errs
+	}
+	for i := range errs {
+		errs[i] = fmt.Errorf("kafka: publish: %w", err)
+	}
+	return errs
+}
+
+func (p *OutboxPublisher) message(e outbox.Entry) kafkago.Message {
+	msg := kafkago.Message{
+		Topic: p.topic,
+		Value: e.Payload,
+		Headers: []kafkago.Header{
+			{Key: HeaderEventName, Value: []byte(e.EventName)},
+			{Key: HeaderContentType, Value: []byte(contentTypeJSON)},
+		},
+	}
+	for k, v := range e.Headers {
+		if k == outbox.HeaderPartitionKey {
+			if key, ok := v.(string); ok {
+				msg.Key = []byte(key)
+			}
+			continue
+		}
+		msg.Headers = append(msg.Headers, kafkago.Header{Key: k, Value: headerValue(v)})
+	}
+	return msg
+}
+
+// headerValue encodes a header value: strings as is, anything else as JSON.
+func headerValue(v any) []byte {
+	if s, ok := v.(string); ok {
+		return []byte(s)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return []byte(fmt.Sprint(v))
+	}
+	return b
+}
@@ -0,0 +1,94 @@
+//go:build unit
+
+package kafka
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"starter-boilerplate/pkg/outbox"
+
+	kafkago "github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeWriter struct {
+	msgs []kafkago.Message
+	err  error
+}
+
+func (w *fakeWriter) WriteMessages(_ context.Context, msgs ...kafkago.Message) error {
+	w.msgs = append(w.msgs, msgs...)
+	return w.err
+}
+
+func headerMap(hs []kafkago.Header) map[string]string {
+	m := make(map[string]string, len(hs))
+	for _, h := range hs {
+		m[h.Key] = string(h.Value)
+	}
+	return m
+}
+
+func TestOutboxPublisher_Publish(t *testing.T) {
+	w := &fakeWriter{}
+	p := &OutboxPublisher{writer: w, topic: "events"}
+
+	err := p.Publish(context.Background(), outbox.Entry{
+		EventName: "user.created",
+		Payload:   []byte(`{"user_id":"u1"}`),
+		Headers: map[string]any{
+			outbox.HeaderPartitionKey: "u1",
+			"trace_id":                "abc",
+			"attempt":                 float64(2),
+		},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, w.msgs, 1)
+	msg := w.msgs[0]
+	assert.Equal(t, "events", msg.Topic)
+	assert.Equal(t, []byte("u1"), msg.Key)
+	assert.JSONEq(t, `{"user_id":"u1"}`, string(msg.Value))
+	assert.Equal(t, map[string]string{
+		HeaderEventName:   "user.created",
+		HeaderContentType: "application/json",
+		"trace_id":        "abc",
+		"attempt":         "2",
+	}, headerMap(msg.Headers))
+}
+
+func TestOutboxPublisher_Publish_WithoutPartitionKey(t *testing.T) {
+	w := &fakeWriter{}
+	p := &OutboxPublisher{writer: w, topic: "events"}
+
+	require.NoError(t, p.Publish(context.Background(), outbox.Entry{EventName: "system.tick", Payload: []byte(`{}`)}))
+
+	assert.Nil(t, w.msgs[0].Key)
+}
+
+func TestOutboxPublisher_PublishBatch_PartialFailure(t *testing.T) {
+	boom := errors.New("leader not available")
+	w := &fakeWriter{err: kafkago.WriteErrors{nil, boom, nil}}
+	p := &OutboxPublisher{writer: w, topic: "events"}
+
+	errs := p.PublishBatch(context.Background(), []outbox.Entry{{EventName: "a"}, {EventName: "b"}, {EventName: "c"}})
+
+	require.Len(t, errs, 3)
+	assert.NoError(t, errs[0])
+	assert.ErrorIs(t, errs[1], boom)
+	assert.NoError(t, errs[2])
+}
+
+func TestOutboxPublisher_PublishBatch_Failure(t *testing.T) {
+	boom := errors.New("connection refused")
+	p := &OutboxPublisher{writer: &fakeWriter{err: boom}, topic: "events"}
+
+	errs := p.PublishBatch(context.Background(), []outbox.Entry{{EventName: "a"}, {EventName: "b"}})
+
+	for _, err := range errs {
+		assert.ErrorIs(t, err, boom)
+	}
+}
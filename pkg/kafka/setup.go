@@ -0,0 +1,29 @@
+package kafka
+
+import (
+	"log/slog"
+	"time"
+
+	kafkago "github.com/segmentio/kafka-go"
+)
+
+// Setup creates the Kafka writer. Returns nil if Kafka is disabled.
+// Messages are hashed by key to partitions and acknowledged by all in-sync replicas.
+func Setup(cfg Config, _ *slog.Logger) *kafkago.Writer {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	slog.Info("kafka writer configured", slog.Any("brokers", cfg.Brokers))
+	return newWriter(cfg.Brokers, cfg.batchTimeout())
+}
+
+func newWriter(brokers []string, batchTimeout time.Duration) *kafkago.Writer {
+	return &kafkago.Writer{
+		Addr:                   kafkago.TCP(brokers...),
+		Balancer:               &kafkago.Hash{},
+		RequiredAcks:           kafkago.RequireAll,
+		BatchTimeout:           batchTimeout,
+		AllowAutoTopicCreation: true,
+	}
+}
@@ -3,6 +3,7 @@ package outbox
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 )
@@ -18,16 +19,56 @@ type Taggable interface {
 	Tags() []string
 }
 
+// Partitioned is an optional interface for events that carry a partition key,
+// usually the aggregate ID. Partitioning transports (Kafka) keep events with
+// the same key in order.
+type Partitioned interface {
+	PartitionKey() string
+}
+
+// HeaderPartitionKey is the entry header holding the event's partition key.
+const HeaderPartitionKey = "partition_key"
+
+// HeaderEntryID is the header the relay adds to published messages with the
+// entry ID.
+const HeaderEntryID = "outbox_id"
+
+// HeaderPosition is the header the relay adds to published messages with the
+// entry's position, so that consumers can resume from it (see
+// Repository.ListPublishedAfter).
+const HeaderPosition = "outbox_position"
+
 // Bus publishes domain events.
 type Bus interface {
 	Publish(ctx context.Context, event Event) error
 }
 
+// Scheduled wraps an event that must not be delivered before At.
+// Publishing it through OutboxBus keeps the entry in the outbox until then.
+type Scheduled struct {
+	Event Event
+	At    time.Time
+}
+
+func (s Scheduled) EventName() string { return s.Event.EventName() }
+
+// Scheduler publishes events for later delivery. Unlike publishing a
+// Scheduled event, it returns the entry ID, which can cancel the delivery.
+type Scheduler interface {
+	Schedule(ctx context.Context, event Event, at time.Time) (int64, error)
+	Cancel(ctx context.Context, id int64) error
+}
+
+// ErrNotScheduled is returned by Cancel when no pending scheduled entry has
+// the given ID: it was already published, cancelled, or never existed.
+var ErrNotScheduled = errors.New("outbox: no pending scheduled entry")
+
 type entryRepository interface {
 	Insert(ctx context.Context, entry *Entry) error
+	DeleteScheduled(ctx context.Context, id int64) (bool, error)
 }
 
-// OutboxBus implements Bus by inserting events into the outbox table.
+// OutboxBus implements Bus and Scheduler by inserting events into the outbox table.
 // It relies on the transaction being present in context (via pkgdb.WithTx).
 type OutboxBus struct {
 	outboxRepo entryRepository
@@ -39,9 +80,36 @@ func NewOutboxBus(outboxRepo *Repository) *OutboxBus {
 }
 
 func (b *OutboxBus) Publish(ctx context.Context, e Event) error {
+	if s, ok := e.(Scheduled); ok {
+		_, err := b.insert(ctx, s.Event, s.At)
+		return err
+	}
+	_, err := b.insert(ctx, e, time.Time{})
+	return err
+}
+
+// Schedule inserts e for delivery at or after at and returns the entry ID.
+// The entry survives restarts like any other outbox entry.
+func (b *OutboxBus) Schedule(ctx context.Context, e Event, at time.Time) (int64, error) {
+	return b.insert(ctx, e, at)
+}
+
+// Cancel removes a scheduled entry that has not been published yet.
+func (b *OutboxBus) Cancel(ctx context.Context, id int64) error {
+	deleted, err := b.outboxRepo.DeleteScheduled(ctx, id)
+	if err != nil {
+		return fmt.Errorf("outbox: cancel %d: %w", id, err)
+	}
+	if !deleted {
+		return ErrNotScheduled
+	}
+	return nil
+}
+
+func (b *OutboxBus) insert(ctx context.Context, e Event, at time.Time) (int64, error) {
 	payload, err := json.Marshal(e)
 	if err != nil {
-		return fmt.Errorf("outbox: marshal event: %w", err)
+		return 0, fmt.Errorf("outbox: marshal event: %w", err)
 	}
 
 	headers := make(map[string]any)
@@ -50,13 +118,33 @@ func (b *OutboxBus) Publish(ctx context.Context, e Event) error {
 			headers["tag."+tag] = true
 		}
 	}
+	if p, ok := e.(Partitioned); ok && p.PartitionKey() != "" {
+		headers[HeaderPartitionKey] = p.PartitionKey()
+	}
 
 	entry := &Entry{
 		EventName: e.EventName(),
 		Payload:   payload,
 		Headers:   headers,
 		CreatedAt: time.Now().Unix(),
+		DeliverAt: deliverAt(at),
+	}
+
+	if err := b.outboxRepo.Insert(ctx, entry); err != nil {
+		return 0, err
 	}
+	return entry.ID, nil
+}
 
-	return b.outboxRepo.Insert(ctx, entry)
+// deliverAt rounds at up to whole unix seconds, so an entry is never
+// published early. The zero time means immediately.
+func deliverAt(at time.Time) int64 {
+	if at.IsZero() {
+		return 0
+	}
+	sec := at.Unix()
+	if at.Nanosecond() > 0 {
+		sec++
+	}
+	return sec
 }
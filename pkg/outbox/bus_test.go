@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -75,6 +76,28 @@ func TestOutboxBus_Publish_WithTags(t *testing.T) {
 	repo.AssertExpectations(t)
 }
 
+type partitionedEvent struct {
+	UserID string `json:"user_id"`
+}
+
+func (e partitionedEvent) EventName() string    { return "partitioned.event" }
+func (e partitionedEvent) PartitionKey() string { return e.UserID }
+
+func TestOutboxBus_Publish_WithPartitionKey(t *testing.T) {
+	repo := new(mockRepository)
+	bus := &OutboxBus{outboxRepo: repo}
+
+	repo.On("Insert", mock.Anything, mock.AnythingOfType("*outbox.Entry")).
+		Run(func(args mock.Arguments) {
+			entry := args.Get(1).(*Entry)
+			assert.Equal(t, "user-1", entry.Headers[HeaderPartitionKey])
+		}).
+		Return(nil)
+
+	require.NoError(t, bus.Publish(context.Background(), partitionedEvent{UserID: "user-1"}))
+	repo.AssertExpectations(t)
+}
+
 func TestOutboxBus_Publish_RepoError(t *testing.T) {
 	repo := new(mockRepository)
 	bus := &OutboxBus{outboxRepo: repo}
@@ -90,3 +113,60 @@ func TestOutboxBus_Publish_RepoError(t *testing.T) {
 	assert.ErrorIs(t, err, repoErr)
 	repo.AssertExpectations(t)
 }
+
+func TestOutboxBus_Publish_Scheduled(t *testing.T) {
+	repo := new(mockRepository)
+	bus := &OutboxBus{outboxRepo: repo}
+	at := time.Unix(1_000, 500)
+
+	repo.On("Insert", mock.Anything, mock.AnythingOfType("*outbox.Entry")).
+		Run(func(args mock.Arguments) {
+			entry := args.Get(1).(*Entry)
+			assert.Equal(t, "test.event", entry.EventName)
+			assert.Equal(t, int64(1_001), entry.DeliverAt, "rounded up to never deliver early")
+			assert.JSONEq(t, `{"name":"later"}`, string(entry.Payload), "payload is the wrapped event")
+		}).
+		Return(nil)
+
+	require.NoError(t, bus.Publish(context.Background(), Scheduled{Event: testEvent{Name: "later"}, At: at}))
+	repo.AssertExpectations(t)
+}
+
+func TestOutboxBus_Schedule_ReturnsID(t *testing.T) {
+	repo := new(mockRepository)
+	bus := &OutboxBus{outboxRepo: repo}
+
+	repo.On("Insert", mock.Anything, mock.AnythingOfType("*outbox.Entry")).
+		Run(func(args mock.Arguments) {
+			entry := args.Get(1).(*Entry)
+			assert.Equal(t, int64(2_000), entry.DeliverAt)
+			entry.ID = 42
+		}).
+		Return(nil)
+
+	id, err := bus.Schedule(context.Background(), testEvent{Name: "later"}, time.Unix(2_000, 0))
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), id)
+}
+
+func TestOutboxBus_Publish_ImmediateHasNoDeliverAt(t *testing.T) {
+	repo := new(mockRepository)
+	bus := &OutboxBus{outboxRepo: repo}
+
+	repo.On("Insert", mock.Anything, mock.MatchedBy(func(e *Entry) bool { return e.DeliverAt == 0 })).Return(nil)
+
+	require.NoError(t, bus.Publish(context.Background(), testEvent{Name: "now"}))
+	repo.AssertExpectations(t)
+}
+
+func TestOutboxBus_Cancel(t *testing.T) {
+	repo := new(mockRepository)
+	bus := &OutboxBus{outboxRepo: repo}
+
+	repo.On("DeleteScheduled", mock.Anything, int64(42)).Return(true, nil)
+	repo.On("DeleteScheduled", mock.Anything, int64(7)).Return(false, nil)
+
+	assert.NoError(t, bus.Cancel(context.Background(), 42))
+	assert.ErrorIs(t, bus.Cancel(context.Background(), 7), ErrNotScheduled)
+}
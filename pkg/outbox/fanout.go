@@ -0,0 +1,91 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// FanoutPublisher publishes every entry with each of its publishers, e.g. to
+// AMQP for the consumers of the app and to Kafka for other systems. An entry
+// counts as published when every publisher either accepted it or rejected it
+// for good (ErrUndeliverable) and at least one accepted it; it is failed only
+// when every publisher rejected it for good. Any other error is retried: the
+// entry goes to all publishers again, so the ones that already accepted it
+// see it twice, as with any at-least-once redelivery.
+type FanoutPublisher struct {
+	publishers []Publisher
+}
+
+func NewFanoutPublisher(publishers ...Publisher) *FanoutPublisher {
+	return &FanoutPublisher{publishers: publishers}
+}
+
+func (p *FanoutPublisher) Publish(ctx context.Context, entry Entry) error {
+	return p.PublishBatch(ctx, []Entry{entry})[0]
+}
+
+// PublishBatch sends entries to each publisher, in batches where supported.
+// A publisher without batches stops at its first failure, like the relay;
+// the entries after it fail with the same error.
+func (p *FanoutPublisher) PublishBatch(ctx context.Context, entries []Entry) []error {
+	perEntry := make([][]error, len(entries))
+	for _, pub := range p.publishers {
+		for i, err := range publishEach(ctx, pub, entries) {
+			perEntry[i] = append(perEntry[i], err)
+		}
+	}
+
+	errs := make([]error, len(entries))
+	for i, pubErrs := range perEntry {
+		errs[i] = fanoutError(entries[i], pubErrs)
+	}
+	return errs
+}
+
+// fanoutError combines the errors of the publishers for one entry. The result
+// wraps ErrUndeliverable only when every publisher returned it, so the relay
+// does not fail an entry another publisher delivered or may still deliver.
+func fanoutError(entry Entry, pubErrs []error) error {
+	var undeliverable, retryable []error
+	for _, err := range pubErrs {
+		switch {
+		case err == nil:
+		case errors.Is(err, ErrUndeliverable):
+			undeliverable = append(undeliverable, err)
+		default:
+			retryable = append(retryable, err)
+		}
+	}
+
+	switch {
+	case len(retryable) > 0:
+		return errors.Join(retryable...)
+	case len(undeliverable) == len(pubErrs):
+		return errors.Join(undeliverable...)
+	case len(undeliverable) > 0:
+		slog.Error("outbox fanout entry undeliverable on some publishers, published on the others",
+			slog.Int64("entry_id", entry.ID),
+			slog.String("event", entry.EventName),
+			slog.String("error", errors.Join(undeliverable...).Error()),
+		)
+	}
+	return nil
+}
+
+func publishEach(ctx context.Context, pub Publisher, entries []Entry) []error {
+	if bp, ok := pub.(BatchPublisher); ok {
+		return bp.PublishBatch(ctx, entries)
+	}
+	errs := make([]error, len(entries))
+	for i := range entries {
+		errs[i] = pub.Publish(ctx, entries[i])
+		if errs[i] != nil && !errors.Is(errs[i], ErrUndeliverable) {
+			for j := i + 1; j < len(entries); j++ {
+				errs[j] = errs[i]
+			}
+			break
+		}
+	}
+	return errs
+}
@@ -0,0 +1,58 @@
+//go:build unit
+
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestFanoutPublisher_PublishesToAll(t *testing.T) {
+	amqp := new(mockPublisher)
+	amqp.On("Publish", mock.Anything, mock.Anything).Return(nil)
+	kafka := new(mockBatchPublisher)
+	kafka.On("PublishBatch", mock.Anything, mock.Anything).Return([]error{nil, nil, nil})
+
+	errs := NewFanoutPublisher(amqp, kafka).PublishBatch(context.Background(), relayEntries())
+
+	assert.Equal(t, []error{nil, nil, nil}, errs)
+	amqp.AssertNumberOfCalls(t, "Publish", 3)
+	kafka.AssertNumberOfCalls(t, "PublishBatch", 1)
+}
+
+func TestFanoutPublisher_JoinsErrorsPerEntry(t *testing.T) {
+	amqp := new(mockPublisher)
+	amqp.On("Publish", mock.Anything, mock.MatchedBy(func(e Entry) bool { return e.ID == 1 })).
+		Return(nil)
+	amqp.On("Publish", mock.Anything, mock.MatchedBy(func(e Entry) bool { return e.ID == 2 })).
+		Return(errors.New("channel closed"))
+	kafka := new(mockBatchPublisher)
+	kafka.On("PublishBatch", mock.Anything, mock.Anything).Return([]error{nil, nil, errors.New("leader not available")})
+
+	errs := NewFanoutPublisher(amqp, kafka).PublishBatch(context.Background(), relayEntries())
+
+	assert.NoError(t, errs[0])
+	assert.EqualError(t, errs[1], "channel closed")
+	assert.EqualError(t, errs[2], "channel closed\nleader not available", "entries after a failure fail with it")
+	amqp.AssertNumberOfCalls(t, "Publish", 2)
+}
+
+func TestFanoutPublisher_UndeliverableOnlyWhenEveryPublisherSaysSo(t *testing.T) {
+	undeliverable := fmt.Errorf("%w: no route", ErrUndeliverable)
+	amqp := new(mockBatchPublisher)
+	amqp.On("PublishBatch", mock.Anything, mock.Anything).Return([]error{undeliverable, undeliverable, undeliverable})
+	kafka := new(mockBatchPublisher)
+	kafka.On("PublishBatch", mock.Anything, mock.Anything).Return([]error{undeliverable, nil, errors.New("leader not available")})
+
+	errs := NewFanoutPublisher(amqp, kafka).PublishBatch(context.Background(), relayEntries())
+
+	assert.ErrorIs(t, errs[0], ErrUndeliverable, "rejected by every publisher: failed")
+	assert.NoError(t, errs[1], "accepted by Kafka: published, not failed")
+	assert.Error(t, errs[2])
+	assert.NotErrorIs(t, errs[2], ErrUndeliverable, "Kafka may still accept it: retried, not failed")
+}
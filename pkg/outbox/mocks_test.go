@@ -16,3 +16,26 @@ func (m *mockRepository) Insert(ctx context.Context, entry *Entry) error {
 	args := m.Called(ctx, entry)
 	return args.Error(0)
 }
+
+func (m *mockRepository) DeleteScheduled(ctx context.Context, id int64) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+type mockPublisher struct {
+	mock.Mock
+}
+
+func (m *mockPublisher) Publish(ctx context.Context, entry Entry) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+type mockBatchPublisher struct {
+	mockPublisher
+}
+
+func (m *mockBatchPublisher) PublishBatch(ctx context.Context, entries []Entry) []error {
+	args := m.Called(ctx, entries)
+	return args.Get(0).([]error)
+}
@@ -15,5 +15,14 @@ type Entry struct {
 	Payload   json.RawMessage `bun:"payload,type:jsonb,notnull"`
 	Headers   map[string]any  `bun:"headers,type:jsonb,notnull,default:'{}'"`
 	CreatedAt int64           `bun:"created_at,notnull"`
+	DeliverAt int64           `bun:"deliver_at,notnull"` // unix seconds; the relay skips the entry until then, 0 = immediately
 	Published bool            `bun:"published,notnull,default:false"`
+	// Position orders published entries by when they were published, unlike
+	// ID (see Repository.NextPositions); 0 until then.
+	Position    int64 `bun:"position,nullzero"`
+	PublishedAt int64 `bun:"published_at,notnull,default:0"` // unix seconds
+	// Failed marks an entry the publisher rejected for good (see
+	// ErrUndeliverable); the relay skips it and Error tells why.
+	Failed bool   `bun:"failed,notnull,default:false"`
+	Error  string `bun:"error,notnull,default:''"`
 }
@@ -2,7 +2,10 @@ package outbox
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"maps"
+	"slices"
 	"time"
 
 	pkgdb "starter-boilerplate/pkg/db"
@@ -10,12 +13,23 @@ import (
 	"github.com/uptrace/bun"
 )
 
+// ErrUndeliverable is wrapped by the errors of publishers for entries that
+// will never be delivered, e.g. ones no queue is bound for. The relay marks
+// such entries failed instead of retrying them.
+var ErrUndeliverable = errors.New("outbox: entry is undeliverable")
+
 // Publisher publishes an outbox entry to the message broker.
 // The implementation decides the transport (AMQP, Kafka, etc).
 type Publisher interface {
 	Publish(ctx context.Context, entry Entry) error
 }
 
+// BatchPublisher is optionally implemented by a Publisher that can send many
+// entries in one go. It returns one error per entry, in order (nil on success).
+type BatchPublisher interface {
+	PublishBatch(ctx context.Context, entries []Entry) []error
+}
+
 // RelayConfig controls the relay polling behaviour.
 type RelayConfig struct {
 	PollInterval time.Duration `yaml:"poll_interval"`
@@ -71,6 +85,8 @@ func (r *Relay) Run(ctx context.Context) error {
 	}
 }
 
+// poll publishes a batch under the relay lock; without it, another relay is
+// publishing and the poll is skipped.
 func (r *Relay) poll(ctx context.Context) error {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -80,7 +96,12 @@ func (r *Relay) poll(ctx context.Context) error {
 
 	txCtx := pkgdb.WithTx(ctx, tx)
 
-	entries, err := r.repo.FetchUnpublished(txCtx, r.cfg.BatchSize)
+	locked, err := r.repo.TryLockRelay(txCtx)
+	if err != nil || !locked {
+		return err
+	}
+
+	entries, err := r.repo.FetchUnpublished(txCtx, time.Now().Unix(), r.cfg.BatchSize)
 	if err != nil {
 		return err
 	}
@@ -88,25 +109,88 @@ func (r *Relay) poll(ctx context.Context) error {
 		return nil
 	}
 
-	var published []int64
+	positions, err := r.repo.NextPositions(txCtx, len(entries))
+	if err != nil {
+		return err
+	}
 	for i := range entries {
-		if err := r.publisher.Publish(ctx, entries[i]); err != nil {
-			slog.Error("outbox relay publish failed",
-				slog.Int64("entry_id", entries[i].ID),
-				slog.String("error", err.Error()),
-			)
-			break // stop at first failure to preserve FIFO ordering
-		}
-		published = append(published, entries[i].ID)
+		entries[i].Position = positions[i]
 	}
 
-	if len(published) == 0 {
+	published, failed := r.publish(ctx, entries)
+	if len(published) == 0 && len(failed) == 0 {
 		return nil
 	}
 
-	if err := r.repo.MarkPublished(txCtx, published); err != nil {
+	if len(published) > 0 {
+		if err := r.repo.MarkPublished(txCtx, publishedPositions(entries, published), time.Now().Unix()); err != nil {
+			return err
+		}
+	}
+	if err := r.repo.MarkFailed(txCtx, failed); err != nil {
 		return err
 	}
 
 	return tx.Commit()
 }
+
+// publish sends entries and returns the IDs of the successfully published
+// prefix, and the errors of the undeliverable entries in it (see
+// ErrUndeliverable). Undeliverable entries are failed and passed over; any
+// other failure stops the prefix to preserve FIFO ordering: entries after it
+// stay unpublished and are retried on the next poll.
+func (r *Relay) publish(ctx context.Context, entries []Entry) (published []int64, failed map[int64]string) {
+	for i := range entries {
+		headers := make(map[string]any, len(entries[i].Headers)+2)
+		maps.Copy(headers, entries[i].Headers)
+		headers[HeaderEntryID] = entries[i].ID
+		headers[HeaderPosition] = entries[i].Position
+		entries[i].Headers = headers
+	}
+
+	var errs []error
+	if bp, ok := r.publisher.(BatchPublisher); ok {
+		errs = bp.PublishBatch(ctx, entries)
+	} else {
+		errs = make([]error, len(entries))
+		for i := range entries {
+			errs[i] = r.publisher.Publish(ctx, entries[i])
+			if errs[i] != nil && !errors.Is(errs[i], ErrUndeliverable) {
+				break
+			}
+		}
+	}
+
+	failed = map[int64]string{}
+	for i := range entries {
+		if errors.Is(errs[i], ErrUndeliverable) {
+			slog.Error("outbox relay entry undeliverable, marked failed",
+				slog.Int64("entry_id", entries[i].ID),
+				slog.String("event", entries[i].EventName),
+				slog.String("error", errs[i].Error()),
+			)
+			failed[entries[i].ID] = errs[i].Error()
+			continue
+		}
+		if errs[i] != nil {
+			slog.Error("outbox relay publish failed",
+				slog.Int64("entry_id", entries[i].ID),
+				slog.String("error", errs[i].Error()),
+			)
+			break
+		}
+		published = append(published, entries[i].ID)
+	}
+	return published, failed
+}
+
+// publishedPositions returns the positions of the published entries by ID.
+func publishedPositions(entries []Entry, published []int64) map[int64]int64 {
+	positions := make(map[int64]int64, len(published))
+	for _, e := range entries {
+		if slices.Contains(published, e.ID) {
+			positions[e.ID] = e.Position
+		}
+	}
+	return positions
+}
@@ -0,0 +1,108 @@
+//go:build unit
+
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func relayEntries() []Entry {
+	return []Entry{{ID: 1, EventName: "a"}, {ID: 2, EventName: "b"}, {ID: 3, EventName: "c"}}
+}
+
+func TestRelay_Publish_Sequential(t *testing.T) {
+	pub := new(mockPublisher)
+	pub.On("Publish", mock.Anything, mock.Anything).Return(nil)
+
+	r := NewRelay(nil, nil, pub, RelayConfig{})
+	published, _ := r.publish(context.Background(), relayEntries())
+
+	assert.Equal(t, []int64{1, 2, 3}, published)
+	pub.AssertNumberOfCalls(t, "Publish", 3)
+}
+
+func TestRelay_Publish_SequentialStopsAtFirstFailure(t *testing.T) {
+	pub := new(mockPublisher)
+	pub.On("Publish", mock.Anything, mock.MatchedBy(func(e Entry) bool { return e.ID == 2 })).Return(errors.New("boom"))
+	pub.On("Publish", mock.Anything, mock.Anything).Return(nil)
+
+	r := NewRelay(nil, nil, pub, RelayConfig{})
+	published, _ := r.publish(context.Background(), relayEntries())
+
+	assert.Equal(t, []int64{1}, published)
+	pub.AssertNumberOfCalls(t, "Publish", 2)
+}
+
+func TestRelay_Publish_BatchMarksAckedPrefix(t *testing.T) {
+	pub := new(mockBatchPublisher)
+	pub.On("PublishBatch", mock.Anything, mock.Anything).Return([]error{nil, errors.New("nacked"), nil})
+
+	r := NewRelay(nil, nil, pub, RelayConfig{})
+	published, _ := r.publish(context.Background(), relayEntries())
+
+	assert.Equal(t, []int64{1}, published, "entries after a failure stay unpublished to keep FIFO order")
+	pub.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+}
+
+func TestRelay_Publish_BatchAllAcked(t *testing.T) {
+	pub := new(mockBatchPublisher)
+	pub.On("PublishBatch", mock.Anything, mock.Anything).Return([]error{nil, nil, nil})
+
+	r := NewRelay(nil, nil, pub, RelayConfig{})
+	published, _ := r.publish(context.Background(), relayEntries())
+
+	assert.Equal(t, []int64{1, 2, 3}, published)
+}
+
+func TestRelay_Publish_AddsEntryIDAndPositionHeaders(t *testing.T) {
+	pub := new(mockPublisher)
+	pub.On("Publish", mock.Anything, mock.MatchedBy(func(e Entry) bool {
+		return e.Headers[HeaderEntryID] == e.ID && e.Headers[HeaderPosition] == int64(12) && e.Headers[HeaderPartitionKey] == "user-1"
+	})).Return(nil)
+
+	r := NewRelay(nil, nil, pub, RelayConfig{})
+	entries := []Entry{{ID: 7, Position: 12, EventName: "a", Headers: map[string]any{HeaderPartitionKey: "user-1"}}}
+	published, _ := r.publish(context.Background(), entries)
+
+	assert.Equal(t, []int64{7}, published)
+	pub.AssertExpectations(t)
+}
+
+func TestRelay_Publish_UndeliverableFirstDoesNotBlock(t *testing.T) {
+	pub := new(mockPublisher)
+	pub.On("Publish", mock.Anything, mock.MatchedBy(func(e Entry) bool { return e.ID == 1 })).
+		Return(fmt.Errorf("%w: no route", ErrUndeliverable))
+	pub.On("Publish", mock.Anything, mock.Anything).Return(nil)
+
+	r := NewRelay(nil, nil, pub, RelayConfig{})
+	published, failed := r.publish(context.Background(), relayEntries())
+
+	assert.Equal(t, []int64{2, 3}, published)
+	assert.Equal(t, map[int64]string{1: "outbox: entry is undeliverable: no route"}, failed)
+	pub.AssertNumberOfCalls(t, "Publish", 3)
+}
+
+func TestRelay_Publish_BatchUndeliverableFirstDoesNotBlock(t *testing.T) {
+	pub := new(mockBatchPublisher)
+	pub.On("PublishBatch", mock.Anything, mock.Anything).
+		Return([]error{fmt.Errorf("%w: no route", ErrUndeliverable), nil, errors.New("nacked")})
+
+	r := NewRelay(nil, nil, pub, RelayConfig{})
+	published, failed := r.publish(context.Background(), relayEntries())
+
+	assert.Equal(t, []int64{2}, published, "other failures still stop the prefix")
+	assert.Contains(t, failed, int64(1))
+	assert.Len(t, failed, 1)
+}
+
+func TestPublishedPositions(t *testing.T) {
+	entries := []Entry{{ID: 3, Position: 10}, {ID: 1, Position: 11}, {ID: 2, Position: 12}}
+
+	assert.Equal(t, map[int64]int64{3: 10, 2: 12}, publishedPositions(entries, []int64{3, 2}))
+}
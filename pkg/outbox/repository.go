@@ -6,8 +6,13 @@ import (
 	pkgdb "starter-boilerplate/pkg/db"
 
 	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
 )
 
+// relayLockKey is the transaction-level advisory lock of the relay that
+// publishes (see TryLockRelay).
+const relayLockKey int64 = 7_240_002
+
 // Repository handles outbox table operations.
 type Repository struct {
 	db *bun.DB
@@ -17,18 +22,64 @@ func NewRepository(db *bun.DB) *Repository {
 	return &Repository{db: db}
 }
 
-// Insert adds an entry to the outbox within the current tx (or fallback db).
+// Insert adds an entry to the outbox within the current tx (or fallback db) and sets its ID.
 func (r *Repository) Insert(ctx context.Context, entry *Entry) error {
-	_, err := pkgdb.Conn(ctx, r.db).NewInsert().Model(entry).ExcludeColumn("id").Exec(ctx)
+	_, err := pkgdb.Conn(ctx, r.db).NewInsert().Model(entry).ExcludeColumn("id").Returning("id").Exec(ctx)
 	return err
 }
 
-// FetchUnpublished returns up to limit unpublished entries, locking them for update.
-func (r *Repository) FetchUnpublished(ctx context.Context, limit int) ([]Entry, error) {
+// DeleteScheduled removes the unpublished scheduled entry with the given ID
+// and reports whether there was one.
+func (r *Repository) DeleteScheduled(ctx context.Context, id int64) (bool, error) {
+	res, err := pkgdb.Conn(ctx, r.db).NewDelete().
+		Model((*Entry)(nil)).
+		Where("id = ?", id).
+		Where("published = FALSE").
+		Where("deliver_at > 0").
+		Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// DeletePublishedBefore removes entries published before the given unix time
+// and returns how many were deleted. It removes them oldest position first
+// and keeps the last published entry, so every position from OldestPosition
+// on is still there.
+func (r *Repository) DeletePublishedBefore(ctx context.Context, before int64) (int64, error) {
+	db := pkgdb.Conn(ctx, r.db)
+	cutoff := db.NewSelect().
+		Model((*Entry)(nil)).
+		ColumnExpr("MAX(position)").
+		Where("published = TRUE").
+		Where("published_at < ?", before)
+	last := db.NewSelect().
+		Model((*Entry)(nil)).
+		ColumnExpr("MAX(position)").
+		Where("published = TRUE")
+	res, err := db.NewDelete().
+		Model((*Entry)(nil)).
+		Where("published = TRUE").
+		Where("position <= (?)", cutoff).
+		Where("position < (?)", last).
+		Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// FetchUnpublished returns up to limit unpublished, not failed entries due at
+// now (unix seconds), locking them for update.
+func (r *Repository) FetchUnpublished(ctx context.Context, now int64, limit int) ([]Entry, error) {
 	var entries []Entry
 	err := pkgdb.Conn(ctx, r.db).NewSelect().
 		Model(&entries).
 		Where("published = FALSE").
+		Where("failed = FALSE").
+		Where("deliver_at <= ?", now).
 		OrderExpr("id ASC").
 		Limit(limit).
 		For("UPDATE SKIP LOCKED").
@@ -36,12 +87,87 @@ func (r *Repository) FetchUnpublished(ctx context.Context, limit int) ([]Entry,
 	return entries, err
 }
 
-// MarkPublished sets published=TRUE for the given IDs.
-func (r *Repository) MarkPublished(ctx context.Context, ids []int64) error {
+// TryLockRelay takes the relay lock until the current transaction ends and
+// reports whether it did. Only its holder publishes, so positions from
+// NextPositions are committed in the order they were taken and a replay
+// never passes over one that becomes visible later.
+func (r *Repository) TryLockRelay(ctx context.Context) (bool, error) {
+	var locked bool
+	err := pkgdb.Conn(ctx, r.db).NewRaw("SELECT pg_try_advisory_xact_lock(?)", relayLockKey).Scan(ctx, &locked)
+	return locked, err
+}
+
+// NextPositions returns n new positions in ascending order. Call it with the
+// relay lock held (see TryLockRelay).
+func (r *Repository) NextPositions(ctx context.Context, n int) ([]int64, error) {
+	var positions []int64
+	err := pkgdb.Conn(ctx, r.db).
+		NewRaw("SELECT nextval('outbox_position_seq') FROM generate_series(1, ?) ORDER BY 1", n).
+		Scan(ctx, &positions)
+	return positions, err
+}
+
+// MarkPublished sets published=TRUE, the position and published_at (unix
+// seconds) of the given entries, by ID.
+func (r *Repository) MarkPublished(ctx context.Context, positions map[int64]int64, at int64) error {
+	ids := make([]int64, 0, len(positions))
+	pos := make([]int64, 0, len(positions))
+	for id, p := range positions {
+		ids = append(ids, id)
+		pos = append(pos, p)
+	}
 	_, err := pkgdb.Conn(ctx, r.db).NewUpdate().
 		Model((*Entry)(nil)).
+		TableExpr("unnest(?::bigint[], ?::bigint[]) AS p (id, position)", pgdialect.Array(ids), pgdialect.Array(pos)).
 		Set("published = TRUE").
-		Where("id IN (?)", bun.In(ids)).
+		Set("position = p.position").
+		Set("published_at = ?", at).
+		Where("entry.id = p.id").
 		Exec(ctx)
 	return err
 }
+
+// MarkFailed sets failed=TRUE and the error for the given entries.
+func (r *Repository) MarkFailed(ctx context.Context, failed map[int64]string) error {
+	for id, reason := range failed {
+		_, err := pkgdb.Conn(ctx, r.db).NewUpdate().
+			Model((*Entry)(nil)).
+			Set("failed = TRUE").
+			Set("error = ?", reason).
+			Where("id = ?", id).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListPublishedAfter returns up to limit published entries with a position
+// above after, in position order, whose event name starts with namePrefix
+// and, unless partitionKey is empty, whose partition key matches.
+func (r *Repository) ListPublishedAfter(ctx context.Context, after int64, namePrefix, partitionKey string, limit int) ([]Entry, error) {
+	var entries []Entry
+	q := pkgdb.Conn(ctx, r.db).NewSelect().
+		Model(&entries).
+		Where("published = TRUE").
+		Where("position > ?", after).
+		Where("starts_with(event_name, ?)", namePrefix)
+	if partitionKey != "" {
+		q = q.Where("headers->>? = ?", HeaderPartitionKey, partitionKey)
+	}
+	err := q.OrderExpr("position ASC").Limit(limit).Scan(ctx)
+	return entries, err
+}
+
+// OldestPosition returns the lowest position left in the outbox, or 0 if no
+// published entry is.
+func (r *Repository) OldestPosition(ctx context.Context) (int64, error) {
+	var oldest int64
+	err := pkgdb.Conn(ctx, r.db).NewSelect().
+		Model((*Entry)(nil)).
+		ColumnExpr("COALESCE(MIN(position), 0)").
+		Where("published = TRUE").
+		Scan(ctx, &oldest)
+	return oldest, err
+}
@@ -0,0 +1,175 @@
+//go:build integration
+
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	pkgdb "starter-boilerplate/pkg/db"
+	"starter-boilerplate/pkg/testcontainer"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/uptrace/bun"
+)
+
+type OutboxRepoSuite struct {
+	suite.Suite
+	pg   *testcontainer.PgContainer
+	repo *Repository
+}
+
+func TestOutboxRepository(t *testing.T) {
+	if err := os.Chdir("../.."); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+
+	pg, err := testcontainer.SetupPgContainer(context.Background(), &testcontainer.PgContainer{
+		Database: "testdb",
+		Username: "testuser",
+		Password: "testpass",
+		HostPort: "25435",
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "setup pg container: %v\n", err)
+		os.Exit(1)
+	}
+
+	suite.Run(t, &OutboxRepoSuite{pg: pg, repo: NewRepository(pg.DB())})
+}
+
+func (s *OutboxRepoSuite) TearDownSuite() {
+	s.pg.Close()
+	s.pg.Terminate(context.Background())
+}
+
+func (s *OutboxRepoSuite) SetupTest() {
+	s.Require().NoError(s.pg.Clean(context.Background()))
+}
+
+func (s *OutboxRepoSuite) insert(name, partitionKey string) int64 {
+	entry := &Entry{
+		EventName: name,
+		Payload:   []byte(`{}`),
+		Headers:   map[string]any{HeaderPartitionKey: partitionKey},
+	}
+	s.Require().NoError(s.repo.Insert(context.Background(), entry))
+	return entry.ID
+}
+
+// publish marks the entries published at the given unix time, with
+// positions in the order given.
+func (s *OutboxRepoSuite) publish(at int64, ids ...int64) {
+	ctx := context.Background()
+	positions, err := s.repo.NextPositions(ctx, len(ids))
+	s.Require().NoError(err)
+	published := make(map[int64]int64, len(ids))
+	for i, id := range ids {
+		published[id] = positions[i]
+	}
+	s.Require().NoError(s.repo.MarkPublished(ctx, published, at))
+}
+
+func entryIDs(entries []Entry) []int64 {
+	ids := make([]int64, len(entries))
+	for i, e := range entries {
+		ids[i] = e.ID
+	}
+	return ids
+}
+
+func (s *OutboxRepoSuite) TestListPublishedAfter() {
+	ctx := context.Background()
+	first := s.insert("user.created", "u1")
+	second := s.insert("user.logged_in", "u2")
+	third := s.insert("user.logged_in", "u1")
+	s.insert("user.logged_in", "u1") // not published yet
+	other := s.insert("order.created", "u1")
+	// Published out of ID order, as when relays commit in turn.
+	s.publish(100, third, first, second, other)
+
+	all, err := s.repo.ListPublishedAfter(ctx, 0, "user.", "", 10)
+	s.Require().NoError(err)
+	s.Require().Equal([]int64{third, first, second}, entryIDs(all))
+	s.Less(all[0].Position, all[1].Position)
+
+	mine, err := s.repo.ListPublishedAfter(ctx, all[0].Position, "user.", "u1", 10)
+	s.Require().NoError(err)
+	s.Equal([]int64{first}, entryIDs(mine))
+
+	page, err := s.repo.ListPublishedAfter(ctx, 0, "user.", "", 2)
+	s.Require().NoError(err)
+	s.Len(page, 2)
+}
+
+func (s *OutboxRepoSuite) TestDeletePublishedBefore_KeepsPositionsFromOldest() {
+	ctx := context.Background()
+	a := s.insert("user.created", "u1")
+	b := s.insert("user.created", "u2")
+	c := s.insert("user.created", "u3")
+	pending := s.insert("user.created", "u4")
+	s.publish(100, b, a)
+	s.publish(300, c)
+
+	n, err := s.repo.DeletePublishedBefore(ctx, 200)
+	s.Require().NoError(err)
+	s.Equal(int64(2), n)
+
+	left, err := s.repo.ListPublishedAfter(ctx, 0, "user.", "", 10)
+	s.Require().NoError(err)
+	s.Require().Equal([]int64{c}, entryIDs(left))
+	oldest, err := s.repo.OldestPosition(ctx)
+	s.Require().NoError(err)
+	s.Equal(left[0].Position, oldest)
+
+	// The last published entry stays, so OldestPosition still tells where
+	// the outbox starts; unpublished entries are never deleted.
+	n, err = s.repo.DeletePublishedBefore(ctx, 400)
+	s.Require().NoError(err)
+	s.Zero(n)
+	entries, err := s.repo.FetchUnpublished(ctx, 0, 10)
+	s.Require().NoError(err)
+	s.Equal([]int64{pending}, entryIDs(entries))
+}
+
+func (s *OutboxRepoSuite) TestTryLockRelay_OneHolderAtATime() {
+	ctx := context.Background()
+	lock := func() (bun.Tx, bool) {
+		tx, err := s.pg.DB().BeginTx(ctx, nil)
+		s.Require().NoError(err)
+		locked, err := s.repo.TryLockRelay(pkgdb.WithTx(ctx, tx))
+		s.Require().NoError(err)
+		return tx, locked
+	}
+
+	first, locked := lock()
+	s.True(locked)
+	second, locked := lock()
+	s.False(locked)
+	s.Require().NoError(second.Rollback())
+
+	s.Require().NoError(first.Commit())
+	third, locked := lock()
+	s.True(locked, "the lock ends with the transaction")
+	s.Require().NoError(third.Rollback())
+}
+
+func (s *OutboxRepoSuite) TestMarkFailed_SkipsEntryOnFetch() {
+	ctx := context.Background()
+	unroutable := s.insert("user.typo", "u1")
+	next := s.insert("user.created", "u1")
+
+	s.Require().NoError(s.repo.MarkFailed(ctx, map[int64]string{unroutable: "no route"}))
+
+	entries, err := s.repo.FetchUnpublished(ctx, 0, 10)
+	s.Require().NoError(err)
+	s.Require().Len(entries, 1)
+	s.Equal(next, entries[0].ID)
+
+	var failed Entry
+	s.Require().NoError(s.pg.DB().NewSelect().Model(&failed).Where("id = ?", unroutable).Scan(ctx))
+	s.True(failed.Failed)
+	s.Equal("no route", failed.Error)
+}
@@ -0,0 +1,213 @@
+package saga
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	pkgamqp "starter-boilerplate/pkg/amqp"
+	pkgdb "starter-boilerplate/pkg/db"
+	"starter-boilerplate/pkg/outbox"
+)
+
+// Config controls the timeout poller.
+type Config struct {
+	PollInterval time.Duration `yaml:"poll_interval"` // default: 5s
+	BatchSize    int           `yaml:"batch_size"`    // default: 100
+}
+
+func (c Config) withDefaults() Config {
+	if c.PollInterval == 0 {
+		c.PollInterval = 5 * time.Second
+	}
+	if c.BatchSize == 0 {
+		c.BatchSize = 100
+	}
+	return c
+}
+
+type instanceStore interface {
+	Create(ctx context.Context, inst *Instance) (bool, error)
+	GetForUpdate(ctx context.Context, saga, correlationID string) (*Instance, error)
+	Update(ctx context.Context, inst *Instance) error
+	FetchExpired(ctx context.Context, now int64, limit int) ([]Instance, error)
+}
+
+// Manager runs saga steps transactionally and compensates instances whose
+// step timed out. Every state change happens in one transaction together with
+// the commands the step sends through the outbox.
+type Manager struct {
+	uow   pkgdb.UoW
+	store instanceStore
+	bus   outbox.Bus
+	cfg   Config
+	now   func() time.Time
+
+	mu    sync.RWMutex
+	sagas map[string]definition
+}
+
+func NewManager(uow pkgdb.UoW, repo *Repository, bus outbox.Bus, cfg Config) *Manager {
+	return &Manager{
+		uow:   uow,
+		store: repo,
+		bus:   bus,
+		cfg:   cfg.withDefaults(),
+		now:   time.Now,
+		sagas: make(map[string]definition),
+	}
+}
+
+func (m *Manager) register(s definition) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if prev, ok := m.sagas[s.Name()]; ok && prev != s {
+		panic(fmt.Sprintf("saga %s: registered twice", s.Name()))
+	}
+	m.sagas[s.Name()] = s
+}
+
+func (m *Manager) definition(name string) (definition, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	s, ok := m.sagas[name]
+	return s, ok
+}
+
+func (m *Manager) save(ctx context.Context, inst *Instance, data any) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("saga %s: marshal data: %w", inst.Saga, err)
+	}
+	inst.Data = raw
+	inst.UpdatedAt = m.now().Unix()
+	return m.store.Update(ctx, inst)
+}
+
+// Start returns an event handler that starts an instance of s, correlated by
+// correlate(e), and runs fn as its first step. A repeated start event for the
+// same correlation ID is ignored. The handler fits sharedevent.Route.
+func Start[D, T any](m *Manager, s *Saga[D], correlate func(e T) string, fn func(ctx context.Context, sc *Context[D], e T) error) func(ctx context.Context, e T, meta pkgamqp.DeliveryMeta) error {
+	m.register(s)
+
+	return func(ctx context.Context, e T, _ pkgamqp.DeliveryMeta) error {
+		return m.uow.Do(ctx, func(ctx context.Context) error {
+			now := m.now().Unix()
+			inst := &Instance{
+				Saga:          s.name,
+				CorrelationID: correlate(e),
+				Status:        StatusRunning,
+				Step:          s.steps[0].Name,
+				Completed:     []string{},
+				Data:          json.RawMessage(`{}`),
+				CreatedAt:     now,
+				UpdatedAt:     now,
+			}
+			created, err := m.store.Create(ctx, inst)
+			if err != nil {
+				return fmt.Errorf("saga %s: create: %w", s.name, err)
+			}
+			if !created {
+				slog.Debug("saga already started", slog.String("saga", s.name), slog.String("correlation_id", inst.CorrelationID))
+				return nil
+			}
+			return runStep(ctx, m, s, inst, 0, e, fn)
+		})
+	}
+}
+
+// Handle returns an event handler that runs fn as step of the instance of s
+// correlated by correlate(e). Events for unknown, finished or already passed
+// instances are ignored; an event for a step not reached yet returns
+// ErrStepNotReached so it is retried (see [AddConsumer]). The handler fits
+// sharedevent.Route.
+func Handle[D, T any](m *Manager, s *Saga[D], step string, correlate func(e T) string, fn func(ctx context.Context, sc *Context[D], e T) error) func(ctx context.Context, e T, meta pkgamqp.DeliveryMeta) error {
+	idx := s.stepIndex(step)
+	m.register(s)
+
+	return func(ctx context.Context, e T, _ pkgamqp.DeliveryMeta) error {
+		return m.uow.Do(ctx, func(ctx context.Context) error {
+			id := correlate(e)
+			inst, err := m.store.GetForUpdate(ctx, s.name, id)
+			if errors.Is(err, sql.ErrNoRows) {
+				slog.Warn("saga instance not found", slog.String("saga", s.name), slog.String("step", step), slog.String("correlation_id", id))
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("saga %s: load: %w", s.name, err)
+			}
+
+			if inst.Status != StatusRunning || s.stepIndex(inst.Step) > idx {
+				slog.Debug("saga step already passed", slog.String("saga", s.name), slog.String("step", step), slog.String("correlation_id", id))
+				return nil
+			}
+			if inst.Step != step {
+				return fmt.Errorf("saga %s: %s (waiting for %s): %w", s.name, step, inst.Step, ErrStepNotReached)
+			}
+			return runStep(ctx, m, s, inst, idx, e, fn)
+		})
+	}
+}
+
+// AddConsumer registers handler, usually the Handler of a sharedevent.Router
+// with the Start and Handle handlers of sagas, as a raw consumer of b. It sets
+// cfg.RetryOnError, because a failed step and an event for a step not reached
+// yet must be requeued, not dropped or dead-lettered. Queues that cannot
+// requeue (streams) fail the broker's config validation.
+func AddConsumer(b *pkgamqp.Broker, cfg pkgamqp.ConsumerConfig, handler func(ctx context.Context, body []byte, meta pkgamqp.DeliveryMeta) error, mws ...pkgamqp.Middleware) {
+	pkgamqp.AddRawConsumer(b, consumerConfig(cfg), handler, mws...)
+}
+
+func consumerConfig(cfg pkgamqp.ConsumerConfig) pkgamqp.ConsumerConfig {
+	retry := true
+	cfg.RetryOnError = &retry
+	return cfg
+}
+
+// Run compensates timed out instances on a ticker until ctx is cancelled.
+func (m *Manager) Run(ctx context.Context) error {
+	slog.Info("saga timeout poller started",
+		slog.Duration("poll_interval", m.cfg.PollInterval),
+		slog.Int("batch_size", m.cfg.BatchSize),
+	)
+
+	ticker := time.NewTicker(m.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := m.expire(ctx); err != nil {
+				slog.Error("saga timeout poll failed", slog.String("error", err.Error()))
+			}
+		}
+	}
+}
+
+// expire compensates one batch of instances past their deadline.
+func (m *Manager) expire(ctx context.Context) error {
+	return m.uow.Do(ctx, func(ctx context.Context) error {
+		insts, err := m.store.FetchExpired(ctx, m.now().Unix(), m.cfg.BatchSize)
+		if err != nil {
+			return err
+		}
+		for i := range insts {
+			s, ok := m.definition(insts[i].Saga)
+			if !ok {
+				slog.Warn("saga timed out but is not registered", slog.String("saga", insts[i].Saga), slog.Int64("id", insts[i].ID))
+				continue
+			}
+			if err := s.expire(ctx, m, &insts[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,35 @@
+package saga
+
+import (
+	"encoding/json"
+
+	"github.com/uptrace/bun"
+)
+
+// Status is the lifecycle state of a saga instance.
+type Status string
+
+const (
+	StatusRunning     Status = "running"     // waiting for the event of Step
+	StatusCompleted   Status = "completed"   // every step succeeded
+	StatusCompensated Status = "compensated" // aborted or timed out; completed steps were undone
+	StatusFailed      Status = "failed"      // a compensation failed; needs manual attention
+)
+
+// Instance is one run of a saga — saga_instances table row.
+// It is unique per (Saga, CorrelationID).
+type Instance struct {
+	bun.BaseModel `bun:"table:saga_instances"`
+
+	ID            int64           `bun:"id,pk,autoincrement"`
+	Saga          string          `bun:"saga,notnull"`
+	CorrelationID string          `bun:"correlation_id,notnull"`
+	Status        Status          `bun:"status,notnull"`
+	Step          string          `bun:"step,notnull"`                 // step waiting for its event; empty once finished
+	Completed     []string        `bun:"completed,type:jsonb,notnull"` // completed steps in order, undone in reverse
+	Data          json.RawMessage `bun:"data,type:jsonb,notnull"`      // saga data D
+	Error         string          `bun:"error,notnull"`                // abort, timeout or compensation failure reason
+	Deadline      int64           `bun:"deadline,notnull"`             // unix seconds the current step times out at; 0 = never
+	CreatedAt     int64           `bun:"created_at,notnull"`
+	UpdatedAt     int64           `bun:"updated_at,notnull"`
+}
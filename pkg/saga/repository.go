@@ -0,0 +1,101 @@
+package saga
+
+import (
+	"context"
+
+	pkgdb "starter-boilerplate/pkg/db"
+
+	"github.com/uptrace/bun"
+)
+
+// Repository handles saga_instances table operations.
+type Repository struct {
+	db *bun.DB
+}
+
+func NewRepository(db *bun.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// Create inserts inst within the current tx (or fallback db) and reports whether
+// it was inserted. An instance with the same saga and correlation ID is left untouched.
+func (r *Repository) Create(ctx context.Context, inst *Instance) (bool, error) {
+	res, err := pkgdb.Conn(ctx, r.db).NewInsert().
+		Model(inst).
+		ExcludeColumn("id").
+		On("CONFLICT (saga, correlation_id) DO NOTHING").
+		Returning("id").
+		Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// GetForUpdate returns the instance of saga correlated with correlationID, locking it.
+// Returns sql.ErrNoRows if there is none.
+func (r *Repository) GetForUpdate(ctx context.Context, saga, correlationID string) (*Instance, error) {
+	inst := new(Instance)
+	err := pkgdb.Conn(ctx, r.db).NewSelect().
+		Model(inst).
+		Where("saga = ?", saga).
+		Where("correlation_id = ?", correlationID).
+		For("UPDATE").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return inst, nil
+}
+
+// Update saves the mutable state of inst.
+func (r *Repository) Update(ctx context.Context, inst *Instance) error {
+	_, err := pkgdb.Conn(ctx, r.db).NewUpdate().
+		Model(inst).
+		Column("status", "step", "completed", "data", "error", "deadline", "updated_at").
+		WherePK().
+		Exec(ctx)
+	return err
+}
+
+// FetchExpired returns up to limit running instances whose deadline is at or
+// before now, locking them for update.
+func (r *Repository) FetchExpired(ctx context.Context, now int64, limit int) ([]Instance, error) {
+	var insts []Instance
+	err := pkgdb.Conn(ctx, r.db).NewSelect().
+		Model(&insts).
+		Where("status = ?", StatusRunning).
+		Where("deadline > 0 AND deadline <= ?", now).
+		OrderExpr("deadline ASC").
+		Limit(limit).
+		For("UPDATE SKIP LOCKED").
+		Scan(ctx)
+	return insts, err
+}
+
+// Get returns the instance with the given ID. Returns sql.ErrNoRows if there is none.
+func (r *Repository) Get(ctx context.Context, id int64) (*Instance, error) {
+	inst := new(Instance)
+	if err := pkgdb.Conn(ctx, r.db).NewSelect().Model(inst).Where("id = ?", id).Scan(ctx); err != nil {
+		return nil, err
+	}
+	return inst, nil
+}
+
+// ListStuck returns up to limit instances that need attention: failed ones and
+// running ones not updated since before, oldest first.
+func (r *Repository) ListStuck(ctx context.Context, before int64, limit int) ([]Instance, error) {
+	var insts []Instance
+	err := pkgdb.Conn(ctx, r.db).NewSelect().
+		Model(&insts).
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.
+				Where("status = ?", StatusFailed).
+				WhereOr("status = ? AND updated_at < ?", StatusRunning, before)
+		}).
+		OrderExpr("updated_at ASC").
+		Limit(limit).
+		Scan(ctx)
+	return insts, err
+}
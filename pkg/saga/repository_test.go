@@ -0,0 +1,133 @@
+//go:build integration
+
+package saga
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+
+	"starter-boilerplate/pkg/testcontainer"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type SagaRepoSuite struct {
+	suite.Suite
+	pg   *testcontainer.PgContainer
+	repo *Repository
+}
+
+func TestSagaRepository(t *testing.T) {
+	if err := os.Chdir("../.."); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+
+	pg, err := testcontainer.SetupPgContainer(context.Background(), &testcontainer.PgContainer{
+		Database: "testdb",
+		Username: "testuser",
+		Password: "testpass",
+		HostPort: "25433",
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "setup pg container: %v\n", err)
+		os.Exit(1)
+	}
+
+	suite.Run(t, &SagaRepoSuite{pg: pg, repo: NewRepository(pg.DB())})
+}
+
+func (s *SagaRepoSuite) TearDownSuite() {
+	s.pg.Close()
+	s.pg.Terminate(context.Background())
+}
+
+func (s *SagaRepoSuite) SetupTest() {
+	s.Require().NoError(s.pg.Clean(context.Background()))
+}
+
+func newTestInstance(correlationID string, status Status, deadline, updatedAt int64) *Instance {
+	return &Instance{
+		Saga:          "registration",
+		CorrelationID: correlationID,
+		Status:        status,
+		Step:          "profile_created",
+		Completed:     []string{"user_created"},
+		Data:          []byte(`{"email":"a@b.c"}`),
+		Deadline:      deadline,
+		CreatedAt:     updatedAt,
+		UpdatedAt:     updatedAt,
+	}
+}
+
+func (s *SagaRepoSuite) TestCreate_IgnoresDuplicate() {
+	ctx := context.Background()
+
+	created, err := s.repo.Create(ctx, newTestInstance("u1", StatusRunning, 0, 1))
+	s.Require().NoError(err)
+	s.True(created)
+
+	created, err = s.repo.Create(ctx, newTestInstance("u1", StatusRunning, 0, 2))
+	s.Require().NoError(err)
+	s.False(created)
+}
+
+func (s *SagaRepoSuite) TestGetForUpdate_RoundTrip() {
+	ctx := context.Background()
+	inst := newTestInstance("u1", StatusRunning, 100, 1)
+	_, err := s.repo.Create(ctx, inst)
+	s.Require().NoError(err)
+
+	inst.Step = "email_sent"
+	inst.Completed = append(inst.Completed, "profile_created")
+	s.Require().NoError(s.repo.Update(ctx, inst))
+
+	found, err := s.repo.GetForUpdate(ctx, "registration", "u1")
+	s.Require().NoError(err)
+	s.Equal(inst.ID, found.ID)
+	s.Equal("email_sent", found.Step)
+	s.Equal([]string{"user_created", "profile_created"}, found.Completed)
+	s.JSONEq(`{"email":"a@b.c"}`, string(found.Data))
+
+	_, err = s.repo.GetForUpdate(ctx, "registration", "missing")
+	s.ErrorIs(err, sql.ErrNoRows)
+}
+
+func (s *SagaRepoSuite) TestFetchExpired() {
+	ctx := context.Background()
+	for _, inst := range []*Instance{
+		newTestInstance("expired", StatusRunning, 50, 1),
+		newTestInstance("future", StatusRunning, 500, 1),
+		newTestInstance("no-timeout", StatusRunning, 0, 1),
+		newTestInstance("done", StatusCompleted, 50, 1),
+	} {
+		_, err := s.repo.Create(ctx, inst)
+		s.Require().NoError(err)
+	}
+
+	insts, err := s.repo.FetchExpired(ctx, 100, 10)
+	s.Require().NoError(err)
+	s.Require().Len(insts, 1)
+	s.Equal("expired", insts[0].CorrelationID)
+}
+
+func (s *SagaRepoSuite) TestListStuck() {
+	ctx := context.Background()
+	for _, inst := range []*Instance{
+		newTestInstance("stale", StatusRunning, 0, 10),
+		newTestInstance("fresh", StatusRunning, 0, 1000),
+		newTestInstance("failed", StatusFailed, 0, 1000),
+		newTestInstance("done", StatusCompleted, 0, 10),
+	} {
+		_, err := s.repo.Create(ctx, inst)
+		s.Require().NoError(err)
+	}
+
+	insts, err := s.repo.ListStuck(ctx, 500, 10)
+	s.Require().NoError(err)
+	s.Require().Len(insts, 2)
+	s.Equal("stale", insts[0].CorrelationID)
+	s.Equal("failed", insts[1].CorrelationID)
+}
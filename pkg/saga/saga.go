@@ -0,0 +1,189 @@
+package saga
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"starter-boilerplate/pkg/outbox"
+)
+
+// ErrStepNotReached is returned by a step handler for an event that arrived
+// before the instance reached its step. The consumer must retry the message,
+// which [AddConsumer] ensures.
+var ErrStepNotReached = errors.New("saga: step not reached yet")
+
+// Step is one stage of a saga. A running instance waits at a step until the
+// step's event arrives (see [Handle]); the event handler then runs and the
+// instance moves on to the next step.
+type Step[D any] struct {
+	Name string
+
+	// Timeout bounds the wait for the step's event once the instance reaches it.
+	// When it expires the saga is compensated. Zero waits forever.
+	// Ignored for the first step, which runs as the saga starts.
+	Timeout time.Duration
+
+	// Compensate undoes the step after a later step aborts or times out,
+	// typically by sending a compensating command. Optional.
+	Compensate func(ctx context.Context, sc *Context[D]) error
+}
+
+// Saga is a process definition: ordered steps over saga data D.
+// D is persisted as JSON between steps.
+type Saga[D any] struct {
+	name  string
+	steps []Step[D]
+}
+
+// New defines a saga. Panics on an empty or duplicate step name.
+func New[D any](name string, steps ...Step[D]) *Saga[D] {
+	if len(steps) == 0 {
+		panic(fmt.Sprintf("saga %s: no steps", name))
+	}
+	seen := make(map[string]bool, len(steps))
+	for _, st := range steps {
+		if st.Name == "" || seen[st.Name] {
+			panic(fmt.Sprintf("saga %s: empty or duplicate step name %q", name, st.Name))
+		}
+		seen[st.Name] = true
+	}
+	return &Saga[D]{name: name, steps: steps}
+}
+
+// Name returns the saga name.
+func (s *Saga[D]) Name() string { return s.name }
+
+func (s *Saga[D]) stepIndex(name string) int {
+	for i, st := range s.steps {
+		if st.Name == name {
+			return i
+		}
+	}
+	panic(fmt.Sprintf("saga %s: unknown step %q", s.name, name))
+}
+
+// Context is passed to step handlers and compensations.
+// Changes to Data are saved when the handler returns nil.
+type Context[D any] struct {
+	ID            int64
+	CorrelationID string
+	Data          D
+
+	bus outbox.Bus
+}
+
+// Send publishes a command through the outbox, in the same transaction as
+// the saga state change.
+func (c *Context[D]) Send(ctx context.Context, cmd outbox.Event) error {
+	return c.bus.Publish(ctx, cmd)
+}
+
+type abortError struct {
+	err error
+}
+
+func (e *abortError) Error() string { return e.err.Error() }
+func (e *abortError) Unwrap() error { return e.err }
+
+// Abort wraps err so that returning it from a step handler compensates the
+// saga instead of retrying the event. The failed step itself is not compensated.
+func Abort(err error) error {
+	return &abortError{err: err}
+}
+
+// definition is the untyped view of a Saga used by the Manager.
+type definition interface {
+	Name() string
+	expire(ctx context.Context, m *Manager, inst *Instance) error
+}
+
+func (s *Saga[D]) newContext(inst *Instance, bus outbox.Bus) (*Context[D], error) {
+	sc := &Context[D]{ID: inst.ID, CorrelationID: inst.CorrelationID, bus: bus}
+	if len(inst.Data) > 0 {
+		if err := json.Unmarshal(inst.Data, &sc.Data); err != nil {
+			return nil, fmt.Errorf("saga %s: unmarshal data: %w", s.name, err)
+		}
+	}
+	return sc, nil
+}
+
+// runStep runs fn for step idx of inst and saves the outcome. It runs inside
+// the handler's transaction; a non-abort error is returned so the transaction
+// rolls back and the event is redelivered.
+func runStep[D, T any](ctx context.Context, m *Manager, s *Saga[D], inst *Instance, idx int, e T, fn func(ctx context.Context, sc *Context[D], e T) error) error {
+	sc, err := s.newContext(inst, m.bus)
+	if err != nil {
+		return err
+	}
+
+	var abort *abortError
+	switch err := fn(ctx, sc, e); {
+	case errors.As(err, &abort):
+		return s.compensate(ctx, m, inst, sc, fmt.Sprintf("step %s aborted: %v", s.steps[idx].Name, abort.err))
+	case err != nil:
+		return err
+	}
+
+	inst.Completed = append(inst.Completed, s.steps[idx].Name)
+	if next := idx + 1; next < len(s.steps) {
+		inst.Step = s.steps[next].Name
+		inst.Deadline = 0
+		if t := s.steps[next].Timeout; t > 0 {
+			inst.Deadline = m.now().Add(t).Unix()
+		}
+	} else {
+		inst.Status = StatusCompleted
+		inst.Step = ""
+		inst.Deadline = 0
+	}
+	return m.save(ctx, inst, sc.Data)
+}
+
+// compensate undoes the completed steps of inst in reverse order and saves the
+// result: compensated, or failed if a compensation returned an error.
+func (s *Saga[D]) compensate(ctx context.Context, m *Manager, inst *Instance, sc *Context[D], reason string) error {
+	slog.Warn("saga compensating",
+		slog.String("saga", s.name),
+		slog.String("correlation_id", inst.CorrelationID),
+		slog.String("reason", reason))
+
+	inst.Status = StatusCompensated
+	inst.Error = reason
+	inst.Deadline = 0
+
+	for _, name := range slices.Backward(inst.Completed) {
+		st := s.steps[s.stepIndex(name)]
+		if st.Compensate == nil {
+			continue
+		}
+		if err := st.Compensate(ctx, sc); err != nil {
+			slog.Error("saga compensation failed",
+				slog.String("saga", s.name),
+				slog.String("correlation_id", inst.CorrelationID),
+				slog.String("step", name),
+				slog.Any("error", err))
+			inst.Status = StatusFailed
+			inst.Error = fmt.Sprintf("%s; compensate %s: %v", reason, name, err)
+			break
+		}
+		inst.Completed = inst.Completed[:len(inst.Completed)-1]
+	}
+
+	if inst.Status == StatusCompensated {
+		inst.Step = ""
+	}
+	return m.save(ctx, inst, sc.Data)
+}
+
+func (s *Saga[D]) expire(ctx context.Context, m *Manager, inst *Instance) error {
+	sc, err := s.newContext(inst, m.bus)
+	if err != nil {
+		return err
+	}
+	return s.compensate(ctx, m, inst, sc, fmt.Sprintf("step %s timed out", inst.Step))
+}
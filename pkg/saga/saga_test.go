@@ -0,0 +1,266 @@
+//go:build unit
+
+package saga
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	pkgamqp "starter-boilerplate/pkg/amqp"
+	"starter-boilerplate/pkg/outbox"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeUoW struct{}
+
+func (fakeUoW) Do(ctx context.Context, fn func(ctx context.Context) error, _ ...*sql.TxOptions) error {
+	return fn(ctx)
+}
+
+type fakeStore struct {
+	byKey map[string]*Instance
+	next  int64
+}
+
+func newFakeStore() *fakeStore { return &fakeStore{byKey: map[string]*Instance{}} }
+
+func (s *fakeStore) Create(_ context.Context, inst *Instance) (bool, error) {
+	key := inst.Saga + "/" + inst.CorrelationID
+	if _, ok := s.byKey[key]; ok {
+		return false, nil
+	}
+	s.next++
+	inst.ID = s.next
+	cp := *inst
+	s.byKey[key] = &cp
+	return true, nil
+}
+
+func (s *fakeStore) GetForUpdate(_ context.Context, saga, correlationID string) (*Instance, error) {
+	inst, ok := s.byKey[saga+"/"+correlationID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	cp := *inst
+	cp.Completed = append([]string(nil), inst.Completed...)
+	return &cp, nil
+}
+
+func (s *fakeStore) Update(_ context.Context, inst *Instance) error {
+	cp := *inst
+	s.byKey[inst.Saga+"/"+inst.CorrelationID] = &cp
+	return nil
+}
+
+func (s *fakeStore) FetchExpired(_ context.Context, now int64, _ int) ([]Instance, error) {
+	var out []Instance
+	for _, inst := range s.byKey {
+		if inst.Status == StatusRunning && inst.Deadline > 0 && inst.Deadline <= now {
+			out = append(out, *inst)
+		}
+	}
+	return out, nil
+}
+
+type sentCommand struct{ Name string }
+
+func (c sentCommand) EventName() string { return c.Name }
+
+type fakeBus struct{ sent []string }
+
+func (b *fakeBus) Publish(_ context.Context, e outbox.Event) error {
+	b.sent = append(b.sent, e.EventName())
+	return nil
+}
+
+type regData struct {
+	Email     string `json:"email"`
+	ProfileID string `json:"profile_id"`
+}
+
+type userCreated struct{ UserID, Email string }
+type profileCreated struct{ UserID, ProfileID string }
+type emailSent struct{ UserID string }
+
+type fixture struct {
+	m     *Manager
+	store *fakeStore
+	bus   *fakeBus
+	clock time.Time
+
+	start          func(context.Context, userCreated, pkgamqp.DeliveryMeta) error
+	onProfile      func(context.Context, profileCreated, pkgamqp.DeliveryMeta) error
+	onEmail        func(context.Context, emailSent, pkgamqp.DeliveryMeta) error
+	profileErr     error
+	compensateFail bool
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	f := &fixture{store: newFakeStore(), bus: &fakeBus{}, clock: time.Unix(1000, 0)}
+	f.m = &Manager{uow: fakeUoW{}, store: f.store, bus: f.bus, cfg: Config{}.withDefaults(), now: func() time.Time { return f.clock }, sagas: map[string]definition{}}
+
+	s := New("registration",
+		Step[regData]{Name: "user_created", Compensate: func(ctx context.Context, sc *Context[regData]) error {
+			if f.compensateFail {
+				return errors.New("crm down")
+			}
+			return sc.Send(ctx, sentCommand{"user.delete"})
+		}},
+		Step[regData]{Name: "profile_created", Timeout: time.Minute, Compensate: func(ctx context.Context, sc *Context[regData]) error {
+			return sc.Send(ctx, sentCommand{"profile.delete." + sc.Data.ProfileID})
+		}},
+		Step[regData]{Name: "email_sent", Timeout: time.Hour},
+	)
+
+	f.start = Start(f.m, s, func(e userCreated) string { return e.UserID },
+		func(ctx context.Context, sc *Context[regData], e userCreated) error {
+			sc.Data.Email = e.Email
+			return sc.Send(ctx, sentCommand{"profile.create"})
+		})
+	f.onProfile = Handle(f.m, s, "profile_created", func(e profileCreated) string { return e.UserID },
+		func(ctx context.Context, sc *Context[regData], e profileCreated) error {
+			if f.profileErr != nil {
+				return f.profileErr
+			}
+			sc.Data.ProfileID = e.ProfileID
+			return sc.Send(ctx, sentCommand{"email.send." + sc.Data.Email})
+		})
+	f.onEmail = Handle(f.m, s, "email_sent", func(e emailSent) string { return e.UserID },
+		func(context.Context, *Context[regData], emailSent) error { return nil })
+	return f
+}
+
+func (f *fixture) instance(t *testing.T) *Instance {
+	t.Helper()
+	inst, err := f.store.GetForUpdate(context.Background(), "registration", "u1")
+	require.NoError(t, err)
+	return inst
+}
+
+func TestSaga_HappyPath(t *testing.T) {
+	f := newFixture(t)
+
+	require.NoError(t, f.start(context.Background(), userCreated{"u1", "a@b.c"}, pkgamqp.DeliveryMeta{}))
+	inst := f.instance(t)
+	assert.Equal(t, StatusRunning, inst.Status)
+	assert.Equal(t, "profile_created", inst.Step)
+	assert.Equal(t, f.clock.Add(time.Minute).Unix(), inst.Deadline)
+
+	require.NoError(t, f.onProfile(context.Background(), profileCreated{"u1", "p1"}, pkgamqp.DeliveryMeta{}))
+	require.NoError(t, f.onEmail(context.Background(), emailSent{"u1"}, pkgamqp.DeliveryMeta{}))
+
+	inst = f.instance(t)
+	assert.Equal(t, StatusCompleted, inst.Status)
+	assert.Empty(t, inst.Step)
+	assert.Equal(t, []string{"user_created", "profile_created", "email_sent"}, inst.Completed)
+	assert.JSONEq(t, `{"email":"a@b.c","profile_id":"p1"}`, string(inst.Data))
+	assert.Equal(t, []string{"profile.create", "email.send.a@b.c"}, f.bus.sent)
+}
+
+func TestSaga_DuplicateAndLateEventsAreIgnored(t *testing.T) {
+	f := newFixture(t)
+	require.NoError(t, f.start(context.Background(), userCreated{"u1", "a@b.c"}, pkgamqp.DeliveryMeta{}))
+	require.NoError(t, f.onProfile(context.Background(), profileCreated{"u1", "p1"}, pkgamqp.DeliveryMeta{}))
+
+	require.NoError(t, f.start(context.Background(), userCreated{"u1", "other@b.c"}, pkgamqp.DeliveryMeta{}))
+	require.NoError(t, f.onProfile(context.Background(), profileCreated{"u1", "p2"}, pkgamqp.DeliveryMeta{}))
+	require.NoError(t, f.onProfile(context.Background(), profileCreated{"unknown", "p3"}, pkgamqp.DeliveryMeta{}))
+
+	inst := f.instance(t)
+	assert.Equal(t, "email_sent", inst.Step)
+	assert.JSONEq(t, `{"email":"a@b.c","profile_id":"p1"}`, string(inst.Data))
+	assert.Len(t, f.bus.sent, 2)
+}
+
+func TestSaga_EarlyEventIsRetried(t *testing.T) {
+	f := newFixture(t)
+	require.NoError(t, f.start(context.Background(), userCreated{"u1", "a@b.c"}, pkgamqp.DeliveryMeta{}))
+
+	err := f.onEmail(context.Background(), emailSent{"u1"}, pkgamqp.DeliveryMeta{})
+
+	assert.ErrorIs(t, err, ErrStepNotReached)
+	assert.Equal(t, "profile_created", f.instance(t).Step)
+}
+
+func TestSaga_TransientErrorLeavesStateUntouched(t *testing.T) {
+	f := newFixture(t)
+	require.NoError(t, f.start(context.Background(), userCreated{"u1", "a@b.c"}, pkgamqp.DeliveryMeta{}))
+	f.profileErr = errors.New("db busy")
+
+	err := f.onProfile(context.Background(), profileCreated{"u1", "p1"}, pkgamqp.DeliveryMeta{})
+
+	assert.ErrorContains(t, err, "db busy")
+	inst := f.instance(t)
+	assert.Equal(t, StatusRunning, inst.Status)
+	assert.Equal(t, "profile_created", inst.Step)
+}
+
+func TestSaga_AbortCompensatesCompletedSteps(t *testing.T) {
+	f := newFixture(t)
+	require.NoError(t, f.start(context.Background(), userCreated{"u1", "a@b.c"}, pkgamqp.DeliveryMeta{}))
+	f.profileErr = Abort(errors.New("invalid profile"))
+
+	require.NoError(t, f.onProfile(context.Background(), profileCreated{"u1", "p1"}, pkgamqp.DeliveryMeta{}))
+
+	inst := f.instance(t)
+	assert.Equal(t, StatusCompensated, inst.Status)
+	assert.Empty(t, inst.Step)
+	assert.Empty(t, inst.Completed)
+	assert.Equal(t, "step profile_created aborted: invalid profile", inst.Error)
+	assert.Equal(t, []string{"profile.create", "user.delete"}, f.bus.sent)
+}
+
+func TestSaga_TimeoutCompensatesInReverseOrder(t *testing.T) {
+	f := newFixture(t)
+	require.NoError(t, f.start(context.Background(), userCreated{"u1", "a@b.c"}, pkgamqp.DeliveryMeta{}))
+	require.NoError(t, f.onProfile(context.Background(), profileCreated{"u1", "p1"}, pkgamqp.DeliveryMeta{}))
+
+	f.clock = f.clock.Add(30 * time.Minute)
+	require.NoError(t, f.m.expire(context.Background()))
+	assert.Equal(t, StatusRunning, f.instance(t).Status, "deadline not reached")
+
+	f.clock = f.clock.Add(time.Hour)
+	require.NoError(t, f.m.expire(context.Background()))
+
+	inst := f.instance(t)
+	assert.Equal(t, StatusCompensated, inst.Status)
+	assert.Equal(t, "step email_sent timed out", inst.Error)
+	assert.Equal(t, []string{"profile.create", "email.send.a@b.c", "profile.delete.p1", "user.delete"}, f.bus.sent)
+}
+
+func TestSaga_FailedCompensationIsRecorded(t *testing.T) {
+	f := newFixture(t)
+	require.NoError(t, f.start(context.Background(), userCreated{"u1", "a@b.c"}, pkgamqp.DeliveryMeta{}))
+	require.NoError(t, f.onProfile(context.Background(), profileCreated{"u1", "p1"}, pkgamqp.DeliveryMeta{}))
+	f.compensateFail = true
+
+	f.clock = f.clock.Add(2 * time.Hour)
+	require.NoError(t, f.m.expire(context.Background()))
+
+	inst := f.instance(t)
+	assert.Equal(t, StatusFailed, inst.Status)
+	assert.Equal(t, "email_sent", inst.Step)
+	assert.Equal(t, []string{"user_created"}, inst.Completed, "steps left to compensate")
+	assert.Equal(t, "step email_sent timed out; compensate user_created: crm down", inst.Error)
+}
+
+func TestNew_PanicsOnDuplicateStep(t *testing.T) {
+	assert.Panics(t, func() { New("s", Step[regData]{Name: "a"}, Step[regData]{Name: "a"}) })
+	assert.Panics(t, func() { New[regData]("s") })
+}
+
+func TestConsumerConfig_RetriesOnError(t *testing.T) {
+	noRetry := false
+	cfg := consumerConfig(pkgamqp.ConsumerConfig{Queue: "registration.saga", RetryOnError: &noRetry})
+
+	require.NotNil(t, cfg.RetryOnError)
+	assert.True(t, *cfg.RetryOnError, "early events must be requeued, not dropped")
+	assert.Equal(t, "registration.saga", cfg.Queue)
+	assert.False(t, noRetry, "the caller's config is not changed")
+}
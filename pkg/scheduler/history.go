@@ -0,0 +1,67 @@
+package scheduler
+
+import (
+	"context"
+
+	pkgdb "starter-boilerplate/pkg/db"
+
+	"github.com/uptrace/bun"
+)
+
+// RunStatus is the outcome of a job run.
+type RunStatus string
+
+const (
+	RunSucceeded RunStatus = "succeeded"
+	RunFailed    RunStatus = "failed"    // returned an error or panicked
+	RunTimedOut  RunStatus = "timed_out" // exceeded Job.Timeout
+)
+
+// Run is one execution of a job — scheduler_runs table row.
+type Run struct {
+	bun.BaseModel `bun:"table:scheduler_runs"`
+
+	ID         int64     `bun:"id,pk,autoincrement"`
+	Job        string    `bun:"job,notnull"`
+	Node       string    `bun:"node,notnull"` // hostname of the leader that ran it
+	Status     RunStatus `bun:"status,notnull"`
+	Error      string    `bun:"error,notnull"`
+	StartedAt  int64     `bun:"started_at,notnull"` // unix seconds
+	DurationMs int64     `bun:"duration_ms,notnull"`
+}
+
+// Repository handles scheduler_runs table operations.
+type Repository struct {
+	db *bun.DB
+}
+
+func NewRepository(db *bun.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// Insert records a run.
+func (r *Repository) Insert(ctx context.Context, run *Run) error {
+	_, err := pkgdb.Conn(ctx, r.db).NewInsert().Model(run).ExcludeColumn("id").Exec(ctx)
+	return err
+}
+
+// DeleteBefore removes the runs of job started before the given unix time.
+func (r *Repository) DeleteBefore(ctx context.Context, job string, before int64) error {
+	_, err := pkgdb.Conn(ctx, r.db).NewDelete().
+		Model((*Run)(nil)).
+		Where("job = ?", job).
+		Where("started_at < ?", before).
+		Exec(ctx)
+	return err
+}
+
+// List returns up to limit most recent runs, of job only if it is not empty.
+func (r *Repository) List(ctx context.Context, job string, limit int) ([]Run, error) {
+	var runs []Run
+	q := pkgdb.Conn(ctx, r.db).NewSelect().Model(&runs)
+	if job != "" {
+		q = q.Where("job = ?", job)
+	}
+	err := q.OrderExpr("started_at DESC, id DESC").Limit(limit).Scan(ctx)
+	return runs, err
+}
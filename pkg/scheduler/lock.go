@@ -0,0 +1,67 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/uptrace/bun"
+)
+
+// leaderLock is a cluster-wide exclusive lock. The holder is the leader.
+type leaderLock interface {
+	// TryAcquire takes the lock if it is free and reports whether it did.
+	TryAcquire(ctx context.Context) (bool, error)
+	// Check returns an error once the lock is no longer held.
+	Check(ctx context.Context) error
+	// Release gives the lock up.
+	Release(ctx context.Context)
+}
+
+// advisoryLock is a Postgres session-level advisory lock. The session is a
+// dedicated connection held while leading: if the process dies the
+// connection closes and Postgres frees the lock.
+type advisoryLock struct {
+	db   *bun.DB
+	key  int64
+	conn *bun.Conn
+}
+
+func (l *advisoryLock) TryAcquire(ctx context.Context) (bool, error) {
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var acquired bool
+	if err := conn.NewRaw("SELECT pg_try_advisory_lock(?)", l.key).Scan(ctx, &acquired); err != nil {
+		_ = conn.Close()
+		return false, err
+	}
+	if !acquired {
+		_ = conn.Close()
+		return false, nil
+	}
+
+	l.conn = &conn
+	return true, nil
+}
+
+func (l *advisoryLock) Check(ctx context.Context) error {
+	if l.conn == nil {
+		return errors.New("scheduler: lock not held")
+	}
+	_, err := l.conn.ExecContext(ctx, "SELECT 1")
+	return err
+}
+
+func (l *advisoryLock) Release(ctx context.Context) {
+	if l.conn == nil {
+		return
+	}
+	if _, err := l.conn.ExecContext(ctx, "SELECT pg_advisory_unlock(?)", l.key); err != nil {
+		slog.Warn("scheduler: advisory unlock failed", slog.Any("error", err))
+	}
+	_ = l.conn.Close()
+	l.conn = nil
+}
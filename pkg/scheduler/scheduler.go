@@ -0,0 +1,304 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/uptrace/bun"
+)
+
+// Config controls leader election and run history.
+type Config struct {
+	// LockKey is the Postgres advisory lock key. Replicas sharing it elect
+	// one leader, which alone runs jobs. Default: 7_240_001.
+	LockKey int64 `yaml:"lock_key"`
+	// LeaderCheck is how often a follower tries to take the lock and the
+	// leader checks it still holds it. Default: 5s.
+	LeaderCheck time.Duration `yaml:"leader_check"`
+	// HistoryRetention is how long runs are kept. Default: 168h.
+	HistoryRetention time.Duration `yaml:"history_retention"`
+}
+
+func (c Config) withDefaults() Config {
+	if c.LockKey == 0 {
+		c.LockKey = 7_240_001
+	}
+	if c.LeaderCheck == 0 {
+		c.LeaderCheck = 5 * time.Second
+	}
+	if c.HistoryRetention == 0 {
+		c.HistoryRetention = 7 * 24 * time.Hour
+	}
+	return c
+}
+
+// Job is a periodic task. Only the leader replica runs it; a run is skipped
+// while the previous run of the same job is still in progress.
+type Job struct {
+	Name string
+	// Schedule is a cron expression (minute hour day-of-month month day-of-week)
+	// or a descriptor such as @hourly or @every 10m.
+	Schedule string
+	// Timeout cancels the run's context. Default: 1m.
+	Timeout time.Duration
+	Run     func(ctx context.Context) error
+}
+
+func (j Job) timeout() time.Duration {
+	if j.Timeout <= 0 {
+		return time.Minute
+	}
+	return j.Timeout
+}
+
+type historyStore interface {
+	Insert(ctx context.Context, run *Run) error
+	DeleteBefore(ctx context.Context, job string, before int64) error
+}
+
+type entry struct {
+	job      Job
+	schedule cron.Schedule
+	next     time.Time
+	running  atomic.Bool
+}
+
+// Scheduler runs registered jobs on exactly one replica, elected with a
+// Postgres advisory lock, and records every run.
+type Scheduler struct {
+	lock    leaderLock
+	history historyStore
+	cfg     Config
+	node    string
+	now     func() time.Time
+
+	jobs    []*entry
+	running sync.WaitGroup
+
+	mu         sync.Mutex
+	cancelJobs context.CancelFunc
+	idle       chan struct{} // closed when Run returns
+}
+
+func NewScheduler(db *bun.DB, repo *Repository, cfg Config) *Scheduler {
+	cfg = cfg.withDefaults()
+	node, _ := os.Hostname()
+	return &Scheduler{
+		lock:    &advisoryLock{db: db, key: cfg.LockKey},
+		history: repo,
+		cfg:     cfg,
+		node:    node,
+		now:     time.Now,
+		idle:    make(chan struct{}),
+	}
+}
+
+// Register adds a job. Call it during setup, before Run.
+// Panics on an invalid schedule or a duplicate name.
+func (s *Scheduler) Register(job Job) {
+	sched, err := cron.ParseStandard(job.Schedule)
+	if err != nil {
+		panic(fmt.Sprintf("scheduler: job %s: invalid schedule %q: %v", job.Name, job.Schedule, err))
+	}
+	s.add(job, sched)
+}
+
+func (s *Scheduler) add(job Job, sched cron.Schedule) {
+	for _, e := range s.jobs {
+		if e.job.Name == job.Name {
+			panic(fmt.Sprintf("scheduler: job %s registered twice", job.Name))
+		}
+	}
+	s.jobs = append(s.jobs, &entry{job: job, schedule: sched})
+}
+
+// Run campaigns for leadership until ctx is cancelled and, while leading,
+// starts jobs when they are due. After ctx is cancelled it waits for the
+// started jobs (see Shutdown) and then releases leadership.
+func (s *Scheduler) Run(ctx context.Context) error {
+	defer close(s.idle)
+
+	if len(s.jobs) == 0 {
+		return nil
+	}
+
+	slog.Info("scheduler started", slog.Int("jobs", len(s.jobs)), slog.String("node", s.node))
+
+	ticker := time.NewTicker(s.cfg.LeaderCheck)
+	defer ticker.Stop()
+
+	for {
+		acquired, err := s.lock.TryAcquire(ctx)
+		if err != nil && ctx.Err() == nil {
+			slog.Error("scheduler: leader election failed", slog.Any("error", err))
+		}
+		if acquired {
+			slog.Info("scheduler: became leader", slog.String("node", s.node))
+			s.lead(ctx)
+			if ctx.Err() != nil {
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Shutdown waits for running jobs until ctx is done, then cancels them.
+// It returns once Run has returned.
+func (s *Scheduler) Shutdown(ctx context.Context) {
+	select {
+	case <-s.idle:
+		return
+	case <-ctx.Done():
+	}
+
+	s.mu.Lock()
+	if s.cancelJobs != nil {
+		slog.Warn("scheduler: shutdown deadline reached, cancelling running jobs")
+		s.cancelJobs()
+	}
+	s.mu.Unlock()
+	<-s.idle
+}
+
+// lead starts due jobs until ctx is cancelled or leadership is lost.
+// The lock is released only after the started jobs have returned, so
+// another replica never runs a job concurrently with this one.
+func (s *Scheduler) lead(ctx context.Context) {
+	jobsCtx, cancelJobs := context.WithCancel(context.WithoutCancel(ctx))
+	s.mu.Lock()
+	s.cancelJobs = cancelJobs
+	s.mu.Unlock()
+
+	defer func() {
+		s.running.Wait()
+		cancelJobs()
+		rctx, rcancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer rcancel()
+		s.lock.Release(rctx)
+		slog.Info("scheduler: leadership released", slog.String("node", s.node))
+	}()
+
+	now := s.now()
+	for _, e := range s.jobs {
+		e.next = e.schedule.Next(now)
+	}
+
+	check := time.NewTicker(s.cfg.LeaderCheck)
+	defer check.Stop()
+
+	for {
+		timer := time.NewTimer(s.nextDue().Sub(s.now()))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-check.C:
+			timer.Stop()
+			if err := s.lock.Check(ctx); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				slog.Error("scheduler: leadership lost, cancelling running jobs", slog.Any("error", err))
+				cancelJobs()
+				return
+			}
+		case <-timer.C:
+			s.startDue(jobsCtx)
+		}
+	}
+}
+
+func (s *Scheduler) nextDue() time.Time {
+	next := s.jobs[0].next
+	for _, e := range s.jobs[1:] {
+		if e.next.Before(next) {
+			next = e.next
+		}
+	}
+	return next
+}
+
+func (s *Scheduler) startDue(ctx context.Context) {
+	now := s.now()
+	for _, e := range s.jobs {
+		if e.next.After(now) {
+			continue
+		}
+		e.next = e.schedule.Next(now)
+
+		if !e.running.CompareAndSwap(false, true) {
+			slog.Warn("scheduler: previous run still in progress, skipping", slog.String("job", e.job.Name))
+			continue
+		}
+		s.running.Add(1)
+		go func() {
+			defer s.running.Done()
+			defer e.running.Store(false)
+			s.runJob(ctx, e.job)
+		}()
+	}
+}
+
+// runJob runs job with its timeout and panic recovery and records the run.
+func (s *Scheduler) runJob(ctx context.Context, job Job) {
+	ctx, cancel := context.WithTimeout(ctx, job.timeout())
+	defer cancel()
+
+	started := s.now()
+	err := safeRun(ctx, job.Run)
+
+	run := &Run{
+		Job:        job.Name,
+		Node:       s.node,
+		Status:     RunSucceeded,
+		StartedAt:  started.Unix(),
+		DurationMs: s.now().Sub(started).Milliseconds(),
+	}
+	switch {
+	case err == nil:
+		slog.Info("scheduler: job succeeded", slog.String("job", job.Name), slog.Int64("duration_ms", run.DurationMs))
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		run.Status, run.Error = RunTimedOut, err.Error()
+		slog.Error("scheduler: job timed out", slog.String("job", job.Name), slog.Any("error", err))
+	default:
+		run.Status, run.Error = RunFailed, err.Error()
+		slog.Error("scheduler: job failed", slog.String("job", job.Name), slog.Any("error", err))
+	}
+
+	hctx, hcancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer hcancel()
+	if err := s.history.Insert(hctx, run); err != nil {
+		slog.Error("scheduler: record run failed", slog.String("job", job.Name), slog.Any("error", err))
+	}
+	if err := s.history.DeleteBefore(hctx, job.Name, started.Add(-s.cfg.HistoryRetention).Unix()); err != nil {
+		slog.Error("scheduler: prune history failed", slog.String("job", job.Name), slog.Any("error", err))
+	}
+}
+
+func safeRun(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("scheduler: job panic",
+				slog.Any("panic", r),
+				slog.String("stack", string(debug.Stack())),
+			)
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return fn(ctx)
+}
@@ -0,0 +1,219 @@
+//go:build unit
+
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeLock struct {
+	free     atomic.Bool
+	lost     atomic.Bool
+	released atomic.Int32
+}
+
+func (l *fakeLock) TryAcquire(context.Context) (bool, error) { return l.free.Load(), nil }
+
+func (l *fakeLock) Check(context.Context) error {
+	if l.lost.Load() {
+		return errors.New("connection reset")
+	}
+	return nil
+}
+
+func (l *fakeLock) Release(context.Context) { l.released.Add(1) }
+
+type fakeHistory struct {
+	mu   sync.Mutex
+	runs []Run
+}
+
+func (h *fakeHistory) Insert(_ context.Context, run *Run) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.runs = append(h.runs, *run)
+	return nil
+}
+
+func (h *fakeHistory) DeleteBefore(context.Context, string, int64) error { return nil }
+
+func (h *fakeHistory) byJob(job string) []Run {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var out []Run
+	for _, r := range h.runs {
+		if r.Job == job {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// every fires at a fixed interval, below cron's one-second resolution.
+type every time.Duration
+
+func (e every) Next(t time.Time) time.Time { return t.Add(time.Duration(e)) }
+
+func newTestScheduler(lock *fakeLock, history *fakeHistory) *Scheduler {
+	return &Scheduler{
+		lock:    lock,
+		history: history,
+		cfg:     Config{LeaderCheck: 5 * time.Millisecond}.withDefaults(),
+		node:    "test",
+		now:     time.Now,
+		idle:    make(chan struct{}),
+	}
+}
+
+// start runs s until the test ends.
+func start(t *testing.T, s *Scheduler) context.CancelFunc {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() { _ = s.Run(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		shutdownCtx, done := context.WithTimeout(context.Background(), time.Second)
+		defer done()
+		s.Shutdown(shutdownCtx)
+	})
+	return cancel
+}
+
+func TestScheduler_RunsJobsOnlyWhileLeader(t *testing.T) {
+	lock := &fakeLock{}
+	history := &fakeHistory{}
+	s := newTestScheduler(lock, history)
+	var calls atomic.Int32
+	s.add(Job{Name: "tick", Run: func(context.Context) error { calls.Add(1); return nil }}, every(5*time.Millisecond))
+	start(t, s)
+
+	time.Sleep(30 * time.Millisecond)
+	assert.Zero(t, calls.Load(), "follower does not run jobs")
+
+	lock.free.Store(true)
+	require.Eventually(t, func() bool { return calls.Load() >= 2 }, time.Second, time.Millisecond)
+
+	runs := history.byJob("tick")
+	require.NotEmpty(t, runs)
+	assert.Equal(t, RunSucceeded, runs[0].Status)
+	assert.Equal(t, "test", runs[0].Node)
+}
+
+func TestScheduler_RecordsFailuresTimeoutsAndPanics(t *testing.T) {
+	lock := &fakeLock{}
+	lock.free.Store(true)
+	history := &fakeHistory{}
+	s := newTestScheduler(lock, history)
+	s.add(Job{Name: "fail", Run: func(context.Context) error { return errors.New("boom") }}, every(5*time.Millisecond))
+	s.add(Job{Name: "slow", Timeout: 5 * time.Millisecond, Run: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}}, every(5*time.Millisecond))
+	s.add(Job{Name: "panic", Run: func(context.Context) error { panic("nil map") }}, every(5*time.Millisecond))
+	start(t, s)
+
+	require.Eventually(t, func() bool {
+		return len(history.byJob("fail")) > 0 && len(history.byJob("slow")) > 0 && len(history.byJob("panic")) > 0
+	}, time.Second, time.Millisecond)
+
+	assert.Equal(t, RunFailed, history.byJob("fail")[0].Status)
+	assert.Equal(t, "boom", history.byJob("fail")[0].Error)
+	assert.Equal(t, RunTimedOut, history.byJob("slow")[0].Status)
+	assert.Equal(t, RunFailed, history.byJob("panic")[0].Status)
+	assert.Equal(t, "panic: nil map", history.byJob("panic")[0].Error)
+}
+
+func TestScheduler_SkipsOverlappingRuns(t *testing.T) {
+	lock := &fakeLock{}
+	lock.free.Store(true)
+	s := newTestScheduler(lock, &fakeHistory{})
+	var concurrent, maxConcurrent atomic.Int32
+	release := make(chan struct{})
+	s.add(Job{Name: "long", Run: func(context.Context) error {
+		n := concurrent.Add(1)
+		defer concurrent.Add(-1)
+		if n > maxConcurrent.Load() {
+			maxConcurrent.Store(n)
+		}
+		<-release
+		return nil
+	}}, every(2*time.Millisecond))
+	start(t, s)
+
+	require.Eventually(t, func() bool { return concurrent.Load() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+
+	assert.Equal(t, int32(1), maxConcurrent.Load())
+}
+
+func TestScheduler_LostLeadershipCancelsJobs(t *testing.T) {
+	lock := &fakeLock{}
+	lock.free.Store(true)
+	s := newTestScheduler(lock, &fakeHistory{})
+	cancelled := make(chan struct{}, 1)
+	s.add(Job{Name: "long", Timeout: time.Hour, Run: func(ctx context.Context) error {
+		<-ctx.Done()
+		select {
+		case cancelled <- struct{}{}:
+		default:
+		}
+		return ctx.Err()
+	}}, every(time.Millisecond))
+	start(t, s)
+
+	time.Sleep(10 * time.Millisecond)
+	lock.free.Store(false)
+	lock.lost.Store(true)
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("job was not cancelled after leadership was lost")
+	}
+	require.Eventually(t, func() bool { return lock.released.Load() == 1 }, time.Second, time.Millisecond)
+}
+
+func TestScheduler_ShutdownWaitsThenCancels(t *testing.T) {
+	lock := &fakeLock{}
+	lock.free.Store(true)
+	s := newTestScheduler(lock, &fakeHistory{})
+	started := make(chan struct{}, 1)
+	s.add(Job{Name: "long", Timeout: time.Hour, Run: func(ctx context.Context) error {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	}}, every(time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() { _ = s.Run(ctx) }()
+	<-started
+	cancel()
+
+	shutdownCtx, done := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer done()
+	begin := time.Now()
+	s.Shutdown(shutdownCtx)
+
+	assert.GreaterOrEqual(t, time.Since(begin), 15*time.Millisecond, "waited for the running job")
+	assert.Equal(t, int32(1), lock.released.Load(), "lock released after the job returned")
+}
+
+func TestScheduler_Register_InvalidSchedule(t *testing.T) {
+	s := newTestScheduler(&fakeLock{}, &fakeHistory{})
+	assert.Panics(t, func() { s.Register(Job{Name: "bad", Schedule: "not a cron"}) })
+
+	s.Register(Job{Name: "ok", Schedule: "*/5 * * * *"})
+	assert.Panics(t, func() { s.Register(Job{Name: "ok", Schedule: "@hourly"}) })
+}
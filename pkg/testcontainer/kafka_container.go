@@ -0,0 +1,84 @@
+package testcontainer
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/go-connections/nat"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// KafkaContainer manages a single-node Kafka test container in KRaft mode.
+type KafkaContainer struct {
+	HostPort   string
+	Partitions int // partitions of auto-created topics, default: 3
+
+	container testcontainers.Container
+}
+
+func (k *KafkaContainer) Start(ctx context.Context) error {
+	partitions := k.Partitions
+	if partitions <= 0 {
+		partitions = 3
+	}
+
+	c, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        "apache/kafka:3.9.1",
+			ExposedPorts: []string{k.HostPort + ":9092/tcp"},
+			Env: map[string]string{
+				"KAFKA_NODE_ID":                                  "1",
+				"KAFKA_PROCESS_ROLES":                            "broker,controller",
+				"KAFKA_LISTENERS":                                "PLAINTEXT://:9092,CONTROLLER://:9093",
+				"KAFKA_ADVERTISED_LISTENERS":                     "PLAINTEXT://localhost:" + k.HostPort,
+				"KAFKA_CONTROLLER_LISTENER_NAMES":                "CONTROLLER",
+				"KAFKA_LISTENER_SECURITY_PROTOCOL_MAP":           "CONTROLLER:PLAINTEXT,PLAINTEXT:PLAINTEXT",
+				"KAFKA_CONTROLLER_QUORUM_VOTERS":                 "1@localhost:9093",
+				"KAFKA_OFFSETS_TOPIC_REPLICATION_FACTOR":         "1",
+				"KAFKA_TRANSACTION_STATE_LOG_REPLICATION_FACTOR": "1",
+				"KAFKA_TRANSACTION_STATE_LOG_MIN_ISR":            "1",
+				"KAFKA_GROUP_INITIAL_REBALANCE_DELAY_MS":         "0",
+				"KAFKA_NUM_PARTITIONS":                           fmt.Sprint(partitions),
+			},
+			HostConfigModifier: func(hc *container.HostConfig) {
+				hc.PortBindings = nat.PortMap{
+					"9092/tcp": []nat.PortBinding{{HostPort: k.HostPort}},
+				}
+			},
+			WaitingFor: wait.ForLog("Kafka Server started").
+				WithStartupTimeout(90 * time.Second),
+		},
+		Started: true,
+	})
+	if err != nil {
+		return fmt.Errorf("start kafka container: %w", err)
+	}
+	k.container = c
+
+	return nil
+}
+
+func (k *KafkaContainer) Migrate(_ context.Context) error { return nil }
+
+func (k *KafkaContainer) InitFixtures() error { return nil }
+
+func (k *KafkaContainer) LoadFixtures(_ *testing.T, _ string) {}
+
+func (k *KafkaContainer) Clean(_ context.Context) error { return nil }
+
+func (k *KafkaContainer) Close() {}
+
+func (k *KafkaContainer) Terminate(ctx context.Context) {
+	if k.container != nil {
+		_ = k.container.Terminate(ctx)
+	}
+}
+
+// Brokers returns the bootstrap broker addresses.
+func (k *KafkaContainer) Brokers() []string {
+	return []string{"localhost:" + k.HostPort}
+}
@@ -0,0 +1,92 @@
+//go:build functional
+
+package functional
+
+import (
+	"context"
+	"net/http"
+
+	gen "starter-boilerplate/gen/user"
+	"starter-boilerplate/pkg/apperror"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func (s *FunctionalSuite) TestErrors_ProblemJSON() {
+	body := `{"email":"user@example.com","password":"` + s.TestPassword + `"}`
+	resp := s.DoRequest(http.MethodPost, "/api/v1/auth/register", body, map[string]string{"X-Request-Id": "req-dup"})
+	s.Require().Equal(http.StatusConflict, resp.StatusCode)
+	s.Assert().Equal("application/problem+json", resp.Header.Get("Content-Type"))
+
+	var p apperror.Problem
+	s.ReadJSON(resp, &p)
+	s.Assert().Equal("email_already_exists", p.Code)
+	s.Assert().Equal("req-dup", p.RequestID)
+	s.Assert().Equal(http.StatusConflict, p.Status)
+}
+
+func (s *FunctionalSuite) TestErrors_ValidationFieldViolations() {
+	resp := s.DoRequest(http.MethodPost, "/api/v1/auth/login", `{"email":"not-an-email","password":"P@ssw0rd123"}`, nil)
+	s.Require().Equal(http.StatusUnprocessableEntity, resp.StatusCode)
+
+	var p apperror.Problem
+	s.ReadJSON(resp, &p)
+	s.Require().NotEmpty(p.Errors)
+	s.Assert().Equal("body.email", p.Errors[0].Field)
+}
+
+func (s *FunctionalSuite) TestErrors_GRPCStatusDetails() {
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-request-id", "req-grpc")
+	_, err := s.UserClient.Login(ctx, &gen.LoginRequest{Email: "not-an-email", Password: s.TestPassword})
+	s.Require().Error(err)
+
+	var info *errdetails.ErrorInfo
+	var violations *errdetails.BadRequest
+	var request *errdetails.RequestInfo
+	for _, d := range status.Convert(err).Details() {
+		switch d := d.(type) {
+		case *errdetails.ErrorInfo:
+			info = d
+		case *errdetails.BadRequest:
+			violations = d
+		case *errdetails.RequestInfo:
+			request = d
+		}
+	}
+	s.Require().NotNil(info)
+	s.Assert().Equal("bad_request", info.Reason)
+	s.Require().NotNil(violations)
+	s.Assert().Equal("email", violations.FieldViolations[0].Field)
+	s.Require().NotNil(request)
+	s.Assert().Equal("req-grpc", request.RequestId)
+}
+
+func (s *FunctionalSuite) TestErrors_Localized() {
+	body := `{"email":"user@example.com","password":"` + s.TestPassword + `"}`
+	resp := s.DoRequest(http.MethodPost, "/api/v1/auth/register", body, map[string]string{"Accept-Language": "ru-RU,ru;q=0.9"})
+	s.Require().Equal(http.StatusConflict, resp.StatusCode)
+	s.Assert().Equal("ru", resp.Header.Get("Content-Language"))
+
+	var p apperror.Problem
+	s.ReadJSON(resp, &p)
+	s.Assert().Equal("email_already_exists", p.Code)
+	s.Assert().Equal("пользователь с таким email уже существует", p.Detail)
+}
+
+func (s *FunctionalSuite) TestErrors_GRPCLocalizedMessage() {
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "grpc-accept-language", "es")
+	_, err := s.UserClient.Login(ctx, &gen.LoginRequest{Email: "user@example.com", Password: "wrong-password"})
+	s.Require().Error(err)
+
+	var localized *errdetails.LocalizedMessage
+	for _, d := range status.Convert(err).Details() {
+		if d, ok := d.(*errdetails.LocalizedMessage); ok {
+			localized = d
+		}
+	}
+	s.Require().NotNil(localized)
+	s.Assert().Equal("es", localized.Locale)
+	s.Assert().Equal("email o contraseña incorrectos", localized.Message)
+}
@@ -0,0 +1,63 @@
+//go:build functional
+
+package functional
+
+import (
+	"context"
+	"net/http"
+
+	gen "starter-boilerplate/gen/user"
+	"starter-boilerplate/gen/user/userconnect"
+
+	"connectrpc.com/connect"
+)
+
+func (s *FunctionalSuite) TestGateway_LoginThenGetUser() {
+	body := `{"email":"user@example.com","password":"` + s.TestPassword + `"}`
+	resp := s.DoRequest(http.MethodPost, "/v1/auth/login", body, nil)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	s.Assert().NotEmpty(resp.Header.Get("X-Request-Id"))
+
+	var pair struct {
+		AccessToken string `json:"accessToken"`
+	}
+	s.ReadJSON(resp, &pair)
+	s.Require().NotEmpty(pair.AccessToken)
+
+	resp = s.DoAuthRequest(http.MethodGet, "/v1/users/usr-user-001", pair.AccessToken, "")
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var u struct {
+		Email string `json:"email"`
+	}
+	s.ReadJSON(resp, &u)
+	s.Assert().Equal("user@example.com", u.Email)
+}
+
+func (s *FunctionalSuite) TestGateway_StatusesMatchHTTPAPI() {
+	token := s.IssueAccessToken("usr-user-001", "user")
+
+	for _, path := range []string{"/api/v1/users/usr-user-002", "/v1/users/usr-user-002"} {
+		resp := s.DoAuthRequest(http.MethodGet, path, token, "")
+		resp.Body.Close()
+		s.Assert().Equal(http.StatusForbidden, resp.StatusCode, path)
+	}
+
+	resp := s.DoRequest(http.MethodGet, "/v1/users/usr-user-001", "", nil)
+	resp.Body.Close()
+	s.Assert().Equal(http.StatusUnauthorized, resp.StatusCode)
+}
+
+func (s *FunctionalSuite) TestConnect_GetUser() {
+	client := userconnect.NewUserContractClient(http.DefaultClient, s.BaseURL)
+
+	req := connect.NewRequest(&gen.GetUserRequest{Id: "usr-user-001"})
+	req.Header().Set("Authorization", "Bearer "+s.IssueAccessToken("usr-user-001", "user"))
+	resp, err := client.GetUser(context.Background(), req)
+	s.Require().NoError(err)
+	s.Assert().Equal("user@example.com", resp.Msg.Email)
+
+	_, err = client.GetUser(context.Background(), connect.NewRequest(&gen.GetUserRequest{Id: "usr-user-001"}))
+	s.Require().Error(err)
+	s.Assert().Equal(connect.CodeUnauthenticated, connect.CodeOf(err))
+}
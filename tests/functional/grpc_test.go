@@ -0,0 +1,253 @@
+//go:build functional
+
+package functional
+
+import (
+	"context"
+	"time"
+
+	gen "starter-boilerplate/gen/user"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func (s *FunctionalSuite) assertGRPCCode(err error, code codes.Code) {
+	s.T().Helper()
+	s.Require().Error(err)
+	s.Assert().Equal(code, status.Code(err), err.Error())
+}
+
+func (s *FunctionalSuite) TestGRPC_LoginThenGetUser() {
+	pair, err := s.UserClient.Login(context.Background(), &gen.LoginRequest{
+		Email:    "user@example.com",
+		Password: s.TestPassword,
+	})
+	s.Require().NoError(err)
+	s.Require().NotEmpty(pair.AccessToken)
+	s.Require().NotEmpty(pair.RefreshToken)
+
+	u, err := s.UserClient.GetUser(s.GRPCAuthCtx(pair.AccessToken), &gen.GetUserRequest{Id: "usr-user-001"})
+	s.Require().NoError(err)
+	s.Assert().Equal("user@example.com", u.Email)
+	s.Assert().Equal("user", u.Role)
+}
+
+func (s *FunctionalSuite) TestGRPC_Login_WrongPassword() {
+	_, err := s.UserClient.Login(context.Background(), &gen.LoginRequest{
+		Email:    "user@example.com",
+		Password: "wrong-password",
+	})
+	s.assertGRPCCode(err, codes.Unauthenticated)
+}
+
+func (s *FunctionalSuite) TestGRPC_Login_InvalidEmail() {
+	_, err := s.UserClient.Login(context.Background(), &gen.LoginRequest{
+		Email:    "not-an-email",
+		Password: s.TestPassword,
+	})
+	s.assertGRPCCode(err, codes.InvalidArgument)
+}
+
+func (s *FunctionalSuite) TestGRPC_Register_DuplicateEmail() {
+	_, err := s.UserClient.Register(context.Background(), &gen.RegisterRequest{
+		Email:    "user@example.com",
+		Password: s.TestPassword,
+	})
+	s.assertGRPCCode(err, codes.Aborted)
+}
+
+func (s *FunctionalSuite) TestGRPC_Refresh() {
+	token := s.IssueRefreshToken("usr-user-001", "user")
+
+	pair, err := s.UserClient.Refresh(context.Background(), &gen.RefreshRequest{RefreshToken: token})
+	s.Require().NoError(err)
+	s.Assert().NotEmpty(pair.AccessToken)
+}
+
+func (s *FunctionalSuite) TestGRPC_GetUser_NoToken() {
+	_, err := s.UserClient.GetUser(context.Background(), &gen.GetUserRequest{Id: "usr-user-001"})
+	s.assertGRPCCode(err, codes.Unauthenticated)
+}
+
+func (s *FunctionalSuite) TestGRPC_GetUser_DeniedAccessToOther() {
+	ctx := s.GRPCAuthCtx(s.IssueAccessToken("usr-user-001", "user"))
+	_, err := s.UserClient.GetUser(ctx, &gen.GetUserRequest{Id: "usr-user-002"})
+	s.assertGRPCCode(err, codes.PermissionDenied)
+}
+
+func (s *FunctionalSuite) TestGRPC_ChangePassword() {
+	ctx := s.GRPCAuthCtx(s.IssueAccessToken("usr-user-002", "user"))
+	_, err := s.UserClient.ChangePassword(ctx, &gen.ChangePasswordRequest{
+		OldPassword: s.TestPassword,
+		NewPassword: "N3wP@ssw0rd",
+	})
+	s.Require().NoError(err)
+
+	_, err = s.UserClient.Login(context.Background(), &gen.LoginRequest{
+		Email:    "other@example.com",
+		Password: "N3wP@ssw0rd",
+	})
+	s.Require().NoError(err)
+}
+
+func (s *FunctionalSuite) TestGRPC_ListUsers_Admin() {
+	ctx := s.GRPCAuthCtx(s.IssueAccessToken("usr-admin-001", "admin"))
+	resp, err := s.UserClient.ListUsers(ctx, &gen.ListUsersRequest{Limit: 2})
+	s.Require().NoError(err)
+	s.Assert().Equal(int64(3), resp.Total)
+	s.Require().Len(resp.Users, 2)
+	s.Assert().Equal("admin@example.com", resp.Users[0].Email)
+}
+
+func (s *FunctionalSuite) TestGRPC_ListUsers_UserDenied() {
+	ctx := s.GRPCAuthCtx(s.IssueAccessToken("usr-user-001", "user"))
+	_, err := s.UserClient.ListUsers(ctx, &gen.ListUsersRequest{})
+	s.assertGRPCCode(err, codes.PermissionDenied)
+}
+
+func (s *FunctionalSuite) TestGRPC_GetAndUpdateProfile() {
+	ctx := s.GRPCAuthCtx(s.IssueAccessToken("usr-user-001", "user"))
+
+	p, err := s.UserClient.GetProfile(ctx, &gen.GetProfileRequest{UserId: "usr-user-001"})
+	s.Require().NoError(err)
+	s.Assert().Equal(float64(3), p.Numbers["logins"])
+
+	p, err = s.UserClient.UpdateProfile(ctx, &gen.UpdateProfileRequest{
+		UserId:     "usr-user-001",
+		SetStrings: map[string]string{"nickname": "neo"},
+	})
+	s.Require().NoError(err)
+	s.Assert().Equal("neo", p.Strings["nickname"])
+	s.Assert().Equal(float64(3), p.Numbers["logins"])
+}
+
+func (s *FunctionalSuite) TestGRPC_UpdateProfile_ServerKeyDenied() {
+	ctx := s.GRPCAuthCtx(s.IssueAccessToken("usr-admin-001", "admin"))
+	_, err := s.UserClient.UpdateProfile(ctx, &gen.UpdateProfileRequest{
+		UserId:      "usr-user-001",
+		IncrNumbers: map[string]float64{"logins": 2},
+	})
+	s.assertGRPCCode(err, codes.PermissionDenied)
+}
+
+func (s *FunctionalSuite) TestGRPC_UpdateProfile_UnknownKey() {
+	ctx := s.GRPCAuthCtx(s.IssueAccessToken("usr-user-001", "user"))
+	_, err := s.UserClient.UpdateProfile(ctx, &gen.UpdateProfileRequest{
+		UserId:     "usr-user-001",
+		SetNumbers: map[string]float64{"karma": 1},
+	})
+	s.assertGRPCCode(err, codes.InvalidArgument)
+}
+
+func (s *FunctionalSuite) TestGRPC_UpdateProfile_TypedValues() {
+	ctx := s.GRPCAuthCtx(s.IssueAccessToken("usr-admin-001", "admin"))
+	p, err := s.UserClient.UpdateProfile(ctx, &gen.UpdateProfileRequest{
+		UserId:   "usr-user-001",
+		SetBools: map[string]bool{"verified": true},
+		AddTags:  map[string]*gen.StringList{"badges": {Values: []string{"early_adopter"}}},
+		CompareAndSet: []*gen.CompareAndSet{{
+			Key:      "nickname",
+			Expected: &gen.ProfileValue{Kind: &gen.ProfileValue_String_{String_: "user"}},
+			Value:    &gen.ProfileValue{Kind: &gen.ProfileValue_String_{String_: "neo"}},
+		}},
+	})
+	s.Require().NoError(err)
+	s.Assert().True(p.Bools["verified"])
+	s.Assert().Equal([]string{"early_adopter"}, p.Tags["badges"].GetValues())
+	s.Assert().Equal("neo", p.Strings["nickname"])
+}
+
+func (s *FunctionalSuite) TestGRPC_UpdateProfile_Empty() {
+	ctx := s.GRPCAuthCtx(s.IssueAccessToken("usr-user-001", "user"))
+	_, err := s.UserClient.UpdateProfile(ctx, &gen.UpdateProfileRequest{UserId: "usr-user-001"})
+	s.assertGRPCCode(err, codes.InvalidArgument)
+}
+
+func (s *FunctionalSuite) TestGRPC_HealthServing() {
+	resp, err := s.HealthClient.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	s.Require().NoError(err)
+	s.Assert().Equal(healthpb.HealthCheckResponse_SERVING, resp.Status)
+
+	resp, err = s.HealthClient.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "user.UserContract"})
+	s.Require().NoError(err)
+	s.Assert().Equal(healthpb.HealthCheckResponse_SERVING, resp.Status)
+}
+
+func (s *FunctionalSuite) TestGRPC_RequestIDEchoed() {
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-request-id", "grpc-req-1")
+	var header metadata.MD
+
+	_, err := s.UserClient.Login(ctx, &gen.LoginRequest{Email: "user@example.com", Password: s.TestPassword}, grpc.Header(&header))
+	s.Require().NoError(err)
+	s.Assert().Equal([]string{"grpc-req-1"}, header.Get("x-request-id"))
+}
+
+func (s *FunctionalSuite) TestGRPC_WatchUserEvents_Live() {
+	ctx, cancel := context.WithTimeout(s.GRPCAuthCtx(s.IssueAccessToken("usr-user-001", "user")), 15*time.Second)
+	defer cancel()
+
+	stream, err := s.UserClient.WatchUserEvents(ctx, &gen.WatchUserEventsRequest{UserId: "usr-user-001"})
+	s.Require().NoError(err)
+
+	received := make(chan *gen.UserEvent, 16)
+	go func() {
+		for {
+			e, err := stream.Recv()
+			if err != nil {
+				close(received)
+				return
+			}
+			received <- e
+		}
+	}()
+
+	// The stream subscribes asynchronously: log in until an event arrives.
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		_, err := s.UserClient.Login(context.Background(), &gen.LoginRequest{Email: "user@example.com", Password: s.TestPassword})
+		s.Require().NoError(err)
+
+		select {
+		case e, ok := <-received:
+			s.Require().True(ok, "stream ended")
+			s.Assert().Equal("user.logged_in", e.Name)
+			s.Assert().Equal("usr-user-001", e.UserId)
+			s.Assert().Positive(e.Position)
+			s.Assert().Contains(string(e.Payload), `"user_id":"usr-user-001"`)
+			return
+		case <-ticker.C:
+		case <-ctx.Done():
+			s.Require().Fail("no event received")
+		}
+	}
+}
+
+func (s *FunctionalSuite) TestGRPC_WatchUserEvents_Resume() {
+	_, err := s.UserClient.Login(context.Background(), &gen.LoginRequest{Email: "other@example.com", Password: s.TestPassword})
+	s.Require().NoError(err)
+
+	// Replay from the start until the relay has published the login.
+	s.Require().Eventually(func() bool {
+		ctx, cancel := context.WithTimeout(s.GRPCAuthCtx(s.IssueAccessToken("usr-admin-001", "admin")), time.Second)
+		defer cancel()
+		stream, err := s.UserClient.WatchUserEvents(ctx, &gen.WatchUserEventsRequest{UserId: "usr-user-002", AfterPosition: proto.Int64(0)})
+		s.Require().NoError(err)
+		e, err := stream.Recv()
+		return err == nil && e.Name == "user.logged_in" && e.UserId == "usr-user-002"
+	}, 10*time.Second, 500*time.Millisecond)
+}
+
+func (s *FunctionalSuite) TestGRPC_WatchUserEvents_AllUsersRequiresAdmin() {
+	ctx := s.GRPCAuthCtx(s.IssueAccessToken("usr-user-001", "user"))
+
+	stream, err := s.UserClient.WatchUserEvents(ctx, &gen.WatchUserEventsRequest{})
+	s.Require().NoError(err)
+	_, err = stream.Recv()
+	s.assertGRPCCode(err, codes.PermissionDenied)
+}
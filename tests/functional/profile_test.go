@@ -0,0 +1,169 @@
+//go:build functional
+
+package functional
+
+import (
+	"net/http"
+
+	"starter-boilerplate/internal/user/transport/dto"
+	"starter-boilerplate/pkg/apperror"
+)
+
+func (s *FunctionalSuite) TestProfile_GetSelf() {
+	token := s.IssueAccessToken("usr-user-001", "user")
+	resp := s.DoAuthRequest(http.MethodGet, "/api/v1/users/usr-user-001/profile", token, "")
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var body struct {
+		Profile dto.ProfileDTO `json:"profile"`
+	}
+	s.ReadJSON(resp, &body)
+	s.Assert().Equal("usr-user-001", body.Profile.UserID)
+	s.Assert().Equal(float64(3), body.Profile.Numbers["logins"])
+	s.Assert().Equal("user", body.Profile.Strings["nickname"])
+	s.Assert().NotContains(body.Profile.Numbers, "password_changes")
+}
+
+func (s *FunctionalSuite) TestProfile_GetOtherPublicKeys() {
+	token := s.IssueAccessToken("usr-user-002", "user")
+	resp := s.DoAuthRequest(http.MethodGet, "/api/v1/users/usr-user-001/profile", token, "")
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var body struct {
+		Profile dto.ProfileDTO `json:"profile"`
+	}
+	s.ReadJSON(resp, &body)
+	s.Assert().Equal(map[string]string{"nickname": "user"}, body.Profile.Strings)
+	s.Assert().Empty(body.Profile.Numbers)
+}
+
+func (s *FunctionalSuite) TestProfile_PatchSelf() {
+	token := s.IssueAccessToken("usr-user-001", "user")
+	resp := s.DoAuthRequest(http.MethodPatch, "/api/v1/users/usr-user-001/profile", token,
+		`{"set_strings":{"nickname":"neo","timezone":"Europe/Berlin"}}`)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var body struct {
+		Profile dto.ProfileDTO `json:"profile"`
+	}
+	s.ReadJSON(resp, &body)
+	s.Assert().Equal("neo", body.Profile.Strings["nickname"])
+	s.Assert().Equal("Europe/Berlin", body.Profile.Strings["timezone"])
+	s.Assert().Equal(float64(3), body.Profile.Numbers["logins"])
+}
+
+func (s *FunctionalSuite) TestProfile_AdminIncrementsCounter() {
+	token := s.IssueAccessToken("usr-admin-001", "admin")
+	for range 2 {
+		resp := s.DoAuthRequest(http.MethodPatch, "/api/v1/users/usr-user-001/profile", token, `{"incr_numbers":{"reputation":5}}`)
+		s.Require().Equal(http.StatusOK, resp.StatusCode)
+		resp.Body.Close()
+	}
+
+	resp := s.DoAuthRequest(http.MethodGet, "/api/v1/users/usr-user-001/profile", token, "")
+	var body struct {
+		Profile dto.ProfileDTO `json:"profile"`
+	}
+	s.ReadJSON(resp, &body)
+	s.Assert().Equal(float64(10), body.Profile.Numbers["reputation"])
+}
+
+func (s *FunctionalSuite) TestProfile_UserCannotSetAdminKey() {
+	token := s.IssueAccessToken("usr-user-001", "user")
+	resp := s.DoAuthRequest(http.MethodPatch, "/api/v1/users/usr-user-001/profile", token, `{"set_strings":{"tier":"pro"}}`)
+	s.Require().Equal(http.StatusForbidden, resp.StatusCode)
+
+	var body apperror.Problem
+	s.ReadJSON(resp, &body)
+	s.Assert().Equal("access_denied", body.Code)
+	s.Require().Len(body.Errors, 1)
+	s.Assert().Equal("tier", body.Errors[0].Field)
+	s.Assert().Equal("read_only", body.Errors[0].Reason)
+}
+
+func (s *FunctionalSuite) TestProfile_UnknownKey() {
+	token := s.IssueAccessToken("usr-user-001", "user")
+	resp := s.DoAuthRequest(http.MethodPatch, "/api/v1/users/usr-user-001/profile", token, `{"set_numbers":{"karma":1}}`)
+	s.Require().Equal(http.StatusBadRequest, resp.StatusCode)
+
+	var body apperror.Problem
+	s.ReadJSON(resp, &body)
+	s.Assert().Equal("invalid_profile_update", body.Code)
+	s.Require().Len(body.Errors, 1)
+	s.Assert().Equal("unknown_key", body.Errors[0].Reason)
+}
+
+func (s *FunctionalSuite) TestProfile_KeyInTwoOperations() {
+	token := s.IssueAccessToken("usr-user-001", "user")
+	resp := s.DoAuthRequest(http.MethodPatch, "/api/v1/users/usr-user-001/profile", token, `{"set_strings":{"bio":"hi"},"delete":["bio"]}`)
+	s.Require().Equal(http.StatusBadRequest, resp.StatusCode)
+
+	var body apperror.Problem
+	s.ReadJSON(resp, &body)
+	s.Assert().Equal("invalid_profile_update", body.Code)
+	s.Require().Len(body.Errors, 1)
+	s.Assert().Equal("bio", body.Errors[0].Field)
+	s.Assert().Equal("changed_twice", body.Errors[0].Reason)
+}
+
+func (s *FunctionalSuite) TestProfile_OutOfBounds() {
+	token := s.IssueAccessToken("usr-admin-001", "admin")
+	resp := s.DoAuthRequest(http.MethodPatch, "/api/v1/users/usr-user-001/profile", token,
+		`{"set_numbers":{"reputation":-1},"set_strings":{"settings.theme":"pink"}}`)
+	s.Require().Equal(http.StatusBadRequest, resp.StatusCode)
+
+	var body apperror.Problem
+	s.ReadJSON(resp, &body)
+	s.Assert().Equal("invalid_profile_update", body.Code)
+	s.Require().Len(body.Errors, 2)
+	s.Assert().Equal("minimum", body.Errors[0].Reason)
+	s.Assert().Equal("enum", body.Errors[1].Reason)
+}
+
+func (s *FunctionalSuite) TestProfile_EmptyPatch() {
+	token := s.IssueAccessToken("usr-user-001", "user")
+	resp := s.DoAuthRequest(http.MethodPatch, "/api/v1/users/usr-user-001/profile", token, `{}`)
+	defer resp.Body.Close()
+	s.Assert().Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *FunctionalSuite) TestProfile_PatchRichOperations() {
+	token := s.IssueAccessToken("usr-user-001", "user")
+	resp := s.DoAuthRequest(http.MethodPatch, "/api/v1/users/usr-user-001/profile", token, `{
+		"set_bools": {"settings.notifications.email": true},
+		"add_tags": {"interests": ["go", "chess"]},
+		"set_if_absent": {"settings.theme": "dark", "nickname": "ignored"},
+		"delete": ["bio"]
+	}`)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var body struct {
+		Profile dto.ProfileDTO `json:"profile"`
+	}
+	s.ReadJSON(resp, &body)
+	s.Assert().Equal(map[string]bool{"settings.notifications.email": true}, body.Profile.Bools)
+	s.Assert().Equal([]string{"chess", "go"}, body.Profile.Tags["interests"])
+	s.Assert().Equal("dark", body.Profile.Strings["settings.theme"])
+	s.Assert().Equal("user", body.Profile.Strings["nickname"])
+}
+
+func (s *FunctionalSuite) TestProfile_CompareAndSet() {
+	token := s.IssueAccessToken("usr-user-001", "user")
+	resp := s.DoAuthRequest(http.MethodPatch, "/api/v1/users/usr-user-001/profile", token,
+		`{"compare_and_set": [{"key": "nickname", "expected": "someone", "value": "neo"}]}`)
+	s.Require().Equal(http.StatusConflict, resp.StatusCode)
+
+	var problem apperror.Problem
+	s.ReadJSON(resp, &problem)
+	s.Assert().Equal("profile_condition_failed", problem.Code)
+
+	resp = s.DoAuthRequest(http.MethodPatch, "/api/v1/users/usr-user-001/profile", token,
+		`{"compare_and_set": [{"key": "nickname", "expected": "user", "value": "neo"}]}`)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var body struct {
+		Profile dto.ProfileDTO `json:"profile"`
+	}
+	s.ReadJSON(resp, &body)
+	s.Assert().Equal("neo", body.Profile.Strings["nickname"])
+}
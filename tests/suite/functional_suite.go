@@ -3,12 +3,14 @@ package suite
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"strings"
 	"time"
 
+	gen "starter-boilerplate/gen/user"
 	"starter-boilerplate/internal"
 	"starter-boilerplate/internal/shared/config"
 	sharedjwt "starter-boilerplate/internal/shared/jwt"
@@ -17,6 +19,10 @@ import (
 
 	"github.com/stretchr/testify/suite"
 	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
 )
 
 // FunctionalSuite is a reusable test suite that boots the full application
@@ -24,10 +30,13 @@ import (
 // to get lifecycle management and HTTP helpers for free.
 type FunctionalSuite struct {
 	suite.Suite
-	CM         *testcontainer.ContainerManager
-	JWTManager *pkgjwt.Manager
-	BaseURL    string
-	cancel     context.CancelFunc
+	CM           *testcontainer.ContainerManager
+	JWTManager   *pkgjwt.Manager
+	BaseURL      string
+	UserClient   gen.UserContractClient
+	HealthClient healthpb.HealthClient
+	grpcConn     *grpc.ClientConn
+	cancel       context.CancelFunc
 
 	// Configuration — set before suite.Run; defaults applied in SetupSuite.
 	PgDatabase   string // default: "testdb"
@@ -103,9 +112,16 @@ func (s *FunctionalSuite) SetupSuite() {
 	case <-time.After(10 * time.Second):
 		s.Require().Fail("server timeout")
 	}
+
+	s.grpcConn, err = grpc.NewClient(fmt.Sprintf("localhost:%d", cfg.GRPC.Port),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	s.Require().NoError(err, "grpc client")
+	s.UserClient = gen.NewUserContractClient(s.grpcConn)
+	s.HealthClient = healthpb.NewHealthClient(s.grpcConn)
 }
 
 func (s *FunctionalSuite) TearDownSuite() {
+	s.grpcConn.Close()
 	s.cancel()
 	s.CM.Close()
 	s.CM.Terminate(context.Background())
@@ -141,6 +157,11 @@ func (s *FunctionalSuite) DoAuthRequest(method, path, token, body string) *http.
 	})
 }
 
+// GRPCAuthCtx returns a context that sends token as bearer authorization metadata.
+func (s *FunctionalSuite) GRPCAuthCtx(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
 func (s *FunctionalSuite) ReadJSON(resp *http.Response, target any) {
 	s.T().Helper()
 	defer resp.Body.Close()